@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package client verifies responses from the Trillian log.
+// Package client verifies responses from Trillian logs and maps.
 package client
 
 import (
@@ -21,7 +21,6 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/google/trillian"
@@ -32,6 +31,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// leafStatusTimeout bounds the GetLeafStatus call that AddLeaf makes to explain a failure, so
+// that a caller's context without a deadline can't keep AddLeaf waiting on it.
+const leafStatusTimeout = 5 * time.Second
+
 // LogClient represents a client for a given Trillian log instance.
 type LogClient struct {
 	LogID  int64
@@ -66,11 +69,39 @@ func (c *LogClient) AddLeaf(ctx context.Context, data []byte) error {
 		return fmt.Errorf("QueueLeaf(): %v", err)
 	}
 	if err := c.WaitForInclusion(ctx, data); err != nil {
-		return fmt.Errorf("WaitForInclusion(): %v", err)
+		return fmt.Errorf("WaitForInclusion(): %v (%v)", err, c.describeLeafStatus(ctx, data))
 	}
 	return nil
 }
 
+// LeafStatus reports whether the leaf for data is queued or sequenced in the log.
+func (c *LogClient) LeafStatus(ctx context.Context, data []byte) (*trillian.GetLeafStatusResponse, error) {
+	leaf := c.logVerifier.buildLeaf(data)
+	return c.client.GetLeafStatus(ctx, &trillian.GetLeafStatusRequest{
+		LogId:            c.LogID,
+		LeafIdentityHash: leaf.LeafIdentityHash,
+	})
+}
+
+// describeLeafStatus explains how far the leaf for data has got through the log, so that
+// AddLeaf failures say whether the leaf is stuck in the queue or was lost.
+func (c *LogClient) describeLeafStatus(ctx context.Context, data []byte) string {
+	ctx, cancel := context.WithTimeout(ctx, leafStatusTimeout)
+	defer cancel()
+	resp, err := c.LeafStatus(ctx, data)
+	if err != nil {
+		return fmt.Sprintf("leaf status unavailable: %v", err)
+	}
+	switch resp.Status {
+	case trillian.LeafStatus_QUEUED:
+		return fmt.Sprintf("leaf queued at %v but not yet sequenced", time.Unix(0, resp.QueueTimestampNanos).UTC())
+	case trillian.LeafStatus_SEQUENCED:
+		return fmt.Sprintf("leaf sequenced at index %v", resp.LeafIndex)
+	default:
+		return "leaf unknown to log"
+	}
+}
+
 // GetByIndex returns a single leaf at the requested index.
 func (c *LogClient) GetByIndex(ctx context.Context, index int64) (*trillian.LogLeaf, error) {
 	resp, err := c.client.GetLeavesByIndex(ctx, &trillian.GetLeavesByIndexRequest{
@@ -88,40 +119,36 @@ func (c *LogClient) GetByIndex(ctx context.Context, index int64) (*trillian.LogL
 
 // ListByIndex returns the requested leaves by index.
 func (c *LogClient) ListByIndex(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
-	indexes := make([]int64, count)
-	for i := range indexes {
-		indexes[i] = start + int64(i)
-	}
-
-	resp, err := c.client.GetLeavesByIndex(ctx,
-		&trillian.GetLeavesByIndexRequest{
-			LogId:     c.LogID,
-			LeafIndex: indexes,
-		})
-	if err != nil {
-		return nil, err
+	leaves := make([]*trillian.LogLeaf, 0, count)
+	// The server may return fewer leaves than requested, so keep asking for the rest.
+	for int64(len(leaves)) < count {
+		resp, err := c.client.GetLeavesByRange(ctx,
+			&trillian.GetLeavesByRangeRequest{
+				LogId:      c.LogID,
+				StartIndex: start + int64(len(leaves)),
+				Count:      count - int64(len(leaves)),
+			})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Leaves) == 0 {
+			break
+		}
+		leaves = append(leaves, resp.Leaves...)
 	}
-	// Responses are not required to be in-order.
-	sort.Sort(byLeafIndex(resp.Leaves))
 	// Verify that we got back the requested leaves.
-	if got, want := len(resp.Leaves), len(indexes); got != want {
+	if got, want := int64(len(leaves)), count; got != want {
 		return nil, fmt.Errorf("len(Leaves): %v, want %v", got, want)
 	}
-	for i, l := range resp.Leaves {
-		if got, want := l.LeafIndex, indexes[i]; got != want {
+	for i, l := range leaves {
+		if got, want := l.LeafIndex, start+int64(i); got != want {
 			return nil, fmt.Errorf("Leaves[%v].Index: %v, want %v", i, got, want)
 		}
 	}
 
-	return resp.Leaves, nil
+	return leaves, nil
 }
 
-type byLeafIndex []*trillian.LogLeaf
-
-func (ll byLeafIndex) Len() int           { return len(ll) }
-func (ll byLeafIndex) Swap(i, j int)      { ll[i], ll[j] = ll[j], ll[i] }
-func (ll byLeafIndex) Less(i, j int) bool { return ll[i].LeafIndex < ll[j].LeafIndex }
-
 // waitForRootUpdate repeatedly fetches the Root until the TreeSize changes
 // or until ctx times out.
 func (c *LogClient) waitForRootUpdate(ctx context.Context) error {
@@ -164,6 +191,11 @@ func (c *LogClient) UpdateRoot(ctx context.Context) error {
 		// Tree has not been updated.
 		return nil
 	}
+	if resp.GetSignedLogRoot().GetTreeSize() < c.root.TreeSize {
+		// The server lags behind the trusted root, e.g. a replica which hasn't
+		// caught up yet. Keep the trusted root.
+		return nil
+	}
 	// Fetch a consistency proof if this isn't the first root we've seen.
 	var consistency *trillian.GetConsistencyProofResponse
 	if c.root.TreeSize > 0 {
@@ -195,24 +227,13 @@ func (c *LogClient) UpdateRoot(ctx context.Context) error {
 func (c *LogClient) WaitForInclusion(ctx context.Context, data []byte) error {
 	leaf := c.logVerifier.buildLeaf(data)
 
-	// Fetch the current Root to improve our chances at a valid inclusion proof.
-	if err := c.UpdateRoot(ctx); err != nil {
-		return err
-	}
-	if c.root.TreeSize == 0 {
-		// If the TreeSize is 0, wait for something to be in the log.
-		// It is illegal to ask for an inclusion proof with TreeSize = 0.
-		if err := c.waitForRootUpdate(ctx); err != nil {
-			return err
-		}
-	}
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
-		err := c.getInclusionProof(ctx, leaf.MerkleLeafHash, c.root.TreeSize)
+		err := c.getInclusionProof(ctx, leaf.MerkleLeafHash)
 		s, ok := status.FromError(err)
 		if !ok {
 			return err
@@ -220,7 +241,7 @@ func (c *LogClient) WaitForInclusion(ctx context.Context, data []byte) error {
 		switch s.Code() {
 		case codes.OK:
 			return nil
-		case codes.NotFound:
+		case codes.NotFound, codes.Unavailable:
 			// Wait for TreeSize to update.
 			if err := c.waitForRootUpdate(ctx); err != nil {
 				return err
@@ -234,10 +255,7 @@ func (c *LogClient) WaitForInclusion(ctx context.Context, data []byte) error {
 // VerifyInclusion updates the log root and ensures that the given leaf data has been included in the log.
 func (c *LogClient) VerifyInclusion(ctx context.Context, data []byte) error {
 	leaf := c.logVerifier.buildLeaf(data)
-	if err := c.UpdateRoot(ctx); err != nil {
-		return fmt.Errorf("UpdateRoot(): %v", err)
-	}
-	return c.getInclusionProof(ctx, leaf.MerkleLeafHash, c.root.TreeSize)
+	return c.getInclusionProof(ctx, leaf.MerkleLeafHash)
 }
 
 // VerifyInclusionAtIndex updates the log root and ensures that the given leaf data has been included in the log at a particular index.
@@ -257,12 +275,15 @@ func (c *LogClient) VerifyInclusionAtIndex(ctx context.Context, data []byte, ind
 	return c.logVerifier.VerifyInclusionAtIndex(&c.root, data, index, resp.Proof.Hashes)
 }
 
-func (c *LogClient) getInclusionProof(ctx context.Context, leafHash []byte, treeSize int64) error {
+// getInclusionProof fetches the inclusion proofs for leafHash in the latest root, together with
+// that root and its consistency proof from the trusted root, in a single request. Once all of
+// them verify, the new root becomes the trusted root.
+func (c *LogClient) getInclusionProof(ctx context.Context, leafHash []byte) error {
 	resp, err := c.client.GetInclusionProofByHash(ctx,
 		&trillian.GetInclusionProofByHashRequest{
-			LogId:    c.LogID,
-			LeafHash: leafHash,
-			TreeSize: treeSize,
+			LogId:         c.LogID,
+			LeafHash:      leafHash,
+			FirstTreeSize: c.root.TreeSize,
 		})
 	if err != nil {
 		return err
@@ -270,11 +291,23 @@ func (c *LogClient) getInclusionProof(ctx context.Context, leafHash []byte, tree
 	if len(resp.Proof) < 1 {
 		return errors.New("no inclusion proof supplied")
 	}
+	root := resp.GetSignedLogRoot()
+	if root == nil {
+		return errors.New("no signed log root supplied")
+	}
+	if root.TreeSize < c.root.TreeSize {
+		// A lagging server rather than a bad request, so it's worth retrying.
+		return status.Errorf(codes.Unavailable, "server root has TreeSize %v, behind trusted TreeSize %v", root.TreeSize, c.root.TreeSize)
+	}
+	if err := c.logVerifier.VerifyRoot(&c.root, root, resp.GetConsistencyProof().GetHashes()); err != nil {
+		return err
+	}
 	for _, proof := range resp.Proof {
-		if err := c.logVerifier.VerifyInclusionByHash(&c.root, leafHash, proof); err != nil {
+		if err := c.logVerifier.VerifyInclusionByHash(root, leafHash, proof); err != nil {
 			return err
 		}
 	}
+	c.root = *root
 	return nil
 }
 
@@ -40,6 +40,11 @@ func (c *MockLogClient) QueueLeaves(ctx context.Context, in *trillian.QueueLeave
 	return c.c.QueueLeaves(ctx, in)
 }
 
+// AddSequencedLeaves forwards requests.
+func (c *MockLogClient) AddSequencedLeaves(ctx context.Context, in *trillian.AddSequencedLeavesRequest, opts ...grpc.CallOption) (*trillian.AddSequencedLeavesResponse, error) {
+	return c.c.AddSequencedLeaves(ctx, in)
+}
+
 // GetInclusionProof forwards requests and optionally corrupts the response.
 func (c *MockLogClient) GetInclusionProof(ctx context.Context, in *trillian.GetInclusionProofRequest, opts ...grpc.CallOption) (*trillian.GetInclusionProofResponse, error) {
 	resp, err := c.c.GetInclusionProof(ctx, in)
@@ -103,11 +108,31 @@ func (c *MockLogClient) GetLeavesByIndex(ctx context.Context, in *trillian.GetLe
 	return c.c.GetLeavesByIndex(ctx, in)
 }
 
+// GetLeavesByRange forwards requests.
+func (c *MockLogClient) GetLeavesByRange(ctx context.Context, in *trillian.GetLeavesByRangeRequest, opts ...grpc.CallOption) (*trillian.GetLeavesByRangeResponse, error) {
+	return c.c.GetLeavesByRange(ctx, in)
+}
+
+// StreamLeavesByRange forwards requests.
+func (c *MockLogClient) StreamLeavesByRange(ctx context.Context, in *trillian.GetLeavesByRangeRequest, opts ...grpc.CallOption) (trillian.TrillianLog_StreamLeavesByRangeClient, error) {
+	return c.c.StreamLeavesByRange(ctx, in)
+}
+
 // GetLeavesByHash forwards requests.
 func (c *MockLogClient) GetLeavesByHash(ctx context.Context, in *trillian.GetLeavesByHashRequest, opts ...grpc.CallOption) (*trillian.GetLeavesByHashResponse, error) {
 	return c.c.GetLeavesByHash(ctx, in)
 }
 
+// GetLeavesByIdentityHash forwards requests.
+func (c *MockLogClient) GetLeavesByIdentityHash(ctx context.Context, in *trillian.GetLeavesByIdentityHashRequest, opts ...grpc.CallOption) (*trillian.GetLeavesByIdentityHashResponse, error) {
+	return c.c.GetLeavesByIdentityHash(ctx, in)
+}
+
+// GetLeafStatus forwards requests.
+func (c *MockLogClient) GetLeafStatus(ctx context.Context, in *trillian.GetLeafStatusRequest, opts ...grpc.CallOption) (*trillian.GetLeafStatusResponse, error) {
+	return c.c.GetLeafStatus(ctx, in)
+}
+
 // GetEntryAndProof forwards requests.
 func (c *MockLogClient) GetEntryAndProof(ctx context.Context, in *trillian.GetEntryAndProofRequest, opts ...grpc.CallOption) (*trillian.GetEntryAndProofResponse, error) {
 	return c.c.GetEntryAndProof(ctx, in)
@@ -57,3 +57,28 @@ type LogVerifier interface {
 	// VerifyInclusionByHash verifies the inclusion proof for data
 	VerifyInclusionByHash(trusted *trillian.SignedLogRoot, leafHash []byte, proof *trillian.Proof) error
 }
+
+// VerifyingMapClient is a client that verifies output from a Trillian Map.
+type VerifyingMapClient interface {
+	// GetAndVerifyLeaves fetches the leaves at indexes from the latest map
+	// revision. The map root is verified, as are the inclusion proofs of all
+	// leaves. Leaves absent from the map are returned with empty values.
+	GetAndVerifyLeaves(ctx context.Context, indexes [][]byte) ([]*trillian.MapLeaf, error)
+	// SetLeaves writes leaves to the map, retrying on transient errors, and
+	// verifies the resulting root.
+	SetLeaves(ctx context.Context, leaves []*trillian.MapLeaf, metadata *trillian.MapperMetadata) (*trillian.SignedMapRoot, error)
+	// UpdateRoot fetches and verifies the current SignedMapRoot.
+	// It checks signatures and that the revision doesn't go backwards.
+	UpdateRoot(ctx context.Context) error
+	// Root provides the last root obtained by the client.
+	Root() trillian.SignedMapRoot
+}
+
+// MapVerifier verifies responses from a Trillian Map.
+type MapVerifier interface {
+	// VerifySignedMapRoot verifies the signature on smr.
+	VerifySignedMapRoot(smr *trillian.SignedMapRoot) error
+	// VerifyMapLeafInclusion verifies that inclusion's leaf is committed to
+	// by smr's root hash, or that its index is empty if it's marked absent.
+	VerifyMapLeafInclusion(smr *trillian.SignedMapRoot, inclusion *trillian.MapLeafInclusion) error
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client/backoff"
+	"github.com/google/trillian/merkle/hashers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MapClient represents a client for a given Trillian map instance.
+type MapClient struct {
+	MapID  int64
+	client trillian.TrillianMapClient
+	*mapVerifier
+	root trillian.SignedMapRoot
+}
+
+// NewMapClient returns a new MapClient.
+func NewMapClient(mapID int64, client trillian.TrillianMapClient, hasher hashers.MapHasher, pubKey crypto.PublicKey) *MapClient {
+	return &MapClient{
+		MapID:  mapID,
+		client: client,
+		mapVerifier: &mapVerifier{
+			mapID:  mapID,
+			hasher: hasher,
+			pubKey: pubKey,
+		},
+	}
+}
+
+// Root returns the last valid root seen by the client.
+// Returns an empty SignedMapRoot if no root has been seen yet.
+func (c *MapClient) Root() trillian.SignedMapRoot {
+	return c.root
+}
+
+// UpdateRoot retrieves the current SignedMapRoot.
+// Verifies the signature, and that the root doesn't go back in revisions.
+func (c *MapClient) UpdateRoot(ctx context.Context) error {
+	resp, err := c.client.GetSignedMapRoot(ctx,
+		&trillian.GetSignedMapRootRequest{
+			MapId: c.MapID,
+		})
+	if err != nil {
+		return err
+	}
+	return c.updateTrustedRoot(resp.GetMapRoot())
+}
+
+// GetAndVerifyLeaves fetches the leaves at indexes from the latest map
+// revision. The map root and the inclusion proofs of all leaves are verified
+// before any leaves are returned. Leaves that aren't present in the map are
+// returned with empty values.
+func (c *MapClient) GetAndVerifyLeaves(ctx context.Context, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	resp, err := c.client.GetLeaves(ctx,
+		&trillian.GetMapLeavesRequest{
+			MapId:          c.MapID,
+			Index:          indexes,
+			Revision:       -1, // Latest revision.
+			CompressProofs: true,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	root := resp.GetMapRoot()
+	if err := c.checkRoot(root); err != nil {
+		return nil, err
+	}
+	if got, want := len(resp.MapLeafInclusion), len(indexes); got != want {
+		return nil, fmt.Errorf("len(MapLeafInclusion): %v, want %v", got, want)
+	}
+	leaves := make([]*trillian.MapLeaf, 0, len(indexes))
+	for i, inclusion := range resp.MapLeafInclusion {
+		if got, want := inclusion.GetLeaf().GetIndex(), indexes[i]; !bytes.Equal(got, want) {
+			return nil, fmt.Errorf("MapLeafInclusion[%v].Leaf.Index: %x, want %x", i, got, want)
+		}
+		if err := c.mapVerifier.VerifyMapLeafInclusion(root, inclusion); err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, inclusion.Leaf)
+	}
+
+	c.root = *root
+	return leaves, nil
+}
+
+// SetLeaves writes leaves to the map, retrying with backoff while the map is
+// unavailable or until ctx is done. The new map root is verified and returned.
+func (c *MapClient) SetLeaves(ctx context.Context, leaves []*trillian.MapLeaf, metadata *trillian.MapperMetadata) (*trillian.SignedMapRoot, error) {
+	b := &backoff.Backoff{
+		Min:    100 * time.Millisecond,
+		Max:    10 * time.Second,
+		Factor: 2,
+		Jitter: true,
+	}
+	req := &trillian.SetMapLeavesRequest{
+		MapId:      c.MapID,
+		Leaves:     leaves,
+		MapperData: metadata,
+	}
+
+	var resp *trillian.SetMapLeavesResponse
+	var permErr error
+	if err := b.Retry(ctx, func() error {
+		var err error
+		resp, err = c.client.SetLeaves(ctx, req)
+		if isRetryable(err) {
+			return err
+		}
+		// Success or a permanent error, either way we're done.
+		permErr = err
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if permErr != nil {
+		return nil, permErr
+	}
+
+	if err := c.updateTrustedRoot(resp.GetMapRoot()); err != nil {
+		return nil, err
+	}
+	return resp.MapRoot, nil
+}
+
+// updateTrustedRoot verifies root and, if valid, makes it the trusted root.
+func (c *MapClient) updateTrustedRoot(root *trillian.SignedMapRoot) error {
+	if err := c.checkRoot(root); err != nil {
+		return err
+	}
+	c.root = *root
+	return nil
+}
+
+// checkRoot verifies root's signature and that it doesn't regress from the
+// trusted root.
+func (c *MapClient) checkRoot(root *trillian.SignedMapRoot) error {
+	if err := c.mapVerifier.VerifySignedMapRoot(root); err != nil {
+		return err
+	}
+	if got, want := root.MapId, c.MapID; got != want {
+		return fmt.Errorf("SignedMapRoot.MapId: %v, want %v", got, want)
+	}
+	switch trusted := c.root.MapRevision; {
+	case root.MapRevision < trusted:
+		return fmt.Errorf("SignedMapRoot.MapRevision: %v, want >= %v", root.MapRevision, trusted)
+	case root.MapRevision == trusted && c.root.RootHash != nil && !bytes.Equal(root.RootHash, c.root.RootHash):
+		return fmt.Errorf("SignedMapRoot.RootHash at revision %v: %x, want %x", trusted, root.RootHash, c.root.RootHash)
+	}
+	return nil
+}
+
+// isRetryable returns true if err is a transient error, worth retrying.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
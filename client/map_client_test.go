@@ -0,0 +1,243 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/maphasher"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeMapClient is a TrillianMapClient that returns canned responses.
+type fakeMapClient struct {
+	trillian.TrillianMapClient
+
+	getLeavesResp *trillian.GetMapLeavesResponse
+	getRootResp   *trillian.GetSignedMapRootResponse
+
+	// setErrs are returned by consecutive SetLeaves calls, after which
+	// setResp is returned.
+	setErrs  []error
+	setResp  *trillian.SetMapLeavesResponse
+	setCalls int
+}
+
+func (f *fakeMapClient) GetLeaves(ctx context.Context, req *trillian.GetMapLeavesRequest, opts ...grpc.CallOption) (*trillian.GetMapLeavesResponse, error) {
+	return f.getLeavesResp, nil
+}
+
+func (f *fakeMapClient) GetSignedMapRoot(ctx context.Context, req *trillian.GetSignedMapRootRequest, opts ...grpc.CallOption) (*trillian.GetSignedMapRootResponse, error) {
+	return f.getRootResp, nil
+}
+
+func (f *fakeMapClient) SetLeaves(ctx context.Context, req *trillian.SetMapLeavesRequest, opts ...grpc.CallOption) (*trillian.SetMapLeavesResponse, error) {
+	f.setCalls++
+	if len(f.setErrs) > 0 {
+		err := f.setErrs[0]
+		f.setErrs = f.setErrs[1:]
+		return nil, err
+	}
+	return f.setResp, nil
+}
+
+func TestMapClient_GetAndVerifyLeaves(t *testing.T) {
+	ctx := context.Background()
+	signer, pk := newTestMapSigner(t)
+	inclusion, rootHash := singleLeafMap([]byte("value"))
+	root := signMapRoot(t, signer, 2, rootHash)
+	index := inclusion.Leaf.Index
+
+	otherIndex := make([]byte, len(index))
+	otherIndex[0] = 1
+
+	tests := []struct {
+		desc    string
+		trusted *trillian.SignedMapRoot
+		indexes [][]byte
+		resp    *trillian.GetMapLeavesResponse
+		wantErr bool
+	}{
+		{
+			desc:    "valid",
+			indexes: [][]byte{index},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+		},
+		{
+			desc:    "sameRevision",
+			trusted: root,
+			indexes: [][]byte{index},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+		},
+		{
+			desc:    "badSignature",
+			indexes: [][]byte{index},
+			resp: &trillian.GetMapLeavesResponse{
+				MapRoot:          &trillian.SignedMapRoot{MapId: testMapID, RootHash: rootHash, Signature: root.Signature},
+				MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion},
+			},
+			wantErr: true,
+		},
+		{
+			desc:    "revisionRegression",
+			trusted: signMapRoot(t, signer, 3, []byte("newer root")),
+			indexes: [][]byte{index},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+			wantErr: true,
+		},
+		{
+			desc:    "forkedRevision",
+			trusted: signMapRoot(t, signer, 2, []byte("forked root")),
+			indexes: [][]byte{index},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+			wantErr: true,
+		},
+		{
+			desc:    "missingLeaves",
+			indexes: [][]byte{index, otherIndex},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+			wantErr: true,
+		},
+		{
+			desc:    "wrongIndex",
+			indexes: [][]byte{otherIndex},
+			resp:    &trillian.GetMapLeavesResponse{MapRoot: root, MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion}},
+			wantErr: true,
+		},
+		{
+			desc:    "badProof",
+			indexes: [][]byte{index},
+			resp: &trillian.GetMapLeavesResponse{
+				MapRoot:          signMapRoot(t, signer, 2, []byte("other root")),
+				MapLeafInclusion: []*trillian.MapLeafInclusion{inclusion},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		client := NewMapClient(testMapID, &fakeMapClient{getLeavesResp: test.resp}, maphasher.Default, pk)
+		if test.trusted != nil {
+			client.root = *test.trusted
+		}
+		wantRoot := client.Root()
+
+		leaves, err := client.GetAndVerifyLeaves(ctx, test.indexes)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: GetAndVerifyLeaves() = (_, %v), wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			// The trusted root must not change on errors.
+			if got := client.Root(); got.MapRevision != wantRoot.MapRevision || !bytes.Equal(got.RootHash, wantRoot.RootHash) {
+				t.Errorf("%v: Root() = %v, want = %v", test.desc, got, wantRoot)
+			}
+			continue
+		}
+
+		if got, want := len(leaves), 1; got != want {
+			t.Fatalf("%v: len(leaves) = %v, want = %v", test.desc, got, want)
+		}
+		if got, want := leaves[0].LeafValue, inclusion.Leaf.LeafValue; !bytes.Equal(got, want) {
+			t.Errorf("%v: LeafValue = %s, want = %s", test.desc, got, want)
+		}
+		if got, want := client.Root().MapRevision, root.MapRevision; got != want {
+			t.Errorf("%v: Root().MapRevision = %v, want = %v", test.desc, got, want)
+		}
+	}
+}
+
+func TestMapClient_UpdateRoot(t *testing.T) {
+	ctx := context.Background()
+	signer, pk := newTestMapSigner(t)
+	root1 := signMapRoot(t, signer, 1, []byte("root 1"))
+	root2 := signMapRoot(t, signer, 2, []byte("root 2"))
+
+	fake := &fakeMapClient{getRootResp: &trillian.GetSignedMapRootResponse{MapRoot: root2}}
+	client := NewMapClient(testMapID, fake, maphasher.Default, pk)
+	if err := client.UpdateRoot(ctx); err != nil {
+		t.Fatalf("UpdateRoot() = %v, want = nil", err)
+	}
+	if got, want := client.Root().MapRevision, root2.MapRevision; got != want {
+		t.Errorf("Root().MapRevision = %v, want = %v", got, want)
+	}
+
+	// Going back in revisions isn't allowed.
+	fake.getRootResp = &trillian.GetSignedMapRootResponse{MapRoot: root1}
+	if err := client.UpdateRoot(ctx); err == nil {
+		t.Error("UpdateRoot() to an older revision = nil, want err")
+	}
+	if got, want := client.Root().MapRevision, root2.MapRevision; got != want {
+		t.Errorf("Root().MapRevision = %v, want = %v", got, want)
+	}
+}
+
+func TestMapClient_SetLeaves(t *testing.T) {
+	signer, pk := newTestMapSigner(t)
+	root := signMapRoot(t, signer, 1, []byte("root"))
+	leaves := []*trillian.MapLeaf{{Index: []byte("index"), LeafValue: []byte("value")}}
+
+	tests := []struct {
+		desc      string
+		setErrs   []error
+		resp      *trillian.SetMapLeavesResponse
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			desc:      "success",
+			resp:      &trillian.SetMapLeavesResponse{MapRoot: root},
+			wantCalls: 1,
+		},
+		{
+			desc:      "retried",
+			setErrs:   []error{status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Aborted, "aborted")},
+			resp:      &trillian.SetMapLeavesResponse{MapRoot: root},
+			wantCalls: 3,
+		},
+		{
+			desc:      "permanentError",
+			setErrs:   []error{status.Error(codes.InvalidArgument, "bad request")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			desc:      "badSignature",
+			resp:      &trillian.SetMapLeavesResponse{MapRoot: &trillian.SignedMapRoot{MapId: testMapID, MapRevision: 1, Signature: root.Signature}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		fake := &fakeMapClient{setErrs: test.setErrs, setResp: test.resp}
+		client := NewMapClient(testMapID, fake, maphasher.Default, pk)
+
+		got, err := client.SetLeaves(ctx, leaves, nil /* metadata */)
+		cancel()
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: SetLeaves() = (_, %v), wantErr = %v", test.desc, err, test.wantErr)
+		} else if !hasErr && got.MapRevision != root.MapRevision {
+			t.Errorf("%v: SetLeaves() returned revision %v, want = %v", test.desc, got.MapRevision, root.MapRevision)
+		}
+		if fake.setCalls != test.wantCalls {
+			t.Errorf("%v: SetLeaves() made %v calls, want = %v", test.desc, fake.setCalls, test.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+
+	"github.com/google/trillian"
+	tcrypto "github.com/google/trillian/crypto"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+)
+
+// mapVerifier contains state needed to verify output from Trillian Maps.
+type mapVerifier struct {
+	mapID  int64
+	hasher hashers.MapHasher
+	pubKey crypto.PublicKey
+}
+
+// NewMapVerifier returns an object that can verify output from Trillian Maps.
+func NewMapVerifier(mapID int64, hasher hashers.MapHasher, pubKey crypto.PublicKey) MapVerifier {
+	return &mapVerifier{
+		mapID:  mapID,
+		hasher: hasher,
+		pubKey: pubKey,
+	}
+}
+
+// VerifySignedMapRoot verifies the signature on smr.
+func (m *mapVerifier) VerifySignedMapRoot(smr *trillian.SignedMapRoot) error {
+	if smr == nil {
+		return fmt.Errorf("VerifySignedMapRoot() error: smr == nil")
+	}
+	// SignedMapRoot contains its own signature, so it has to be removed from a
+	// copy of the root to get the object that was actually signed.
+	root := *smr
+	root.Signature = nil
+	if err := tcrypto.VerifyObject(m.pubKey, root, smr.Signature); err != nil {
+		return fmt.Errorf("VerifyObject(SignedMapRoot): %v", err)
+	}
+	return nil
+}
+
+// VerifyMapLeafInclusion verifies that inclusion's leaf is committed to by
+// smr's root hash. Both plain and compressed proofs are accepted. If the
+// inclusion is marked absent, the proof must show that the leaf's index is
+// empty instead.
+func (m *mapVerifier) VerifyMapLeafInclusion(smr *trillian.SignedMapRoot, inclusion *trillian.MapLeafInclusion) error {
+	if smr == nil {
+		return fmt.Errorf("VerifyMapLeafInclusion() error: smr == nil")
+	}
+	if inclusion.GetLeaf() == nil {
+		return fmt.Errorf("VerifyMapLeafInclusion() error: leaf == nil")
+	}
+
+	leaf := inclusion.Leaf
+	if got, want := leaf.LeafHash, m.hasher.HashLeaf(m.mapID, leaf.Index, leaf.LeafValue); !bytes.Equal(got, want) {
+		return fmt.Errorf("HashLeaf(%x): %x, want %x", leaf.Index, got, want)
+	}
+	proof := inclusion.Inclusion
+	if len(inclusion.InclusionBitmap) != 0 {
+		var err error
+		if proof, err = merkle.DecompressMapInclusionProof(inclusion.InclusionBitmap, proof, m.hasher); err != nil {
+			return fmt.Errorf("DecompressMapInclusionProof(%x): %v", leaf.Index, err)
+		}
+	}
+	if inclusion.Absent {
+		if len(leaf.LeafValue) != 0 {
+			return fmt.Errorf("absent leaf %x has value %x", leaf.Index, leaf.LeafValue)
+		}
+		if err := merkle.VerifyMapNonInclusionProof(m.mapID, leaf.Index, smr.RootHash, proof, m.hasher); err != nil {
+			return fmt.Errorf("VerifyMapNonInclusionProof(%x): %v", leaf.Index, err)
+		}
+		return nil
+	}
+	if err := merkle.VerifyMapInclusionProof(m.mapID, leaf.Index, leaf.LeafValue, smr.RootHash, proof, m.hasher); err != nil {
+		return fmt.Errorf("VerifyMapInclusionProof(%x): %v", leaf.Index, err)
+	}
+	return nil
+}
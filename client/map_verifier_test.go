@@ -0,0 +1,155 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/google/trillian"
+	tcrypto "github.com/google/trillian/crypto"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/maphasher"
+	"github.com/google/trillian/testonly"
+)
+
+const testMapID = 12345
+
+// newTestMapSigner returns a signer and the matching public key.
+func newTestMapSigner(t *testing.T) (*tcrypto.Signer, crypto.PublicKey) {
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	pk, err := pem.UnmarshalPublicKey(testonly.DemoPublicKey)
+	if err != nil {
+		t.Fatalf("Failed to load public key, err=%v", err)
+	}
+	return tcrypto.NewSHA256Signer(key), pk
+}
+
+// signMapRoot returns a SignedMapRoot for rootHash at revision, signed the
+// same way as by the map server.
+func signMapRoot(t *testing.T, signer *tcrypto.Signer, revision int64, rootHash []byte) *trillian.SignedMapRoot {
+	root := trillian.SignedMapRoot{
+		MapId:       testMapID,
+		MapRevision: revision,
+		RootHash:    rootHash,
+	}
+	sig, err := signer.SignObject(root)
+	if err != nil {
+		t.Fatalf("SignObject(): %v", err)
+	}
+	root.Signature = sig
+	return &root
+}
+
+// singleLeafMap returns the inclusion of a leaf at the all-zeros index, along
+// with the root hash of a map where that's the only leaf.
+func singleLeafMap(value []byte) (*trillian.MapLeafInclusion, []byte) {
+	h := maphasher.Default
+	index := make([]byte, h.Size())
+	leafHash := h.HashLeaf(testMapID, index, value)
+
+	// All siblings are empty, and the leaf is always on the left.
+	rootHash := leafHash
+	for height := 0; height < h.BitLen(); height++ {
+		rootHash = h.HashChildren(rootHash, h.HashEmpty(testMapID, index, height))
+	}
+	return &trillian.MapLeafInclusion{
+		Leaf: &trillian.MapLeaf{
+			Index:     index,
+			LeafHash:  leafHash,
+			LeafValue: value,
+		},
+		Inclusion: make([][]byte, h.BitLen()),
+	}, rootHash
+}
+
+func TestVerifySignedMapRoot(t *testing.T) {
+	signer, pk := newTestMapSigner(t)
+	root := signMapRoot(t, signer, 1, []byte("root hash"))
+
+	tampered := *root
+	tampered.MapRevision++
+
+	tests := []struct {
+		desc    string
+		root    *trillian.SignedMapRoot
+		wantErr bool
+	}{
+		{desc: "valid", root: root},
+		{desc: "nil", root: nil, wantErr: true},
+		{desc: "tampered", root: &tampered, wantErr: true},
+	}
+	for _, test := range tests {
+		v := NewMapVerifier(testMapID, maphasher.Default, pk)
+		if err := v.VerifySignedMapRoot(test.root); (err != nil) != test.wantErr {
+			t.Errorf("%v: VerifySignedMapRoot() = %v, wantErr = %v", test.desc, err, test.wantErr)
+		}
+	}
+}
+
+func TestVerifyMapLeafInclusion(t *testing.T) {
+	signer, pk := newTestMapSigner(t)
+	inclusion, rootHash := singleLeafMap([]byte("value"))
+	root := signMapRoot(t, signer, 1, rootHash)
+
+	badValue := *inclusion.Leaf
+	badValue.LeafValue = []byte("other value")
+	badHash := *inclusion.Leaf
+	badHash.LeafHash = maphasher.Default.HashLeaf(testMapID, badHash.Index, []byte("other value"))
+	badHash.LeafValue = []byte("other value")
+	otherRoot := signMapRoot(t, signer, 1, []byte("other root"))
+	bitmap, hashes := merkle.CompressMapInclusionProof(inclusion.Inclusion)
+	badBitmap := make([]byte, len(bitmap))
+	badBitmap[0] = 0x01
+	h := maphasher.Default
+	emptyRoot := signMapRoot(t, signer, 1, h.HashEmpty(testMapID, make([]byte, h.Size()), h.BitLen()))
+	absent := &trillian.MapLeafInclusion{
+		Leaf:      &trillian.MapLeaf{Index: inclusion.Leaf.Index, LeafHash: h.HashLeaf(testMapID, inclusion.Leaf.Index, nil)},
+		Inclusion: inclusion.Inclusion,
+		Absent:    true,
+	}
+	absentWithValue := *absent
+	absentWithValue.Leaf = inclusion.Leaf
+
+	tests := []struct {
+		desc      string
+		root      *trillian.SignedMapRoot
+		inclusion *trillian.MapLeafInclusion
+		wantErr   bool
+	}{
+		{desc: "valid", root: root, inclusion: inclusion},
+		{desc: "nilRoot", inclusion: inclusion, wantErr: true},
+		{desc: "nilLeaf", root: root, inclusion: &trillian.MapLeafInclusion{}, wantErr: true},
+		{desc: "leafHashMismatch", root: root, inclusion: &trillian.MapLeafInclusion{Leaf: &badValue, Inclusion: inclusion.Inclusion}, wantErr: true},
+		{desc: "wrongLeaf", root: root, inclusion: &trillian.MapLeafInclusion{Leaf: &badHash, Inclusion: inclusion.Inclusion}, wantErr: true},
+		{desc: "wrongRoot", root: otherRoot, inclusion: inclusion, wantErr: true},
+		{desc: "shortProof", root: root, inclusion: &trillian.MapLeafInclusion{Leaf: inclusion.Leaf}, wantErr: true},
+		{desc: "compressed", root: root, inclusion: &trillian.MapLeafInclusion{Leaf: inclusion.Leaf, Inclusion: hashes, InclusionBitmap: bitmap}},
+		{desc: "badBitmap", root: root, inclusion: &trillian.MapLeafInclusion{Leaf: inclusion.Leaf, Inclusion: hashes, InclusionBitmap: badBitmap}, wantErr: true},
+		{desc: "absent", root: emptyRoot, inclusion: absent},
+		{desc: "absentButPresent", root: root, inclusion: absent, wantErr: true},
+		{desc: "absentWithValue", root: emptyRoot, inclusion: &absentWithValue, wantErr: true},
+	}
+	for _, test := range tests {
+		v := NewMapVerifier(testMapID, maphasher.Default, pk)
+		if err := v.VerifyMapLeafInclusion(test.root, test.inclusion); (err != nil) != test.wantErr {
+			t.Errorf("%v: VerifyMapLeafInclusion() = %v, wantErr = %v", test.desc, err, test.wantErr)
+		}
+	}
+}
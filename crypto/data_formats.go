@@ -31,6 +31,8 @@ const (
 	mapKeyRootHash       string = "RootHash"
 	mapKeyTimestampNanos string = "TimestampNanos"
 	mapKeyTreeSize       string = "TreeSize"
+	mapKeyLogID          string = "LogID"
+	mapKeyMerkleLeafHash string = "MerkleLeafHash"
 )
 
 // HashLogRoot hashes SignedLogRoot objects using ObjectHash with
@@ -49,3 +51,16 @@ func HashLogRoot(root trillian.SignedLogRoot) []byte {
 	hash := objecthash.ObjectHash(rootMap)
 	return hash[:]
 }
+
+// HashSignedEntryTimestamp hashes SignedEntryTimestamp objects using ObjectHash
+// with "LogID", "MerkleLeafHash", and "TimestampNanos", used as keys in a map.
+func HashSignedEntryTimestamp(set trillian.SignedEntryTimestamp) []byte {
+	// As in HashLogRoot, int64 values are formatted as strings.
+	setMap := map[string]string{
+		mapKeyLogID:          strconv.FormatInt(set.LogId, 10),
+		mapKeyMerkleLeafHash: base64.StdEncoding.EncodeToString(set.MerkleLeafHash),
+		mapKeyTimestampNanos: strconv.FormatInt(set.TimestampNanos, 10)}
+
+	hash := objecthash.ObjectHash(setMap)
+	return hash[:]
+}
@@ -64,3 +64,21 @@ func TestHashLogRoot(t *testing.T) {
 	}
 
 }
+
+func TestHashSignedEntryTimestamp(t *testing.T) {
+	unique := make(map[[20]byte]bool)
+	for _, set := range []trillian.SignedEntryTimestamp{
+		{TimestampNanos: 2267709, LogId: 1, MerkleLeafHash: []byte("Islington")},
+		{TimestampNanos: 2267708, LogId: 1, MerkleLeafHash: []byte("Islington")},
+		{TimestampNanos: 2267709, LogId: 2, MerkleLeafHash: []byte("Islington")},
+		{TimestampNanos: 2267709, LogId: 1, MerkleLeafHash: []byte("Oslington")},
+	} {
+		hash := HashSignedEntryTimestamp(set)
+		var h [20]byte
+		copy(h[:], hash)
+		if _, ok := unique[h]; ok {
+			t.Errorf("Found duplicate hash from input %v", set)
+		}
+		unique[h] = true
+	}
+}
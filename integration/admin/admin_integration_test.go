@@ -34,39 +34,6 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func TestAdminServer_Unimplemented(t *testing.T) {
-	client, closeFn, err := setupAdminServer()
-	if err != nil {
-		t.Fatalf("setupAdminServer() failed: %v", err)
-	}
-	defer closeFn()
-
-	ctx := context.Background()
-	tree, err := client.CreateTree(ctx, &trillian.CreateTreeRequest{Tree: testonly.LogTree})
-	if err != nil {
-		t.Fatalf("CreateTree returned err = %v", err)
-	}
-
-	tests := []struct {
-		desc string
-		fn   func(context.Context, trillian.TrillianAdminClient) error
-	}{
-		{
-			desc: "DeleteTree",
-			fn: func(ctx context.Context, c trillian.TrillianAdminClient) error {
-				_, err := c.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: tree.TreeId})
-				return err
-			},
-		},
-	}
-	for _, test := range tests {
-		err := test.fn(ctx, client)
-		if s, ok := status.FromError(err); !ok || s.Code() != codes.Unimplemented {
-			t.Errorf("%v: got = %v, want = %s", test.desc, err, codes.Unimplemented)
-		}
-	}
-}
-
 func TestAdminServer_CreateTree(t *testing.T) {
 	client, closeFn, err := setupAdminServer()
 	if err != nil {
@@ -263,6 +230,47 @@ func TestAdminServer_GetTree(t *testing.T) {
 	}
 }
 
+func TestAdminServer_DeleteTree(t *testing.T) {
+	client, closeFn, err := setupAdminServer()
+	if err != nil {
+		t.Fatalf("setupAdminServer() failed: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	tree, err := client.CreateTree(ctx, &trillian.CreateTreeRequest{Tree: testonly.LogTree})
+	if err != nil {
+		t.Fatalf("CreateTree() returned err = %v", err)
+	}
+
+	if _, err := client.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: tree.TreeId}); err != nil {
+		t.Fatalf("DeleteTree() returned err = %v", err)
+	}
+	_, err = client.GetTree(ctx, &trillian.GetTreeRequest{TreeId: tree.TreeId})
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.NotFound {
+		t.Errorf("GetTree() of deleted tree = (_, %v), wantCode = %v", err, codes.NotFound)
+	}
+	_, err = client.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: tree.TreeId})
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.NotFound {
+		t.Errorf("DeleteTree() of deleted tree = (_, %v), wantCode = %v", err, codes.NotFound)
+	}
+
+	undeleted, err := client.UndeleteTree(ctx, &trillian.UndeleteTreeRequest{TreeId: tree.TreeId})
+	if err != nil {
+		t.Fatalf("UndeleteTree() returned err = %v", err)
+	}
+	if got, want := undeleted.TreeState, trillian.TreeState_ACTIVE; got != want {
+		t.Errorf("UndeleteTree() returned TreeState = %s, want = %s", got, want)
+	}
+	if _, err := client.GetTree(ctx, &trillian.GetTreeRequest{TreeId: tree.TreeId}); err != nil {
+		t.Errorf("GetTree() of undeleted tree returned err = %v", err)
+	}
+	_, err = client.UndeleteTree(ctx, &trillian.UndeleteTreeRequest{TreeId: tree.TreeId})
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.FailedPrecondition {
+		t.Errorf("UndeleteTree() of active tree = (_, %v), wantCode = %v", err, codes.FailedPrecondition)
+	}
+}
+
 func TestAdminServer_ListTrees(t *testing.T) {
 	client, closeFn, err := setupAdminServer()
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"strings"
 	"time"
@@ -130,6 +131,12 @@ func RunLogIntegration(client trillian.TrillianLogClient, params TestParameters)
 		return fmt.Errorf("could not read back log entries: %v", err)
 	}
 
+	// Step 3a - Read the leaves again by range, unary and streamed, and check they match
+	glog.Infof("Reading back leaves by range ...")
+	if err := checkLeavesByRange(params.treeID, client, params, leafMap); err != nil {
+		return fmt.Errorf("could not read back log entries by range: %v", err)
+	}
+
 	// Step 4 - Cross validation between log and memory tree root hashes
 	glog.Infof("Checking log STH with our constructed in-memory tree ...")
 	tree, err := buildMemoryMerkleTree(leafMap, params)
@@ -162,6 +169,13 @@ func RunLogIntegration(client trillian.TrillianLogClient, params TestParameters)
 		}
 	}
 
+	// Probe the same leaves again by identity hash, with inclusion proofs
+	for _, testIndex := range inclusionProofTestIndices {
+		if err := checkLeafByIdentityHash(testIndex, params.treeID, tree, leafMap, client, params); err != nil {
+			return fmt.Errorf("log leaf index: %d lookup by identity hash failed: %v", testIndex, err)
+		}
+	}
+
 	// TODO(al): test some inclusion proofs by Merkle hash too.
 
 	// Step 6 - Test some consistency proofs
@@ -362,6 +376,53 @@ func readbackLogEntries(logID int64, client trillian.TrillianLogClient, params T
 	return leafMap, nil
 }
 
+func checkLeavesByRange(logID int64, client trillian.TrillianLogClient, params TestParameters, leafMap map[int64]*trillian.LogLeaf) error {
+	ctx, cancel := getRPCDeadlineContext(params)
+	defer cancel()
+
+	req := &trillian.GetLeavesByRangeRequest{LogId: logID, StartIndex: params.startLeaf, Count: params.leafCount}
+	resp, err := client.GetLeavesByRange(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Leaves) == 0 {
+		return fmt.Errorf("expected leaves from %d, log returned none", params.startLeaf)
+	}
+	leaves := resp.Leaves
+
+	stream, err := client.StreamLeavesByRange(ctx, req)
+	if err != nil {
+		return err
+	}
+	streamed := make([]*trillian.LogLeaf, 0, params.leafCount)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		streamed = append(streamed, resp.Leaves...)
+	}
+	if got, want := int64(len(streamed)), params.leafCount; got != want {
+		return fmt.Errorf("streamed %d leaves, want %d", got, want)
+	}
+
+	for _, ll := range [][]*trillian.LogLeaf{leaves, streamed} {
+		for i, leaf := range ll {
+			index := params.startLeaf + int64(i)
+			if got, want := leaf.LeafIndex, index; got != want {
+				return fmt.Errorf("leaf %d has index %d", want, got)
+			}
+			if want := leafMap[index]; !bytes.Equal(leaf.LeafValue, want.LeafValue) || !bytes.Equal(leaf.MerkleLeafHash, want.MerkleLeafHash) {
+				return fmt.Errorf("leaf %d by range: %v, want %v", index, leaf, want)
+			}
+		}
+	}
+	return nil
+}
+
 func checkLogRootHashMatches(tree *merkle.InMemoryMerkleTree, client trillian.TrillianLogClient, params TestParameters) error {
 	// Check the STH against the hash we got from our tree
 	resp, err := getLatestSignedLogRoot(client, params)
@@ -452,6 +513,47 @@ func checkInclusionProofsAtIndex(index int64, logID int64, tree *merkle.InMemory
 	return nil
 }
 
+// checkLeafByIdentityHash looks up the leaf at index by its identity hash and verifies the
+// inclusion proofs returned for it. A log accepting duplicates may return more than one leaf.
+func checkLeafByIdentityHash(index int64, logID int64, tree *merkle.InMemoryMerkleTree, leafMap map[int64]*trillian.LogLeaf, client trillian.TrillianLogClient, params TestParameters) error {
+	leaf, ok := leafMap[index]
+	if !ok {
+		return fmt.Errorf("no leaf at index %d", index)
+	}
+	treeSize := params.leafCount
+	ctx, cancel := getRPCDeadlineContext(params)
+	resp, err := client.GetLeavesByIdentityHash(ctx, &trillian.GetLeavesByIdentityHashRequest{
+		LogId:            logID,
+		LeafIdentityHash: [][]byte{leaf.LeafIdentityHash},
+		IncludeProof:     true,
+		TreeSize:         treeSize,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+	if got, want := len(resp.Proof), len(resp.Leaves); got != want {
+		return fmt.Errorf("got %d proofs for %d leaves", got, want)
+	}
+
+	root := tree.RootAtSnapshot(treeSize).Hash()
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	found := false
+	for i, l := range resp.Leaves {
+		if !bytes.Equal(l.LeafIdentityHash, leaf.LeafIdentityHash) {
+			return fmt.Errorf("leaf %d has identity hash %x, want %x", l.LeafIndex, l.LeafIdentityHash, leaf.LeafIdentityHash)
+		}
+		if err := verifier.VerifyInclusionProof(l.LeafIndex, treeSize, resp.Proof[i].Hashes, root, l.MerkleLeafHash); err != nil {
+			return err
+		}
+		found = found || l.LeafIndex == index
+	}
+	if !found {
+		return fmt.Errorf("leaf %d not returned", index)
+	}
+	return nil
+}
+
 func checkConsistencyProof(consistParams consistencyProofParams, treeID int64, tree *merkle.InMemoryMerkleTree, client trillian.TrillianLogClient, params TestParameters, batchSize int64) error {
 	// We expect the proof request to succeed
 	ctx, cancel := getRPCDeadlineContext(params)
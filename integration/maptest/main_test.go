@@ -34,9 +34,9 @@ func TestMain(m *testing.M) {
 
 	if *server == "" {
 		ctx := context.Background()
-		mapEnv, err := integration.NewMapEnv(ctx, "MapIntegrationTestMain")
+		mapEnv, err := integration.NewInMemoryMapEnv(ctx, "MapIntegrationTestMain")
 		if err != nil {
-			log.Fatalf("NewMapEnv(): %v", err)
+			log.Fatalf("NewInMemoryMapEnv(): %v", err)
 		}
 		env = mapEnv
 		defer env.Close()
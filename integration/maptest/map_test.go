@@ -28,6 +28,8 @@ import (
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/testonly/integration"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	tcrypto "github.com/google/trillian/crypto"
 	stestonly "github.com/google/trillian/storage/testonly"
@@ -87,10 +89,26 @@ func verifyGetMapLeavesResponse(getResp *trillian.GetMapLeavesResponse, indexes
 		index := incl.GetLeaf().GetIndex()
 		leafHash := incl.GetLeaf().GetLeafHash()
 		proof := incl.GetInclusion()
+		if bitmap := incl.GetInclusionBitmap(); len(bitmap) != 0 {
+			var err error
+			if proof, err = merkle.DecompressMapInclusionProof(bitmap, proof, hasher); err != nil {
+				return fmt.Errorf("DecompressMapInclusionProof(%x): %v", index, err)
+			}
+		}
 
 		if got, want := leafHash, hasher.HashLeaf(treeID, index, leaf); !bytes.Equal(got, want) {
 			return fmt.Errorf("HashLeaf(%s): %x, want %x", leaf, got, want)
 		}
+		if incl.GetAbsent() {
+			if len(leaf) != 0 {
+				return fmt.Errorf("absent leaf %x has value %s", index, leaf)
+			}
+			if err := merkle.VerifyMapNonInclusionProof(treeID, index,
+				rootHash, proof, hasher); err != nil {
+				return fmt.Errorf("VerifyMapNonInclusionProof(%x): %v", index, err)
+			}
+			continue
+		}
 		if err := merkle.VerifyMapInclusionProof(treeID, index,
 			leaf, rootHash, proof, hasher); err != nil {
 			return fmt.Errorf("VerifyMapInclusionProof(%x): %v", index, err)
@@ -122,7 +140,7 @@ func TestLeafHistory(t *testing.T) {
 	for _, tc := range []struct {
 		desc         string
 		HashStrategy []trillian.HashStrategy
-		set          [][]*trillian.MapLeaf
+		set          []*trillian.SetMapLeavesRequest
 		get          []struct {
 			revision  int64
 			Index     []byte
@@ -132,18 +150,18 @@ func TestLeafHistory(t *testing.T) {
 		{
 			desc:         "single leaf update",
 			HashStrategy: []trillian.HashStrategy{trillian.HashStrategy_TEST_MAP_HASHER, trillian.HashStrategy_CONIKS_SHA512_256},
-			set: [][]*trillian.MapLeaf{
+			set: []*trillian.SetMapLeavesRequest{
 				{}, // Advance revision without changing anything.
-				{
+				{Leaves: []*trillian.MapLeaf{
 					{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("A")},
-				},
+				}},
 				{}, // Advance revision without changing anything.
-				{
+				{Leaves: []*trillian.MapLeaf{
 					{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("B")},
-				},
-				{
+				}},
+				{Leaves: []*trillian.MapLeaf{
 					{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("C")},
-				},
+				}},
 			},
 			get: []struct {
 				revision  int64
@@ -158,6 +176,33 @@ func TestLeafHistory(t *testing.T) {
 				{revision: 5, Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("C")},
 			},
 		},
+		{
+			desc:         "leaf deletion",
+			HashStrategy: []trillian.HashStrategy{trillian.HashStrategy_TEST_MAP_HASHER, trillian.HashStrategy_CONIKS_SHA512_256},
+			set: []*trillian.SetMapLeavesRequest{
+				{Leaves: []*trillian.MapLeaf{
+					{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("A")},
+					{Index: h2b("0000000000000180000000000000000000000000000000000000000000000000"), LeafValue: []byte("B")},
+				}},
+				{DeleteIndex: [][]byte{h2b("0000000000000000000000000000000000000000000000000000000000000000")}},
+				{DeleteIndex: [][]byte{h2b("0000000000000180000000000000000000000000000000000000000000000000")}},
+				{Leaves: []*trillian.MapLeaf{
+					{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("C")},
+				}},
+			},
+			get: []struct {
+				revision  int64
+				Index     []byte
+				LeafValue []byte
+			}{
+				{revision: 1, Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("A")},
+				{revision: 2, Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: nil}, // Deleted.
+				{revision: 2, Index: h2b("0000000000000180000000000000000000000000000000000000000000000000"), LeafValue: []byte("B")},
+				{revision: 3, Index: h2b("0000000000000180000000000000000000000000000000000000000000000000"), LeafValue: nil}, // Empty map.
+				{revision: 4, Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("C")},
+				{revision: 4, Index: h2b("0000000000000180000000000000000000000000000000000000000000000000"), LeafValue: nil},
+			},
+		},
 	} {
 		for _, hashStrategy := range tc.HashStrategy {
 			tree, hasher, err := newTreeWithHasher(ctx, env, hashStrategy)
@@ -173,8 +218,9 @@ func TestLeafHistory(t *testing.T) {
 
 			for _, batch := range tc.set {
 				setResp, err := env.MapClient.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
-					MapId:  tree.TreeId,
-					Leaves: batch,
+					MapId:       tree.TreeId,
+					Leaves:      batch.Leaves,
+					DeleteIndex: batch.DeleteIndex,
 				})
 				if err != nil {
 					t.Errorf("%v: SetLeaves(): %v", tc.desc, err)
@@ -200,6 +246,10 @@ func TestLeafHistory(t *testing.T) {
 					t.Errorf("GetLeaves(rev: %v).LeafValue: %s, want %s", batch.revision, got, want)
 					continue
 				}
+				if got, want := getResp.MapLeafInclusion[0].GetAbsent(), batch.LeafValue == nil; got != want {
+					t.Errorf("GetLeaves(rev: %v).Absent: %v, want %v", batch.revision, got, want)
+					continue
+				}
 
 				if err := verifyGetMapLeavesResponse(getResp, indexes, batch.revision,
 					pubKey, hasher, tree.TreeId); err != nil {
@@ -211,6 +261,110 @@ func TestLeafHistory(t *testing.T) {
 	}
 }
 
+func TestGetLeafHistory(t *testing.T) {
+	ctx := context.Background()
+	index := h2b("0000000000000000000000000000000000000000000000000000000000000000")
+	other := h2b("0000000000000180000000000000000000000000000000000000000000000000")
+	set := []*trillian.SetMapLeavesRequest{
+		{Leaves: []*trillian.MapLeaf{{Index: index, LeafValue: []byte("A")}}}, // Revision 1.
+		{Leaves: []*trillian.MapLeaf{{Index: other, LeafValue: []byte("X")}}}, // Revision 2 doesn't change the leaf.
+		{Leaves: []*trillian.MapLeaf{{Index: index, LeafValue: []byte("B")}}}, // Revision 3.
+		{Leaves: []*trillian.MapLeaf{{Index: index, LeafValue: []byte("B")}}}, // Revision 4 rewrites the same value.
+		{DeleteIndex: [][]byte{index}},                                        // Revision 5 deletes the leaf.
+	}
+	want := []struct {
+		revision  int64
+		LeafValue []byte
+	}{
+		{revision: 1, LeafValue: []byte("A")},
+		{revision: 3, LeafValue: []byte("B")},
+		{revision: 5, LeafValue: nil},
+	}
+
+	for _, hashStrategy := range []trillian.HashStrategy{trillian.HashStrategy_TEST_MAP_HASHER, trillian.HashStrategy_CONIKS_SHA512_256} {
+		tree, hasher, err := newTreeWithHasher(ctx, env, hashStrategy)
+		if err != nil {
+			t.Fatalf("newTreeWithHasher(%v): %v", hashStrategy, err)
+		}
+		pubKey, err := der.UnmarshalPublicKey(tree.GetPublicKey().GetDer())
+		if err != nil {
+			t.Fatalf("UnmarshalPublicKey(%v): %v", hashStrategy, err)
+		}
+		for _, batch := range set {
+			if _, err := env.MapClient.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
+				MapId:       tree.TreeId,
+				Leaves:      batch.Leaves,
+				DeleteIndex: batch.DeleteIndex,
+			}); err != nil {
+				t.Fatalf("%v: SetLeaves(): %v", hashStrategy, err)
+			}
+		}
+
+		// Fetch the whole history, two revisions at a time.
+		var revisions []*trillian.MapLeafRevision
+		req := &trillian.GetLeafHistoryRequest{
+			MapId:      tree.TreeId,
+			Index:      index,
+			ToRevision: -1,
+			PageSize:   2,
+		}
+		for {
+			resp, err := env.MapClient.GetLeafHistory(ctx, req)
+			if err != nil {
+				t.Fatalf("%v: GetLeafHistory(): %v", hashStrategy, err)
+			}
+			revisions = append(revisions, resp.Revisions...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+
+		if got, want := len(revisions), len(want); got != want {
+			t.Fatalf("%v: GetLeafHistory() returned %d revisions, want %d", hashStrategy, got, want)
+		}
+		for i, r := range revisions {
+			if got, want := r.Revision, want[i].revision; got != want {
+				t.Errorf("%v: revisions[%d].Revision: %v, want %v", hashStrategy, i, got, want)
+			}
+			if got, want := r.GetMapLeafInclusion().GetLeaf().GetLeafValue(), want[i].LeafValue; !bytes.Equal(got, want) {
+				t.Errorf("%v: revisions[%d].LeafValue: %s, want %s", hashStrategy, i, got, want)
+			}
+			// Each revision must verify like a GetLeaves response for it.
+			getResp := &trillian.GetMapLeavesResponse{
+				MapLeafInclusion: []*trillian.MapLeafInclusion{r.MapLeafInclusion},
+				MapRoot:          r.MapRoot,
+			}
+			if err := verifyGetMapLeavesResponse(getResp, [][]byte{index}, r.Revision,
+				pubKey, hasher, tree.TreeId); err != nil {
+				t.Errorf("%v: verifyGetMapLeavesResponse(rev %v): %v", hashStrategy, r.Revision, err)
+			}
+		}
+	}
+}
+
+func TestSetLeavesRejectsEmptyValue(t *testing.T) {
+	ctx := context.Background()
+	tree, _, err := newTreeWithHasher(ctx, env, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	_, err = env.MapClient.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
+		MapId: tree.TreeId,
+		Leaves: []*trillian.MapLeaf{
+			{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("A")},
+			{Index: h2b("0000000000000000000000000000000000000000000000000000000000000001"), LeafValue: nil},
+		},
+	})
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+		t.Errorf("SetLeaves(empty value): %v, want code %v", err, codes.InvalidArgument)
+	}
+	// Nothing was written.
+	if err := isEmptyMap(ctx, env, tree); err != nil {
+		t.Errorf("isEmptyMap(): %v", err)
+	}
+}
+
 func TestInclusion(t *testing.T) {
 	ctx := context.Background()
 	for _, tc := range []struct {
@@ -232,7 +386,6 @@ func TestInclusion(t *testing.T) {
 				{Index: h2b("0000000000000000000000000000000000000000000000000000000000000000"), LeafValue: []byte("A")},
 				{Index: h2b("0000000000000000000000000000000000000000000000000000000000000001"), LeafValue: []byte("B")},
 				{Index: h2b("0000000000000000000000000000000000000000000000000000000000000002"), LeafValue: []byte("C")},
-				{Index: h2b("0000000000000000000000000000000000000000000000000000000000000003"), LeafValue: nil},
 			},
 		},
 		{
@@ -270,20 +423,22 @@ func TestInclusion(t *testing.T) {
 			for _, l := range tc.leaves {
 				indexes = append(indexes, l.Index)
 			}
-			getResp, err := env.MapClient.GetLeaves(ctx, &trillian.GetMapLeavesRequest{
-				MapId:    tree.TreeId,
-				Index:    indexes,
-				Revision: -1,
-			})
-			if err != nil {
-				t.Errorf("%v: GetLeaves(): %v", tc.desc, err)
-				continue
-			}
+			for _, compress := range []bool{false, true} {
+				getResp, err := env.MapClient.GetLeaves(ctx, &trillian.GetMapLeavesRequest{
+					MapId:          tree.TreeId,
+					Index:          indexes,
+					Revision:       -1,
+					CompressProofs: compress,
+				})
+				if err != nil {
+					t.Errorf("%v: GetLeaves(compress: %v): %v", tc.desc, compress, err)
+					continue
+				}
 
-			if err := verifyGetMapLeavesResponse(getResp, indexes, 1,
-				pubKey, hasher, tree.TreeId); err != nil {
-				t.Errorf("%v: verifyGetMapLeavesResponse(): %v", tc.desc, err)
-				continue
+				if err := verifyGetMapLeavesResponse(getResp, indexes, 1,
+					pubKey, hasher, tree.TreeId); err != nil {
+					t.Errorf("%v: verifyGetMapLeavesResponse(compress: %v): %v", tc.desc, compress, err)
+				}
 			}
 		}
 	}
@@ -0,0 +1,57 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import "sync"
+
+// BatchSizer keeps the batch size of each log across sequencer passes. A batch that comes
+// back full shows that the log's Unsequenced backlog is at least as large as the batch, so the
+// next batch is twice as large. The batch size drops back to the base as soon as a batch isn't
+// full.
+type BatchSizer struct {
+	mu    sync.Mutex
+	sizes map[int64]int
+}
+
+// NewBatchSizer creates a BatchSizer with every log at its base batch size.
+func NewBatchSizer() *BatchSizer {
+	return &BatchSizer{sizes: make(map[int64]int)}
+}
+
+// Limit returns the number of leaves to dequeue from logID, which is at least base and at
+// most maxFactor times base.
+func (b *BatchSizer) Limit(logID int64, base, maxFactor int) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	size := b.sizes[logID]
+	if max := base * maxFactor; size > max {
+		size = max
+	}
+	if size < base {
+		size = base
+	}
+	return size
+}
+
+// Update records that dequeued leaves were returned for a batch of limit leaves of logID.
+func (b *BatchSizer) Update(logID int64, limit, dequeued int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if limit > 0 && dequeued >= limit {
+		b.sizes[logID] = limit * 2
+	} else {
+		delete(b.sizes, logID)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import "testing"
+
+func TestBatchSizer(t *testing.T) {
+	const logID = 12345
+	for _, test := range []struct {
+		desc      string
+		updates   [][2]int // limit, dequeued
+		base      int
+		maxFactor int
+		want      int
+	}{
+		{desc: "new", base: 10, maxFactor: 4, want: 10},
+		{desc: "full", updates: [][2]int{{10, 10}}, base: 10, maxFactor: 4, want: 20},
+		{desc: "capped", updates: [][2]int{{20, 20}, {40, 40}}, base: 10, maxFactor: 4, want: 40},
+		{desc: "notFull", updates: [][2]int{{10, 10}, {20, 19}}, base: 10, maxFactor: 4, want: 10},
+		{desc: "noFactor", updates: [][2]int{{10, 10}}, base: 10, maxFactor: 1, want: 10},
+		{desc: "largerBase", updates: [][2]int{{10, 10}}, base: 30, maxFactor: 4, want: 30},
+		{desc: "disabled", updates: [][2]int{{0, 0}}, base: 0, maxFactor: 4, want: 0},
+	} {
+		b := NewBatchSizer()
+		for _, u := range test.updates {
+			b.Update(logID, u[0], u[1])
+		}
+		if got := b.Limit(logID, test.base, test.maxFactor); got != test.want {
+			t.Errorf("%v: Limit() = %v, want %v", test.desc, got, test.want)
+		}
+		if got := b.Limit(logID+1, test.base, test.maxFactor); got != test.base {
+			t.Errorf("%v: Limit() of other log = %v, want %v", test.desc, got, test.base)
+		}
+	}
+}
@@ -51,6 +51,11 @@ var (
 	seqStoreRootLatency    monitoring.Histogram
 	seqCommitLatency       monitoring.Histogram
 	seqCounter             monitoring.Counter
+	seqTreeCacheHits       monitoring.Counter
+	seqMergeDelay          monitoring.Histogram
+	seqMMDViolations       monitoring.Counter
+	seqOldestLeafAge       monitoring.Gauge
+	seqMMDExceeded         monitoring.Gauge
 
 	// QuotaIncreaseFactor is the multiplier used for the number of tokens added back to
 	// sequencing-based quotas. The resulting PutTokens call is equivalent to
@@ -88,6 +93,11 @@ func createMetrics(mf monitoring.MetricFactory) {
 	seqStoreRootLatency = mf.NewHistogram("sequencer_latency_store_root", "Latency of store-root part of sequencer batch operation in seconds", logIDLabel)
 	seqCommitLatency = mf.NewHistogram("sequencer_latency_commit", "Latency of commit part of sequencer batch operation in seconds", logIDLabel)
 	seqCounter = mf.NewCounter("sequencer_sequenced", "Number of leaves sequenced", logIDLabel)
+	seqTreeCacheHits = mf.NewCounter("sequencer_tree_cache_hits", "Number of batches that reused the cached compact Merkle tree", logIDLabel)
+	seqMergeDelay = mf.NewHistogram("sequencer_merge_delay", "Delay between queueing and integrating leaves in seconds", logIDLabel)
+	seqMMDViolations = mf.NewCounter("sequencer_mmd_violations", "Number of leaves integrated later than the maximum merge delay", logIDLabel)
+	seqOldestLeafAge = mf.NewGauge("sequencer_oldest_unsequenced_age", "Age of the oldest unsequenced leaf in seconds", logIDLabel)
+	seqMMDExceeded = mf.NewGauge("sequencer_mmd_exceeded", "Set to 1 if the oldest unsequenced leaf is older than the maximum merge delay", logIDLabel)
 }
 
 // TODO(Martin2112): Add admin support for safely changing params like guard window during operation
@@ -103,6 +113,11 @@ type Sequencer struct {
 	logStorage storage.LogStorage
 	signer     *crypto.Signer
 	qm         quota.Manager
+	treeCache  *CompactTreeCache
+
+	pipelined      bool
+	batchSizer     *BatchSizer
+	maxBatchFactor int
 }
 
 // maxTreeDepth sets an upper limit on the size of Log trees.
@@ -111,14 +126,25 @@ type Sequencer struct {
 //           the subtrees.
 const maxTreeDepth = 64
 
+const (
+	// mmdUrgency is the fraction of the maximum merge delay after which the queue is
+	// considered urgent, and batches are enlarged to catch up with the backlog.
+	mmdUrgency = 0.5
+	// mmdUrgentBatchFactor is the multiplier applied to the batch size of urgent logs.
+	mmdUrgentBatchFactor = 4
+)
+
 // NewSequencer creates a new Sequencer instance for the specified inputs.
+// treeCache may be nil, in which case the compact Merkle tree is loaded from storage on
+// every batch.
 func NewSequencer(
 	hasher hashers.LogHasher,
 	timeSource util.TimeSource,
 	logStorage storage.LogStorage,
 	signer *crypto.Signer,
 	mf monitoring.MetricFactory,
-	qm quota.Manager) *Sequencer {
+	qm quota.Manager,
+	treeCache *CompactTreeCache) *Sequencer {
 	once.Do(func() {
 		createMetrics(mf)
 	})
@@ -128,31 +154,62 @@ func NewSequencer(
 		logStorage: logStorage,
 		signer:     signer,
 		qm:         qm,
+		treeCache:  treeCache,
 	}
 }
 
-// TODO: This currently doesn't use the batch api for fetching the required nodes. This
-// would be more efficient but requires refactoring.
+// SetPipelined controls whether batches of a LOG tree are pipelined. A pipelined pass
+// integrates the leaves dequeued by the previous pass while it dequeues the next batch and
+// assigns it the following indices, so the two batches share a transaction and the signing of
+// one root overlaps with the storage work for the next batch.
+// Each pass still commits a single root, so tree revisions stay strictly ordered.
+func (s *Sequencer) SetPipelined(pipelined bool) {
+	s.pipelined = pipelined
+}
+
+// SetBatchSizer makes the sequencer adapt the number of leaves it dequeues from a LOG tree to
+// the backlog of the log, using sizer to carry the state between passes. Batches are at most
+// maxFactor times the limit passed to SequenceBatch, including those enlarged because the
+// oldest leaf is close to the maximum merge delay.
+func (s *Sequencer) SetBatchSizer(sizer *BatchSizer, maxFactor int) {
+	s.batchSizer = sizer
+	s.maxBatchFactor = maxFactor
+}
+
+// buildMerkleTreeFromStorageAtRoot loads the compact Merkle tree state for root, fetching all the
+// nodes it needs with a single GetMerkleNodes call.
 func (s Sequencer) buildMerkleTreeFromStorageAtRoot(ctx context.Context, root trillian.SignedLogRoot, tx storage.TreeTX) (*merkle.CompactMerkleTree, error) {
-	mt, err := merkle.NewCompactMerkleTreeWithState(s.hasher, root.TreeSize, func(depth int, index int64) ([]byte, error) {
-		nodeID, err := storage.NewNodeIDForTreeCoords(int64(depth), index, maxTreeDepth)
-		if err != nil {
-			glog.Warningf("%v: Failed to create nodeID: %v", root.LogId, err)
-			return nil, err
+	mt, err := merkle.NewCompactMerkleTreeWithState(s.hasher, root.TreeSize, func(depths []int, indices []int64) ([][]byte, error) {
+		nodeIDs := make([]storage.NodeID, len(depths))
+		for i, depth := range depths {
+			nodeID, err := storage.NewNodeIDForTreeCoords(int64(depth), indices[i], maxTreeDepth)
+			if err != nil {
+				glog.Warningf("%v: Failed to create nodeID: %v", root.LogId, err)
+				return nil, err
+			}
+			nodeIDs[i] = nodeID
 		}
-		nodes, err := tx.GetMerkleNodes(ctx, root.TreeRevision, []storage.NodeID{nodeID})
 
+		nodes, err := tx.GetMerkleNodes(ctx, root.TreeRevision, nodeIDs)
 		if err != nil {
 			glog.Warningf("%v: Failed to get Merkle nodes: %v", root.LogId, err)
 			return nil, err
 		}
 
-		// We expect to get exactly one node here
-		if nodes == nil || len(nodes) != 1 {
-			return nil, fmt.Errorf("%v: Did not retrieve one node while loading CompactMerkleTree, got %#v for ID %v@%v", root.LogId, nodes, nodeID.String(), root.TreeRevision)
+		// Storage doesn't promise to return the nodes in the order they were asked for.
+		fetched := make(map[string][]byte, len(nodes))
+		for _, node := range nodes {
+			fetched[node.NodeID.String()] = node.Hash
 		}
-
-		return nodes[0].Hash, nil
+		hashes := make([][]byte, len(nodeIDs))
+		for i, nodeID := range nodeIDs {
+			hash, ok := fetched[nodeID.String()]
+			if !ok {
+				return nil, fmt.Errorf("%v: Did not retrieve node %v@%v while loading CompactMerkleTree, got %d of %d nodes", root.LogId, nodeID.String(), root.TreeRevision, len(nodes), len(nodeIDs))
+			}
+			hashes[i] = hash
+		}
+		return hashes, nil
 	}, root.RootHash)
 
 	return mt, err
@@ -203,7 +260,13 @@ func (s Sequencer) sequenceLeaves(mt *merkle.CompactMerkleTree, leaves []*trilli
 	return nodeMap, leaves, nil
 }
 
-func (s Sequencer) initMerkleTreeFromStorage(ctx context.Context, currentRoot trillian.SignedLogRoot, tx storage.LogTreeTX) (*merkle.CompactMerkleTree, error) {
+func (s Sequencer) initMerkleTreeFromStorage(ctx context.Context, logID int64, currentRoot trillian.SignedLogRoot, tx storage.LogTreeTX) (*merkle.CompactMerkleTree, error) {
+	if s.treeCache != nil {
+		if mt := s.treeCache.Take(logID, currentRoot); mt != nil {
+			seqTreeCacheHits.Inc(strconv.FormatInt(logID, 10))
+			return mt, nil
+		}
+	}
 	if currentRoot.TreeSize == 0 {
 		return merkle.NewCompactMerkleTree(s.hasher), nil
 	}
@@ -222,12 +285,127 @@ func (s Sequencer) createRootSignature(ctx context.Context, root trillian.Signed
 	return signature, nil
 }
 
+// checkMergeDelay records how long each of the newly integrated leaves waited in the queue,
+// and warns if any of them took longer than maxMergeDelay. A zero maxMergeDelay disables the
+// warning.
+func (s Sequencer) checkMergeDelay(logID int64, leaves []*trillian.LogLeaf, maxMergeDelay time.Duration) {
+	label := strconv.FormatInt(logID, 10)
+	now := s.timeSource.Now()
+	violations := 0
+	var worst time.Duration
+	for _, leaf := range leaves {
+		delay := now.Sub(time.Unix(0, leaf.QueueTimestampNanos))
+		seqMergeDelay.Observe(delay.Seconds(), label)
+		if maxMergeDelay > 0 && delay > maxMergeDelay {
+			violations++
+		}
+		if delay > worst {
+			worst = delay
+		}
+	}
+	if violations > 0 {
+		seqMMDViolations.Add(float64(violations), label)
+		glog.Warningf("%v: %v leaves integrated later than the maximum merge delay of %v, worst delay %v", logID, violations, maxMergeDelay, worst)
+	}
+}
+
+// checkQueueAge updates the queue age metrics of the log from its oldest queued leaf, and
+// returns the number of leaves to dequeue. If maxMergeDelay is set, and the oldest leaf has
+// waited for more than mmdUrgency of it, the batch is enlarged so the backlog, which is
+// dequeued oldest first, is integrated before the deadline.
+func (s Sequencer) checkQueueAge(ctx context.Context, tx storage.LogTreeTX, logID int64, limit int, maxMergeDelay time.Duration) (int, error) {
+	label := strconv.FormatInt(logID, 10)
+	oldest, err := tx.GetOldestQueueTimestamp(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var age time.Duration
+	if !oldest.IsZero() {
+		age = s.timeSource.Now().Sub(oldest)
+	}
+	seqOldestLeafAge.Set(age.Seconds(), label)
+	if maxMergeDelay <= 0 {
+		return limit, nil
+	}
+	if age > maxMergeDelay {
+		seqMMDExceeded.Set(1, label)
+		glog.Warningf("%v: oldest unsequenced leaf has waited %v, longer than the maximum merge delay of %v", logID, age, maxMergeDelay)
+	} else {
+		seqMMDExceeded.Set(0, label)
+	}
+	if age >= time.Duration(float64(maxMergeDelay)*mmdUrgency) {
+		return limit * mmdUrgentBatchFactor, nil
+	}
+	return limit, nil
+}
+
+// pendingLeaves returns the leaves of a LOG tree that were assigned indices past the current
+// tree size by an earlier pipelined pass, but are not yet integrated. Up to limit+1 leaves are
+// read, so that the caller can tell whether there are more than limit of them.
+func (s Sequencer) pendingLeaves(ctx context.Context, tx storage.LogTreeTX, root trillian.SignedLogRoot, limit int) ([]*trillian.LogLeaf, error) {
+	leaves, err := tx.GetLeavesByRange(ctx, root.TreeSize, int64(limit)+1)
+	if err != nil {
+		return nil, err
+	}
+	// Pending leaves are always assigned as a contiguous run, so a gap means the log is corrupt.
+	for i, leaf := range leaves {
+		if want := root.TreeSize + int64(i); leaf.LeafIndex != want {
+			return nil, fmt.Errorf("%v: got pending leaf with index %v, want %v", root.LogId, leaf.LeafIndex, want)
+		}
+	}
+	return leaves, nil
+}
+
+// integratedRoot is the result of integrating a batch of leaves into a compact Merkle tree.
+type integratedRoot struct {
+	nodeMap map[string]storage.Node
+	root    trillian.SignedLogRoot
+	err     error
+}
+
+// integrate adds leaves to merkleTree, and builds the resulting root at newVersion, ready for
+// signing. It doesn't touch storage, so it may run alongside other work in the transaction.
+func (s Sequencer) integrate(merkleTree *merkle.CompactMerkleTree, leaves []*trillian.LogLeaf, currentRoot trillian.SignedLogRoot, newVersion int64) integratedRoot {
+	label := strconv.FormatInt(currentRoot.LogId, 10)
+	start := s.timeSource.Now()
+	nodeMap, sequencedLeaves, err := s.sequenceLeaves(merkleTree, leaves)
+	if err != nil {
+		return integratedRoot{err: err}
+	}
+	seqWriteTreeLatency.Observe(s.since(start), label)
+
+	// We should still have the same number of leaves
+	if got, want := len(sequencedLeaves), len(leaves); got != want {
+		return integratedRoot{err: fmt.Errorf("%v: wanted: %v leaves after sequencing but we got: %v", currentRoot.LogId, want, got)}
+	}
+
+	// Create the log root ready for signing
+	newLogRoot := trillian.SignedLogRoot{
+		RootHash:       merkleTree.CurrentRoot(),
+		TimestampNanos: s.timeSource.Now().UnixNano(),
+		TreeSize:       merkleTree.Size(),
+		LogId:          currentRoot.LogId,
+		TreeRevision:   newVersion,
+	}
+	return integratedRoot{nodeMap: nodeMap, root: newLogRoot}
+}
+
 // SequenceBatch wraps up all the operations needed to take a batch of queued leaves
-// and integrate them into the tree.
+// and integrate them into the tree. Leaves that were queued more than maxMergeDelay ago are
+// reported as maximum merge delay violations, and logs whose queue is close to maxMergeDelay
+// are sequenced in larger batches.
+// Leaves of PREORDERED_LOG trees already have their indices, so they're integrated as they are,
+// and only once all the leaves before them have been added.
+// If the sequencer is pipelined, the leaves dequeued by a pass are only assigned their indices,
+// and are integrated by the next pass of the log. Leaves left pending by a pipelined pass are
+// always integrated first, so a log can safely stop being pipelined.
 // TODO(Martin2112): Can possibly improve by deferring a function that attempts to rollback,
 // which will fail if the tx was committed. Should only do this if we can hide the details of
 // the underlying storage transactions and it doesn't create other problems.
-func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, guardWindow, maxRootDurationInterval time.Duration) (int, error) {
+func (s Sequencer) SequenceBatch(ctx context.Context, tree *trillian.Tree, limit int, guardWindow, maxRootDurationInterval, maxMergeDelay time.Duration) (int, error) {
+	logID := tree.TreeId
+	preordered := tree.TreeType == trillian.TreeType_PREORDERED_LOG
+	pipelined := s.pipelined && !preordered
 	start := s.timeSource.Now()
 	stageStart := start
 	label := strconv.FormatInt(logID, 10)
@@ -240,15 +418,20 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 	defer seqBatches.Inc(label)
 	defer func() { seqLatency.Observe(s.since(start), label) }()
 
-	// Very recent leaves inside the guard window will not be available for sequencing
-	guardCutoffTime := s.timeSource.Now().Add(-guardWindow)
-	leaves, err := tx.DequeueLeaves(ctx, limit, guardCutoffTime)
-	if err != nil {
-		glog.Warningf("%v: Sequencer failed to dequeue leaves: %v", logID, err)
-		return 0, err
+	if !preordered {
+		base := limit
+		if s.batchSizer != nil {
+			limit = s.batchSizer.Limit(logID, limit, s.maxBatchFactor)
+		}
+		if limit, err = s.checkQueueAge(ctx, tx, logID, limit, maxMergeDelay); err != nil {
+			glog.Warningf("%v: Sequencer failed to get oldest queue timestamp: %v", logID, err)
+			return 0, err
+		}
+		// An urgent queue enlarges the batch further, but never past the sizer's cap.
+		if max := base * s.maxBatchFactor; s.batchSizer != nil && limit > max {
+			limit = max
+		}
 	}
-	seqDequeueLatency.Observe(s.since(stageStart), label)
-	stageStart = s.timeSource.Now()
 
 	// Get the latest known root from storage
 	currentRoot, err := tx.LatestSignedLogRoot(ctx)
@@ -273,6 +456,67 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 		return 0, s.SignRoot(ctx, logID)
 	}
 
+	// Leaves assigned indices by a pipelined pass go into the tree before any new ones. New
+	// leaves can only be dequeued once it's known where the pending ones end.
+	var pending []*trillian.LogLeaf
+	dequeue := true
+	if !preordered {
+		if pending, err = s.pendingLeaves(ctx, tx, currentRoot, limit); err != nil {
+			glog.Warningf("%v: Sequencer failed to get pending leaves: %v", logID, err)
+			return 0, err
+		}
+		if len(pending) > limit {
+			pending = pending[:limit]
+			dequeue = false
+		}
+	}
+
+	var dequeued []*trillian.LogLeaf
+	if dequeue {
+		dequeueLimit := limit
+		if !pipelined {
+			dequeueLimit -= len(pending)
+		}
+		// Very recent leaves inside the guard window will not be available for sequencing
+		guardCutoffTime := s.timeSource.Now().Add(-guardWindow)
+		dequeued, err = tx.DequeueLeaves(ctx, dequeueLimit, guardCutoffTime)
+		if err != nil {
+			glog.Warningf("%v: Sequencer failed to dequeue leaves: %v", logID, err)
+			return 0, err
+		}
+		if s.batchSizer != nil && !preordered {
+			s.batchSizer.Update(logID, dequeueLimit, len(dequeued))
+		}
+	}
+	seqDequeueLatency.Observe(s.since(stageStart), label)
+	stageStart = s.timeSource.Now()
+
+	if preordered {
+		// Storage only returns the leaves that directly follow the tree, but a leaf integrated at
+		// the wrong index could never be fixed, so make sure.
+		for i, leaf := range dequeued {
+			if want := currentRoot.TreeSize + int64(i); leaf.LeafIndex != want {
+				return 0, fmt.Errorf("%v: got leaf with index %v, want %v", logID, leaf.LeafIndex, want)
+			}
+		}
+	}
+
+	leaves := pending
+	var assigned []*trillian.LogLeaf
+	if pipelined {
+		// The new leaves follow the pending ones, and stay pending until the next pass.
+		next := currentRoot.TreeSize + int64(len(pending))
+		for i, leaf := range dequeued {
+			leaf.LeafIndex = next + int64(i)
+		}
+		assigned = dequeued
+	} else {
+		leaves = append(leaves, dequeued...)
+		if !preordered {
+			assigned = dequeued
+		}
+	}
+
 	// There might be no work to be done. But we possibly still need to create an signed root if the
 	// current one is too old. If there's work to be done then we'll be creating a root anyway.
 	numLeaves := len(leaves)
@@ -280,14 +524,26 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 		nowNanos := s.timeSource.Now().UnixNano()
 		interval := time.Duration(nowNanos - currentRoot.TimestampNanos)
 		if maxRootDurationInterval == 0 || interval < maxRootDurationInterval {
-			// We have nothing to integrate into the tree
+			// We have nothing to integrate into the tree, but a pipelined pass may have
+			// leaves to make pending for the next one.
+			if len(assigned) > 0 {
+				if err := tx.UpdateSequencedLeaves(ctx, assigned); err != nil {
+					glog.Warningf("%v: Sequencer failed to update sequenced leaves: %v", logID, err)
+					return 0, err
+				}
+				if err := tx.Commit(); err != nil {
+					return 0, err
+				}
+				glog.Infof("%v: assigned indices to %v pending leaves", logID, len(assigned))
+				return 0, nil
+			}
 			glog.V(1).Infof("No leaves sequenced in this signing operation.")
 			return 0, tx.Commit()
 		}
 		glog.Infof("Force new root generation as %v since last root", interval)
 	}
 
-	merkleTree, err := s.initMerkleTreeFromStorage(ctx, currentRoot, tx)
+	merkleTree, err := s.initMerkleTreeFromStorage(ctx, logID, currentRoot, tx)
 	if err != nil {
 		return 0, err
 	}
@@ -303,23 +559,41 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 		return 0, fmt.Errorf("%v: got writeRevision of %v, but expected %v", logID, got, want)
 	}
 
-	// Assign leaf sequence numbers and collate node updates
-	nodeMap, sequencedLeaves, err := s.sequenceLeaves(merkleTree, leaves)
-	if err != nil {
-		return 0, err
+	// Hashing and signing only need the compact tree, so a pipelined pass does them while it
+	// hands the new leaves to storage. The transaction itself is only used by this goroutine.
+	var result integratedRoot
+	if pipelined {
+		done := make(chan integratedRoot, 1)
+		go func() {
+			result := s.integrate(merkleTree, leaves, currentRoot, newVersion)
+			if result.err == nil {
+				result.root.Signature, result.err = s.createRootSignature(ctx, result.root)
+			}
+			done <- result
+		}()
+		if len(assigned) > 0 {
+			if err := tx.UpdateSequencedLeaves(ctx, assigned); err != nil {
+				glog.Warningf("%v: Sequencer failed to update sequenced leaves: %v", logID, err)
+				<-done
+				return 0, err
+			}
+		}
+		result = <-done
+	} else {
+		result = s.integrate(merkleTree, leaves, currentRoot, newVersion)
 	}
-	seqWriteTreeLatency.Observe(s.since(stageStart), label)
-	stageStart = s.timeSource.Now()
-
-	// We should still have the same number of leaves
-	if want := len(sequencedLeaves); numLeaves != want {
-		return 0, fmt.Errorf("%v: wanted: %v leaves after sequencing but we got: %v", logID, want, numLeaves)
+	if result.err != nil {
+		return 0, result.err
 	}
+	stageStart = s.timeSource.Now()
 
-	// Write the new sequence numbers to the leaves in the DB
-	if err := tx.UpdateSequencedLeaves(ctx, sequencedLeaves); err != nil {
-		glog.Warningf("%v: Sequencer failed to update sequenced leaves: %v", logID, err)
-		return 0, err
+	// Write the new sequence numbers to the leaves in the DB. Pre-ordered and pending leaves were
+	// stored with theirs.
+	if !pipelined && !preordered {
+		if err := tx.UpdateSequencedLeaves(ctx, assigned); err != nil {
+			glog.Warningf("%v: Sequencer failed to update sequenced leaves: %v", logID, err)
+			return 0, err
+		}
 	}
 	seqUpdateLeavesLatency.Observe(s.since(stageStart), label)
 	stageStart = s.timeSource.Now()
@@ -327,7 +601,7 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 	// Build objects for the nodes to be updated. Because we deduped via the map each
 	// node can only be created / updated once in each tree revision and they cannot
 	// conflict when we do the storage update.
-	targetNodes, err := s.buildNodesFromNodeMap(nodeMap, newVersion)
+	targetNodes, err := s.buildNodesFromNodeMap(result.nodeMap, newVersion)
 	if err != nil {
 		// probably an internal error with map building, unexpected
 		glog.Warningf("%v: Failed to build target nodes in sequencer: %v", logID, err)
@@ -342,25 +616,19 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 	seqSetNodesLatency.Observe(s.since(stageStart), label)
 	stageStart = s.timeSource.Now()
 
-	// Create the log root ready for signing
-	newLogRoot := trillian.SignedLogRoot{
-		RootHash:       merkleTree.CurrentRoot(),
-		TimestampNanos: s.timeSource.Now().UnixNano(),
-		TreeSize:       merkleTree.Size(),
-		LogId:          currentRoot.LogId,
-		TreeRevision:   newVersion,
-	}
-	seqTreeSize.Set(float64(merkleTree.Size()), label)
+	newLogRoot := result.root
+	seqTreeSize.Set(float64(newLogRoot.TreeSize), label)
 
-	// Hash and sign the root, update it with the signature
-	signature, err := s.createRootSignature(ctx, newLogRoot)
-	if err != nil {
-		glog.Warningf("%v: signer failed to sign root: %v", logID, err)
-		return 0, err
+	// Hash and sign the root, update it with the signature. A pipelined pass has done so already.
+	if !pipelined {
+		signature, err := s.createRootSignature(ctx, newLogRoot)
+		if err != nil {
+			glog.Warningf("%v: signer failed to sign root: %v", logID, err)
+			return 0, err
+		}
+		newLogRoot.Signature = signature
 	}
 
-	newLogRoot.Signature = signature
-
 	if err := tx.StoreSignedLogRoot(ctx, newLogRoot); err != nil {
 		glog.Warningf("%v: failed to write updated tree root: %v", logID, err)
 		return 0, err
@@ -373,6 +641,12 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 		return 0, err
 	}
 	seqCommitLatency.Observe(s.since(stageStart), label)
+	if s.treeCache != nil {
+		s.treeCache.Put(logID, newLogRoot, merkleTree)
+	}
+	if numLeaves > 0 && !preordered {
+		s.checkMergeDelay(logID, leaves, maxMergeDelay)
+	}
 
 	// Let quota.Manager know about newly-sequenced entries.
 	// All possibly influenced quotas are replenished: {Tree/Global, Read/Write}.
@@ -398,6 +672,9 @@ func (s Sequencer) SequenceBatch(ctx context.Context, logID int64, limit int, gu
 
 	seqCounter.Add(float64(numLeaves), label)
 	glog.Infof("%v: sequenced %v leaves, size %v, tree-revision %v", logID, numLeaves, newLogRoot.TreeSize, newLogRoot.TreeRevision)
+	if pipelined {
+		glog.Infof("%v: assigned indices to %v pending leaves", logID, len(assigned))
+	}
 	return numLeaves, nil
 }
 
@@ -419,7 +696,7 @@ func (s Sequencer) SignRoot(ctx context.Context, logID int64) error {
 
 	// Initialize a Merkle Tree from the state in storage. This should fail if the tree is
 	// in a corrupt state.
-	merkleTree, err := s.initMerkleTreeFromStorage(ctx, currentRoot, tx)
+	merkleTree, err := s.initMerkleTreeFromStorage(ctx, logID, currentRoot, tx)
 	if err != nil {
 		return err
 	}
@@ -448,7 +725,13 @@ func (s Sequencer) SignRoot(ctx context.Context, logID int64) error {
 	}
 	glog.V(2).Infof("%v: new signed root, size %v, tree-revision %v", logID, newLogRoot.TreeSize, newLogRoot.TreeRevision)
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if s.treeCache != nil {
+		s.treeCache.Put(logID, newLogRoot, merkleTree)
+	}
+	return nil
 }
 
 // since() returns the time in seconds since a particular time, according to
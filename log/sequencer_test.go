@@ -15,6 +15,7 @@
 package log
 
 import (
+	"bytes"
 	"context"
 	gocrypto "crypto"
 	"errors"
@@ -28,9 +29,11 @@ import (
 	"github.com/google/trillian/crypto"
 	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/crypto/sigpb"
+	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/rfc6962"
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
 	stestonly "github.com/google/trillian/storage/testonly"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/util"
@@ -129,6 +132,9 @@ type testParameters struct {
 	dequeuedLeaves []*trillian.LogLeaf
 	dequeuedError  error
 
+	// pendingLeaves are the leaves left pending by an earlier pipelined pass.
+	pendingLeaves []*trillian.LogLeaf
+
 	latestSignedRootError error
 	latestSignedRoot      *trillian.SignedLogRoot
 
@@ -229,6 +235,11 @@ func createTestContext(ctrl *gomock.Controller, params testParameters) (testCont
 		}
 	}
 
+	// The queue age is only read from LOG trees, which not all tests use.
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).AnyTimes().Return(time.Time{}, nil)
+	// Pending leaves are only read once the latest root is known, which not all tests get to.
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(params.pendingLeaves, nil)
+
 	if params.latestSignedRoot != nil {
 		mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*params.latestSignedRoot, params.latestSignedRootError)
 	}
@@ -255,7 +266,7 @@ func createTestContext(ctrl *gomock.Controller, params testParameters) (testCont
 	if qm == nil {
 		qm = quota.Noop()
 	}
-	sequencer := NewSequencer(rfc6962.DefaultHasher, util.NewFakeTimeSource(fakeTimeForTest), mockStorage, signer, nil, qm)
+	sequencer := NewSequencer(rfc6962.DefaultHasher, util.NewFakeTimeSource(fakeTimeForTest), mockStorage, signer, nil, qm, nil)
 	return testContext{mockTx: mockTx, mockStorage: mockStorage, signer: signer, sequencer: sequencer}, context.Background()
 }
 
@@ -301,6 +312,7 @@ func TestSequenceBatch(t *testing.T) {
 	var tests = []struct {
 		desc            string
 		params          testParameters
+		preordered      bool
 		guardWindow     time.Duration
 		maxRootDuration time.Duration
 		wantCount       int
@@ -392,6 +404,7 @@ func TestSequenceBatch(t *testing.T) {
 				logID:               154035,
 				dequeueLimit:        1,
 				dequeuedError:       errors.New("dequeue"),
+				latestSignedRoot:    &testRoot16,
 				skipStoreSignedRoot: true,
 			},
 			errStr: "dequeue",
@@ -400,8 +413,7 @@ func TestSequenceBatch(t *testing.T) {
 			desc: "get-signed-root-fails",
 			params: testParameters{
 				logID:                 154035,
-				dequeueLimit:          1,
-				dequeuedLeaves:        []*trillian.LogLeaf{getLeaf42()},
+				skipDequeue:           true,
 				latestSignedRoot:      &testRoot16,
 				latestSignedRootError: errors.New("root"),
 				skipStoreSignedRoot:   true,
@@ -505,6 +517,36 @@ func TestSequenceBatch(t *testing.T) {
 			},
 			wantCount: 1,
 		},
+		{
+			// Pre-ordered leaves already carry their index, so they're integrated without
+			// updating their sequence numbers.
+			desc: "sequence-preordered-leaf-16",
+			params: testParameters{
+				logID:            154035,
+				writeRevision:    testRoot16.TreeRevision + 1,
+				dequeueLimit:     1,
+				shouldCommit:     true,
+				dequeuedLeaves:   []*trillian.LogLeaf{testLeaf16},
+				latestSignedRoot: &testRoot16,
+				merkleNodesSet:   &updatedNodes,
+				storeSignedRoot:  &expectedSignedRoot,
+				signer:           signer1,
+			},
+			preordered: true,
+			wantCount:  1,
+		},
+		{
+			desc: "preordered-leaf-wrong-index",
+			params: testParameters{
+				logID:               154035,
+				dequeueLimit:        1,
+				dequeuedLeaves:      []*trillian.LogLeaf{getLeaf42()},
+				latestSignedRoot:    &testRoot16,
+				skipStoreSignedRoot: true,
+			},
+			preordered: true,
+			errStr:     "index 42",
+		},
 	}
 
 	for _, test := range tests {
@@ -512,6 +554,10 @@ func TestSequenceBatch(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
+			tree := &trillian.Tree{TreeId: test.params.logID, TreeType: trillian.TreeType_LOG}
+			if test.preordered {
+				tree.TreeType = trillian.TreeType_PREORDERED_LOG
+			}
 			qm := quota.NewMockManager(ctrl)
 			test.params.qm = qm
 			if test.wantCount > 0 {
@@ -519,7 +565,7 @@ func TestSequenceBatch(t *testing.T) {
 			}
 			c, ctx := createTestContext(ctrl, test.params)
 
-			got, err := c.sequencer.SequenceBatch(ctx, test.params.logID, 1, test.guardWindow, test.maxRootDuration)
+			got, err := c.sequencer.SequenceBatch(ctx, tree, 1, test.guardWindow, test.maxRootDuration, 0)
 			if err != nil {
 				if test.errStr == "" {
 					t.Errorf("SequenceBatch(%+v)=%v,%v; want _,nil", test.params, got, err)
@@ -554,6 +600,7 @@ func TestSequenceBatch_PutTokens(t *testing.T) {
 	const limit = 1000
 	const guardWindow = 10 * time.Second
 	const maxRootDuration = 1 * time.Hour
+	tree := &trillian.Tree{TreeId: treeID, TreeType: trillian.TreeType_LOG}
 
 	// Expected PutTokens specs
 	specs := []quota.Spec{
@@ -620,8 +667,10 @@ func TestSequenceBatch_PutTokens(t *testing.T) {
 			// Correctness of operation is tested elsewhere. The focus here is the interaction
 			// between Sequencer and quota.Manager.
 			logTX := storage.NewMockLogTreeTX(ctrl)
+			logTX.EXPECT().GetOldestQueueTimestamp(any).Return(time.Time{}, nil)
 			logTX.EXPECT().DequeueLeaves(any, any, any).Return(test.leaves, nil)
 			logTX.EXPECT().LatestSignedLogRoot(any).Return(testRoot16, nil)
+			logTX.EXPECT().GetLeavesByRange(any, any, any).Return(nil, nil)
 			logTX.EXPECT().WriteRevision().AnyTimes().Return(testRoot16.TreeRevision + 1)
 			logTX.EXPECT().UpdateSequencedLeaves(any, any).AnyTimes().Return(nil)
 			logTX.EXPECT().SetMerkleNodes(any, any).AnyTimes().Return(nil)
@@ -636,8 +685,8 @@ func TestSequenceBatch_PutTokens(t *testing.T) {
 				qm.EXPECT().PutTokens(any, test.wantTokens, specs)
 			}
 
-			sequencer := NewSequencer(hasher, ts, logStorage, signer, nil /* mf */, qm)
-			leaves, err := sequencer.SequenceBatch(ctx, treeID, limit, guardWindow, maxRootDuration)
+			sequencer := NewSequencer(hasher, ts, logStorage, signer, nil /* mf */, qm, nil /* treeCache */)
+			leaves, err := sequencer.SequenceBatch(ctx, tree, limit, guardWindow, maxRootDuration, 0)
 			if err != nil {
 				t.Errorf("%v: SequenceBatch() returned err = %v", test.desc, err)
 				return
@@ -649,6 +698,459 @@ func TestSequenceBatch_PutTokens(t *testing.T) {
 	}
 }
 
+func TestCheckMergeDelay(t *testing.T) {
+	ts := util.NewFakeTimeSource(fakeTimeForTest)
+	// Metrics are shared between tests, so each case uses a log of its own.
+	s := NewSequencer(rfc6962.DefaultHasher, ts, nil, nil, nil /* mf */, nil, nil /* treeCache */)
+	queuedAgo := func(d time.Duration) *trillian.LogLeaf {
+		return &trillian.LogLeaf{QueueTimestampNanos: fakeTimeForTest.Add(-d).UnixNano()}
+	}
+	leaves := []*trillian.LogLeaf{queuedAgo(time.Second), queuedAgo(time.Minute), queuedAgo(time.Hour)}
+
+	for _, test := range []struct {
+		logID          int64
+		maxMergeDelay  time.Duration
+		wantViolations float64
+	}{
+		{logID: 1001, maxMergeDelay: 0, wantViolations: 0},
+		{logID: 1002, maxMergeDelay: 2 * time.Hour, wantViolations: 0},
+		{logID: 1003, maxMergeDelay: 10 * time.Second, wantViolations: 2},
+		{logID: 1004, maxMergeDelay: time.Hour, wantViolations: 0},
+	} {
+		s.checkMergeDelay(test.logID, leaves, test.maxMergeDelay)
+		label := fmt.Sprint(test.logID)
+		if got := seqMMDViolations.Value(label); got != test.wantViolations {
+			t.Errorf("checkMergeDelay(%v): %v violations, want %v", test.maxMergeDelay, got, test.wantViolations)
+		}
+		if count, _ := seqMergeDelay.Info(label); count != uint64(len(leaves)) {
+			t.Errorf("checkMergeDelay(%v): %v delays observed, want %v", test.maxMergeDelay, count, len(leaves))
+		}
+	}
+}
+
+func TestCheckQueueAge(t *testing.T) {
+	ctx := context.Background()
+	ts := util.NewFakeTimeSource(fakeTimeForTest)
+	s := NewSequencer(rfc6962.DefaultHasher, ts, nil, nil, nil /* mf */, nil, nil /* treeCache */)
+
+	for _, test := range []struct {
+		desc         string
+		logID        int64
+		oldest       time.Time
+		mmd          time.Duration
+		err          error
+		wantLimit    int
+		wantAge      float64
+		wantExceeded float64
+	}{
+		{desc: "empty", logID: 1011, mmd: time.Hour, wantLimit: 10},
+		{desc: "recent", logID: 1012, oldest: fakeTimeForTest.Add(-time.Minute), mmd: time.Hour, wantLimit: 10, wantAge: 60},
+		{desc: "urgent", logID: 1013, oldest: fakeTimeForTest.Add(-40 * time.Minute), mmd: time.Hour, wantLimit: 40, wantAge: 2400},
+		{desc: "exceeded", logID: 1014, oldest: fakeTimeForTest.Add(-2 * time.Hour), mmd: time.Hour, wantLimit: 40, wantAge: 7200, wantExceeded: 1},
+		{desc: "error", logID: 1015, mmd: time.Hour, err: errors.New("getoldest")},
+		{desc: "noMMD", logID: 1016, oldest: fakeTimeForTest.Add(-2 * time.Hour), wantLimit: 10, wantAge: 7200},
+	} {
+		ctrl := gomock.NewController(t)
+		tx := storage.NewMockLogTreeTX(ctrl)
+		tx.EXPECT().GetOldestQueueTimestamp(ctx).Return(test.oldest, test.err)
+
+		limit, err := s.checkQueueAge(ctx, tx, test.logID, 10, test.mmd)
+		if err != test.err {
+			t.Errorf("%v: checkQueueAge() = (_, %v), want = (_, %v)", test.desc, err, test.err)
+		}
+		if limit != test.wantLimit {
+			t.Errorf("%v: checkQueueAge() = (%v, _), want = (%v, _)", test.desc, limit, test.wantLimit)
+		}
+		label := fmt.Sprint(test.logID)
+		if got := seqOldestLeafAge.Value(label); got != test.wantAge {
+			t.Errorf("%v: oldest unsequenced age = %v, want = %v", test.desc, got, test.wantAge)
+		}
+		if got := seqMMDExceeded.Value(label); got != test.wantExceeded {
+			t.Errorf("%v: mmd exceeded = %v, want = %v", test.desc, got, test.wantExceeded)
+		}
+		ctrl.Finish()
+	}
+}
+
+// TestSequenceBatchReloadsCompactTree sequences into a tree whose size isn't a power of two, so
+// that the compact Merkle tree has to be rebuilt from several stored nodes.
+func TestSequenceBatchReloadsCompactTree(t *testing.T) {
+	ctx := context.Background()
+	cryptoSigner, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+
+	ls := memory.NewLogStorage(nil)
+	atx, err := memory.NewAdminStorage(ls).Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin(): %v", err)
+	}
+	tree, err := atx.CreateTree(ctx, stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	if err := atx.Commit(); err != nil {
+		t.Fatalf("Commit(): %v", err)
+	}
+
+	hasher := rfc6962.DefaultHasher
+	ts := util.NewFakeTimeSource(fakeTimeForTest)
+	sequencer := NewSequencer(hasher, ts, ls, crypto.NewSHA256Signer(cryptoSigner), nil, quota.Noop(), nil /* treeCache */)
+	// The first pass only creates the signed root of the empty tree.
+	if _, err := sequencer.SequenceBatch(ctx, tree, 1, 0, 0, 0); err != nil {
+		t.Fatalf("SequenceBatch(): %v", err)
+	}
+
+	imt := merkle.NewInMemoryMerkleTree(hasher)
+	leaves := make([]*trillian.LogLeaf, 7)
+	for i := range leaves {
+		value := []byte(fmt.Sprintf("leaf-%d", i))
+		leafHash := hasher.HashLeaf(value)
+		leaves[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash, LeafValue: value}
+		imt.AddLeaf(value)
+	}
+	tx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree(): %v", err)
+	}
+	if _, err := tx.QueueLeaves(ctx, leaves, fakeTimeForTest.Add(-time.Minute)); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit(): %v", err)
+	}
+
+	for _, limit := range []int{3, 2, 2} {
+		if got, err := sequencer.SequenceBatch(ctx, tree, limit, 0, 0, 0); err != nil || got != limit {
+			t.Fatalf("SequenceBatch(%d): %v, %v; want %d, nil", limit, got, err, limit)
+		}
+	}
+
+	stx, err := ls.SnapshotForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer stx.Close()
+	root, err := stx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		t.Fatalf("LatestSignedLogRoot(): %v", err)
+	}
+	if got, want := root.TreeSize, int64(len(leaves)); got != want {
+		t.Errorf("TreeSize: %v, want %v", got, want)
+	}
+	if got, want := root.RootHash, imt.CurrentRoot().Hash(); !bytes.Equal(got, want) {
+		t.Errorf("RootHash: %x, want %x", got, want)
+	}
+}
+
+func TestSequenceBatchPipelined(t *testing.T) {
+	ctx := context.Background()
+	cryptoSigner, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+
+	ls := memory.NewLogStorage(nil)
+	atx, err := memory.NewAdminStorage(ls).Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin(): %v", err)
+	}
+	tree, err := atx.CreateTree(ctx, stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	if err := atx.Commit(); err != nil {
+		t.Fatalf("Commit(): %v", err)
+	}
+
+	hasher := rfc6962.DefaultHasher
+	ts := util.NewFakeTimeSource(fakeTimeForTest)
+	sequencer := NewSequencer(hasher, ts, ls, crypto.NewSHA256Signer(cryptoSigner), nil, quota.Noop(), NewCompactTreeCache())
+	// The first pass only creates the signed root of the empty tree.
+	if _, err := sequencer.SequenceBatch(ctx, tree, 1, 0, 0, 0); err != nil {
+		t.Fatalf("SequenceBatch(): %v", err)
+	}
+
+	imt := merkle.NewInMemoryMerkleTree(hasher)
+	leaves := make([]*trillian.LogLeaf, 7)
+	for i := range leaves {
+		value := []byte(fmt.Sprintf("leaf-%d", i))
+		leafHash := hasher.HashLeaf(value)
+		leaves[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash, LeafValue: value}
+		imt.AddLeaf(value)
+	}
+	tx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree(): %v", err)
+	}
+	if _, err := tx.QueueLeaves(ctx, leaves, fakeTimeForTest.Add(-time.Minute)); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit(): %v", err)
+	}
+
+	latestRoot := func() trillian.SignedLogRoot {
+		stx, err := ls.SnapshotForTree(ctx, tree.TreeId)
+		if err != nil {
+			t.Fatalf("SnapshotForTree(): %v", err)
+		}
+		defer stx.Close()
+		root, err := stx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("LatestSignedLogRoot(): %v", err)
+		}
+		return root
+	}
+
+	label := fmt.Sprint(tree.TreeId)
+	// Each pipelined pass integrates the leaves dequeued by the one before. The last pass isn't
+	// pipelined, so it integrates the pending leaf along with whatever is left in the queue.
+	for _, pass := range []struct {
+		pipelined bool
+		want      int
+		wantSize  int64
+	}{
+		{pipelined: true, want: 0, wantSize: 0},
+		{pipelined: true, want: 3, wantSize: 3},
+		{pipelined: true, want: 3, wantSize: 6},
+		{pipelined: false, want: 1, wantSize: 7},
+	} {
+		sequencer.SetPipelined(pass.pipelined)
+		if got, err := sequencer.SequenceBatch(ctx, tree, 3, 0, 0, 0); err != nil || got != pass.want {
+			t.Fatalf("SequenceBatch(pipelined: %v): %v, %v; want %d, nil", pass.pipelined, got, err, pass.want)
+		}
+		if got := latestRoot().TreeSize; got != pass.wantSize {
+			t.Errorf("TreeSize after SequenceBatch(pipelined: %v): %v, want %v", pass.pipelined, got, pass.wantSize)
+		}
+		// Merge delays are only observed once leaves are integrated, not when they're assigned.
+		if got, _ := seqMergeDelay.Info(label); got != uint64(pass.wantSize) {
+			t.Errorf("Merge delays observed after SequenceBatch(pipelined: %v): %v, want %v", pass.pipelined, got, pass.wantSize)
+		}
+	}
+
+	if got, want := latestRoot().RootHash, imt.CurrentRoot().Hash(); !bytes.Equal(got, want) {
+		t.Errorf("RootHash: %x, want %x", got, want)
+	}
+}
+
+func TestSequenceBatchAdaptiveBatchSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+	sizer := NewBatchSizer()
+	any := gomock.Any()
+
+	// The log's backlog fills the first two batches, so each is followed by a larger one, up to
+	// 4 times the base batch size of 10. The short fourth batch resets the batch size.
+	for _, pass := range []struct {
+		wantLimit int
+		dequeued  int
+	}{
+		{wantLimit: 10, dequeued: 10},
+		{wantLimit: 20, dequeued: 20},
+		{wantLimit: 40, dequeued: 40},
+		{wantLimit: 40, dequeued: 5},
+		{wantLimit: 10, dequeued: 0},
+	} {
+		dequeued := make([]*trillian.LogLeaf, pass.dequeued)
+		for i := range dequeued {
+			leafHash := rfc6962.DefaultHasher.HashLeaf([]byte{byte(i)})
+			dequeued[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash}
+		}
+		logTX := storage.NewMockLogTreeTX(ctrl)
+		logTX.EXPECT().GetOldestQueueTimestamp(any).Return(time.Time{}, nil)
+		logTX.EXPECT().LatestSignedLogRoot(any).Return(testRoot16, nil)
+		logTX.EXPECT().GetLeavesByRange(any, testRoot16.TreeSize, int64(pass.wantLimit+1)).Return(nil, nil)
+		logTX.EXPECT().DequeueLeaves(any, pass.wantLimit, any).Return(dequeued, nil)
+		logTX.EXPECT().WriteRevision().AnyTimes().Return(testRoot16.TreeRevision + 1)
+		logTX.EXPECT().UpdateSequencedLeaves(any, any).AnyTimes().Return(nil)
+		logTX.EXPECT().SetMerkleNodes(any, any).AnyTimes().Return(nil)
+		logTX.EXPECT().StoreSignedLogRoot(any, any).AnyTimes().Return(nil)
+		logTX.EXPECT().Commit().Return(nil)
+		logTX.EXPECT().Close().Return(nil)
+		logStorage := storage.NewMockLogStorage(ctrl)
+		logStorage.EXPECT().BeginForTree(any, any).Return(logTX, nil)
+
+		sequencer := NewSequencer(rfc6962.DefaultHasher, util.NewFakeTimeSource(fakeTimeForTest), logStorage, crypto.NewSHA256Signer(signer), nil, quota.Noop(), nil /* treeCache */)
+		sequencer.SetBatchSizer(sizer, 4)
+		if _, err := sequencer.SequenceBatch(context.Background(), stestonly.LogTree, 10, 0, 0, 0); err != nil {
+			t.Fatalf("SequenceBatch(): %v", err)
+		}
+	}
+}
+
+func TestSequenceBatchAdaptiveBatchSizeWithPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+	sizer := NewBatchSizer()
+	any := gomock.Any()
+
+	// Leaves left pending by a pipelined pass take 4 of the 10 leaves of the batch, so only 6 are
+	// dequeued. A full dequeue still means there's a backlog.
+	pending := make([]*trillian.LogLeaf, 4)
+	for i := range pending {
+		leafHash := rfc6962.DefaultHasher.HashLeaf([]byte{byte(i)})
+		pending[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash, LeafIndex: testRoot16.TreeSize + int64(i)}
+	}
+	dequeued := make([]*trillian.LogLeaf, 6)
+	for i := range dequeued {
+		leafHash := rfc6962.DefaultHasher.HashLeaf([]byte{byte(len(pending) + i)})
+		dequeued[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash}
+	}
+	logTX := storage.NewMockLogTreeTX(ctrl)
+	logTX.EXPECT().GetOldestQueueTimestamp(any).Return(time.Time{}, nil)
+	logTX.EXPECT().LatestSignedLogRoot(any).Return(testRoot16, nil)
+	logTX.EXPECT().GetLeavesByRange(any, testRoot16.TreeSize, int64(11)).Return(pending, nil)
+	logTX.EXPECT().DequeueLeaves(any, 6, any).Return(dequeued, nil)
+	logTX.EXPECT().WriteRevision().AnyTimes().Return(testRoot16.TreeRevision + 1)
+	logTX.EXPECT().UpdateSequencedLeaves(any, dequeued).Return(nil)
+	logTX.EXPECT().SetMerkleNodes(any, any).Return(nil)
+	logTX.EXPECT().StoreSignedLogRoot(any, any).Return(nil)
+	logTX.EXPECT().Commit().Return(nil)
+	logTX.EXPECT().Close().Return(nil)
+	logStorage := storage.NewMockLogStorage(ctrl)
+	logStorage.EXPECT().BeginForTree(any, any).Return(logTX, nil)
+
+	sequencer := NewSequencer(rfc6962.DefaultHasher, util.NewFakeTimeSource(fakeTimeForTest), logStorage, crypto.NewSHA256Signer(signer), nil, quota.Noop(), nil /* treeCache */)
+	sequencer.SetBatchSizer(sizer, 4)
+	if _, err := sequencer.SequenceBatch(context.Background(), stestonly.LogTree, 10, 0, 0, 0); err != nil {
+		t.Fatalf("SequenceBatch(): %v", err)
+	}
+	if got, want := sizer.Limit(stestonly.LogTree.TreeId, 10, 4), 12; got != want {
+		t.Errorf("Limit() after a full dequeue of 6 leaves: %v, want %v", got, want)
+	}
+}
+
+func TestSequenceBatchUrgentBatchSizeCapped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+	any := gomock.Any()
+
+	// The oldest leaf is close to the maximum merge delay, which would quadruple the batch, but
+	// the batch sizer caps it at twice the base batch size of 10.
+	logTX := storage.NewMockLogTreeTX(ctrl)
+	logTX.EXPECT().GetOldestQueueTimestamp(any).Return(fakeTimeForTest.Add(-40*time.Minute), nil)
+	logTX.EXPECT().LatestSignedLogRoot(any).Return(testRoot16, nil)
+	logTX.EXPECT().GetLeavesByRange(any, testRoot16.TreeSize, int64(21)).Return(nil, nil)
+	logTX.EXPECT().DequeueLeaves(any, 20, any).Return(nil, nil)
+	logTX.EXPECT().Commit().Return(nil)
+	logTX.EXPECT().Close().Return(nil)
+	logStorage := storage.NewMockLogStorage(ctrl)
+	logStorage.EXPECT().BeginForTree(any, any).Return(logTX, nil)
+
+	sequencer := NewSequencer(rfc6962.DefaultHasher, util.NewFakeTimeSource(fakeTimeForTest), logStorage, crypto.NewSHA256Signer(signer), nil, quota.Noop(), nil /* treeCache */)
+	sequencer.SetBatchSizer(NewBatchSizer(), 2)
+	if _, err := sequencer.SequenceBatch(context.Background(), stestonly.LogTree, 10, 0, 0, time.Hour); err != nil {
+		t.Fatalf("SequenceBatch(): %v", err)
+	}
+}
+
+// countingLogStorage counts the GetMerkleNodes calls made through its transactions.
+type countingLogStorage struct {
+	storage.LogStorage
+	reads *int
+}
+
+func (s countingLogStorage) BeginForTree(ctx context.Context, treeID int64) (storage.LogTreeTX, error) {
+	tx, err := s.LogStorage.BeginForTree(ctx, treeID)
+	return countingLogTreeTX{tx, s.reads}, err
+}
+
+type countingLogTreeTX struct {
+	storage.LogTreeTX
+	reads *int
+}
+
+func (t countingLogTreeTX) GetMerkleNodes(ctx context.Context, rev int64, ids []storage.NodeID) ([]storage.Node, error) {
+	*t.reads++
+	return t.LogTreeTX.GetMerkleNodes(ctx, rev, ids)
+}
+
+func TestSequenceBatchReusesCachedTree(t *testing.T) {
+	ctx := context.Background()
+	cryptoSigner, err := newSignerWithFixedSig(expectedSignedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create test signer (%v)", err)
+	}
+
+	for _, test := range []struct {
+		desc      string
+		treeCache *CompactTreeCache
+		// dropAfter, if positive, is the pass after which the cached tree is dropped.
+		dropAfter int
+		wantReads int
+	}{
+		{desc: "noCache", wantReads: 2},
+		{desc: "cache", treeCache: NewCompactTreeCache()},
+		{desc: "dropped", treeCache: NewCompactTreeCache(), dropAfter: 2, wantReads: 1},
+	} {
+		ls := memory.NewLogStorage(nil)
+		atx, err := memory.NewAdminStorage(ls).Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin(): %v", err)
+		}
+		tree, err := atx.CreateTree(ctx, stestonly.LogTree)
+		if err != nil {
+			t.Fatalf("CreateTree(): %v", err)
+		}
+		if err := atx.Commit(); err != nil {
+			t.Fatalf("Commit(): %v", err)
+		}
+
+		hasher := rfc6962.DefaultHasher
+		leaves := make([]*trillian.LogLeaf, 7)
+		for i := range leaves {
+			value := []byte(fmt.Sprintf("leaf-%d", i))
+			leafHash := hasher.HashLeaf(value)
+			leaves[i] = &trillian.LogLeaf{LeafIdentityHash: leafHash, MerkleLeafHash: leafHash, LeafValue: value}
+		}
+		tx, err := ls.BeginForTree(ctx, tree.TreeId)
+		if err != nil {
+			t.Fatalf("BeginForTree(): %v", err)
+		}
+		if _, err := tx.QueueLeaves(ctx, leaves, fakeTimeForTest.Add(-time.Minute)); err != nil {
+			t.Fatalf("QueueLeaves(): %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit(): %v", err)
+		}
+
+		reads := 0
+		cls := countingLogStorage{ls, &reads}
+		ts := util.NewFakeTimeSource(fakeTimeForTest)
+		sequencer := NewSequencer(hasher, ts, cls, crypto.NewSHA256Signer(cryptoSigner), nil, quota.Noop(), test.treeCache)
+		// The first pass only creates the signed root of the empty tree, and the following
+		// ones grow the tree to sizes 3, 5 and 7. Only the last two need stored nodes.
+		for i, limit := range []int{1, 3, 2, 2} {
+			if _, err := sequencer.SequenceBatch(ctx, tree, limit, 0, 0, 0); err != nil {
+				t.Fatalf("%v: SequenceBatch(): %v", test.desc, err)
+			}
+			if i+1 == test.dropAfter {
+				test.treeCache.Drop(tree.TreeId)
+			}
+		}
+		if reads != test.wantReads {
+			t.Errorf("%v: GetMerkleNodes() called %d times, want %d", test.desc, reads, test.wantReads)
+		}
+	}
+}
+
 func TestSignRoot(t *testing.T) {
 	signer0, err := newSignerWithFixedSig(expectedSignedRoot0.Signature)
 	if err != nil {
@@ -0,0 +1,76 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle"
+)
+
+// CompactTreeCache holds the compact Merkle tree of each log as of the last root this
+// instance signed for it, so that a signer which stays master for a log doesn't have to
+// reload the tree from storage on every pass.
+// A cached tree is only used if it still matches the log's stored root: any other writer
+// would have moved the root on to a new revision.
+type CompactTreeCache struct {
+	mu    sync.Mutex
+	trees map[int64]cachedTree
+}
+
+type cachedTree struct {
+	revision int64
+	tree     *merkle.CompactMerkleTree
+}
+
+// NewCompactTreeCache creates an empty CompactTreeCache.
+func NewCompactTreeCache() *CompactTreeCache {
+	return &CompactTreeCache{trees: make(map[int64]cachedTree)}
+}
+
+// Take removes the cached tree of logID from the cache and returns it, provided it is the
+// tree for root. Otherwise it returns nil.
+// The caller is expected to modify the returned tree, and to Put it back once the new
+// state is committed.
+func (c *CompactTreeCache) Take(logID int64, root trillian.SignedLogRoot) *merkle.CompactMerkleTree {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.trees[logID]
+	if !ok {
+		return nil
+	}
+	delete(c.trees, logID)
+	if cached.revision != root.TreeRevision || cached.tree.Size() != root.TreeSize || !bytes.Equal(cached.tree.CurrentRoot(), root.RootHash) {
+		return nil
+	}
+	return cached.tree
+}
+
+// Put caches tree as the state of logID at root.
+func (c *CompactTreeCache) Put(logID int64, root trillian.SignedLogRoot, tree *merkle.CompactMerkleTree) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trees[logID] = cachedTree{revision: root.TreeRevision, tree: tree}
+}
+
+// Drop removes the cached tree of logID, if any. It should be called when mastership for
+// the log is lost.
+func (c *CompactTreeCache) Drop(logID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.trees, logID)
+}
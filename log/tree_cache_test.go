@@ -0,0 +1,62 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+)
+
+func TestCompactTreeCache(t *testing.T) {
+	const logID = 12345
+	tree := merkle.NewCompactMerkleTree(rfc6962.DefaultHasher)
+	for i := 0; i < 3; i++ {
+		if _, _, err := tree.AddLeaf([]byte{byte(i)}, func(int, int64, []byte) error { return nil }); err != nil {
+			t.Fatalf("AddLeaf(): %v", err)
+		}
+	}
+	root := trillian.SignedLogRoot{TreeSize: 3, TreeRevision: 7, RootHash: tree.CurrentRoot()}
+
+	for _, test := range []struct {
+		desc     string
+		logID    int64
+		root     trillian.SignedLogRoot
+		drop     bool
+		wantTree bool
+	}{
+		{desc: "match", logID: logID, root: root, wantTree: true},
+		{desc: "otherLog", logID: logID + 1, root: root},
+		{desc: "newerRevision", logID: logID, root: trillian.SignedLogRoot{TreeSize: 3, TreeRevision: 8, RootHash: root.RootHash}},
+		{desc: "otherSize", logID: logID, root: trillian.SignedLogRoot{TreeSize: 4, TreeRevision: 7, RootHash: root.RootHash}},
+		{desc: "otherHash", logID: logID, root: trillian.SignedLogRoot{TreeSize: 3, TreeRevision: 7, RootHash: []byte("other")}},
+		{desc: "dropped", logID: logID, root: root, drop: true},
+	} {
+		c := NewCompactTreeCache()
+		c.Put(logID, root, tree)
+		if test.drop {
+			c.Drop(logID)
+		}
+		if got := c.Take(test.logID, test.root); (got != nil) != test.wantTree {
+			t.Errorf("%v: Take() = %v, want tree: %v", test.desc, got, test.wantTree)
+		}
+		// Take always removes the log's entry.
+		if got := c.Take(test.logID, test.root); got != nil {
+			t.Errorf("%v: second Take() = %v, want nil", test.desc, got)
+		}
+	}
+}
@@ -57,17 +57,18 @@ func bitLen(x int64) int {
 	return r
 }
 
-// GetNodeFunc is a function prototype which can look up particular nodes within a non-compact Merkle tree.
+// GetNodesFunc is a function prototype which can look up a batch of nodes within a non-compact Merkle tree.
 // Used by the CompactMerkleTree to populate itself with correct state when starting up with a non-empty tree.
-type GetNodeFunc func(depth int, index int64) ([]byte, error)
+// depths and indices hold the co-ordinates of the nodes, and the hashes must be returned in the same order.
+type GetNodesFunc func(depths []int, indices []int64) ([][]byte, error)
 
 // NewCompactMerkleTreeWithState creates a new CompactMerkleTree for the passed in |size|.
 // This can fail if the nodes required to recreate the tree state cannot be fetched or the calculated
 // root hash after population does not match the value we expect.
-// |f| will be called a number of times with the co-ordinates of internal MerkleTree nodes whose hash values are
+// |f| will be called once with the co-ordinates of all the internal MerkleTree nodes whose hash values are
 // required to initialize the internal state of the CompactMerkleTree.  |expectedRoot| is the known-good tree root
 // of the tree at |size|, and is used to verify the correct initial state of the CompactMerkleTree after initialisation.
-func NewCompactMerkleTreeWithState(hasher hashers.LogHasher, size int64, f GetNodeFunc, expectedRoot []byte) (*CompactMerkleTree, error) {
+func NewCompactMerkleTreeWithState(hasher hashers.LogHasher, size int64, f GetNodesFunc, expectedRoot []byte) (*CompactMerkleTree, error) {
 	sizeBits := bitLen(size)
 
 	r := CompactMerkleTree{
@@ -83,19 +84,27 @@ func NewCompactMerkleTreeWithState(hasher hashers.LogHasher, size int64, f GetNo
 		r.nodes[sizeBits-1] = r.root
 	} else {
 		// Pull in the nodes we need to repopulate our compact tree and verify the root
+		var depths []int
+		var indices []int64
 		for depth := 0; depth < sizeBits; depth++ {
 			if size&1 == 1 {
-				index := size - 1
-				log.V(1).Infof("fetching d: %d i: %d, leaving size %d", depth, index, size)
-				h, err := f(depth, index)
-				if err != nil {
-					log.Warningf("Failed to fetch node depth %d index %d: %s", depth, index, err)
-					return nil, err
-				}
-				r.nodes[depth] = h
+				depths = append(depths, depth)
+				indices = append(indices, size-1)
 			}
 			size >>= 1
 		}
+		log.V(1).Infof("fetching d: %v i: %v", depths, indices)
+		hashes, err := f(depths, indices)
+		if err != nil {
+			log.Warningf("Failed to fetch nodes depths %v indices %v: %s", depths, indices, err)
+			return nil, err
+		}
+		if got, want := len(hashes), len(depths); got != want {
+			return nil, fmt.Errorf("fetched %d node hashes, want %d", got, want)
+		}
+		for i, depth := range depths {
+			r.nodes[depth] = hashes[i]
+		}
 		r.recalculateRoot(func(depth int, index int64, hash []byte) error {
 			return nil
 		})
@@ -19,6 +19,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -32,7 +33,7 @@ func checkUnusedNodesInvariant(c *CompactMerkleTree) error {
 	// The structure of this invariant check mirrors the structure in
 	// NewCompactMerkleTreeWithState in which only the nodes which
 	// should be present for a tree of given size are fetched from the
-	// backing store via GetNodeFunc.
+	// backing store via GetNodesFunc.
 	size := c.size
 	sizeBits := bitLen(size)
 	if isPerfectTree(size) {
@@ -162,18 +163,22 @@ func TestAddingLeaves(t *testing.T) {
 	}
 }
 
-func failingGetNodeFunc(int, int64) ([]byte, error) {
-	return []byte{}, errors.New("bang")
+func failingGetNodesFunc([]int, []int64) ([][]byte, error) {
+	return nil, errors.New("bang")
 }
 
 // This returns something that won't result in a valid root hash match, doesn't really
 // matter what it is but it must be correct length for an SHA256 hash as if it was real
-func fixedHashGetNodeFunc(int, int64) ([]byte, error) {
-	return []byte("12345678901234567890123456789012"), nil
+func fixedHashGetNodesFunc(depths []int, _ []int64) ([][]byte, error) {
+	hashes := make([][]byte, len(depths))
+	for i := range hashes {
+		hashes[i] = []byte("12345678901234567890123456789012")
+	}
+	return hashes, nil
 }
 
 func TestLoadingTreeFailsNodeFetch(t *testing.T) {
-	_, err := NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, failingGetNodeFunc, []byte("notimportant"))
+	_, err := NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, failingGetNodesFunc, []byte("notimportant"))
 
 	if err == nil || !strings.Contains(err.Error(), "bang") {
 		t.Errorf("Did not return correctly on failed node fetch: %v", err)
@@ -183,7 +188,7 @@ func TestLoadingTreeFailsNodeFetch(t *testing.T) {
 func TestLoadingTreeFailsBadRootHash(t *testing.T) {
 	// Supply a root hash that can't possibly match the result of the SHA 256 hashing on our dummy
 	// data
-	_, err := NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, fixedHashGetNodeFunc, []byte("nomatch!nomatch!nomatch!nomatch!"))
+	_, err := NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, fixedHashGetNodesFunc, []byte("nomatch!nomatch!nomatch!nomatch!"))
 	_, ok := err.(RootHashMismatchError)
 
 	if err == nil || !ok {
@@ -191,6 +196,35 @@ func TestLoadingTreeFailsBadRootHash(t *testing.T) {
 	}
 }
 
+func TestLoadingTreeFetchesNodesInOneBatch(t *testing.T) {
+	calls := 0
+	f := func(depths []int, indices []int64) ([][]byte, error) {
+		calls++
+		// 237 = 0b11101101
+		if want := []int{0, 2, 3, 5, 6, 7}; !reflect.DeepEqual(depths, want) {
+			t.Errorf("depths: %v, want %v", depths, want)
+		}
+		if want := []int64{236, 58, 28, 6, 2, 0}; !reflect.DeepEqual(indices, want) {
+			t.Errorf("indices: %v, want %v", indices, want)
+		}
+		return fixedHashGetNodesFunc(depths, indices)
+	}
+	NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, f, []byte("notimportant"))
+	if calls != 1 {
+		t.Errorf("GetNodesFunc called %d times, want 1", calls)
+	}
+}
+
+func TestLoadingTreeFailsShortNodeFetch(t *testing.T) {
+	f := func(depths []int, indices []int64) ([][]byte, error) {
+		hashes, err := fixedHashGetNodesFunc(depths, indices)
+		return hashes[1:], err
+	}
+	if _, err := NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, 237, f, []byte("notimportant")); err == nil {
+		t.Error("Did not fail when too few nodes were fetched")
+	}
+}
+
 func nodeKey(d int, i int64) (string, error) {
 	n, err := storage.NewNodeIDForTreeCoords(int64(d), i, 64)
 	if err != nil {
@@ -207,13 +241,16 @@ func TestCompactVsFullTree(t *testing.T) {
 		cmt, err := NewCompactMerkleTreeWithState(
 			rfc6962.DefaultHasher,
 			imt.LeafCount(),
-			func(depth int, index int64) ([]byte, error) {
-				k, err := nodeKey(depth, index)
-				if err != nil {
-					t.Errorf("failed to create nodeID: %v", err)
+			func(depths []int, indices []int64) ([][]byte, error) {
+				hashes := make([][]byte, len(depths))
+				for i := range depths {
+					k, err := nodeKey(depths[i], indices[i])
+					if err != nil {
+						t.Errorf("failed to create nodeID: %v", err)
+					}
+					hashes[i] = nodes[k]
 				}
-				h := nodes[k]
-				return h, nil
+				return hashes, nil
 			}, imt.CurrentRoot().Hash())
 
 		if err != nil {
@@ -59,7 +59,14 @@ func NewHStar2(treeID int64, hasher hashers.MapHasher) HStar2 {
 // which contains the given set of non-null leaves.
 func (s *HStar2) HStar2Root(depth int, values []HStar2LeafHash) ([]byte, error) {
 	sort.Sort(ByIndex{values})
-	return s.hStar2b(0, depth, values, smtZero, nil, nil)
+	root, err := s.hStar2b(0, depth, values, smtZero, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return s.hashEmpty(smtZero, 0), nil
+	}
+	return root, nil
 }
 
 // SparseGetNodeFunc should return any pre-existing node hash for the node address.
@@ -73,9 +80,27 @@ type SparseSetNodeFunc func(depth int, index *big.Int, hash []byte) error
 // internal node values. Values must not contain multiple leaves for the same
 // index.
 //
+// Values with a nil LeafHash delete the leaf at their index. Empty nodes are
+// represented by nil hashes: get may return nil for nodes that don't exist, and
+// set is called with a nil hash for nodes that became empty.
+//
 // prefix is the location of this subtree within the larger tree. Root is at nil.
 // subtreeDepth is the number of levels in this subtree.
 func (s *HStar2) HStar2Nodes(prefix []byte, subtreeDepth int, values []HStar2LeafHash,
+	get SparseGetNodeFunc, set SparseSetNodeFunc) ([]byte, error) {
+	root, err := s.hStar2Nodes(prefix, subtreeDepth, values, get, set)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		offset := storage.NewNodeIDFromPrefixSuffix(prefix, storage.Suffix{}, s.hasher.BitLen()).BigInt()
+		return s.hashEmpty(offset, len(prefix)*8), nil
+	}
+	return root, nil
+}
+
+// hStar2Nodes is like HStar2Nodes, but returns nil if the subtree is empty.
+func (s *HStar2) hStar2Nodes(prefix []byte, subtreeDepth int, values []HStar2LeafHash,
 	get SparseGetNodeFunc, set SparseSetNodeFunc) ([]byte, error) {
 	if glog.V(3) {
 		glog.Infof("HStar2Nodes(%x, %v, %v)", prefix, subtreeDepth, len(values))
@@ -94,6 +119,8 @@ func (s *HStar2) HStar2Nodes(prefix []byte, subtreeDepth int, values []HStar2Lea
 }
 
 // hStar2b computes a sparse Merkle tree root value recursively.
+// Empty subtrees have a nil root, except for the root of the whole tree, which
+// is always set.
 func (s *HStar2) hStar2b(depth, maxDepth int, values []HStar2LeafHash, offset *big.Int,
 	get SparseGetNodeFunc, set SparseSetNodeFunc) ([]byte, error) {
 	if depth == maxDepth {
@@ -122,28 +149,47 @@ func (s *HStar2) hStar2b(depth, maxDepth int, values []HStar2LeafHash, offset *b
 	if err != nil {
 		return nil, err
 	}
-	h := s.hasher.HashChildren(lhs, rhs)
-	s.set(offset, depth, h, set)
+
+	var h []byte
+	switch {
+	case lhs == nil && rhs == nil && depth > 0:
+		// Both children are empty (i.e. their leaves were deleted), thus so is
+		// this node.
+	case lhs == nil && rhs == nil:
+		h = s.hashEmpty(offset, depth)
+	default:
+		// Since empty values are tied to a location and a level,
+		// HashEmpty(level1) != HashChildren(E0, E0), so empty children are only
+		// hashed when they have a non-empty sibling.
+		if lhs == nil {
+			lhs = s.hashEmpty(offset, depth+1)
+		}
+		if rhs == nil {
+			rhs = s.hashEmpty(split, depth+1)
+		}
+		h = s.hasher.HashChildren(lhs, rhs)
+	}
+	if err := s.set(offset, depth, h, set); err != nil {
+		return nil, err
+	}
 	return h, nil
 }
 
-// get attempts to use getter. If getter fails, returns the HashEmpty value.
+// get attempts to use getter. Returns nil if getter isn't set or the node
+// doesn't exist.
 func (s *HStar2) get(index *big.Int, depth int, getter SparseGetNodeFunc) ([]byte, error) {
-	// if we've got a function for getting existing node values, try it:
-	if getter != nil {
-		h, err := getter(depth, index)
-		if err != nil {
-			return nil, err
-		}
-		// if we got a value then we'll use that
-		if h != nil {
-			return h, nil
-		}
+	if getter == nil {
+		return nil, nil
 	}
+	return getter(depth, index)
+}
+
+// hashEmpty returns the HashEmpty value of the node at index and depth.
+func (s *HStar2) hashEmpty(index *big.Int, depth int) []byte {
 	// TODO(gdbelvin): Hashers should accept depth as their main argument.
 	height := s.hasher.BitLen() - depth
 	nodeID := storage.NewNodeIDFromBigInt(index.BitLen(), index, s.hasher.BitLen())
-	return s.hasher.HashEmpty(s.treeID, nodeID.Path, height), nil
+	return s.hasher.HashEmpty(s.treeID, nodeID.Path, height)
 }
 
 // set attempts to use setter if it not nil.
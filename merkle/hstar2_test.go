@@ -20,6 +20,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/google/trillian/merkle/coniks"
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/merkle/maphasher"
 	"github.com/google/trillian/storage"
@@ -126,6 +127,69 @@ func TestHStar2GetSet(t *testing.T) {
 	}
 }
 
+// TestHStar2Delete ensures that deleting leaves from a stored tree results in
+// the same root as building the tree without them.
+func TestHStar2Delete(t *testing.T) {
+	for _, hasher := range []hashers.MapHasher{maphasher.Default, coniks.Default} {
+		// Node cache is shared between tree builds and in effect plays the
+		// role of the TreeStorage layer.
+		cache := make(map[string][]byte)
+		s := NewHStar2(treeID, hasher)
+		update := func(values []HStar2LeafHash) ([]byte, error) {
+			return s.HStar2Nodes(nil, hasher.BitLen(), values,
+				func(depth int, index *big.Int) ([]byte, error) {
+					return cache[fmt.Sprintf("%x/%d", index, depth)], nil
+				},
+				func(depth int, index *big.Int, hash []byte) error {
+					key := fmt.Sprintf("%x/%d", index, depth)
+					if hash == nil {
+						delete(cache, key)
+						return nil
+					}
+					cache[key] = hash
+					return nil
+				})
+		}
+		deleted := func(index []byte) []HStar2LeafHash {
+			return []HStar2LeafHash{{Index: new(big.Int).SetBytes(index)}}
+		}
+
+		a, b, c := testonly.HashKey("a"), testonly.HashKey("b"), testonly.HashKey("c")
+		if _, err := update(createHStar2Leaves(treeID, hasher, a, []byte("0"), b, []byte("1"), c, []byte("2"))); err != nil {
+			t.Fatalf("%T: Failed to add leaves: %v", hasher, err)
+		}
+
+		for _, test := range []struct {
+			desc    string
+			delete  []byte
+			wantIVs [][]byte
+		}{
+			{desc: "deleteB", delete: b, wantIVs: [][]byte{a, []byte("0"), c, []byte("2")}},
+			{desc: "deleteBAgain", delete: b, wantIVs: [][]byte{a, []byte("0"), c, []byte("2")}},
+			{desc: "deleteA", delete: a, wantIVs: [][]byte{c, []byte("2")}},
+			{desc: "deleteC", delete: c},
+		} {
+			root, err := update(deleted(test.delete))
+			if err != nil {
+				t.Errorf("%T: %v: Failed to calculate root: %v", hasher, test.desc, err)
+				continue
+			}
+			want, err := s.HStar2Root(hasher.BitLen(), createHStar2Leaves(treeID, hasher, test.wantIVs...))
+			if err != nil {
+				t.Fatalf("%T: %v: HStar2Root(): %v", hasher, test.desc, err)
+			}
+			if !bytes.Equal(root, want) {
+				t.Errorf("%T: %v: root: %x, want: %x", hasher, test.desc, root, want)
+			}
+		}
+
+		// Only the root of the (now empty) tree is left.
+		if got, want := len(cache), 1; got != want {
+			t.Errorf("%T: %v nodes left, want %v", hasher, got, want)
+		}
+	}
+}
+
 // Create intermediate "root" values for the passed in HStar2LeafHashes.
 // These "root" hashes are from (assumed distinct) subtrees of size
 // 256-prefixSize, and can be passed in as leaves to top-subtree calculation.
@@ -0,0 +1,62 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/google/trillian/merkle/hashers"
+)
+
+// CompressMapInclusionProof drops the empty elements of a map inclusion proof.
+// Bit i of the returned bitmap (bit i%8 of byte i/8) is set iff proof[i] is
+// non-empty, and hashes holds the non-empty elements in order.
+func CompressMapInclusionProof(proof [][]byte) (bitmap []byte, hashes [][]byte) {
+	bitmap = make([]byte, (len(proof)+7)/8)
+	hashes = make([][]byte, 0)
+	for i, p := range proof {
+		if len(p) == 0 {
+			continue
+		}
+		bitmap[i/8] |= 1 << uint(i%8)
+		hashes = append(hashes, p)
+	}
+	return bitmap, hashes
+}
+
+// DecompressMapInclusionProof reverses CompressMapInclusionProof. The empty
+// elements are left empty, and VerifyMapInclusionProof computes them with
+// h.HashEmpty.
+func DecompressMapInclusionProof(bitmap []byte, hashes [][]byte, h hashers.MapHasher) ([][]byte, error) {
+	if got, want := len(bitmap)*8, h.BitLen(); got != want {
+		return nil, fmt.Errorf("bitmap len: %d bits, want %d", got, want)
+	}
+	proof := make([][]byte, h.BitLen())
+	next := 0
+	for i := range proof {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		if next >= len(hashes) {
+			return nil, fmt.Errorf("bitmap has more than %d bits set", len(hashes))
+		}
+		proof[i] = hashes[next]
+		next++
+	}
+	if next != len(hashes) {
+		return nil, fmt.Errorf("bitmap has %d bits set, want %d", next, len(hashes))
+	}
+	return proof, nil
+}
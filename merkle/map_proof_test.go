@@ -0,0 +1,70 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkle
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/trillian/merkle/maphasher"
+)
+
+func TestCompressMapInclusionProof(t *testing.T) {
+	h := maphasher.Default
+	proof := make([][]byte, h.BitLen())
+	proof[0] = []byte("zero")
+	proof[9] = []byte("nine")
+	proof[255] = []byte("last")
+
+	bitmap, hashes := CompressMapInclusionProof(proof)
+	wantBitmap := make([]byte, 32)
+	wantBitmap[0] = 0x01
+	wantBitmap[1] = 0x02
+	wantBitmap[31] = 0x80
+	if !bytes.Equal(bitmap, wantBitmap) {
+		t.Errorf("CompressMapInclusionProof(): bitmap %x, want %x", bitmap, wantBitmap)
+	}
+	if want := [][]byte{proof[0], proof[9], proof[255]}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("CompressMapInclusionProof(): hashes %s, want %s", hashes, want)
+	}
+
+	got, err := DecompressMapInclusionProof(bitmap, hashes, h)
+	if err != nil {
+		t.Fatalf("DecompressMapInclusionProof(): %v", err)
+	}
+	if !reflect.DeepEqual(got, proof) {
+		t.Errorf("DecompressMapInclusionProof(): %s, want %s", got, proof)
+	}
+}
+
+func TestDecompressMapInclusionProofErrors(t *testing.T) {
+	h := maphasher.Default
+	bitmap := make([]byte, 32)
+	bitmap[0] = 0x03
+	for _, tc := range []struct {
+		desc   string
+		bitmap []byte
+		hashes [][]byte
+	}{
+		{desc: "short bitmap", bitmap: bitmap[:31], hashes: [][]byte{{1}, {2}}},
+		{desc: "too few hashes", bitmap: bitmap, hashes: [][]byte{{1}}},
+		{desc: "too many hashes", bitmap: bitmap, hashes: [][]byte{{1}, {2}, {3}}},
+	} {
+		if _, err := DecompressMapInclusionProof(tc.bitmap, tc.hashes, h); err == nil {
+			t.Errorf("%v: DecompressMapInclusionProof(): nil, want error", tc.desc)
+		}
+	}
+}
@@ -28,8 +28,32 @@ import (
 // The process is essentially the same as the inclusion proof checking for
 // append-only logs, but adds support for nil/"default" proof nodes.
 //
+// An empty leaf stands for an absent leaf, which is verified the same way as
+// by VerifyMapNonInclusionProof. Map servers reject empty leaf values, so
+// every written leaf has a non-empty value.
+//
 // Returns nil on a successful verification, and an error otherwise.
 func VerifyMapInclusionProof(treeID int64, index, leaf, expectedRoot []byte, proof [][]byte, h hashers.MapHasher) error {
+	var leafHash []byte
+	if len(leaf) != 0 {
+		leafHash = h.HashLeaf(treeID, index, leaf)
+	}
+	return verifyMapProof(treeID, index, leafHash, expectedRoot, proof, h)
+}
+
+// VerifyMapNonInclusionProof verifies that index holds the empty value in the
+// map with root expectedRoot, i.e. that there is no leaf at index.
+//
+// Returns nil on a successful verification, and an error otherwise.
+func VerifyMapNonInclusionProof(treeID int64, index, expectedRoot []byte, proof [][]byte, h hashers.MapHasher) error {
+	// An absent leaf has no leaf hash; it is verified against the empty
+	// branch computed with h.HashEmpty.
+	return verifyMapProof(treeID, index, nil, expectedRoot, proof, h)
+}
+
+// verifyMapProof recomputes the root from leafHash at index and proof, and
+// checks it against expectedRoot. A nil leafHash stands for the empty leaf.
+func verifyMapProof(treeID int64, index, leafHash, expectedRoot []byte, proof [][]byte, h hashers.MapHasher) error {
 	if got, want := len(index)*8, h.BitLen(); got != want {
 		return fmt.Errorf("index len: %d, want %d", got, want)
 	}
@@ -42,10 +66,7 @@ func VerifyMapInclusionProof(treeID int64, index, leaf, expectedRoot []byte, pro
 		}
 	}
 
-	var runningHash []byte
-	if len(leaf) != 0 {
-		runningHash = h.HashLeaf(treeID, index, leaf)
-	}
+	runningHash := leafHash
 
 	nID := storage.NewNodeIDFromHash(index)
 	for height, sib := range nID.Siblings() {
@@ -17,6 +17,7 @@ package merkle
 import (
 	"testing"
 
+	"github.com/google/trillian/merkle/coniks"
 	"github.com/google/trillian/merkle/maphasher"
 	"github.com/google/trillian/testonly"
 )
@@ -99,3 +100,47 @@ func TestMapHasherTestVectors(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyMapNonInclusionProof(t *testing.T) {
+	h := maphasher.Default
+	index := testonly.HashKey("key-0-848")
+	emptyRoot := h.HashEmpty(treeID, make([]byte, h.Size()), h.BitLen())
+	emptyProof := make([][]byte, h.BitLen())
+	otherProof := make([][]byte, h.BitLen())
+	otherProof[0] = h.HashLeaf(treeID, index, []byte("value"))
+
+	for _, tc := range []struct {
+		desc  string
+		root  []byte
+		proof [][]byte
+		want  bool
+	}{
+		{"empty map", emptyRoot, emptyProof, true},
+		{"incorrect root", []byte("w"), emptyProof, false},
+		{"incorrect proof", emptyRoot, otherProof, false},
+		{"short proof", emptyRoot, emptyProof[1:], false},
+	} {
+		err := VerifyMapNonInclusionProof(treeID, index, tc.root, tc.proof, h)
+		if got := err == nil; got != tc.want {
+			t.Errorf("%v: VerifyMapNonInclusionProof(): %v, want %v", tc.desc, err, tc.want)
+		}
+	}
+}
+
+func TestVerifyMapInclusionProofEmptyValue(t *testing.T) {
+	// The CONIKS hasher distinguishes an empty value from an absent leaf.
+	h := coniks.Default
+	index := testonly.HashKey("key-0-848")
+	emptyRoot := h.HashEmpty(treeID, make([]byte, h.Size()), h.BitLen())
+	emptyProof := make([][]byte, h.BitLen())
+
+	// An empty value stands for an absent leaf, so it is included in the empty map.
+	for _, leaf := range [][]byte{nil, {}} {
+		if err := VerifyMapInclusionProof(treeID, index, leaf, emptyRoot, emptyProof, h); err != nil {
+			t.Errorf("VerifyMapInclusionProof(%#v, empty map): %v", leaf, err)
+		}
+	}
+	if err := VerifyMapNonInclusionProof(treeID, index, emptyRoot, emptyProof, h); err != nil {
+		t.Errorf("VerifyMapNonInclusionProof(empty map): %v", err)
+	}
+}
@@ -56,7 +56,7 @@ type indexAndHash struct {
 
 // rootHashOrError represents a (sub-)tree root hash, or an error which
 // prevented the calculation from completing.
-// TODO(gdbelvin): represent an empty subtree with a nil hash?
+// Empty subtrees have a nil hash.
 type rootHashOrError struct {
 	hash []byte
 	err  error
@@ -68,6 +68,7 @@ type rootHashOrError struct {
 // and dropped in.
 type Subtree interface {
 	// SetLeaf sets a single leaf hash for integration into a sparse Merkle tree.
+	// A nil hash deletes the leaf.
 	SetLeaf(ctx context.Context, index []byte, hash []byte) error
 
 	// CalculateRoot instructs the subtree worker to start calculating the root
@@ -227,7 +228,8 @@ func (s *subtreeWriter) buildSubtree(ctx context.Context) {
 
 	// calculate new root, and intermediate nodes:
 	hs2 := NewHStar2(s.treeID, s.hasher)
-	root, err := hs2.HStar2Nodes(s.prefix, s.subtreeDepth, leaves,
+	// Empty subtrees have a nil root, so they're also empty in the parent.
+	root, err := hs2.hStar2Nodes(s.prefix, s.subtreeDepth, leaves,
 		func(depth int, index *big.Int) ([]byte, error) {
 			nodeID := storage.NewNodeIDFromBigInt(depth, index, s.hasher.BitLen())
 			glog.V(4).Infof("buildSubtree.get(%x, %d) nid: %x, %v",
@@ -268,6 +270,9 @@ func (s *subtreeWriter) buildSubtree(ctx context.Context) {
 		s.root <- rootHashOrError{nil, err}
 		return
 	}
+	if root == nil && len(s.prefix) == 0 {
+		root = hs2.hashEmpty(smtZero, 0)
+	}
 
 	// write nodes back to storage
 	if err := s.tx.SetMerkleNodes(ctx, nodesToStore); err != nil {
@@ -384,42 +389,72 @@ func (s SparseMerkleTreeReader) RootAtRevision(ctx context.Context, rev int64) (
 // specified key at the specified revision.
 // If the revision does not exist it will return ErrNoSuchRevision error.
 func (s SparseMerkleTreeReader) InclusionProof(ctx context.Context, rev int64, index []byte) ([][]byte, error) {
-	nid := storage.NewNodeIDFromHash(index)
-	sibs := nid.Siblings()
-	nodes, err := s.tx.GetMerkleNodes(ctx, rev, sibs)
+	proofs, err := s.BatchInclusionProof(ctx, rev, [][]byte{index})
+	if err != nil {
+		return nil, err
+	}
+	return proofs[0], nil
+}
+
+// BatchInclusionProof returns inclusion (or non-inclusion) proofs for the
+// specified keys at the specified revision, in the same order as indices.
+// The nodes of all proofs are fetched from storage with a single read, so
+// nodes shared between proofs (e.g. those near the root) are only read once.
+// If the revision does not exist it will return ErrNoSuchRevision error.
+func (s SparseMerkleTreeReader) BatchInclusionProof(ctx context.Context, rev int64, indices [][]byte) ([][][]byte, error) {
+	// Collect the deduplicated set of nodes needed by all proofs.
+	sibs := make([][]storage.NodeID, len(indices))
+	ids := make([]storage.NodeID, 0)
+	wanted := make(map[string]bool)
+	for i, index := range indices {
+		nid := storage.NewNodeIDFromHash(index)
+		sibs[i] = nid.Siblings()
+		for _, id := range sibs[i] {
+			if k := id.String(); !wanted[k] {
+				wanted[k] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	nodes, err := s.tx.GetMerkleNodes(ctx, rev, ids)
 	if err != nil {
 		return nil, err
 	}
 
 	nodeMap := make(map[string]*storage.Node)
-	glog.Infof("Got Nodes: ")
 	for _, n := range nodes {
 		n := n // need this or we'll end up with the same node hash repeated in the map
-		glog.Infof("   %x, %d: %x", n.NodeID.Path, len(n.NodeID.String()), n.Hash)
+		glog.V(2).Infof("   %x, %d: %x", n.NodeID.Path, len(n.NodeID.String()), n.Hash)
 		nodeMap[n.NodeID.String()] = &n
 	}
 
-	// We're building a full proof from a combination of whichever nodes we got
+	// We're building full proofs from a combination of whichever nodes we got
 	// back from the storage layer, and the set of "null" hashes.
-	r := make([][]byte, len(sibs), len(sibs))
-	// For each proof element:
-	for i := 0; i < len(r); i++ {
-		proofID := sibs[i]
-		pNode := nodeMap[proofID.String()]
-		if pNode == nil {
-			// we have no node for this level from storage, so the client will use
-			// the null hash.
-			continue
+	used := make(map[string]bool)
+	proofs := make([][][]byte, len(indices))
+	for i := range indices {
+		r := make([][]byte, len(sibs[i]))
+		// For each proof element:
+		for j, proofID := range sibs[i] {
+			k := proofID.String()
+			pNode := nodeMap[k]
+			if pNode == nil {
+				// we have no node for this level from storage, so the client will use
+				// the null hash.
+				continue
+			}
+			r[j] = pNode.Hash
+			used[k] = true
 		}
-		r[i] = pNode.Hash
-		delete(nodeMap, proofID.String())
+		proofs[i] = r
 	}
 
 	// Make sure we used up all the returned nodes, otherwise something's gone wrong.
-	if remaining := len(nodeMap); remaining != 0 {
+	if remaining := len(nodeMap) - len(used); remaining != 0 {
 		return nil, fmt.Errorf("failed to consume all returned nodes; got %d nodes, but %d remain(s) unused", len(nodes), remaining)
 	}
-	return r, nil
+	return proofs, nil
 }
 
 // SetLeaves adds a batch of leaves to the in-flight tree update.
@@ -443,6 +478,7 @@ type HashKeyValue struct {
 	// HashedKey is the hash of the key data
 	HashedKey []byte
 
-	// HashedValue is the hash of the value data.
+	// HashedValue is the hash of the value data. A nil HashedValue deletes the
+	// leaf, resetting it to its HashEmpty value.
 	HashedValue []byte
 }
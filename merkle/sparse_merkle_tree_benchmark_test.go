@@ -0,0 +1,158 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkle_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/testonly"
+
+	_ "github.com/google/trillian/merkle/maphasher" // TEST_MAP_HASHER
+)
+
+// roundTrip is the simulated latency of a storage read.
+const roundTrip = 100 * time.Microsecond
+
+// slowTX adds roundTrip latency to each node read, as a database would.
+type slowTX struct {
+	storage.ReadOnlyTreeTX
+}
+
+func (t slowTX) GetMerkleNodes(ctx context.Context, rev int64, ids []storage.NodeID) ([]storage.Node, error) {
+	time.Sleep(roundTrip)
+	return t.ReadOnlyTreeTX.GetMerkleNodes(ctx, rev, ids)
+}
+
+// newBenchmarkMap returns an in-memory map with numLeaves leaves, along with
+// the leaf indices.
+func newBenchmarkMap(ctx context.Context, b *testing.B, numLeaves int) (storage.MapStorage, *trillian.Tree, [][]byte) {
+	ms := memory.NewMapStorage()
+	atx, err := memory.NewMapAdminStorage(ms).Begin(ctx)
+	if err != nil {
+		b.Fatalf("Begin(): %v", err)
+	}
+	tree, err := atx.CreateTree(ctx, testonly.MapTree)
+	if err != nil {
+		b.Fatalf("CreateTree(): %v", err)
+	}
+	if err := atx.Commit(); err != nil {
+		b.Fatalf("Commit(): %v", err)
+	}
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		b.Fatalf("NewMapHasher(): %v", err)
+	}
+
+	tx, err := ms.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		b.Fatalf("BeginForTree(): %v", err)
+	}
+	defer tx.Close()
+	w, err := merkle.NewSparseMerkleTreeWriter(ctx, tree.TreeId, tx.WriteRevision(), hasher,
+		func() (storage.TreeTX, error) {
+			return ms.BeginForTree(ctx, tree.TreeId)
+		})
+	if err != nil {
+		b.Fatalf("NewSparseMerkleTreeWriter(): %v", err)
+	}
+	indices := make([][]byte, 0, numLeaves)
+	kvs := make([]merkle.HashKeyValue, 0, numLeaves)
+	for i := 0; i < numLeaves; i++ {
+		index := hasher.HashLeaf(tree.TreeId, nil, []byte(fmt.Sprintf("key-%d", i)))
+		indices = append(indices, index)
+		kvs = append(kvs, merkle.HashKeyValue{
+			HashedKey:   index,
+			HashedValue: hasher.HashLeaf(tree.TreeId, index, []byte("value")),
+		})
+	}
+	if err := w.SetLeaves(ctx, kvs); err != nil {
+		b.Fatalf("SetLeaves(): %v", err)
+	}
+	rootHash, err := w.CalculateRoot()
+	if err != nil {
+		b.Fatalf("CalculateRoot(): %v", err)
+	}
+	if err := tx.StoreSignedMapRoot(ctx, trillian.SignedMapRoot{
+		MapId:       tree.TreeId,
+		MapRevision: tx.WriteRevision(),
+		RootHash:    rootHash,
+	}); err != nil {
+		b.Fatalf("StoreSignedMapRoot(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("Commit(): %v", err)
+	}
+	return ms, tree, indices
+}
+
+// BenchmarkInclusionProofs compares fetching the inclusion proofs of many
+// leaves one by one, as GetLeaves used to, against a single batch.
+func BenchmarkInclusionProofs(b *testing.B) {
+	ctx := context.Background()
+	for _, numLeaves := range []int{1, 10, 100, 500} {
+		ms, tree, indices := newBenchmarkMap(ctx, b, numLeaves)
+		hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+		if err != nil {
+			b.Fatalf("NewMapHasher(): %v", err)
+		}
+
+		for _, bc := range []struct {
+			desc   string
+			proofs func(r *merkle.SparseMerkleTreeReader, rev int64) error
+		}{
+			{
+				desc: "perIndex",
+				proofs: func(r *merkle.SparseMerkleTreeReader, rev int64) error {
+					for _, index := range indices {
+						if _, err := r.InclusionProof(ctx, rev, index); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+			{
+				desc: "batch",
+				proofs: func(r *merkle.SparseMerkleTreeReader, rev int64) error {
+					_, err := r.BatchInclusionProof(ctx, rev, indices)
+					return err
+				},
+			},
+		} {
+			b.Run(fmt.Sprintf("%d/%s", numLeaves, bc.desc), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tx, err := ms.SnapshotForTree(ctx, tree.TreeId)
+					if err != nil {
+						b.Fatalf("SnapshotForTree(): %v", err)
+					}
+					rev := tx.ReadRevision()
+					r := merkle.NewSparseMerkleTreeReader(rev, hasher, slowTX{tx})
+					if err := bc.proofs(r, rev); err != nil {
+						b.Fatalf("%v: %v", bc.desc, err)
+					}
+					tx.Close()
+				}
+			})
+		}
+	}
+}
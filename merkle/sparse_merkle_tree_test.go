@@ -328,6 +328,52 @@ func TestInclusionProofGetsTooManyNodes(t *testing.T) {
 	}
 }
 
+func TestBatchInclusionProof(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// The keys only differ in their last bit, so they're each other's
+	// sibling at the bottom of the tree, and share all other siblings.
+	keyA := testonly.HashKey("SomeArbitraryKey")
+	keyB := append([]byte{}, keyA...)
+	keyB[len(keyB)-1] ^= 1
+	nodeA := storage.Node{NodeID: storage.NewNodeIDFromHash(keyA), Hash: []byte("A")}
+	nodeB := storage.Node{NodeID: storage.NewNodeIDFromHash(keyB), Hash: []byte("B")}
+	top := storage.NewNodeIDFromHash(keyA)
+	top = *top.MaskLeft(1).Neighbor()
+	nodeTop := storage.Node{NodeID: top, Hash: []byte("top")}
+
+	const rev = 100
+	r, tx := getSparseMerkleTreeReaderWithMockTX(mockCtrl, rev)
+	tx.EXPECT().GetMerkleNodes(ctx, int64(rev), gomock.Any()).Times(1).Do(func(_ context.Context, _ int64, ids []storage.NodeID) {
+		if got, want := len(ids), 257; got != want {
+			t.Errorf("GetMerkleNodes() got %d ids, want %d", got, want)
+		}
+	}).Return([]storage.Node{nodeA, nodeB, nodeTop}, nil)
+
+	proofs, err := r.BatchInclusionProof(ctx, rev, [][]byte{keyA, keyB, keyA})
+	if err != nil {
+		t.Fatalf("BatchInclusionProof(): %v", err)
+	}
+	if got, want := len(proofs), 3; got != want {
+		t.Fatalf("BatchInclusionProof() returned %d proofs, want %d", got, want)
+	}
+	for i, want := range [][]byte{nodeB.Hash, nodeA.Hash, nodeB.Hash} {
+		proof := proofs[i]
+		if got := len(proof); got != 256 {
+			t.Fatalf("len(proofs[%d]) = %d, want 256", i, got)
+		}
+		if got := proof[0]; !bytes.Equal(got, want) {
+			t.Errorf("proofs[%d][0] = %s, want %s", i, got, want)
+		}
+		if got, want := proof[255], nodeTop.Hash; !bytes.Equal(got, want) {
+			t.Errorf("proofs[%d][255] = %s, want %s", i, got, want)
+		}
+	}
+}
+
 type sparseKeyValue struct {
 	k, v string
 }
@@ -16,6 +16,9 @@ package admin
 
 import (
 	"bytes"
+	"encoding/base64"
+	"strconv"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/ptypes"
@@ -25,23 +28,45 @@ import (
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle/hashers"
 	_ "github.com/google/trillian/merkle/rfc6962" // Make hashers available
+	"github.com/google/trillian/storage"
 	"github.com/google/trillian/trees"
+	"github.com/google/trillian/util"
 	"golang.org/x/net/context"
 	"google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-var errNotImplemented = status.Errorf(codes.Unimplemented, "not implemented")
+const (
+	// defaultListTreesPageSize is the page size used by ListTrees if the
+	// request doesn't specify one.
+	defaultListTreesPageSize = 100
+
+	// maxListTreesPageSize is the maximum page size allowed by ListTrees.
+	// Larger requests are capped to it.
+	maxListTreesPageSize = 1000
+)
 
 // Server is an implementation of trillian.TrillianAdminServer.
 type Server struct {
 	registry extension.Registry
+
+	// undeleteWindow is how long after deletion a tree may be undeleted.
+	// Zero means there's no limit.
+	undeleteWindow time.Duration
+	timeSource     util.TimeSource
 }
 
 // New returns a trillian.TrillianAdminServer implementation.
 func New(registry extension.Registry) *Server {
-	return &Server{registry}
+	return &Server{registry: registry, timeSource: util.SystemTimeSource{}}
+}
+
+// SetUndeleteWindow limits UndeleteTree to trees soft-deleted less than window
+// ago. It should match the delete threshold of the DeletedTreeGC, so trees
+// due to be hard-deleted can't be restored.
+func (s *Server) SetUndeleteWindow(window time.Duration) {
+	s.undeleteWindow = window
 }
 
 // IsHealthy returns nil if the server is healthy, error otherwise.
@@ -52,13 +77,21 @@ func (s *Server) IsHealthy() error {
 
 // ListTrees implements trillian.TrillianAdminServer.ListTrees.
 func (s *Server) ListTrees(ctx context.Context, req *trillian.ListTreesRequest) (*trillian.ListTreesResponse, error) {
+	opts, err := listTreesOptions(req)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := opts.PageSize
+	// Ask for an extra tree, so we know whether there's a next page.
+	opts.PageSize++
+
 	tx, err := s.registry.AdminStorage.Snapshot(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Close()
 	// TODO(codingllama): This needs access control
-	resp, err := tx.ListTrees(ctx)
+	trees, err := tx.ListTrees(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +99,68 @@ func (s *Server) ListTrees(ctx context.Context, req *trillian.ListTreesRequest)
 		return nil, err
 	}
 
-	for _, tree := range resp {
+	resp := &trillian.ListTreesResponse{}
+	if len(trees) > pageSize {
+		trees = trees[:pageSize]
+		resp.NextPageToken = encodePageToken(trees[pageSize-1].TreeId)
+	}
+	for _, tree := range trees {
 		redact(tree)
 	}
-	return &trillian.ListTreesResponse{Tree: resp}, nil
+	resp.Tree = trees
+	return resp, nil
+}
+
+// listTreesOptions converts req into storage.ListTreesOptions.
+// The returned PageSize is always positive and capped to
+// maxListTreesPageSize.
+func listTreesOptions(req *trillian.ListTreesRequest) (storage.ListTreesOptions, error) {
+	opts := storage.ListTreesOptions{
+		TreeType:    req.TreeType,
+		TreeState:   req.TreeState,
+		ShowDeleted: req.ShowDeleted,
+	}
+
+	switch size := req.PageSize; {
+	case size < 0:
+		return opts, status.Errorf(codes.InvalidArgument, "page_size must be non-negative, got %v", size)
+	case size == 0:
+		opts.PageSize = defaultListTreesPageSize
+	case size > maxListTreesPageSize:
+		opts.PageSize = maxListTreesPageSize
+	default:
+		opts.PageSize = int(size)
+	}
+
+	if req.PageToken != "" {
+		afterID, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return opts, status.Errorf(codes.InvalidArgument, "invalid page_token: %v", err)
+		}
+		opts.AfterTreeID = afterID
+	}
+
+	switch req.TreeState {
+	case trillian.TreeState_SOFT_DELETED, trillian.TreeState_HARD_DELETED:
+		// Filtering by a deleted state implies show_deleted.
+		opts.ShowDeleted = true
+	}
+	return opts, nil
+}
+
+// encodePageToken returns an opaque page token that continues listing after
+// the tree identified by lastTreeID.
+func encodePageToken(lastTreeID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastTreeID, 10)))
+}
+
+// decodePageToken is the inverse of encodePageToken.
+func decodePageToken(token string) (int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(b), 10, 64)
 }
 
 // GetTree implements trillian.TrillianAdminServer.GetTree.
@@ -89,7 +180,7 @@ func (s *Server) CreateTree(ctx context.Context, request *trillian.CreateTreeReq
 		return nil, status.Errorf(codes.InvalidArgument, "a tree is required")
 	}
 	switch tree.TreeType {
-	case trillian.TreeType_LOG:
+	case trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG:
 		if _, err := hashers.NewLogHasher(tree.HashStrategy); err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "failed to create hasher for tree: %v", err.Error())
 		}
@@ -214,6 +305,16 @@ func applyUpdateMask(from, to *trillian.Tree, mask *field_mask.FieldMask) error
 			to.StorageSettings = from.StorageSettings
 		case "max_root_duration":
 			to.MaxRootDuration = from.MaxRootDuration
+		case "signing_disabled":
+			to.SigningDisabled = from.SigningDisabled
+		case "sequencing_disabled":
+			to.SequencingDisabled = from.SequencingDisabled
+		case "sequence_interval":
+			to.SequenceInterval = from.SequenceInterval
+		case "max_merge_delay":
+			to.MaxMergeDelay = from.MaxMergeDelay
+		case "scheduling_priority":
+			to.SchedulingPriority = from.SchedulingPriority
 		default:
 			return status.Errorf(codes.InvalidArgument, "invalid update_mask path: %q", path)
 		}
@@ -222,8 +323,52 @@ func applyUpdateMask(from, to *trillian.Tree, mask *field_mask.FieldMask) error
 }
 
 // DeleteTree implements trillian.TrillianAdminServer.DeleteTree.
-func (s *Server) DeleteTree(context.Context, *trillian.DeleteTreeRequest) (*empty.Empty, error) {
-	return nil, errNotImplemented
+func (s *Server) DeleteTree(ctx context.Context, req *trillian.DeleteTreeRequest) (*empty.Empty, error) {
+	tx, err := s.registry.AdminStorage.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	if _, err := tx.SoftDeleteTree(ctx, req.GetTreeId()); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// UndeleteTree implements trillian.TrillianAdminServer.UndeleteTree.
+func (s *Server) UndeleteTree(ctx context.Context, req *trillian.UndeleteTreeRequest) (*trillian.Tree, error) {
+	tx, err := s.registry.AdminStorage.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	if s.undeleteWindow > 0 {
+		tree, err := tx.GetTree(ctx, req.GetTreeId())
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.ValidateTreeIsSoftDeleted(tree); err != nil {
+			return nil, err
+		}
+		deleteTime, err := softDeleteTime(tree)
+		if err != nil {
+			return nil, err
+		}
+		if s.timeSource.Now().Sub(deleteTime) >= s.undeleteWindow {
+			return nil, status.Errorf(codes.FailedPrecondition, "tree %v was deleted more than %v ago and can no longer be undeleted", tree.TreeId, s.undeleteWindow)
+		}
+	}
+	tree, err := tx.UndeleteTree(ctx, req.GetTreeId())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return redact(tree), nil
 }
 
 // redact removes sensitive information from t. Returns t for convenience.
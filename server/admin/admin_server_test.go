@@ -39,35 +39,11 @@ import (
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/util"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/genproto/protobuf/field_mask"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
-func TestServer_Unimplemented(t *testing.T) {
-	tests := []struct {
-		desc string
-		fn   func(context.Context, *Server) error
-	}{
-		{
-			desc: "DeleteTree",
-			fn: func(ctx context.Context, s *Server) error {
-				_, err := s.DeleteTree(ctx, &trillian.DeleteTreeRequest{})
-				return err
-			},
-		},
-	}
-	ctx := context.Background()
-	s := &Server{}
-	for _, test := range tests {
-		err := test.fn(ctx, s)
-		if s, ok := status.FromError(err); !ok || s.Code() != codes.Unimplemented {
-			t.Errorf("%v: got = %v, want = %s", test.desc, err, codes.Unimplemented)
-		}
-	}
-}
-
 func TestServer_BeginError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -120,6 +96,20 @@ func TestServer_BeginError(t *testing.T) {
 				return err
 			},
 		},
+		{
+			desc: "DeleteTree",
+			fn: func(ctx context.Context, s *Server) error {
+				_, err := s.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: 12345})
+				return err
+			},
+		},
+		{
+			desc: "UndeleteTree",
+			fn: func(ctx context.Context, s *Server) error {
+				_, err := s.UndeleteTree(ctx, &trillian.UndeleteTreeRequest{TreeId: 12345})
+				return err
+			},
+		},
 	}
 
 	ctx := context.Background()
@@ -161,6 +151,8 @@ func TestServer_ListTrees(t *testing.T) {
 	}
 
 	ctx := context.Background()
+	// An extra tree is requested to determine whether there's a next page.
+	defaultOpts := storage.ListTreesOptions{PageSize: defaultListTreesPageSize + 1}
 	nextTreeID := int64(17)
 	storedTrees := []*trillian.Tree{}
 	nowPB, _ := ptypes.TimestampProto(time.Now())
@@ -188,12 +180,12 @@ func TestServer_ListTrees(t *testing.T) {
 		s := setup.server
 
 		if test.listErr {
-			tx.EXPECT().ListTrees(ctx).Return(nil, errors.New("error listing trees"))
+			tx.EXPECT().ListTrees(ctx, defaultOpts).Return(nil, errors.New("error listing trees"))
 		} else {
 			// Take a defensive copy, otherwise the server may end up changing our
 			// source-of-truth trees.
 			trees := copyAndUpdate(storedTrees, func(*trillian.Tree) {})
-			tx.EXPECT().ListTrees(ctx).Return(trees, nil)
+			tx.EXPECT().ListTrees(ctx, defaultOpts).Return(trees, nil)
 		}
 
 		resp, err := s.ListTrees(ctx, &trillian.ListTreesRequest{})
@@ -211,6 +203,143 @@ func TestServer_ListTrees(t *testing.T) {
 	}
 }
 
+func TestServer_ListTrees_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storedTrees := []*trillian.Tree{}
+	for id := int64(1); id <= 5; id++ {
+		tree := *testonly.LogTree
+		tree.TreeId = id
+		storedTrees = append(storedTrees, &tree)
+	}
+
+	// pageSize is small enough that listing takes multiple pages.
+	const pageSize = 2
+	ctx := context.Background()
+	wantIDs := []int64{1, 2, 3, 4, 5}
+	gotIDs := []int64{}
+	pageToken := ""
+	for i := 0; ; i++ {
+		if i > len(storedTrees) {
+			t.Fatalf("ListTrees() didn't finish after %v pages", i)
+		}
+
+		var afterID int64
+		if pageToken != "" {
+			var err error
+			if afterID, err = decodePageToken(pageToken); err != nil {
+				t.Fatalf("decodePageToken(%q) = (_, %v), want = (_, nil)", pageToken, err)
+			}
+		}
+		var page []*trillian.Tree
+		for _, tree := range storedTrees {
+			if tree.TreeId > afterID && len(page) < pageSize+1 {
+				page = append(page, tree)
+			}
+		}
+
+		setup := setupAdminServer(ctrl, nil /* keygen */, true /* snapshot */, true /* shouldCommit */, false /* commitErr */)
+		wantOpts := storage.ListTreesOptions{AfterTreeID: afterID, PageSize: pageSize + 1, ShowDeleted: true}
+		setup.snapshotTX.EXPECT().ListTrees(ctx, wantOpts).Return(copyAndUpdate(page, func(*trillian.Tree) {}), nil)
+
+		req := &trillian.ListTreesRequest{PageSize: pageSize, PageToken: pageToken, ShowDeleted: true}
+		resp, err := setup.server.ListTrees(ctx, req)
+		if err != nil {
+			t.Fatalf("ListTrees(_, %+v) = (_, %v), want = (_, nil)", req, err)
+		}
+		if got := len(resp.Tree); got > pageSize {
+			t.Errorf("ListTrees(_, %+v) returned %v trees, want <= %v", req, got, pageSize)
+		}
+		for _, tree := range resp.Tree {
+			gotIDs = append(gotIDs, tree.TreeId)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	if diff := pretty.Compare(gotIDs, wantIDs); diff != "" {
+		t.Errorf("paginated tree IDs diff (-got +want):\n%v", diff)
+	}
+}
+
+func TestListTreesOptions(t *testing.T) {
+	tests := []struct {
+		desc     string
+		req      *trillian.ListTreesRequest
+		wantOpts storage.ListTreesOptions
+		wantErr  bool
+	}{
+		{
+			desc:     "empty",
+			req:      &trillian.ListTreesRequest{},
+			wantOpts: storage.ListTreesOptions{PageSize: defaultListTreesPageSize},
+		},
+		{
+			desc:     "pageSize",
+			req:      &trillian.ListTreesRequest{PageSize: 10},
+			wantOpts: storage.ListTreesOptions{PageSize: 10},
+		},
+		{
+			desc:     "pageSizeCapped",
+			req:      &trillian.ListTreesRequest{PageSize: maxListTreesPageSize + 1},
+			wantOpts: storage.ListTreesOptions{PageSize: maxListTreesPageSize},
+		},
+		{
+			desc:    "negativePageSize",
+			req:     &trillian.ListTreesRequest{PageSize: -1},
+			wantErr: true,
+		},
+		{
+			desc:     "pageToken",
+			req:      &trillian.ListTreesRequest{PageToken: encodePageToken(12345)},
+			wantOpts: storage.ListTreesOptions{AfterTreeID: 12345, PageSize: defaultListTreesPageSize},
+		},
+		{
+			desc:    "badPageToken",
+			req:     &trillian.ListTreesRequest{PageToken: "not a token"},
+			wantErr: true,
+		},
+		{
+			desc: "filters",
+			req: &trillian.ListTreesRequest{
+				TreeType:    trillian.TreeType_MAP,
+				TreeState:   trillian.TreeState_FROZEN,
+				ShowDeleted: true,
+			},
+			wantOpts: storage.ListTreesOptions{
+				PageSize:    defaultListTreesPageSize,
+				TreeType:    trillian.TreeType_MAP,
+				TreeState:   trillian.TreeState_FROZEN,
+				ShowDeleted: true,
+			},
+		},
+		{
+			desc: "deletedStateImpliesShowDeleted",
+			req:  &trillian.ListTreesRequest{TreeState: trillian.TreeState_SOFT_DELETED},
+			wantOpts: storage.ListTreesOptions{
+				PageSize:    defaultListTreesPageSize,
+				TreeState:   trillian.TreeState_SOFT_DELETED,
+				ShowDeleted: true,
+			},
+		},
+	}
+	for _, test := range tests {
+		opts, err := listTreesOptions(test.req)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: listTreesOptions() = (_, %v), wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+		if diff := pretty.Compare(opts, test.wantOpts); diff != "" {
+			t.Errorf("%v: post-listTreesOptions() diff (-got +want):\n%v", test.desc, diff)
+		}
+	}
+}
+
 // copyAndUpdate makes a deep copy of a slice, allowing for an optional redact function to run on
 // every element.
 func copyAndUpdate(s []*trillian.Tree, f func(*trillian.Tree)) []*trillian.Tree {
@@ -343,6 +472,9 @@ func TestServer_CreateTree(t *testing.T) {
 	keySignatureMismatch := validTree
 	keySignatureMismatch.SignatureAlgorithm = sigpb.DigitallySigned_RSA
 
+	preorderedTree := validTree
+	preorderedTree.TreeType = trillian.TreeType_PREORDERED_LOG
+
 	tests := []struct {
 		desc                  string
 		req                   *trillian.CreateTreeRequest
@@ -356,6 +488,11 @@ func TestServer_CreateTree(t *testing.T) {
 			req:        &trillian.CreateTreeRequest{Tree: &validTree},
 			wantCommit: true,
 		},
+		{
+			desc:       "preorderedTree",
+			req:        &trillian.CreateTreeRequest{Tree: &preorderedTree},
+			wantCommit: true,
+		},
 		{
 			desc:    "nilTree",
 			req:     &trillian.CreateTreeRequest{},
@@ -556,13 +693,22 @@ func TestServer_UpdateTree(t *testing.T) {
 
 	// successTree specifies changes in all rw fields
 	successTree := &trillian.Tree{
-		TreeState:       trillian.TreeState_FROZEN,
-		DisplayName:     "Brand New Tree Name",
-		Description:     "Brand New Tree Desc",
-		StorageSettings: settings,
-		MaxRootDuration: ptypes.DurationProto(2 * time.Nanosecond),
+		TreeState:          trillian.TreeState_FROZEN,
+		DisplayName:        "Brand New Tree Name",
+		Description:        "Brand New Tree Desc",
+		StorageSettings:    settings,
+		MaxRootDuration:    ptypes.DurationProto(2 * time.Nanosecond),
+		SigningDisabled:    true,
+		SequencingDisabled: true,
+		SequenceInterval:   ptypes.DurationProto(3 * time.Second),
+		MaxMergeDelay:      ptypes.DurationProto(time.Hour),
+		SchedulingPriority: 2,
 	}
-	successMask := &field_mask.FieldMask{Paths: []string{"tree_state", "display_name", "description", "storage_settings", "max_root_duration"}}
+	successMask := &field_mask.FieldMask{Paths: []string{
+		"tree_state", "display_name", "description", "storage_settings", "max_root_duration",
+		"signing_disabled", "sequencing_disabled", "sequence_interval", "max_merge_delay",
+		"scheduling_priority",
+	}}
 
 	successWant := existingTree
 	successWant.TreeState = successTree.TreeState
@@ -571,6 +717,11 @@ func TestServer_UpdateTree(t *testing.T) {
 	successWant.StorageSettings = successTree.StorageSettings
 	successWant.PrivateKey = nil // redacted on responses
 	successWant.MaxRootDuration = successTree.MaxRootDuration
+	successWant.SigningDisabled = successTree.SigningDisabled
+	successWant.SequencingDisabled = successTree.SequencingDisabled
+	successWant.SequenceInterval = successTree.SequenceInterval
+	successWant.MaxMergeDelay = successTree.MaxMergeDelay
+	successWant.SchedulingPriority = successTree.SchedulingPriority
 
 	tests := []struct {
 		desc                           string
@@ -666,6 +817,174 @@ func TestServer_UpdateTree(t *testing.T) {
 	}
 }
 
+func TestServer_DeleteTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nowPB, _ := ptypes.TimestampProto(time.Now())
+	deletedTree := *testonly.LogTree
+	deletedTree.TreeId = 12345
+	deletedTree.TreeState = trillian.TreeState_SOFT_DELETED
+	deletedTree.CreateTime = nowPB
+	deletedTree.UpdateTime = nowPB
+	deletedTree.DeleteTime = nowPB
+
+	tests := []struct {
+		desc                           string
+		deleteErr                      error
+		commitErr, wantErr, wantCommit bool
+	}{
+		{
+			desc:       "success",
+			wantCommit: true,
+		},
+		{
+			desc:      "deleteErr",
+			deleteErr: errors.New("error deleting tree"),
+			wantErr:   true,
+		},
+		{
+			desc:       "commitErr",
+			commitErr:  true,
+			wantErr:    true,
+			wantCommit: true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		setup := setupAdminServer(
+			ctrl,
+			nil,   /* keygen */
+			false, /* snapshot */
+			test.wantCommit,
+			test.commitErr)
+
+		tx := setup.tx
+		s := setup.server
+
+		var retTree *trillian.Tree
+		if test.deleteErr == nil {
+			retTree = &deletedTree
+		}
+		tx.EXPECT().SoftDeleteTree(ctx, deletedTree.TreeId).Return(retTree, test.deleteErr)
+
+		_, err := s.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: deletedTree.TreeId})
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: DeleteTree() returned err = %q, wantErr = %v", test.desc, err, test.wantErr)
+		}
+	}
+}
+
+func TestServer_UndeleteTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	nowPB, _ := ptypes.TimestampProto(now)
+	undeletedTree := *testonly.LogTree
+	undeletedTree.TreeId = 12345
+	undeletedTree.CreateTime = nowPB
+	undeletedTree.UpdateTime = nowPB
+
+	wantTree := undeletedTree
+	wantTree.PrivateKey = nil // redacted on responses
+
+	const undeleteWindow = 24 * time.Hour
+	deletedTree := func(deleteTime time.Time) *trillian.Tree {
+		tree := undeletedTree
+		tree.TreeState = trillian.TreeState_SOFT_DELETED
+		tree.DeleteTime, _ = ptypes.TimestampProto(deleteTime)
+		return &tree
+	}
+
+	tests := []struct {
+		desc string
+		// storedTree is the tree as read by GetTree, if the undelete window is
+		// enforced.
+		storedTree                     *trillian.Tree
+		undeleteErr                    error
+		commitErr, wantErr, wantCommit bool
+	}{
+		{
+			desc:       "success",
+			wantCommit: true,
+		},
+		{
+			desc:        "undeleteErr",
+			undeleteErr: errors.New("error undeleting tree"),
+			wantErr:     true,
+		},
+		{
+			desc:       "commitErr",
+			commitErr:  true,
+			wantErr:    true,
+			wantCommit: true,
+		},
+		{
+			desc:       "withinWindow",
+			storedTree: deletedTree(now.Add(-undeleteWindow / 2)),
+			wantCommit: true,
+		},
+		{
+			desc:       "windowExpired",
+			storedTree: deletedTree(now.Add(-undeleteWindow)),
+			wantErr:    true,
+		},
+		{
+			desc: "notDeleted",
+			storedTree: func() *trillian.Tree {
+				tree := undeletedTree
+				return &tree
+			}(),
+			wantErr: true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		setup := setupAdminServer(
+			ctrl,
+			nil,   /* keygen */
+			false, /* snapshot */
+			test.wantCommit,
+			test.commitErr)
+
+		tx := setup.tx
+		s := setup.server
+
+		callUndelete := true
+		if test.storedTree != nil {
+			s.SetUndeleteWindow(undeleteWindow)
+			s.timeSource = util.NewFakeTimeSource(now)
+			tx.EXPECT().GetTree(ctx, undeletedTree.TreeId).Return(test.storedTree, nil)
+			callUndelete = !test.wantErr
+		}
+
+		if callUndelete {
+			var retTree *trillian.Tree
+			if test.undeleteErr == nil {
+				// Take a copy, as the server redacts the returned tree.
+				tree := undeletedTree
+				retTree = &tree
+			}
+			tx.EXPECT().UndeleteTree(ctx, undeletedTree.TreeId).Return(retTree, test.undeleteErr)
+		}
+
+		tree, err := s.UndeleteTree(ctx, &trillian.UndeleteTreeRequest{TreeId: undeletedTree.TreeId})
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: UndeleteTree() returned err = %q, wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+		if !proto.Equal(tree, &wantTree) {
+			diff := pretty.Compare(tree, &wantTree)
+			t.Errorf("%v: post-UndeleteTree diff:\n%v", test.desc, diff)
+		}
+	}
+}
+
 // adminTestSetup contains an operational Server and required dependencies.
 // It's created via setupAdminServer.
 type adminTestSetup struct {
@@ -714,7 +1033,7 @@ func setupAdminServer(ctrl *gomock.Controller, keygen keys.ProtoGenerator, snaps
 		NewKeyProto:  keygen,
 	}
 
-	s := &Server{registry}
+	s := New(registry)
 
 	return adminTestSetup{registry, as, tx, snapshotTX, s}
 }
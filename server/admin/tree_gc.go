@@ -0,0 +1,186 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/util"
+	"golang.org/x/net/context"
+)
+
+var (
+	hardDeleteCounter  monitoring.Counter
+	deleteErrorCounter monitoring.Counter
+	metricsOnce        = sync.Once{}
+
+	listSoftDeletedOpts = storage.ListTreesOptions{
+		TreeState:   trillian.TreeState_SOFT_DELETED,
+		ShowDeleted: true,
+	}
+)
+
+func initMetrics(mf monitoring.MetricFactory) {
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	hardDeleteCounter = mf.NewCounter("deleted_tree_gc_hard_delete_count", "Total number of trees hard-deleted by the DeletedTreeGC")
+	deleteErrorCounter = mf.NewCounter("deleted_tree_gc_error_count", "Total number of errors hard-deleting trees in the DeletedTreeGC")
+}
+
+// DeletedTreeGC garbage collects deleted trees.
+//
+// Trees are soft-deleted by DeleteTree, after which they may be undeleted for
+// a period of deleteThreshold. Once that period is over, DeletedTreeGC
+// hard-deletes them, permanently removing all of their data.
+type DeletedTreeGC struct {
+	admin storage.AdminStorage
+
+	// deleteThreshold is the minimum time a tree has to remain soft-deleted
+	// before it's hard-deleted.
+	deleteThreshold time.Duration
+
+	// minRunInterval is the minimum interval between garbage collection
+	// sweeps.
+	minRunInterval time.Duration
+
+	timeSource util.TimeSource
+}
+
+// NewDeletedTreeGC returns a new DeletedTreeGC.
+func NewDeletedTreeGC(admin storage.AdminStorage, deleteThreshold, minRunInterval time.Duration, mf monitoring.MetricFactory) *DeletedTreeGC {
+	metricsOnce.Do(func() { initMetrics(mf) })
+	return &DeletedTreeGC{
+		admin:           admin,
+		deleteThreshold: deleteThreshold,
+		minRunInterval:  minRunInterval,
+		timeSource:      util.SystemTimeSource{},
+	}
+}
+
+// Run starts the tree garbage collection process. It runs until ctx is
+// cancelled.
+func (gc *DeletedTreeGC) Run(ctx context.Context) {
+	for {
+		count, err := gc.RunOnce(ctx)
+		if err != nil {
+			glog.Errorf("DeletedTreeGC.RunOnce error: %v", err)
+		}
+		if count > 0 {
+			glog.Infof("DeletedTreeGC: hard-deleted %v tree(s)", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(gc.minRunInterval):
+		}
+	}
+}
+
+// RunOnce performs a single tree garbage collection sweep. Returns the number
+// of successfully hard-deleted trees.
+//
+// It attempts to hard-delete as many eligible trees as possible, regardless of
+// failures. If it encounters any failures the returned error is non-nil.
+func (gc *DeletedTreeGC) RunOnce(ctx context.Context) (int, error) {
+	now := gc.timeSource.Now()
+
+	trees, err := gc.listTrees(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var errs []error
+	for _, tree := range trees {
+		if tree.TreeState != trillian.TreeState_SOFT_DELETED {
+			continue
+		}
+		deleteTime, err := softDeleteTime(tree)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if now.Sub(deleteTime) < gc.deleteThreshold {
+			continue
+		}
+
+		// Each tree is deleted in its own transaction, as purging a tree's data
+		// may be expensive and a failure shouldn't affect other trees.
+		if err := gc.hardDeleteTree(ctx, tree.TreeId); err != nil {
+			deleteErrorCounter.Inc()
+			errs = append(errs, err)
+			continue
+		}
+		hardDeleteCounter.Inc()
+		count++
+	}
+
+	if len(errs) > 0 {
+		return count, fmt.Errorf("encountered %v error(s) hard-deleting trees: %v", len(errs), errs)
+	}
+	return count, nil
+}
+
+func (gc *DeletedTreeGC) listTrees(ctx context.Context) ([]*trillian.Tree, error) {
+	tx, err := gc.admin.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	trees, err := tx.ListTrees(ctx, listSoftDeletedOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+func (gc *DeletedTreeGC) hardDeleteTree(ctx context.Context, treeID int64) error {
+	tx, err := gc.admin.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+	if err := tx.HardDeleteTree(ctx, treeID); err != nil {
+		return fmt.Errorf("error hard-deleting tree %v: %v", treeID, err)
+	}
+	return tx.Commit()
+}
+
+// softDeleteTime returns the time tree was soft-deleted.
+// Trees soft-deleted before delete_time was recorded have none, in which case
+// update_time is used instead.
+func softDeleteTime(tree *trillian.Tree) (time.Time, error) {
+	ts := tree.DeleteTime
+	if ts == nil {
+		ts = tree.UpdateTime
+	}
+	deleteTime, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("tree %v has malformed delete time: %v", tree.TreeId, err)
+	}
+	return deleteTime, nil
+}
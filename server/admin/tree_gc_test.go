@@ -0,0 +1,168 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/util"
+)
+
+func TestDeletedTreeGC_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2017, 9, 28, 12, 0, 0, 0, time.UTC)
+	deleteThreshold := 1 * time.Hour
+
+	// newTree returns a tree in state, with delete and update times set to
+	// deleteTime (if not zero).
+	newTree := func(id int64, state trillian.TreeState, deleteTime time.Time) *trillian.Tree {
+		tree := *testonly.LogTree
+		tree.TreeId = id
+		tree.TreeState = state
+		if !deleteTime.IsZero() {
+			ts, err := ptypes.TimestampProto(deleteTime)
+			if err != nil {
+				t.Fatalf("TimestampProto() = (_, %v), want = (_, nil)", err)
+			}
+			tree.UpdateTime = ts
+			tree.DeleteTime = ts
+		}
+		return &tree
+	}
+
+	active := newTree(1, trillian.TreeState_ACTIVE, time.Time{})
+	frozen := newTree(2, trillian.TreeState_FROZEN, time.Time{})
+	hardDeleted := newTree(3, trillian.TreeState_HARD_DELETED, now.Add(-2*deleteThreshold))
+	recentlyDeleted := newTree(4, trillian.TreeState_SOFT_DELETED, now.Add(-deleteThreshold/2))
+	expired1 := newTree(5, trillian.TreeState_SOFT_DELETED, now.Add(-deleteThreshold))
+	expired2 := newTree(6, trillian.TreeState_SOFT_DELETED, now.Add(-2*deleteThreshold))
+	// Trees soft-deleted before delete_time was recorded have none.
+	expiredNoDeleteTime := newTree(7, trillian.TreeState_SOFT_DELETED, now.Add(-2*deleteThreshold))
+	expiredNoDeleteTime.DeleteTime = nil
+
+	allTrees := []*trillian.Tree{active, frozen, hardDeleted, recentlyDeleted, expired1, expired2, expiredNoDeleteTime}
+
+	tests := []struct {
+		desc string
+		// hardDeleteErrs maps the trees expected to be hard-deleted to the error
+		// returned by HardDeleteTree.
+		hardDeleteErrs map[int64]error
+		listErr        error
+		wantCount      int
+		wantErr        bool
+	}{
+		{
+			desc: "success",
+			hardDeleteErrs: map[int64]error{
+				expired1.TreeId:            nil,
+				expired2.TreeId:            nil,
+				expiredNoDeleteTime.TreeId: nil,
+			},
+			wantCount: 3,
+		},
+		{
+			desc: "partialFailure",
+			hardDeleteErrs: map[int64]error{
+				expired1.TreeId:            errors.New("hard-delete failed"),
+				expired2.TreeId:            nil,
+				expiredNoDeleteTime.TreeId: nil,
+			},
+			wantCount: 2,
+			wantErr:   true,
+		},
+		{
+			desc:    "listErr",
+			listErr: errors.New("list failed"),
+			wantErr: true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		as := storage.NewMockAdminStorage(ctrl)
+
+		snapshotTX := storage.NewMockReadOnlyAdminTX(ctrl)
+		as.EXPECT().Snapshot(ctx).Return(snapshotTX, nil)
+		snapshotTX.EXPECT().Close().Return(nil)
+		if test.listErr != nil {
+			snapshotTX.EXPECT().ListTrees(ctx, listSoftDeletedOpts).Return(nil, test.listErr)
+		} else {
+			snapshotTX.EXPECT().ListTrees(ctx, listSoftDeletedOpts).Return(allTrees, nil)
+			snapshotTX.EXPECT().Commit().Return(nil)
+		}
+
+		if n := len(test.hardDeleteErrs); n > 0 {
+			tx := storage.NewMockAdminTX(ctrl)
+			as.EXPECT().Begin(ctx).Times(n).Return(tx, nil)
+			tx.EXPECT().Close().Times(n).Return(nil)
+			for id, err := range test.hardDeleteErrs {
+				tx.EXPECT().HardDeleteTree(ctx, id).Return(err)
+				if err == nil {
+					tx.EXPECT().Commit().Return(nil)
+				}
+			}
+		}
+
+		gc := NewDeletedTreeGC(as, deleteThreshold, 1*time.Second /* minRunInterval */, nil /* mf */)
+		gc.timeSource = util.NewFakeTimeSource(now)
+
+		count, err := gc.RunOnce(ctx)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: RunOnce() returned err = %v, wantErr = %v", test.desc, err, test.wantErr)
+		}
+		if count != test.wantCount {
+			t.Errorf("%v: RunOnce() returned count = %v, want = %v", test.desc, count, test.wantCount)
+		}
+	}
+}
+
+func TestDeletedTreeGC_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	as := storage.NewMockAdminStorage(ctrl)
+	snapshotTX := storage.NewMockReadOnlyAdminTX(ctrl)
+	as.EXPECT().Snapshot(gomock.Any()).MinTimes(2).Return(snapshotTX, nil)
+	snapshotTX.EXPECT().ListTrees(gomock.Any(), listSoftDeletedOpts).MinTimes(2).Return(nil, nil)
+	snapshotTX.EXPECT().Commit().MinTimes(2).Return(nil)
+	snapshotTX.EXPECT().Close().MinTimes(2).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gc := NewDeletedTreeGC(as, 1*time.Hour /* deleteThreshold */, 1*time.Millisecond /* minRunInterval */, nil /* mf */)
+
+	done := make(chan struct{})
+	go func() {
+		gc.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() didn't return after ctx was cancelled")
+	}
+}
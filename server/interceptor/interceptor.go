@@ -116,14 +116,72 @@ func (i *TrillianInterceptor) UnaryInterceptor(ctx context.Context, req interfac
 	return resp, err
 }
 
+// StreamInterceptor executes the TrillianInterceptor logic for server-streaming RPCs.
+// The request that opens the stream is checked like a unary request, and pays for the first
+// response. Each further response is charged one more token of the same kind, so streaming a
+// range of leaves costs as much as reading it with unary calls.
+func (i *TrillianInterceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	stream := &trillianStream{ServerStream: ss, ctx: ss.Context(), tp: &trillianProcessor{parent: i}}
+	err := handler(srv, stream)
+	if stream.admitted {
+		stream.tp.After(stream.ctx, nil, err)
+	}
+	return err
+}
+
+// trillianStream is a grpc.ServerStream that runs a trillianProcessor over the request
+// received, and charges quota for the responses sent.
+type trillianStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	tp  *trillianProcessor
+	req interface{}
+	// admitted is set once the request has passed the processor's Before checks.
+	admitted bool
+	// sent is set once the first response has been sent.
+	sent bool
+}
+
+func (s *trillianStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *trillianStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.req != nil {
+		return status.Errorf(codes.Unimplemented, "client-streaming RPCs are not supported")
+	}
+	s.req = m
+	ctx, err := s.tp.Before(s.ctx, m)
+	if err != nil {
+		return err
+	}
+	s.ctx = ctx
+	s.admitted = true
+	return nil
+}
+
+func (s *trillianStream) SendMsg(m interface{}) error {
+	if s.sent && s.admitted {
+		if err := s.tp.getTokens(s.ctx, s.req, 1); err != nil {
+			return err
+		}
+	}
+	s.sent = true
+	return s.ServerStream.SendMsg(m)
+}
+
 // NewProcessor returns a RequestProcessor for the TrillianInterceptor logic.
 func (i *TrillianInterceptor) NewProcessor() RequestProcessor {
 	return &trillianProcessor{parent: i}
 }
 
 type trillianProcessor struct {
-	parent *TrillianInterceptor
-	info   *rpcInfo
+	parent    *TrillianInterceptor
+	info      *rpcInfo
+	quotaUser string
 }
 
 func (tp *trillianProcessor) Before(ctx context.Context, req interface{}) (context.Context, error) {
@@ -136,6 +194,7 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}) (conte
 	}()
 
 	quotaUser := tp.parent.qm.GetUser(ctx, req)
+	tp.quotaUser = quotaUser
 	info, err := getRPCInfo(req, quotaUser)
 	if err != nil {
 		incRequestDeniedCounter(badInfoReason, 0, quotaUser)
@@ -153,20 +212,31 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}) (conte
 		// TODO(codingllama): Add auth interception
 	}
 
-	if len(info.specs) > 0 && info.tokens > 0 {
-		err := tp.parent.qm.GetTokens(ctx, info.tokens, info.specs)
-		if err != nil {
-			if !tp.parent.quotaDryRun {
-				incRequestDeniedCounter(insufficientTokensReason, info.treeID, quotaUser)
-				return ctx, status.Errorf(codes.ResourceExhausted, "quota exhausted: %v", err)
-			}
-			glog.Warningf("(quotaDryRun) Request %+v not denied due to dry run mode: %v", req, err)
-		}
-		quota.Metrics.IncAcquired(info.tokens, info.specs, err != nil)
+	if err := tp.getTokens(ctx, req, info.tokens); err != nil {
+		return ctx, err
 	}
 	return ctx, nil
 }
 
+// getTokens acquires tokens from the quota specs of the request. It fails with ResourceExhausted
+// if there aren't enough tokens, unless in quotaDryRun mode.
+func (tp *trillianProcessor) getTokens(ctx context.Context, req interface{}, tokens int) error {
+	info := tp.info
+	if len(info.specs) == 0 || tokens <= 0 {
+		return nil
+	}
+	err := tp.parent.qm.GetTokens(ctx, tokens, info.specs)
+	if err != nil {
+		if !tp.parent.quotaDryRun {
+			incRequestDeniedCounter(insufficientTokensReason, info.treeID, tp.quotaUser)
+			return status.Errorf(codes.ResourceExhausted, "quota exhausted: %v", err)
+		}
+		glog.Warningf("(quotaDryRun) Request %+v not denied due to dry run mode: %v", req, err)
+	}
+	quota.Metrics.IncAcquired(tokens, info.specs, err != nil)
+	return nil
+}
+
 func (tp *trillianProcessor) After(ctx context.Context, resp interface{}, handlerErr error) {
 	if tp.info == nil {
 		glog.Warningf("After called with nil rpcInfo, resp = [%+v], handlerErr = [%v]", resp, handlerErr)
@@ -179,6 +249,7 @@ func (tp *trillianProcessor) After(ctx context.Context, resp interface{}, handle
 	//   cause a corresponding sequencing to happen)
 	// * Requests that filter out duplicates (e.g., QueueLeaf and QueueLeaves, for the same
 	//   reason as above: duplicates aren't queued for sequencing)
+	// * AddSequencedLeaves requests, for leaves that were rejected or already present
 	tokens := 0
 	if handlerErr != nil {
 		// Return the tokens spent by invalid requests
@@ -195,6 +266,12 @@ func (tp *trillianProcessor) After(ctx context.Context, resp interface{}, handle
 					tokens++
 				}
 			}
+		case *trillian.AddSequencedLeavesResponse:
+			for _, leaf := range resp.GetResults() {
+				if !isLeafOK(leaf) {
+					tokens++
+				}
+			}
 		}
 	}
 	if tokens > 0 && len(tp.info.specs) > 0 {
@@ -220,7 +297,7 @@ type rpcInfo struct {
 	// treeID is the tree ID tied to this RPC, if any (zero means no tree).
 	treeID int64
 
-	// opts is the trees.GetOpts appropriate to this RPC (TreeType, readonly vs readwrite, etc).
+	// opts is the trees.GetOpts appropriate to this RPC (TreeTypes, readonly vs readwrite, etc).
 	// opts is not set if doesNotHaveTree is true.
 	opts trees.GetOpts
 
@@ -235,7 +312,7 @@ type rpcInfo struct {
 // RPCs are mapped using the following logic:
 // treeID is acquired via one of the "Request" interfaces defined below (treeIDRequest,
 // logIDRequest, mapIDRequest, etc). Requests must implement to one of those.
-// TreeType and Readonly are determined based on the request type.
+// TreeTypes and Readonly are determined based on the request type.
 func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 	var treeID int64
 	switch req := req.(type) {
@@ -243,6 +320,8 @@ func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 		// OK, tree is being created
 	case *trillian.ListTreesRequest:
 		// OK, no single tree ID (potentially many trees)
+	case *trillian.UndeleteTreeRequest:
+		// OK, tree is deleted (thus not retrievable via trees.GetTree)
 	case treeIDRequest:
 		treeID = req.GetTreeId()
 	case treeRequest:
@@ -255,7 +334,7 @@ func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 		return nil, status.Errorf(codes.Internal, "cannot retrieve treeID from request: %T", req)
 	}
 
-	treeType, readonly, err := getRequestInfo(req)
+	treeTypes, readonly, err := getRequestInfo(req)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +345,7 @@ func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 	}
 	var specs []quota.Spec
 	switch {
-	case treeType == trillian.TreeType_UNKNOWN_TREE_TYPE:
+	case len(treeTypes) == 0:
 		// Don't impose quota on Admin requests.
 		// Sequencing-based replenishment is not tied in any way to Admin, so charging tokens for it
 		// leads to direct leakage.
@@ -284,6 +363,13 @@ func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 		}
 	}
 
+	opts := trees.GetOpts{TreeTypes: treeTypes, Readonly: readonly}
+	if len(treeTypes) == 0 {
+		// Admin requests must work on FROZEN trees, otherwise they couldn't be
+		// unfrozen or deleted.
+		opts.Readonly = true
+	}
+
 	tokens := 1
 	switch req := req.(type) {
 	case logLeavesRequest:
@@ -294,23 +380,25 @@ func getRPCInfo(req interface{}, quotaUser string) (*rpcInfo, error) {
 
 	return &rpcInfo{
 		treeID: treeID,
-		opts:   trees.GetOpts{TreeType: treeType, Readonly: readonly},
+		opts:   opts,
 		specs:  specs,
 		tokens: tokens,
 	}, nil
 }
 
-func getRequestInfo(req interface{}) (trillian.TreeType, bool, error) {
+// getRequestInfo returns the tree types req may be applied to, and whether it's readonly.
+// Admin requests may be applied to any tree, which is reported as no tree types.
+func getRequestInfo(req interface{}) ([]trillian.TreeType, bool, error) {
 	if readonly, ok := getAdminRequestInfo(req); ok {
-		return trillian.TreeType_UNKNOWN_TREE_TYPE, readonly, nil
+		return nil, readonly, nil
 	}
 	if readonly, ok := getLogRequestInfo(req); ok {
-		return trillian.TreeType_LOG, readonly, nil
+		return getLogTreeTypes(req, readonly), readonly, nil
 	}
 	if readonly, ok := getMapRequestInfo(req); ok {
-		return trillian.TreeType_MAP, readonly, nil
+		return []trillian.TreeType{trillian.TreeType_MAP}, readonly, nil
 	}
-	return trillian.TreeType_UNKNOWN_TREE_TYPE, false, fmt.Errorf("unmapped request type: %T", req)
+	return nil, false, fmt.Errorf("unmapped request type: %T", req)
 }
 
 func getAdminRequestInfo(req interface{}) (bool, bool) {
@@ -322,6 +410,7 @@ func getAdminRequestInfo(req interface{}) (bool, bool) {
 		readonly = true
 	case *trillian.CreateTreeRequest,
 		*trillian.DeleteTreeRequest,
+		*trillian.UndeleteTreeRequest,
 		*trillian.UpdateTreeRequest:
 	default:
 		ok = false
@@ -338,11 +427,15 @@ func getLogRequestInfo(req interface{}) (bool, bool) {
 		*trillian.GetInclusionProofByHashRequest,
 		*trillian.GetInclusionProofRequest,
 		*trillian.GetLatestSignedLogRootRequest,
+		*trillian.GetLeafStatusRequest,
 		*trillian.GetLeavesByHashRequest,
+		*trillian.GetLeavesByIdentityHashRequest,
 		*trillian.GetLeavesByIndexRequest,
+		*trillian.GetLeavesByRangeRequest,
 		*trillian.GetSequencedLeafCountRequest:
 		readonly = true
-	case *trillian.QueueLeafRequest,
+	case *trillian.AddSequencedLeavesRequest,
+		*trillian.QueueLeafRequest,
 		*trillian.QueueLeavesRequest:
 	default:
 		ok = false
@@ -350,11 +443,25 @@ func getLogRequestInfo(req interface{}) (bool, bool) {
 	return readonly, ok
 }
 
+// getLogTreeTypes returns the log types that a log request may be applied to. Any log may be
+// read, but leaves are only queued to LOG trees and added at given indices to PREORDERED_LOG
+// trees.
+func getLogTreeTypes(req interface{}, readonly bool) []trillian.TreeType {
+	if readonly {
+		return []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+	}
+	if _, ok := req.(*trillian.AddSequencedLeavesRequest); ok {
+		return []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}
+	}
+	return []trillian.TreeType{trillian.TreeType_LOG}
+}
+
 func getMapRequestInfo(req interface{}) (bool, bool) {
 	readonly := false
 	ok := true
 	switch req.(type) {
-	case *trillian.GetMapLeavesRequest,
+	case *trillian.GetLeafHistoryRequest,
+		*trillian.GetMapLeavesRequest,
 		*trillian.GetSignedMapRootByRevisionRequest,
 		*trillian.GetSignedMapRootRequest:
 		readonly = true
@@ -43,6 +43,12 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 	logTree.TreeId = 10
 	mapTree := *testonly.MapTree
 	mapTree.TreeId = 11
+	frozenLogTree := *testonly.LogTree
+	frozenLogTree.TreeId = 12
+	frozenLogTree.TreeState = trillian.TreeState_FROZEN
+	frozenMapTree := *testonly.MapTree
+	frozenMapTree.TreeId = 13
+	frozenMapTree.TreeState = trillian.TreeState_FROZEN
 	unknownTreeID := int64(999)
 
 	admin := storage.NewMockAdminStorage(ctrl)
@@ -50,6 +56,8 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 	admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
 	adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(&logTree, nil)
 	adminTX.EXPECT().GetTree(gomock.Any(), mapTree.TreeId).AnyTimes().Return(&mapTree, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), frozenLogTree.TreeId).AnyTimes().Return(&frozenLogTree, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), frozenMapTree.TreeId).AnyTimes().Return(&frozenMapTree, nil)
 	adminTX.EXPECT().GetTree(gomock.Any(), unknownTreeID).AnyTimes().Return(nil, errors.New("not found"))
 	adminTX.EXPECT().Close().AnyTimes().Return(nil)
 	adminTX.EXPECT().Commit().AnyTimes().Return(nil)
@@ -90,6 +98,41 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 			req:     &trillian.GetTreeRequest{TreeId: unknownTreeID},
 			wantErr: true,
 		},
+		{
+			desc:     "frozenLogRead",
+			req:      &trillian.GetLatestSignedLogRootRequest{LogId: frozenLogTree.TreeId},
+			wantTree: &frozenLogTree,
+		},
+		{
+			desc:    "frozenLogQueueLeaf",
+			req:     &trillian.QueueLeafRequest{LogId: frozenLogTree.TreeId},
+			wantErr: true,
+		},
+		{
+			desc:    "frozenLogQueueLeaves",
+			req:     &trillian.QueueLeavesRequest{LogId: frozenLogTree.TreeId},
+			wantErr: true,
+		},
+		{
+			desc:     "frozenMapRead",
+			req:      &trillian.GetSignedMapRootRequest{MapId: frozenMapTree.TreeId},
+			wantTree: &frozenMapTree,
+		},
+		{
+			desc:    "frozenMapSetLeaves",
+			req:     &trillian.SetMapLeavesRequest{MapId: frozenMapTree.TreeId},
+			wantErr: true,
+		},
+		{
+			desc:     "frozenTreeUpdate",
+			req:      &trillian.UpdateTreeRequest{Tree: &trillian.Tree{TreeId: frozenLogTree.TreeId}},
+			wantTree: &frozenLogTree,
+		},
+		{
+			desc:     "frozenTreeDelete",
+			req:      &trillian.DeleteTreeRequest{TreeId: frozenMapTree.TreeId},
+			wantTree: &frozenMapTree,
+		},
 	}
 
 	ctx := context.Background()
@@ -280,10 +323,15 @@ func TestTrillianInterceptor_QuotaInterception_ReturnsTokens(t *testing.T) {
 	logTree := *testonly.LogTree
 	logTree.TreeId = 10
 
+	preorderedTree := *testonly.LogTree
+	preorderedTree.TreeId = 11
+	preorderedTree.TreeType = trillian.TreeType_PREORDERED_LOG
+
 	admin := storage.NewMockAdminStorage(ctrl)
 	adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
 	admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
 	adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(&logTree, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), preorderedTree.TreeId).AnyTimes().Return(&preorderedTree, nil)
 	adminTX.EXPECT().Close().AnyTimes().Return(nil)
 	adminTX.EXPECT().Commit().AnyTimes().Return(nil)
 
@@ -371,6 +419,27 @@ func TestTrillianInterceptor_QuotaInterception_ReturnsTokens(t *testing.T) {
 			wantGetTokens: 3,
 			wantPutTokens: 2,
 		},
+		{
+			desc: "sequencedLeaves",
+			req: &trillian.AddSequencedLeavesRequest{
+				LogId:  preorderedTree.TreeId,
+				Leaves: []*trillian.LogLeaf{{}, {}, {}},
+			},
+			resp: &trillian.AddSequencedLeavesResponse{
+				Results: []*trillian.QueuedLogLeaf{
+					{Status: status.New(codes.AlreadyExists, "duplicate leaf").Proto()},
+					{Status: status.New(codes.FailedPrecondition, "conflicting leaf").Proto()},
+					{},
+				},
+			},
+			specs: []quota.Spec{
+				{Group: quota.User, Kind: quota.Write, User: user},
+				{Group: quota.Tree, Kind: quota.Write, TreeID: preorderedTree.TreeId},
+				{Group: quota.Global, Kind: quota.Write},
+			},
+			wantGetTokens: 3,
+			wantPutTokens: 2,
+		},
 		{
 			desc: "badQueueLeavesRequest",
 			req: &trillian.QueueLeavesRequest{
@@ -464,17 +533,116 @@ func TestTrillianInterceptor_BeforeAfter(t *testing.T) {
 	}
 }
 
+func TestTrillianInterceptor_StreamInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logTree := *testonly.LogTree
+	logTree.TreeId = 10
+	unknownTreeID := int64(999)
+
+	admin := storage.NewMockAdminStorage(ctrl)
+	adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+	admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(&logTree, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), unknownTreeID).AnyTimes().Return(nil, status.Error(codes.NotFound, "not found"))
+	adminTX.EXPECT().Close().AnyTimes().Return(nil)
+	adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+
+	user := "llama"
+	specs := []quota.Spec{
+		{Group: quota.User, Kind: quota.Read, User: user},
+		{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+		{Group: quota.Global, Kind: quota.Read},
+	}
+	tests := []struct {
+		desc      string
+		logID     int64
+		responses int
+		// getTokensErrs are returned by successive GetTokens calls, one per response.
+		getTokensErrs []error
+		wantCode      codes.Code
+		wantSent      int
+		wantPut       int
+	}{
+		{
+			desc:          "threeResponses",
+			logID:         logTree.TreeId,
+			responses:     3,
+			getTokensErrs: []error{nil, nil, nil},
+			wantSent:      3,
+		},
+		{
+			desc:          "quotaExhaustedMidStream",
+			logID:         logTree.TreeId,
+			responses:     3,
+			getTokensErrs: []error{nil, errors.New("not enough tokens")},
+			wantCode:      codes.ResourceExhausted,
+			wantSent:      1,
+			wantPut:       1,
+		},
+		{
+			desc:      "unknownTree",
+			logID:     unknownTreeID,
+			responses: 3,
+			wantCode:  codes.NotFound,
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		qm := quota.NewMockManager(ctrl)
+		qm.EXPECT().GetUser(gomock.Any(), gomock.Any()).MaxTimes(1).Return(user)
+		var calls []*gomock.Call
+		for _, err := range test.getTokensErrs {
+			calls = append(calls, qm.EXPECT().GetTokens(gomock.Any(), 1, specs).Return(err))
+		}
+		gomock.InOrder(calls...)
+		if test.wantPut > 0 {
+			qm.EXPECT().PutTokens(gomock.Any(), test.wantPut, specs).Return(nil)
+		}
+
+		stream := &fakeServerStream{ctx: ctx, req: &trillian.GetLeavesByRangeRequest{LogId: test.logID}}
+		handler := func(srv interface{}, stream grpc.ServerStream) error {
+			req := &trillian.GetLeavesByRangeRequest{}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			if _, ok := trees.FromContext(stream.Context()); !ok {
+				t.Errorf("%v: tree not in stream ctx", test.desc)
+			}
+			for i := 0; i < test.responses; i++ {
+				if err := stream.SendMsg(&trillian.GetLeavesByRangeResponse{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		intercept := New(admin, qm, false /* quotaDryRun */, nil /* mf */)
+		err := intercept.StreamInterceptor(nil /* srv */, stream, &grpc.StreamServerInfo{}, handler)
+		if s, ok := status.FromError(err); !ok || s.Code() != test.wantCode {
+			t.Errorf("%v: StreamInterceptor() returned err = %q, wantCode = %v", test.desc, err, test.wantCode)
+		}
+		if got := len(stream.sent); got != test.wantSent {
+			t.Errorf("%v: sent %v responses, want %v", test.desc, got, test.wantSent)
+		}
+	}
+}
+
 func TestGetRPCInfo(t *testing.T) {
+	anyLog := []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 	tests := []struct {
 		desc                  string
 		req                   interface{}
 		wantID                int64
-		wantType              trillian.TreeType
+		wantTypes             []trillian.TreeType
 		wantReadonly, wantErr bool
 	}{
 		{
-			desc: "createTree",
-			req:  &trillian.CreateTreeRequest{},
+			desc:         "createTree",
+			req:          &trillian.CreateTreeRequest{},
+			wantReadonly: true,
 		},
 		{
 			desc:         "listTrees",
@@ -488,40 +656,83 @@ func TestGetRPCInfo(t *testing.T) {
 			wantReadonly: true,
 		},
 		{
-			desc:   "rwTreeIDAdminRequest",
-			req:    &trillian.DeleteTreeRequest{TreeId: 10},
-			wantID: 10,
+			// Admin requests are allowed on FROZEN trees, thus read the tree as
+			// readonly.
+			desc:         "rwTreeIDAdminRequest",
+			req:          &trillian.DeleteTreeRequest{TreeId: 10},
+			wantID:       10,
+			wantReadonly: true,
+		},
+		{
+			desc:         "undeleteTree",
+			req:          &trillian.UndeleteTreeRequest{TreeId: 10},
+			wantReadonly: true,
 		},
 		{
-			desc:   "rwTreeAdminRequest",
-			req:    &trillian.UpdateTreeRequest{Tree: &trillian.Tree{TreeId: 10}},
-			wantID: 10,
+			desc:         "rwTreeAdminRequest",
+			req:          &trillian.UpdateTreeRequest{Tree: &trillian.Tree{TreeId: 10}},
+			wantID:       10,
+			wantReadonly: true,
 		},
 		{
 			desc:         "getLogRequest",
 			req:          &trillian.GetConsistencyProofRequest{LogId: 20},
 			wantID:       20,
-			wantType:     trillian.TreeType_LOG,
+			wantTypes:    anyLog,
+			wantReadonly: true,
+		},
+		{
+			desc:         "getLeafStatusRequest",
+			req:          &trillian.GetLeafStatusRequest{LogId: 20},
+			wantID:       20,
+			wantTypes:    anyLog,
+			wantReadonly: true,
+		},
+		{
+			desc:         "getLeavesByIdentityHashRequest",
+			req:          &trillian.GetLeavesByIdentityHashRequest{LogId: 20},
+			wantID:       20,
+			wantTypes:    anyLog,
+			wantReadonly: true,
+		},
+		{
+			desc:         "getLeavesByRangeRequest",
+			req:          &trillian.GetLeavesByRangeRequest{LogId: 20},
+			wantID:       20,
+			wantTypes:    anyLog,
 			wantReadonly: true,
 		},
 		{
-			desc:     "rwLogRequest",
-			req:      &trillian.QueueLeafRequest{LogId: 20},
-			wantID:   20,
-			wantType: trillian.TreeType_LOG,
+			desc:      "rwLogRequest",
+			req:       &trillian.QueueLeafRequest{LogId: 20},
+			wantID:    20,
+			wantTypes: []trillian.TreeType{trillian.TreeType_LOG},
+		},
+		{
+			desc:      "addSequencedLeavesRequest",
+			req:       &trillian.AddSequencedLeavesRequest{LogId: 20},
+			wantID:    20,
+			wantTypes: []trillian.TreeType{trillian.TreeType_PREORDERED_LOG},
 		},
 		{
 			desc:         "getMapRequest",
 			req:          &trillian.GetMapLeavesRequest{MapId: 30},
 			wantID:       30,
-			wantType:     trillian.TreeType_MAP,
+			wantTypes:    []trillian.TreeType{trillian.TreeType_MAP},
 			wantReadonly: true,
 		},
 		{
-			desc:     "rwMapRequest",
-			req:      &trillian.SetMapLeavesRequest{MapId: 30},
-			wantID:   30,
-			wantType: trillian.TreeType_MAP,
+			desc:         "getLeafHistoryRequest",
+			req:          &trillian.GetLeafHistoryRequest{MapId: 30},
+			wantID:       30,
+			wantTypes:    []trillian.TreeType{trillian.TreeType_MAP},
+			wantReadonly: true,
+		},
+		{
+			desc:      "rwMapRequest",
+			req:       &trillian.SetMapLeavesRequest{MapId: 30},
+			wantID:    30,
+			wantTypes: []trillian.TreeType{trillian.TreeType_MAP},
 		},
 		{
 			desc:    "unknownRequestType",
@@ -541,7 +752,7 @@ func TestGetRPCInfo(t *testing.T) {
 		if got, want := info.treeID, test.wantID; got != want {
 			t.Errorf("%v: info.treeID = %v, want = %v", test.desc, got, want)
 		}
-		wantOpts := &trees.GetOpts{TreeType: test.wantType, Readonly: test.wantReadonly}
+		wantOpts := &trees.GetOpts{TreeTypes: test.wantTypes, Readonly: test.wantReadonly}
 		if diff := pretty.Compare(info.opts, wantOpts); diff != "" {
 			t.Errorf("%v: info.opts diff:\n%v", test.desc, diff)
 		}
@@ -714,6 +925,28 @@ func (f *fakeHandler) run(ctx context.Context, req interface{}) (interface{}, er
 	return f.resp, f.err
 }
 
+// fakeServerStream is a grpc.ServerStream that receives req and records the messages sent.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	req  proto.Message
+	sent []interface{}
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), f.req)
+	return nil
+}
+
+func (f *fakeServerStream) SendMsg(m interface{}) error {
+	f.sent = append(f.sent, m)
+	return nil
+}
+
 type fakeInterceptor struct {
 	key    interface{}
 	val    interface{}
@@ -24,8 +24,11 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
 	"github.com/google/trillian/util"
 )
 
@@ -34,6 +37,8 @@ const (
 	minMasterCheckInterval = 50 * time.Millisecond
 	minMasterHoldInterval  = 10 * time.Second
 	logIDLabel             = "logid"
+	// electionCloseTimeout bounds the time spent handing off mastership of a log on shutdown.
+	electionCloseTimeout = 5 * time.Second
 )
 
 var (
@@ -41,6 +46,7 @@ var (
 	knownLogs    monitoring.Gauge
 	resignations monitoring.Counter
 	isMaster     monitoring.Gauge
+	passDelay    monitoring.Histogram
 )
 
 func createMetrics(mf monitoring.MetricFactory) {
@@ -50,6 +56,7 @@ func createMetrics(mf monitoring.MetricFactory) {
 	knownLogs = mf.NewGauge("known_logs", "Set to 1 for known logs (whether this instance is master or not)", logIDLabel)
 	resignations = mf.NewCounter("master_resignations", "Number of mastership resignations", logIDLabel)
 	isMaster = mf.NewGauge("is_master", "Whether this instance is master (0/1)", logIDLabel)
+	passDelay = mf.NewHistogram("log_operation_schedule_delay", "Delay between the start of a pass and the start of processing of each log in seconds", logIDLabel)
 }
 
 // LogOperation defines a task that operates on a log. Examples are scheduling, signing,
@@ -62,6 +69,13 @@ type LogOperation interface {
 	ExecutePass(ctx context.Context, logID int64, info *LogOperationInfo) (int, error)
 }
 
+// MastershipListener may be implemented by a LogOperation that keeps per-log state which is
+// only valid while this instance is continuously master for the log.
+type MastershipListener interface {
+	// MastershipLost is called when this instance stops being master for logID.
+	MastershipLost(logID int64)
+}
+
 // LogOperationInfo bundles up information needed for running a set of LogOperations.
 type LogOperationInfo struct {
 	// Registry provides access to Trillian storage.
@@ -73,6 +87,16 @@ type LogOperationInfo struct {
 	BatchSize int
 	// TimeSource should be used by the LogOperation to allow mocking for tests.
 	TimeSource util.TimeSource
+	// MaxMergeDelay is the time within which queued leaves should be integrated.
+	// Leaves that take longer are reported by the sequencer. Zero disables the check, unless the
+	// log sets its own max_merge_delay.
+	MaxMergeDelay time.Duration
+	// PipelineSequencing makes the sequencer dequeue the next batch of a log while it integrates
+	// the previous one, so leaves are integrated by the pass after the one that dequeued them.
+	PipelineSequencing bool
+	// MaxBatchSizeFactor lets the batch size of a log with a large backlog grow up to this
+	// multiple of BatchSize. Values below 2 keep the batch size fixed.
+	MaxBatchSizeFactor int
 
 	// The following parameters govern the overall scheduling of LogOperations
 	// by a LogOperationManager.
@@ -80,6 +104,8 @@ type LogOperationInfo struct {
 	// RunInterval is the time between starting batches of processing.  If a
 	// batch takes longer than this interval to complete, the next batch
 	// will start immediately.
+	// Logs that specify a sequence_interval are processed at their own
+	// interval instead.
 	RunInterval time.Duration
 	// PreElectionPause is the maximum interval to wait before starting a
 	// mastership election for a particular log.
@@ -94,6 +120,15 @@ type LogOperationInfo struct {
 	ResignOdds int
 	// NumWorkers is the number of worker goroutines to run in parallel.
 	NumWorkers int
+	// ScheduleByLoad makes each pass skip logs that have nothing to do, and process the others
+	// in order of their backlog, the age of their latest root and their scheduling_priority.
+	// This reads the backlog, latest root and stored leaf count of all logs in one snapshot on
+	// each pass.
+	ScheduleByLoad bool
+	// DrainTimeout is how long in-flight passes may keep running once the OperationLoop's
+	// context is cancelled. Passes still running after that are cancelled, which rolls back
+	// their storage transactions. Zero cancels in-flight passes straight away.
+	DrainTimeout time.Duration
 }
 
 type electionRunner struct {
@@ -103,6 +138,8 @@ type electionRunner struct {
 	cancel   context.CancelFunc
 	wg       *sync.WaitGroup
 	election util.MasterElection
+	// listener, if set, is told when mastership is lost.
+	listener MastershipListener
 }
 
 func (er *electionRunner) Run(ctx context.Context) {
@@ -119,10 +156,26 @@ func (er *electionRunner) Run(ctx context.Context) {
 		glog.Errorf("%d: election.Start() failed: %v", er.logID, err)
 		return
 	}
-	defer func(ctx context.Context, er *electionRunner) {
+	master := false
+	defer func() {
+		// ctx is done by now, so the election is released under a context of its own. Resigning
+		// lets another instance take over without waiting for this one's lease to expire.
+		closeCtx, cancel := context.WithTimeout(context.Background(), electionCloseTimeout)
+		defer cancel()
+		if master {
+			if err := er.election.ResignAndRestart(closeCtx); err != nil {
+				glog.Errorf("%d: failed to resign mastership on shutdown: %v", er.logID, err)
+				isMaster.Set(0.0, label)
+			} else {
+				glog.Infof("%d: resigned mastership on shutdown, handing off to other instances", er.logID)
+				er.lostMastership(label)
+			}
+		}
 		glog.Infof("%d: shutdown election-monitoring loop", er.logID)
-		er.election.Close(ctx)
-	}(ctx, er)
+		if err := er.election.Close(closeCtx); err != nil {
+			glog.Errorf("%d: election.Close() failed: %v", er.logID, err)
+		}
+	}()
 
 	for {
 		glog.V(1).Infof("%d: When I left you, I was but the learner", er.logID)
@@ -131,6 +184,7 @@ func (er *electionRunner) Run(ctx context.Context) {
 			return
 		}
 		glog.V(1).Infof("%d: Now, I am the master", er.logID)
+		master = true
 		er.tracker.Set(er.logID, true)
 		isMaster.Set(1.0, label)
 		masterSince := time.Now()
@@ -144,23 +198,25 @@ func (er *electionRunner) Run(ctx context.Context) {
 				return
 			default:
 			}
-			master, err := er.election.IsMaster(ctx)
+			isStillMaster, err := er.election.IsMaster(ctx)
 			if err != nil {
 				glog.Errorf("%d: failed to check mastership status", er.logID)
+				master = false
+				er.lostMastership(label)
 				break
 			}
-			if !master {
+			if !isStillMaster {
 				glog.Errorf("%d: no longer the master!", er.logID)
-				er.tracker.Set(er.logID, false)
-				isMaster.Set(0.0, label)
+				master = false
+				er.lostMastership(label)
 				break
 			}
 			if er.shouldResign(masterSince) {
 				glog.Infof("%d: deliberately resigning mastership", er.logID)
 				resignations.Inc(label)
 				if err := er.election.ResignAndRestart(ctx); err == nil {
-					er.tracker.Set(er.logID, false)
-					isMaster.Set(0.0, label)
+					master = false
+					er.lostMastership(label)
 					break
 				}
 				glog.Errorf("%d: failed to resign mastership", er.logID)
@@ -169,6 +225,15 @@ func (er *electionRunner) Run(ctx context.Context) {
 	}
 }
 
+// lostMastership records that this instance is no longer master for the log.
+func (er *electionRunner) lostMastership(label string) {
+	er.tracker.Set(er.logID, false)
+	isMaster.Set(0.0, label)
+	if er.listener != nil {
+		er.listener.MastershipLost(er.logID)
+	}
+}
+
 func (er *electionRunner) shouldResign(masterSince time.Time) bool {
 	now := er.info.TimeSource.Now()
 	duration := now.Sub(masterSince)
@@ -197,6 +262,10 @@ type LogOperationManager struct {
 	tracker        *util.MasterTracker
 	heldMutex      sync.Mutex
 	lastHeld       []int64
+
+	// nextRun holds the time at which each log is next due for processing,
+	// according to its run interval. Logs not present are due immediately.
+	nextRun map[int64]time.Time
 }
 
 // fixupElectionInfo ensures operation parameters have required minimum values.
@@ -225,6 +294,7 @@ func NewLogOperationManager(info LogOperationInfo, logOperation LogOperation) *L
 		info:           fixupElectionInfo(info),
 		logOperation:   logOperation,
 		electionRunner: make(map[int64]*electionRunner),
+		nextRun:        make(map[int64]time.Time),
 	}
 }
 
@@ -247,6 +317,88 @@ func (l *LogOperationManager) getLogIDs(ctx context.Context) ([]int64, error) {
 	return logIDs, nil
 }
 
+// getLogTrees returns all log trees in admin storage, pre-ordered logs included, keyed by tree
+// ID.
+func (l *LogOperationManager) getLogTrees(ctx context.Context) (map[int64]*trillian.Tree, error) {
+	tx, err := l.info.Registry.AdminStorage.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx for retrieving log trees: %v", err)
+	}
+	defer tx.Close()
+
+	allTrees, err := tx.ListTrees(ctx, storage.ListTreesOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log trees: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit listing log trees: %v", err)
+	}
+
+	treeMap := make(map[int64]*trillian.Tree, len(allTrees))
+	for _, tree := range allTrees {
+		if tree.TreeType == trillian.TreeType_LOG || tree.TreeType == trillian.TreeType_PREORDERED_LOG {
+			treeMap[tree.TreeId] = tree
+		}
+	}
+	return treeMap, nil
+}
+
+// logsDue returns the subset of logIDs that should be processed at now.
+// Frozen logs and logs with signing disabled are skipped, as are logs that were
+// processed less than their run interval ago. The next run time of returned
+// logs is updated.
+func (l *LogOperationManager) logsDue(logIDs []int64, logTrees map[int64]*trillian.Tree, now time.Time) []int64 {
+	nextRun := make(map[int64]time.Time, len(logIDs))
+	due := make([]int64, 0, len(logIDs))
+	for _, logID := range logIDs {
+		tree := logTrees[logID]
+		switch {
+		case tree != nil && tree.TreeState == trillian.TreeState_FROZEN:
+			glog.V(1).Infof("%v: log is frozen, skipping", logID)
+			continue
+		case tree != nil && tree.SigningDisabled:
+			glog.V(1).Infof("%v: signing disabled, skipping", logID)
+			continue
+		}
+		if next, ok := l.nextRun[logID]; ok && now.Before(next) {
+			nextRun[logID] = next
+			continue
+		}
+		nextRun[logID] = now.Add(l.runInterval(tree))
+		due = append(due, logID)
+	}
+	// Logs that are gone (or no longer held) are dropped from the schedule.
+	l.nextRun = nextRun
+	return due
+}
+
+// runInterval returns the interval between passes for tree, which is its
+// sequence_interval, if set, or RunInterval otherwise.
+func (l *LogOperationManager) runInterval(tree *trillian.Tree) time.Duration {
+	if tree == nil || tree.SequenceInterval == nil {
+		return l.info.RunInterval
+	}
+	interval, err := ptypes.Duration(tree.SequenceInterval)
+	if err != nil || interval <= 0 {
+		return l.info.RunInterval
+	}
+	return interval
+}
+
+// nextRunWait returns how long to wait from now before the next pass, given
+// that the last pass started at start. Passes happen at least every
+// RunInterval, so new logs and control changes are picked up.
+func (l *LogOperationManager) nextRunWait(start, now time.Time) time.Duration {
+	next := start.Add(l.info.RunInterval)
+	for _, t := range l.nextRun {
+		if t.Before(next) {
+			next = t
+		}
+	}
+	return next.Sub(now)
+}
+
 func (l *LogOperationManager) masterFor(ctx context.Context, allIDs []int64) ([]int64, error) {
 	if l.info.Registry.ElectionFactory == nil {
 		return allIDs, nil
@@ -277,6 +429,9 @@ func (l *LogOperationManager) masterFor(ctx context.Context, allIDs []int64) ([]
 			wg:       &l.runnerWG,
 			election: election,
 		}
+		if listener, ok := l.logOperation.(MastershipListener); ok {
+			l.electionRunner[logID].listener = listener
+		}
 		l.runnerWG.Add(1)
 		go l.electionRunner[logID].Run(innerCtx)
 	}
@@ -296,7 +451,10 @@ func (l *LogOperationManager) updateHeldIDs(logIDs, allIDs []int64) {
 	}
 }
 
-func (l *LogOperationManager) getLogsAndExecutePass(ctx context.Context) error {
+// getLogsAndExecutePass runs a pass over the logs that are due. Once stop is closed, no more
+// logs are started, but those already running are left to finish.
+func (l *LogOperationManager) getLogsAndExecutePass(ctx context.Context, stop <-chan struct{}) error {
+	passStart := time.Now()
 	allIDs, err := l.getLogIDs(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve full list of log IDs: %v", err)
@@ -307,6 +465,16 @@ func (l *LogOperationManager) getLogsAndExecutePass(ctx context.Context) error {
 	}
 	l.updateHeldIDs(logIDs, allIDs)
 
+	logTrees, err := l.getLogTrees(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve log trees: %v", err)
+	}
+	now := l.info.TimeSource.Now()
+	logIDs = l.logsDue(logIDs, logTrees, now)
+	if l.info.ScheduleByLoad {
+		logIDs = l.scheduleByLoad(ctx, logIDs, logTrees, now)
+	}
+
 	numWorkers := l.info.NumWorkers
 	if numWorkers == 0 {
 		glog.Warning("Executing a LogOperation pass with numWorkers == 0, assuming 1")
@@ -337,8 +505,15 @@ func (l *LogOperationManager) getLogsAndExecutePass(ctx context.Context) error {
 				if !more {
 					return
 				}
+				select {
+				case <-stop:
+					glog.V(1).Infof("%v: shutting down, not processing", logID)
+					continue
+				default:
+				}
 
 				start := time.Now()
+				passDelay.Observe(start.Sub(passStart).Seconds(), strconv.FormatInt(logID, 10))
 				count, err := l.logOperation.ExecutePass(ctx, logID, &l.info)
 				if err != nil {
 					glog.Errorf("ExecutePass(%v) failed: %v", logID, err)
@@ -369,47 +544,74 @@ func (l *LogOperationManager) getLogsAndExecutePass(ctx context.Context) error {
 
 // OperationSingle performs a single pass of the manager.
 func (l *LogOperationManager) OperationSingle(ctx context.Context) {
-	if err := l.getLogsAndExecutePass(ctx); err != nil {
+	if err := l.getLogsAndExecutePass(ctx, nil); err != nil {
 		glog.Errorf("failed to perform operation: %v", err)
 	}
 }
 
-// OperationLoop starts the manager working. It continues until told to exit.
+// OperationLoop starts the manager working. It continues until ctx is cancelled.
+// Passes that are in flight at that point are given up to DrainTimeout to finish, and then
+// mastership of every log is handed off.
 // TODO(Martin2112): No mechanism for error reporting etc., this is OK for v1 but needs work
 func (l *LogOperationManager) OperationLoop(ctx context.Context) {
 	glog.Infof("Log operation manager starting")
 
+	// Passes and elections run under a context of their own, which is only cancelled if
+	// in-flight passes outlast DrainTimeout, so that a log's mastership isn't given up while
+	// it's still being processed.
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+	drained := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-drained:
+			return
+		}
+		glog.Infof("Draining in-flight passes for up to %v", l.info.DrainTimeout)
+		select {
+		case <-time.After(l.info.DrainTimeout):
+			glog.Warningf("Passes still in flight after %v, cancelling them", l.info.DrainTimeout)
+			cancelRun()
+		case <-drained:
+		}
+	}()
+
 	// Outer loop, runs until terminated
 loop:
 	for {
 		// TODO(alcutter): want a child context with deadline here?
-		start := time.Now()
-		if err := l.getLogsAndExecutePass(ctx); err != nil {
+		start := l.info.TimeSource.Now()
+		if err := l.getLogsAndExecutePass(runCtx, ctx.Done()); err != nil {
 			glog.Errorf("failed to execute operation on logs: %v", err)
 		}
 
 		glog.V(1).Infof("Log operation manager pass complete")
 
-		// See if it's time to quit
-		select {
-		case <-ctx.Done():
-			glog.Infof("Log operation manager shutting down")
-			break loop
-		default:
-		}
-
-		// Wait for the configured time before going for another pass
-		duration := time.Now().Sub(start)
-		wait := l.info.RunInterval - duration
+		// Wait until the next log is due before going for another pass, unless it's time to quit
+		now := l.info.TimeSource.Now()
+		duration := now.Sub(start)
+		wait := l.nextRunWait(start, now)
 		if wait > 0 {
 			glog.V(1).Infof("Processing started at %v for %v; wait %v before next run", start, duration, wait)
-			time.Sleep(wait)
 		} else {
 			glog.V(1).Infof("Processing started at %v for %v; start next run immediately", start, duration)
+			wait = 0
+		}
+		select {
+		case <-ctx.Done():
+			glog.Infof("Log operation manager shutting down")
+			break loop
+		case <-time.After(wait):
 		}
 	}
+	close(drained)
 
-	// Terminate all the election runners
+	// Terminate all the election runners, which resign the logs they're master for.
+	if l.tracker != nil {
+		held := l.tracker.Held()
+		glog.Infof("handing off mastership of %d log(s): %v", len(held), held)
+	}
 	for logID, runner := range l.electionRunner {
 		if runner == nil {
 			continue
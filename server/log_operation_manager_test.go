@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/util"
@@ -138,7 +140,8 @@ func TestLogOperationManagerPassesIDs(t *testing.T) {
 	mockStorage.EXPECT().Snapshot(gomock.Any()).Return(mockTx, nil)
 
 	registry := extension.Registry{
-		LogStorage: mockStorage,
+		AdminStorage: newMockAdminStorageWithLogs(ctrl),
+		LogStorage:   mockStorage,
 	}
 
 	mockLogOp := NewMockLogOperation(ctrl)
@@ -152,6 +155,211 @@ func TestLogOperationManagerPassesIDs(t *testing.T) {
 	lom.OperationSingle(ctx)
 }
 
+func TestLogOperationManagerListTreesFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTx := storage.NewMockReadOnlyLogTX(ctrl)
+	mockTx.EXPECT().GetActiveLogIDs(gomock.Any()).Return([]int64{1}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockStorage.EXPECT().Snapshot(gomock.Any()).Return(mockTx, nil)
+
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(ctrl)
+	mockAdminTx.EXPECT().ListTrees(gomock.Any(), gomock.Any()).Return(nil, errors.New("listtrees"))
+	mockAdminTx.EXPECT().Close().Return(nil)
+	mockAdmin := storage.NewMockAdminStorage(ctrl)
+	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   mockStorage,
+	}
+
+	// No ExecutePass calls are expected.
+	mockLogOp := NewMockLogOperation(ctrl)
+
+	ctx := context.Background()
+	info := defaultLogOperationInfo(registry)
+	lom := NewLogOperationManager(info, mockLogOp)
+
+	lom.OperationSingle(ctx)
+}
+
+func TestLogOperationManagerSkipsSigningDisabledAndFrozen(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	enabledLog := &trillian.Tree{TreeId: 145, TreeType: trillian.TreeType_LOG}
+	disabledLog := &trillian.Tree{TreeId: 451, TreeType: trillian.TreeType_LOG, SigningDisabled: true}
+	// Sequencing disabled is up to the LogOperation, so the log is still passed on.
+	sequencingDisabledLog := &trillian.Tree{TreeId: 541, TreeType: trillian.TreeType_LOG, SequencingDisabled: true}
+	frozenLog := &trillian.Tree{TreeId: 514, TreeType: trillian.TreeType_LOG, TreeState: trillian.TreeState_FROZEN}
+	frozenPreorderedLog := &trillian.Tree{TreeId: 515, TreeType: trillian.TreeType_PREORDERED_LOG, TreeState: trillian.TreeState_FROZEN}
+
+	mockTx := storage.NewMockReadOnlyLogTX(ctrl)
+	mockTx.EXPECT().GetActiveLogIDs(gomock.Any()).Return([]int64{enabledLog.TreeId, disabledLog.TreeId, sequencingDisabledLog.TreeId, frozenLog.TreeId, frozenPreorderedLog.TreeId}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockStorage.EXPECT().Snapshot(gomock.Any()).Return(mockTx, nil)
+
+	registry := extension.Registry{
+		AdminStorage: newMockAdminStorageWithLogs(ctrl, enabledLog, disabledLog, sequencingDisabledLog, frozenLog, frozenPreorderedLog),
+		LogStorage:   mockStorage,
+	}
+
+	mockLogOp := NewMockLogOperation(ctrl)
+	mockLogOp.EXPECT().ExecutePass(gomock.Any(), enabledLog.TreeId, gomock.Any())
+	mockLogOp.EXPECT().ExecutePass(gomock.Any(), sequencingDisabledLog.TreeId, gomock.Any())
+
+	info := defaultLogOperationInfo(registry)
+	lom := NewLogOperationManager(info, mockLogOp)
+
+	lom.OperationSingle(ctx)
+}
+
+func TestLogOperationManagerPerLogInterval(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// defaultLog runs every RunInterval (1s), slowLog every minute.
+	defaultLog := &trillian.Tree{TreeId: 1, TreeType: trillian.TreeType_LOG}
+	slowLog := &trillian.Tree{TreeId: 2, TreeType: trillian.TreeType_LOG, SequenceInterval: ptypes.DurationProto(time.Minute)}
+
+	mockTx := storage.NewMockReadOnlyLogTX(ctrl)
+	mockTx.EXPECT().GetActiveLogIDs(gomock.Any()).AnyTimes().Return([]int64{defaultLog.TreeId, slowLog.TreeId}, nil)
+	mockTx.EXPECT().Commit().AnyTimes().Return(nil)
+	mockTx.EXPECT().Close().AnyTimes().Return(nil)
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockStorage.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(mockTx, nil)
+
+	registry := extension.Registry{
+		AdminStorage: newMockAdminStorageWithLogs(ctrl, defaultLog, slowLog),
+		LogStorage:   mockStorage,
+	}
+
+	startTime := time.Date(2017, 10, 2, 12, 0, 0, 0, time.UTC)
+	timeSource := util.NewFakeTimeSource(startTime)
+	mockLogOp := NewMockLogOperation(ctrl)
+	info := defaultLogOperationInfo(registry)
+	info.TimeSource = timeSource
+	lom := NewLogOperationManager(info, mockLogOp)
+
+	passes := []struct {
+		offset  time.Duration
+		wantIDs []int64
+		// wantWait is the wait before the next pass, assuming it started at
+		// offset and finished instantly.
+		wantWait time.Duration
+	}{
+		{offset: 0, wantIDs: []int64{1, 2}, wantWait: time.Second},
+		{offset: 500 * time.Millisecond, wantWait: 500 * time.Millisecond},
+		{offset: 2 * time.Second, wantIDs: []int64{1}, wantWait: time.Second},
+		{offset: time.Minute, wantIDs: []int64{1, 2}, wantWait: time.Second},
+	}
+	for _, pass := range passes {
+		now := startTime.Add(pass.offset)
+		timeSource.Set(now)
+		for _, id := range pass.wantIDs {
+			mockLogOp.EXPECT().ExecutePass(gomock.Any(), id, gomock.Any())
+		}
+		lom.OperationSingle(ctx)
+		if got := lom.nextRunWait(now, now); got != pass.wantWait {
+			t.Errorf("offset %v: nextRunWait() = %v, want = %v", pass.offset, got, pass.wantWait)
+		}
+	}
+}
+
+func TestLogOperationManagerScheduleByLoad(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2017, 10, 2, 12, 0, 0, 0, time.UTC)
+	// busyLog has three batches queued. urgentLog has fewer leaves queued, but a high priority.
+	// idleLog has nothing queued and a fresh root. staleLog has nothing queued either, but its
+	// root is older than its max_root_duration. newLog has never been signed. preorderedLog has
+	// a fresh root.
+	busyLog := &trillian.Tree{TreeId: 1, TreeType: trillian.TreeType_LOG}
+	urgentLog := &trillian.Tree{TreeId: 2, TreeType: trillian.TreeType_LOG, SchedulingPriority: 9}
+	idleLog := &trillian.Tree{TreeId: 3, TreeType: trillian.TreeType_LOG, MaxRootDuration: ptypes.DurationProto(time.Hour)}
+	staleLog := &trillian.Tree{TreeId: 4, TreeType: trillian.TreeType_LOG, MaxRootDuration: ptypes.DurationProto(time.Minute)}
+	newLog := &trillian.Tree{TreeId: 5, TreeType: trillian.TreeType_LOG}
+	preorderedLog := &trillian.Tree{TreeId: 6, TreeType: trillian.TreeType_PREORDERED_LOG}
+	counts := storage.CountByLogID{busyLog.TreeId: 150, urgentLog.TreeId: 10}
+	roots := map[int64]trillian.SignedLogRoot{
+		busyLog.TreeId:       {RootHash: []byte("root"), TimestampNanos: now.UnixNano()},
+		urgentLog.TreeId:     {RootHash: []byte("root"), TimestampNanos: now.UnixNano()},
+		idleLog.TreeId:       {RootHash: []byte("root"), TimestampNanos: now.Add(-time.Minute).UnixNano(), TreeSize: 10},
+		staleLog.TreeId:      {RootHash: []byte("root"), TimestampNanos: now.Add(-2 * time.Minute).UnixNano()},
+		preorderedLog.TreeId: {RootHash: []byte("root"), TimestampNanos: now.UnixNano(), TreeSize: 10},
+	}
+	logIDs := []int64{1, 2, 3, 4, 5, 6}
+
+	for _, test := range []struct {
+		desc    string
+		ends    storage.CountByLogID
+		wantIDs []int64
+	}{
+		// Weights: newLog ~1.5e9, staleLog 120, busyLog 3, urgentLog (10/50)*10 = 2.
+		{
+			desc:    "integrated",
+			ends:    storage.CountByLogID{idleLog.TreeId: 10, preorderedLog.TreeId: 10},
+			wantIDs: []int64{5, 4, 1, 2},
+		},
+		// Leaves stored beyond the root are waiting to be integrated, so idleLog is processed
+		// with a weight of 50/50 + 60 from the age of its root, and preorderedLog with 25/50.
+		{
+			desc:    "pending",
+			ends:    storage.CountByLogID{idleLog.TreeId: 60, preorderedLog.TreeId: 35},
+			wantIDs: []int64{5, 4, 3, 1, 2, 6},
+		},
+	} {
+		mockTx := storage.NewMockReadOnlyLogTX(ctrl)
+		mockTx.EXPECT().GetActiveLogIDs(gomock.Any()).Return(logIDs, nil)
+		mockTx.EXPECT().GetUnsequencedCounts(gomock.Any()).Return(counts, nil)
+		mockTx.EXPECT().GetLatestSignedLogRoots(gomock.Any()).Return(roots, nil)
+		mockTx.EXPECT().GetSequencedLeafEnds(gomock.Any()).Return(test.ends, nil)
+		mockTx.EXPECT().Commit().Times(2).Return(nil)
+		mockTx.EXPECT().Close().Times(2).Return(nil)
+		mockStorage := storage.NewMockLogStorage(ctrl)
+		mockStorage.EXPECT().Snapshot(gomock.Any()).Times(2).Return(mockTx, nil)
+
+		registry := extension.Registry{
+			AdminStorage: newMockAdminStorageWithLogs(ctrl, busyLog, urgentLog, idleLog, staleLog, newLog, preorderedLog),
+			LogStorage:   mockStorage,
+		}
+		mockLogOp := NewMockLogOperation(ctrl)
+		var calls []*gomock.Call
+		for _, id := range test.wantIDs {
+			calls = append(calls, mockLogOp.EXPECT().ExecutePass(gomock.Any(), id, gomock.Any()))
+		}
+		gomock.InOrder(calls...)
+
+		info := defaultLogOperationInfo(registry)
+		info.TimeSource = util.NewFakeTimeSource(now)
+		info.ScheduleByLoad = true
+		lom := NewLogOperationManager(info, mockLogOp)
+		lom.OperationSingle(ctx)
+	}
+}
+
+// newMockAdminStorageWithLogs returns a MockAdminStorage that lists logTrees
+// on every Snapshot.
+func newMockAdminStorageWithLogs(ctrl *gomock.Controller, logTrees ...*trillian.Tree) *storage.MockAdminStorage {
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(ctrl)
+	mockAdminTx.EXPECT().ListTrees(gomock.Any(), storage.ListTreesOptions{}).AnyTimes().Return(logTrees, nil)
+	mockAdminTx.EXPECT().Commit().AnyTimes().Return(nil)
+	mockAdminTx.EXPECT().Close().AnyTimes().Return(nil)
+	mockAdmin := storage.NewMockAdminStorage(ctrl)
+	mockAdmin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(mockAdminTx, nil)
+	return mockAdmin
+}
+
 func TestShouldResign(t *testing.T) {
 	startTime := time.Date(1970, 9, 19, 12, 00, 00, 00, time.UTC)
 	var tests = []struct {
@@ -300,6 +508,18 @@ func (te *testElection) Close(ctx context.Context) error {
 	return te.closeErr
 }
 
+// testListener records the logs it's told mastership was lost for.
+type testListener struct {
+	mu   sync.Mutex
+	lost []int64
+}
+
+func (tl *testListener) MastershipLost(logID int64) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.lost = append(tl.lost, logID)
+}
+
 type failureFactory struct{}
 
 func (ff failureFactory) NewElection(ctx context.Context, treeID int64) (util.MasterElection, error) {
@@ -320,12 +540,12 @@ func TestElectionRunnerRun(t *testing.T) {
 				waitBlocks: true,
 			},
 		},
+		// Mastership is resigned on shutdown.
 		{
 			election: testElection{
 				waitBlocks: false,
 				isMaster:   true,
 			},
-			wantMaster: true,
 		},
 		// Error cases
 		{
@@ -344,18 +564,14 @@ func TestElectionRunnerRun(t *testing.T) {
 				isMasterErr: errors.New("on IsMaster"),
 				waitBlocks:  false,
 			},
-			wantMaster: true,
 		},
 		{
 			election: testElection{
 				waitBlocks: false,
 				isMaster:   false,
 			},
-			// a) WaitForMaster() falls through => master=true
-			// b) IsMaster() returns false => master=false
-			// goroutine termination happens after a) before b) so...
-			wantMaster: true,
 		},
+		// Mastership is kept if resigning on shutdown fails.
 		{
 			election: testElection{
 				waitBlocks: false,
@@ -393,3 +609,173 @@ func TestElectionRunnerRun(t *testing.T) {
 		}
 	}
 }
+
+func TestElectionRunnerNotifiesListener(t *testing.T) {
+	ctx := context.Background()
+	info := fixupElectionInfo(LogOperationInfo{TimeSource: util.NewFakeTimeSource(time.Now())})
+	for _, test := range []struct {
+		desc     string
+		election testElection
+		wantLost bool
+	}{
+		{desc: "neverMaster", election: testElection{waitBlocks: true}},
+		{desc: "resignedOnShutdown", election: testElection{isMaster: true}, wantLost: true},
+		{desc: "resignOnShutdownFails", election: testElection{isMaster: true, resignErr: errors.New("on resign")}},
+		{desc: "lostMastership", election: testElection{isMaster: false}, wantLost: true},
+		{desc: "isMasterFails", election: testElection{isMaster: true, isMasterErr: errors.New("on IsMaster")}, wantLost: true},
+	} {
+		logID := int64(6962)
+		ctx, cancel := context.WithCancel(ctx)
+		listener := &testListener{}
+		var wg sync.WaitGroup
+		er := electionRunner{
+			logID:    logID,
+			info:     &info,
+			tracker:  util.NewMasterTracker([]int64{logID}),
+			election: &test.election,
+			wg:       &wg,
+			listener: listener,
+		}
+		wg.Add(1)
+		go er.Run(ctx)
+		time.Sleep(2 * minMasterCheckInterval)
+		cancel()
+		wg.Wait()
+
+		listener.mu.Lock()
+		lost := listener.lost
+		listener.mu.Unlock()
+		if got := len(lost) > 0; got != test.wantLost {
+			t.Errorf("%v: MastershipLost() called for %v, want calls: %v", test.desc, lost, test.wantLost)
+		}
+		for _, id := range lost {
+			if id != logID {
+				t.Errorf("%v: MastershipLost(%v), want %v", test.desc, id, logID)
+			}
+		}
+	}
+}
+
+// blockingLogOp is a LogOperation whose passes don't return until release is closed, or their
+// context is done.
+type blockingLogOp struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	errs    []error
+}
+
+func (b *blockingLogOp) Name() string {
+	return "blocking"
+}
+
+func (b *blockingLogOp) ExecutePass(ctx context.Context, logID int64, info *LogOperationInfo) (int, error) {
+	b.once.Do(func() { close(b.started) })
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errs = append(b.errs, ctx.Err())
+	return 0, ctx.Err()
+}
+
+// handoffElection is a testElection that records whether it was resigned and closed.
+type handoffElection struct {
+	testElection
+	mu       sync.Mutex
+	resigned bool
+	closed   bool
+}
+
+func (he *handoffElection) ResignAndRestart(ctx context.Context) error {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+	he.resigned = true
+	return ctx.Err()
+}
+
+func (he *handoffElection) Close(ctx context.Context) error {
+	he.mu.Lock()
+	defer he.mu.Unlock()
+	he.closed = true
+	return ctx.Err()
+}
+
+type handoffElectionFactory struct {
+	election *handoffElection
+}
+
+func (hf handoffElectionFactory) NewElection(ctx context.Context, treeID int64) (util.MasterElection, error) {
+	return hf.election, nil
+}
+
+func TestLogOperationManagerDrainsOnShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	const logID = 1
+
+	for _, test := range []struct {
+		desc         string
+		drainTimeout time.Duration
+		release      bool
+		wantErr      error
+	}{
+		{desc: "drained", drainTimeout: time.Minute, release: true},
+		{desc: "timedOut", drainTimeout: 10 * time.Millisecond, wantErr: context.Canceled},
+	} {
+		mockTx := storage.NewMockReadOnlyLogTX(ctrl)
+		mockTx.EXPECT().GetActiveLogIDs(gomock.Any()).AnyTimes().Return([]int64{logID}, nil)
+		mockTx.EXPECT().Commit().AnyTimes().Return(nil)
+		mockTx.EXPECT().Close().AnyTimes().Return(nil)
+		mockStorage := storage.NewMockLogStorage(ctrl)
+		mockStorage.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(mockTx, nil)
+
+		election := &handoffElection{testElection: testElection{isMaster: true}}
+		registry := extension.Registry{
+			AdminStorage:    newMockAdminStorageWithLogs(ctrl, &trillian.Tree{TreeId: logID, TreeType: trillian.TreeType_LOG}),
+			LogStorage:      mockStorage,
+			ElectionFactory: handoffElectionFactory{election: election},
+		}
+		logOp := &blockingLogOp{started: make(chan struct{}), release: make(chan struct{})}
+		info := defaultLogOperationInfo(registry)
+		info.RunInterval = 10 * time.Millisecond
+		info.TimeSource = util.SystemTimeSource{}
+		info.DrainTimeout = test.drainTimeout
+		lom := NewLogOperationManager(info, logOp)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			lom.OperationLoop(ctx)
+			close(done)
+		}()
+
+		// Shut down while a pass is in flight.
+		<-logOp.started
+		cancel()
+		if test.release {
+			time.Sleep(50 * time.Millisecond)
+			election.mu.Lock()
+			if election.resigned || election.closed {
+				t.Errorf("%v: election resigned or closed while a pass was in flight", test.desc)
+			}
+			election.mu.Unlock()
+			close(logOp.release)
+		}
+		<-done
+
+		logOp.mu.Lock()
+		if got, want := logOp.errs, []error{test.wantErr}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: ExecutePass() context errors = %v, want %v", test.desc, got, want)
+		}
+		logOp.mu.Unlock()
+		election.mu.Lock()
+		if !election.resigned || !election.closed {
+			t.Errorf("%v: election resigned = %v, closed = %v, want both true", test.desc, election.resigned, election.closed)
+		}
+		election.mu.Unlock()
+	}
+}
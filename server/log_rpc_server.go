@@ -15,8 +15,12 @@
 package server
 
 import (
+	"strconv"
+	"sync"
+
 	"github.com/golang/glog"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/hashers"
@@ -35,11 +39,38 @@ import (
 // Pass this as a fixed value to proof calculations. It's used as the max depth of the tree
 const proofMaxBitLen = 64
 
+// maxLeavesPerRangeResponse caps the leaves returned by GetLeavesByRange, and
+// by each response of StreamLeavesByRange.
+const maxLeavesPerRangeResponse = 1000
+
+// DefaultMaxResultsPerHash is the default value of TrillianLogRPCServer.MaxResultsPerHash.
+const DefaultMaxResultsPerHash = 1000
+
+var (
+	// Leaves of either kind of log may be read, but they're only queued to LOG trees and only
+	// added at given indices to PREORDERED_LOG trees.
+	optsLogRead = trees.GetOpts{
+		TreeTypes: []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG},
+		Readonly:  true,
+	}
+	optsLogWrite           = trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}}
+	optsPreorderedLogWrite = trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}}
+)
+
 // TrillianLogRPCServer implements the RPC API defined in the proto
 type TrillianLogRPCServer struct {
+	// MaxResultsPerHash caps the leaves returned by GetLeavesByHash and GetLeavesByIdentityHash,
+	// and the proofs returned by GetInclusionProofByHash, in a single response. Further results
+	// are paged.
+	MaxResultsPerHash int
+
 	registry    extension.Registry
 	timeSource  util.TimeSource
 	leafCounter monitoring.Counter
+
+	// signers caches the signers for SignedEntryTimestamps, keyed by tree ID.
+	signers   map[int64]*crypto.Signer
+	signersMu sync.Mutex
 }
 
 // NewTrillianLogRPCServer creates a new RPC server backed by a LogStorageProvider.
@@ -49,13 +80,15 @@ func NewTrillianLogRPCServer(registry extension.Registry, timeSource util.TimeSo
 		mf = monitoring.InertMetricFactory{}
 	}
 	return &TrillianLogRPCServer{
-		registry:   registry,
-		timeSource: timeSource,
+		MaxResultsPerHash: DefaultMaxResultsPerHash,
+		registry:          registry,
+		timeSource:        timeSource,
 		leafCounter: mf.NewCounter(
 			"queued_leaves",
 			"Number of leaves requested to be queued",
 			"status",
 		),
+		signers: make(map[int64]*crypto.Signer),
 	}
 }
 
@@ -67,8 +100,9 @@ func (t *TrillianLogRPCServer) IsHealthy() error {
 // QueueLeaf submits one leaf to the queue.
 func (t *TrillianLogRPCServer) QueueLeaf(ctx context.Context, req *trillian.QueueLeafRequest) (*trillian.QueueLeafResponse, error) {
 	queueReq := &trillian.QueueLeavesRequest{
-		LogId:  req.LogId,
-		Leaves: []*trillian.LogLeaf{req.Leaf},
+		LogId:                       req.LogId,
+		Leaves:                      []*trillian.LogLeaf{req.Leaf},
+		IncludeSignedEntryTimestamp: req.IncludeSignedEntryTimestamp,
 	}
 	queueRsp, err := t.QueueLeaves(ctx, queueReq)
 	if err != nil {
@@ -90,14 +124,23 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 	}
 	logID := req.LogId
 
-	tree, hasher, err := t.getTreeAndHasher(ctx, logID, false /* readonly */)
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsLogWrite)
 	if err != nil {
 		return nil, err
 	}
 	ctx = trees.NewContext(ctx, tree)
 
+	queueTimestamp := t.timeSource.Now()
 	for i := range req.Leaves {
 		req.Leaves[i].MerkleLeafHash = hasher.HashLeaf(req.Leaves[i].LeafValue)
+		req.Leaves[i].QueueTimestampNanos = queueTimestamp.UnixNano()
+	}
+
+	var signer *crypto.Signer
+	if req.IncludeSignedEntryTimestamp {
+		if signer, err = t.getSigner(ctx, tree); err != nil {
+			return nil, err
+		}
 	}
 
 	tx, err := t.prepareStorageTx(ctx, logID)
@@ -106,11 +149,26 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 	}
 	defer tx.Close()
 
-	existingLeaves, err := tx.QueueLeaves(ctx, req.Leaves, t.timeSource.Now())
+	existingLeaves, err := tx.QueueLeaves(ctx, req.Leaves, queueTimestamp)
 	if err != nil {
 		return nil, err
 	}
 
+	// Sign before committing, so that a leaf is never queued without the promise the caller
+	// asked for.
+	var sets []*trillian.SignedEntryTimestamp
+	if signer != nil {
+		sets = make([]*trillian.SignedEntryTimestamp, len(existingLeaves))
+		for i, existingLeaf := range existingLeaves {
+			if existingLeaf != nil {
+				continue
+			}
+			if sets[i], err = signEntryTimestamp(signer, logID, req.Leaves[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if err := t.commitAndLog(ctx, logID, tx, "QueueLeaves"); err != nil {
 		return nil, err
 	}
@@ -128,6 +186,9 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 		} else {
 			// Return the leaf from the request if it is new.
 			queuedLeaf := trillian.QueuedLogLeaf{Leaf: req.Leaves[i]}
+			if sets != nil {
+				queuedLeaf.SignedEntryTimestamp = sets[i]
+			}
 			queuedLeaves = append(queuedLeaves, &queuedLeaf)
 			t.leafCounter.Inc("new")
 		}
@@ -135,6 +196,68 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 	return &trillian.QueueLeavesResponse{QueuedLeaves: queuedLeaves}, nil
 }
 
+// signEntryTimestamp returns a SignedEntryTimestamp promising to integrate leaf, which has
+// just been queued.
+func signEntryTimestamp(signer *crypto.Signer, logID int64, leaf *trillian.LogLeaf) (*trillian.SignedEntryTimestamp, error) {
+	set := trillian.SignedEntryTimestamp{
+		TimestampNanos: leaf.QueueTimestampNanos,
+		LogId:          logID,
+		MerkleLeafHash: leaf.MerkleLeafHash,
+	}
+	signature, err := signer.Sign(crypto.HashSignedEntryTimestamp(set))
+	if err != nil {
+		glog.Warningf("%v: signer failed to sign entry timestamp: %v", logID, err)
+		return nil, status.Errorf(codes.Internal, "failed to sign entry timestamp: %v", err)
+	}
+	set.Signature = signature
+	return &set, nil
+}
+
+// AddSequencedLeaves adds a batch of leaves to a pre-ordered log, at the indices set by the caller.
+// The leaves are integrated into the tree by the signer once all the leaves before them are present.
+func (t *TrillianLogRPCServer) AddSequencedLeaves(ctx context.Context, req *trillian.AddSequencedLeavesRequest) (*trillian.AddSequencedLeavesResponse, error) {
+	if err := validateAddSequencedLeavesRequest(req); err != nil {
+		return nil, err
+	}
+	logID := req.LogId
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsPreorderedLogWrite)
+	if err != nil {
+		return nil, err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	for i := range req.Leaves {
+		req.Leaves[i].MerkleLeafHash = hasher.HashLeaf(req.Leaves[i].LeafValue)
+	}
+
+	tx, err := t.prepareStorageTx(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	results, err := tx.AddSequencedLeaves(ctx, req.Leaves)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.commitAndLog(ctx, logID, tx, "AddSequencedLeaves"); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Status == nil || result.Status.Code == int32(codes.OK):
+			t.leafCounter.Inc("new")
+		case result.Status.Code == int32(codes.AlreadyExists):
+			t.leafCounter.Inc("existing")
+		default:
+			t.leafCounter.Inc("conflicting")
+		}
+	}
+	return &trillian.AddSequencedLeavesResponse{Results: results}, nil
+}
+
 // GetInclusionProof obtains the proof of inclusion in the tree for a leaf that has been sequenced.
 // Similar to the get proof by hash handler but one less step as we don't need to look up the index
 func (t *TrillianLogRPCServer) GetInclusionProof(ctx context.Context, req *trillian.GetInclusionProofRequest) (*trillian.GetInclusionProofResponse, error) {
@@ -143,7 +266,7 @@ func (t *TrillianLogRPCServer) GetInclusionProof(ctx context.Context, req *trill
 	}
 	logID := req.LogId
 
-	tree, hasher, err := t.getTreeAndHasher(ctx, logID, true /* readonly */)
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsLogRead)
 	if err != nil {
 		return nil, err
 	}
@@ -166,12 +289,20 @@ func (t *TrillianLogRPCServer) GetInclusionProof(ctx context.Context, req *trill
 	if err != nil {
 		return nil, err
 	}
+	consistency, err := getConsistencyProofToRoot(ctx, tx, hasher, req.TreeSize, root.TreeSize)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &trillian.GetInclusionProofResponse{Proof: &proof}, nil
+	return &trillian.GetInclusionProofResponse{
+		Proof:            &proof,
+		SignedLogRoot:    &root,
+		ConsistencyProof: consistency,
+	}, nil
 }
 
 // GetInclusionProofByHash obtains proofs of inclusion by leaf hash. Because some logs can
@@ -180,9 +311,13 @@ func (t *TrillianLogRPCServer) GetInclusionProofByHash(ctx context.Context, req
 	if err := validateGetInclusionProofByHashRequest(req); err != nil {
 		return nil, err
 	}
+	offset, err := parseHashPageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
 	logID := req.LogId
 
-	tree, hasher, err := t.getTreeAndHasher(ctx, logID, true /* readonly */)
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsLogRead)
 	if err != nil {
 		return nil, err
 	}
@@ -196,37 +331,59 @@ func (t *TrillianLogRPCServer) GetInclusionProofByHash(ctx context.Context, req
 	}
 	defer tx.Close()
 
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find the leaf index of the supplied hash
 	leafHashes := [][]byte{req.LeafHash}
-	leaves, err := tx.GetLeavesByHash(ctx, leafHashes, req.OrderBySequence)
+	leaves, nextPageToken, err := t.getLeavesByHashPage(ctx, tx, leafHashes, req.OrderBySequence, offset, root.TreeSize)
 	if err != nil {
 		return nil, err
 	}
-	if len(leaves) < 1 {
+	if len(leaves) < 1 && offset == 0 {
 		return nil, status.Errorf(codes.NotFound, "No leaves for hash: %x", req.LeafHash)
 	}
 
-	root, err := tx.LatestSignedLogRoot(ctx)
-	if err != nil {
-		return nil, err
+	treeSize := req.TreeSize
+	if treeSize == 0 {
+		treeSize = root.TreeSize
 	}
 
-	// TODO(Martin2112): Need to define a limit on number of results or some form of paging etc.
 	proofs := make([]*trillian.Proof, 0, len(leaves))
 	for _, leaf := range leaves {
-		proof, err := getInclusionProofForLeafIndex(ctx, tx, hasher, req.TreeSize, leaf.LeafIndex, root.TreeSize)
+		proof, err := getInclusionProofForLeafIndex(ctx, tx, hasher, treeSize, leaf.LeafIndex, root.TreeSize)
 		if err != nil {
 			return nil, err
 		}
 		proofs = append(proofs, &proof)
 	}
 
+	// A FirstTreeSize beyond the latest root means that this server lags behind
+	// the caller, e.g. a replica which hasn't caught up yet. The consistency
+	// proof is left unset, and the caller can tell from SignedLogRoot.
+	var consistency *trillian.Proof
+	if req.FirstTreeSize > 0 && req.FirstTreeSize <= treeSize {
+		consistency = &trillian.Proof{}
+		if req.FirstTreeSize < treeSize {
+			proof, err := getConsistencyProof(ctx, tx, hasher, req.FirstTreeSize, treeSize, root.TreeSize)
+			if err != nil {
+				return nil, err
+			}
+			consistency = &proof
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return &trillian.GetInclusionProofByHashResponse{
-		Proof: proofs,
+		Proof:            proofs,
+		NextPageToken:    nextPageToken,
+		SignedLogRoot:    &root,
+		ConsistencyProof: consistency,
 	}, nil
 }
 
@@ -239,7 +396,7 @@ func (t *TrillianLogRPCServer) GetConsistencyProof(ctx context.Context, req *tri
 	}
 	logID := req.LogId
 
-	tree, hasher, err := t.getTreeAndHasher(ctx, logID, true /* readonly */)
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsLogRead)
 	if err != nil {
 		return nil, err
 	}
@@ -256,14 +413,7 @@ func (t *TrillianLogRPCServer) GetConsistencyProof(ctx context.Context, req *tri
 		return nil, err
 	}
 
-	nodeFetches, err := merkle.CalcConsistencyProofNodeAddresses(req.FirstTreeSize, req.SecondTreeSize, root.TreeSize, proofMaxBitLen)
-	if err != nil {
-		return nil, err
-	}
-
-	// Do all the node fetches at the second tree revision, which is what the node ids were calculated
-	// against.
-	proof, err := fetchNodesAndBuildProof(ctx, tx, hasher, tx.ReadRevision(), 0, nodeFetches)
+	proof, err := getConsistencyProof(ctx, tx, hasher, req.FirstTreeSize, req.SecondTreeSize, root.TreeSize)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +423,7 @@ func (t *TrillianLogRPCServer) GetConsistencyProof(ctx context.Context, req *tri
 	}
 
 	// We have everything we need. Return the proof
-	return &trillian.GetConsistencyProofResponse{Proof: &proof}, nil
+	return &trillian.GetConsistencyProofResponse{Proof: &proof, SignedLogRoot: &root}, nil
 }
 
 // GetLatestSignedLogRoot obtains the latest published tree root for the Merkle Tree that
@@ -306,10 +456,19 @@ func (t *TrillianLogRPCServer) GetSequencedLeafCount(ctx context.Context, req *t
 	}
 	defer tx.Close()
 
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	leafCount, err := tx.GetSequencedLeafCount(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// Storage also counts the leaves that are stored at their indices but not integrated yet.
+	if leafCount > root.TreeSize {
+		leafCount = root.TreeSize
+	}
 
 	if err := t.commitAndLog(ctx, req.LogId, tx, "GetSequencedLeafCount"); err != nil {
 		return nil, err
@@ -319,9 +478,8 @@ func (t *TrillianLogRPCServer) GetSequencedLeafCount(ctx context.Context, req *t
 }
 
 // GetLeavesByIndex obtains one or more leaves based on their sequence number within the
-// tree. It is not possible to fetch leaves that have been queued but not yet integrated.
-// TODO: Validate indices against published tree size in case we implement write sharding that
-// can get ahead of this point. Not currently clear what component should own this state.
+// tree. It is not possible to fetch leaves that have been queued but not yet integrated, nor
+// leaves beyond the latest signed tree head.
 func (t *TrillianLogRPCServer) GetLeavesByIndex(ctx context.Context, req *trillian.GetLeavesByIndexRequest) (*trillian.GetLeavesByIndexResponse, error) {
 	if !validateLeafIndices(req.LeafIndex) {
 		return &trillian.GetLeavesByIndexResponse{}, nil
@@ -333,6 +491,11 @@ func (t *TrillianLogRPCServer) GetLeavesByIndex(ctx context.Context, req *trilli
 	}
 	defer tx.Close()
 
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	leaves, err := tx.GetLeavesByIndex(ctx, req.LeafIndex)
 	if err != nil {
 		return nil, err
@@ -343,17 +506,257 @@ func (t *TrillianLogRPCServer) GetLeavesByIndex(ctx context.Context, req *trilli
 	}
 
 	return &trillian.GetLeavesByIndexResponse{
-		Leaves: leaves,
+		Leaves: integratedLeaves(leaves, root.TreeSize),
 	}, nil
 }
 
+// GetLeavesByRange obtains consecutive leaves from a starting index. Only leaves covered by the
+// latest signed tree head are returned, and at most maxLeavesPerRangeResponse of them.
+func (t *TrillianLogRPCServer) GetLeavesByRange(ctx context.Context, req *trillian.GetLeavesByRangeRequest) (*trillian.GetLeavesByRangeResponse, error) {
+	if err := validateGetLeavesByRangeRequest(req); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.prepareReadOnlyStorageTx(ctx, req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count := req.Count
+	if count > maxLeavesPerRangeResponse {
+		count = maxLeavesPerRangeResponse
+	}
+	leaves, err := getLeavesByRange(ctx, tx, req.StartIndex, clampRangeEnd(req.StartIndex, count, root.TreeSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.commitAndLog(ctx, req.LogId, tx, "GetLeavesByRange"); err != nil {
+		return nil, err
+	}
+
+	return &trillian.GetLeavesByRangeResponse{Leaves: leaves}, nil
+}
+
+// StreamLeavesByRange sends the leaves GetLeavesByRange would return without its limit on the
+// number of leaves, maxLeavesPerRangeResponse at a time. Each response is read in its own
+// transaction, so slow clients don't hold storage transactions open.
+func (t *TrillianLogRPCServer) StreamLeavesByRange(req *trillian.GetLeavesByRangeRequest, stream trillian.TrillianLog_StreamLeavesByRangeServer) error {
+	ctx := stream.Context()
+	if err := validateGetLeavesByRangeRequest(req); err != nil {
+		return err
+	}
+	if _, _, err := t.getTreeAndHasher(ctx, req.LogId, optsLogRead); err != nil {
+		return err
+	}
+
+	treeSize, err := t.latestTreeSize(ctx, req.LogId)
+	if err != nil {
+		return err
+	}
+
+	start, end := req.StartIndex, clampRangeEnd(req.StartIndex, req.Count, treeSize)
+	for start < end {
+		chunkEnd := clampRangeEnd(start, maxLeavesPerRangeResponse, end)
+		leaves, err := t.readLeavesByRange(ctx, req.LogId, start, chunkEnd)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&trillian.GetLeavesByRangeResponse{Leaves: leaves}); err != nil {
+			return err
+		}
+		start = chunkEnd
+	}
+	return nil
+}
+
+// latestTreeSize returns the size of the latest signed tree head of the log.
+func (t *TrillianLogRPCServer) latestTreeSize(ctx context.Context, logID int64) (int64, error) {
+	tx, err := t.prepareReadOnlyStorageTx(ctx, logID)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Close()
+
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := t.commitAndLog(ctx, logID, tx, "StreamLeavesByRange"); err != nil {
+		return 0, err
+	}
+	return root.TreeSize, nil
+}
+
+// readLeavesByRange reads the leaves from start to end (exclusive) in a new transaction.
+func (t *TrillianLogRPCServer) readLeavesByRange(ctx context.Context, logID, start, end int64) ([]*trillian.LogLeaf, error) {
+	tx, err := t.prepareReadOnlyStorageTx(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	leaves, err := getLeavesByRange(ctx, tx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.commitAndLog(ctx, logID, tx, "StreamLeavesByRange"); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// clampRangeEnd returns the end (exclusive) of the range of count indexes from start, clamped to
+// treeSize. The result is never less than start.
+func clampRangeEnd(start, count, treeSize int64) int64 {
+	end := treeSize
+	if count < treeSize-start {
+		end = start + count
+	}
+	if end < start {
+		return start
+	}
+	return end
+}
+
+// getLeavesByRange reads the leaves from start to end (exclusive), which must all have been
+// sequenced.
+func getLeavesByRange(ctx context.Context, tx storage.ReadOnlyLogTreeTX, start, end int64) ([]*trillian.LogLeaf, error) {
+	if start >= end {
+		return []*trillian.LogLeaf{}, nil
+	}
+	leaves, err := tx.GetLeavesByRange(ctx, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+	if got, want := int64(len(leaves)), end-start; got != want {
+		return nil, status.Errorf(codes.Internal, "len(leaves): %v, want %v", got, want)
+	}
+	for i, leaf := range leaves {
+		if got, want := leaf.LeafIndex, start+int64(i); got != want {
+			return nil, status.Errorf(codes.Internal, "leaves[%v].LeafIndex: %v, want %v", i, got, want)
+		}
+	}
+	return leaves, nil
+}
+
 // GetLeavesByHash obtains one or more leaves based on their tree hash. It is not possible
 // to fetch leaves that have been queued but not yet integrated. Logs may accept duplicate
 // entries so this may return more results than the number of hashes in the request.
 func (t *TrillianLogRPCServer) GetLeavesByHash(ctx context.Context, req *trillian.GetLeavesByHashRequest) (*trillian.GetLeavesByHashResponse, error) {
-	return t.getLeavesByHashInternal(ctx, "GetLeavesByHash", req, func(tx storage.ReadOnlyLogTreeTX, hashes [][]byte, sequenceOrder bool) ([]*trillian.LogLeaf, error) {
-		return tx.GetLeavesByHash(ctx, hashes, sequenceOrder)
+	return t.getLeavesByHashInternal(ctx, "GetLeavesByHash", req)
+}
+
+// GetLeavesByIdentityHash obtains sequenced leaves by their LeafIdentityHash, which is all that
+// some personalities know about their entries. If requested, each leaf comes with an inclusion
+// proof. At most MaxResultsPerHash leaves are returned, along with a page token for the rest.
+func (t *TrillianLogRPCServer) GetLeavesByIdentityHash(ctx context.Context, req *trillian.GetLeavesByIdentityHashRequest) (*trillian.GetLeavesByIdentityHashResponse, error) {
+	if err := validateGetLeavesByIdentityHashRequest(req); err != nil {
+		return nil, err
+	}
+	offset, err := parseHashPageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	tx, err := t.prepareReadOnlyStorageTx(ctx, req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, nextPageToken, err := t.getLeavesPage(offset, root.TreeSize, func(limit, offset int) ([]*trillian.LogLeaf, error) {
+		return tx.GetLeavesByIdentityHash(ctx, req.LeafIdentityHash, limit, offset)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	var proofs []*trillian.Proof
+	if req.IncludeProof {
+		treeSize := req.TreeSize
+		if treeSize == 0 {
+			treeSize = root.TreeSize
+		}
+		for _, leaf := range leaves {
+			if leaf.LeafIndex >= treeSize {
+				return nil, status.Errorf(codes.OutOfRange, "leaf %v is not in the tree of size %v", leaf.LeafIndex, treeSize)
+			}
+		}
+		proofs = make([]*trillian.Proof, 0, len(leaves))
+		for _, leaf := range leaves {
+			proof, err := getInclusionProofForLeafIndex(ctx, tx, hasher, treeSize, leaf.LeafIndex, root.TreeSize)
+			if err != nil {
+				return nil, err
+			}
+			proofs = append(proofs, &proof)
+		}
+	}
+
+	if err := t.commitAndLog(ctx, req.LogId, tx, "GetLeavesByIdentityHash"); err != nil {
+		return nil, err
+	}
+
+	return &trillian.GetLeavesByIdentityHashResponse{
+		Leaves:        leaves,
+		Proof:         proofs,
+		SignedLogRoot: &root,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// GetLeafStatus reports whether the leaf with the given LeafIdentityHash is waiting to be
+// sequenced, has been sequenced, or is unknown to the log.
+func (t *TrillianLogRPCServer) GetLeafStatus(ctx context.Context, req *trillian.GetLeafStatusRequest) (*trillian.GetLeafStatusResponse, error) {
+	if err := validateGetLeafStatusRequest(req); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.prepareReadOnlyStorageTx(ctx, req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leafStatus, err := tx.GetLeafStatus(ctx, req.LeafIdentityHash)
+	if err != nil {
+		return nil, err
+	}
+	// The leaf may have been stored with its index before the log integrated it.
+	if leafStatus.Status == trillian.LeafStatus_SEQUENCED && leafStatus.LeafIndex >= root.TreeSize {
+		leafStatus = storage.LeafStatus{Status: trillian.LeafStatus_QUEUED}
+	}
+
+	if err := t.commitAndLog(ctx, req.LogId, tx, "GetLeafStatus"); err != nil {
+		return nil, err
+	}
+
+	return &trillian.GetLeafStatusResponse{
+		Status:              leafStatus.Status,
+		QueueTimestampNanos: leafStatus.QueueTimestampNanos,
+		LeafIndex:           leafStatus.LeafIndex,
+	}, nil
 }
 
 // GetEntryAndProof returns both a Merkle Leaf entry and an inclusion proof for a given index
@@ -364,7 +767,7 @@ func (t *TrillianLogRPCServer) GetEntryAndProof(ctx context.Context, req *trilli
 	}
 	logID := req.LogId
 
-	tree, hasher, err := t.getTreeAndHasher(ctx, logID, true /* readonly */)
+	tree, hasher, err := t.getTreeAndHasher(ctx, logID, optsLogRead)
 	if err != nil {
 		return nil, err
 	}
@@ -398,14 +801,21 @@ func (t *TrillianLogRPCServer) GetEntryAndProof(ctx context.Context, req *trilli
 		return nil, status.Errorf(codes.Internal, "expected one leaf from storage but got: %d", len(leaves))
 	}
 
+	consistency, err := getConsistencyProofToRoot(ctx, tx, hasher, req.TreeSize, root.TreeSize)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	// Work is complete, we have everything we need for the response
 	return &trillian.GetEntryAndProofResponse{
-		Proof: &proof,
-		Leaf:  leaves[0],
+		Proof:            &proof,
+		Leaf:             leaves[0],
+		SignedLogRoot:    &root,
+		ConsistencyProof: consistency,
 	}, nil
 }
 
@@ -467,12 +877,44 @@ func getInclusionProofForLeafIndex(ctx context.Context, tx storage.ReadOnlyLogTr
 	return fetchNodesAndBuildProof(ctx, tx, hasher, tx.ReadRevision(), leafIndex, proofNodeIDs)
 }
 
+// getConsistencyProof builds the consistency proof between snapshot1 and snapshot2, reading
+// the nodes at the revision of the tree with treeSize leaves.
+func getConsistencyProof(ctx context.Context, tx storage.ReadOnlyLogTreeTX, hasher hashers.LogHasher, snapshot1, snapshot2, treeSize int64) (trillian.Proof, error) {
+	nodeFetches, err := merkle.CalcConsistencyProofNodeAddresses(snapshot1, snapshot2, treeSize, proofMaxBitLen)
+	if err != nil {
+		return trillian.Proof{}, err
+	}
+
+	// Do all the node fetches at the second tree revision, which is what the node ids were calculated
+	// against.
+	return fetchNodesAndBuildProof(ctx, tx, hasher, tx.ReadRevision(), 0, nodeFetches)
+}
+
+// getConsistencyProofToRoot builds the consistency proof from a proof's tree
+// size to the latest root, which has treeSize leaves, so that the proof can be
+// verified against that root. It returns nil if the proof is already for the
+// latest root.
+func getConsistencyProofToRoot(ctx context.Context, tx storage.ReadOnlyLogTreeTX, hasher hashers.LogHasher, snapshot, treeSize int64) (*trillian.Proof, error) {
+	if snapshot >= treeSize {
+		return nil, nil
+	}
+	proof, err := getConsistencyProof(ctx, tx, hasher, snapshot, treeSize, treeSize)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
 // getLeavesByHashInternal does the work of fetching leaves by either their raw data or merkle
 // tree hash depending on the supplied fetch function
-func (t *TrillianLogRPCServer) getLeavesByHashInternal(ctx context.Context, desc string, req *trillian.GetLeavesByHashRequest, fetchFunc func(storage.ReadOnlyLogTreeTX, [][]byte, bool) ([]*trillian.LogLeaf, error)) (*trillian.GetLeavesByHashResponse, error) {
+func (t *TrillianLogRPCServer) getLeavesByHashInternal(ctx context.Context, desc string, req *trillian.GetLeavesByHashRequest) (*trillian.GetLeavesByHashResponse, error) {
 	if len(req.LeafHash) == 0 || !validateLeafHashes(req.LeafHash) {
 		return nil, status.Errorf(codes.FailedPrecondition, "Invalid leaf hash")
 	}
+	offset, err := parseHashPageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, err := t.prepareReadOnlyStorageTx(ctx, req.LogId)
 	if err != nil {
@@ -480,7 +922,12 @@ func (t *TrillianLogRPCServer) getLeavesByHashInternal(ctx context.Context, desc
 	}
 	defer tx.Close()
 
-	leaves, err := fetchFunc(tx, req.LeafHash, req.OrderBySequence)
+	root, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, nextPageToken, err := t.getLeavesByHashPage(ctx, tx, req.LeafHash, req.OrderBySequence, offset, root.TreeSize)
 	if err != nil {
 		return nil, err
 	}
@@ -490,16 +937,92 @@ func (t *TrillianLogRPCServer) getLeavesByHashInternal(ctx context.Context, desc
 	}
 
 	return &trillian.GetLeavesByHashResponse{
-		Leaves: leaves,
+		Leaves:        leaves,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
-func (t *TrillianLogRPCServer) getTreeAndHasher(ctx context.Context, treeID int64, readonly bool) (*trillian.Tree, hashers.LogHasher, error) {
-	tree, err := trees.GetTree(
-		ctx,
-		t.registry.AdminStorage,
-		treeID,
-		trees.GetOpts{TreeType: trillian.TreeType_LOG, Readonly: readonly})
+// getLeavesByHashPage reads at most MaxResultsPerHash leaves matching hashes, skipping the first
+// offset matches. If there are more matches, it also returns the page token to fetch them.
+// Leaves at or beyond treeSize are left out.
+func (t *TrillianLogRPCServer) getLeavesByHashPage(ctx context.Context, tx storage.ReadOnlyLogTreeTX, hashes [][]byte, orderBySequence bool, offset int, treeSize int64) ([]*trillian.LogLeaf, string, error) {
+	return t.getLeavesPage(offset, treeSize, func(limit, offset int) ([]*trillian.LogLeaf, error) {
+		return tx.GetLeavesByHash(ctx, hashes, orderBySequence, limit, offset)
+	})
+}
+
+// getLeavesPage reads at most MaxResultsPerHash leaves with get, skipping the first offset
+// matches. If there are more matches, it also returns the page token to fetch them. Leaves at or
+// beyond treeSize are left out.
+func (t *TrillianLogRPCServer) getLeavesPage(offset int, treeSize int64, get func(limit, offset int) ([]*trillian.LogLeaf, error)) ([]*trillian.LogLeaf, string, error) {
+	limit := t.MaxResultsPerHash
+	if limit <= 0 {
+		limit = DefaultMaxResultsPerHash
+	}
+	// Ask for one more leaf than needed to find out whether there's another page.
+	leaves, err := get(limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	// Pages are in index order, so leaves not integrated yet come last, after any integrated
+	// leaves on later pages.
+	for i, leaf := range leaves {
+		if leaf.LeafIndex >= treeSize {
+			leaves = leaves[:i]
+			break
+		}
+	}
+	if len(leaves) <= limit {
+		return leaves, "", nil
+	}
+	return leaves[:limit], strconv.Itoa(offset + limit), nil
+}
+
+// integratedLeaves returns the leaves below treeSize. Leaves of PREORDERED_LOG trees, and
+// leaves assigned indices by a pipelined signer, are stored before they're integrated, and must
+// not be served until a signed log root covers them.
+func integratedLeaves(leaves []*trillian.LogLeaf, treeSize int64) []*trillian.LogLeaf {
+	ret := make([]*trillian.LogLeaf, 0, len(leaves))
+	for _, leaf := range leaves {
+		if leaf.LeafIndex < treeSize {
+			ret = append(ret, leaf)
+		}
+	}
+	return ret
+}
+
+// parseHashPageToken returns the number of results to skip for a page token of
+// GetLeavesByHash, GetLeavesByIdentityHash or GetInclusionProofByHash.
+func parseHashPageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(token)
+	if err != nil || offset < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+	}
+	return offset, nil
+}
+
+// getSigner returns a signer for the given tree.
+// Signers are cached, so only one will be created per tree.
+func (t *TrillianLogRPCServer) getSigner(ctx context.Context, tree *trillian.Tree) (*crypto.Signer, error) {
+	t.signersMu.Lock()
+	defer t.signersMu.Unlock()
+
+	if signer, ok := t.signers[tree.TreeId]; ok {
+		return signer, nil
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to get signer for log %v: %v", tree.TreeId, err)
+	}
+	t.signers[tree.TreeId] = signer
+	return signer, nil
+}
+
+func (t *TrillianLogRPCServer) getTreeAndHasher(ctx context.Context, treeID int64, opts trees.GetOpts) (*trillian.Tree, hashers.LogHasher, error) {
+	tree, err := trees.GetTree(ctx, t.registry.AdminStorage, treeID, opts)
 	if err != nil {
 		return nil, nil, err
 	}
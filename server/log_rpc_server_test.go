@@ -15,20 +15,32 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
+	tcrypto "github.com/google/trillian/crypto"
+	"github.com/google/trillian/crypto/keys"
 	"github.com/google/trillian/extension"
+	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/rfc6962"
 	"github.com/google/trillian/storage"
 	stestonly "github.com/google/trillian/storage/testonly"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -112,6 +124,7 @@ func TestGetLeavesByIndexStorageError(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetLeavesByIndex", readOnly,
 		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().GetLeavesByIndex(gomock.Any(), []int64{0}).Return(nil, errors.New("STORAGE"))
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -142,6 +155,7 @@ func TestGetLeavesByIndexCommitFails(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetLeavesByIndex", readOnly,
 		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().GetLeavesByIndex(gomock.Any(), []int64{0}).Return([]*trillian.LogLeaf{leaf1}, nil)
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -159,6 +173,7 @@ func TestGetLeavesByIndex(t *testing.T) {
 	mockStorage := storage.NewMockLogStorage(ctrl)
 	mockTx := storage.NewMockLogTreeTX(ctrl)
 	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), leaf0Request.LogId).Return(mockTx, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 	mockTx.EXPECT().GetLeavesByIndex(gomock.Any(), []int64{0}).Return([]*trillian.LogLeaf{leaf1}, nil)
 	mockTx.EXPECT().Commit().Return(nil)
 	mockTx.EXPECT().Close().Return(nil)
@@ -187,6 +202,7 @@ func TestGetLeavesByIndexMultiple(t *testing.T) {
 	mockStorage := storage.NewMockLogStorage(ctrl)
 	mockTx := storage.NewMockLogTreeTX(ctrl)
 	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), leaf03Request.LogId).Return(mockTx, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 	mockTx.EXPECT().GetLeavesByIndex(gomock.Any(), []int64{0, 3}).Return([]*trillian.LogLeaf{leaf1, leaf3}, nil)
 	mockTx.EXPECT().Commit().Return(nil)
 	mockTx.EXPECT().Close().Return(nil)
@@ -216,6 +232,210 @@ func TestGetLeavesByIndexMultiple(t *testing.T) {
 	}
 }
 
+func TestGetLeavesByIndexNotIntegrated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A leaf stored at its index, but not yet covered by the signed log root.
+	pending := &trillian.LogLeaf{LeafIndex: signedRoot1.TreeSize, LeafValue: []byte("pending")}
+	req := &trillian.GetLeavesByIndexRequest{LogId: logID1, LeafIndex: []int64{1, signedRoot1.TreeSize}}
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().GetLeavesByIndex(gomock.Any(), req.LeafIndex).Return([]*trillian.LogLeaf{leaf1, pending}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	resp, err := server.GetLeavesByIndex(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetLeavesByIndex(): %v", err)
+	}
+	if len(resp.Leaves) != 1 || !proto.Equal(resp.Leaves[0], leaf1) {
+		t.Errorf("GetLeavesByIndex().Leaves: %v, want [%v]", resp.Leaves, leaf1)
+	}
+}
+
+func TestGetLeavesByRangeInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+
+	for _, req := range []*trillian.GetLeavesByRangeRequest{
+		{LogId: logID1, StartIndex: -1, Count: 1},
+		{LogId: logID1, StartIndex: 0, Count: 0},
+		{LogId: logID1, StartIndex: 0, Count: -1},
+	} {
+		_, err := server.GetLeavesByRange(context.Background(), req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("GetLeavesByRange(%+v): %v, want code %v", req, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestGetLeavesByRangeStorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &trillian.GetLeavesByRangeRequest{LogId: logID1, StartIndex: 0, Count: 1}
+	test := newParameterizedTest(ctrl, "GetLeavesByRange", readOnly,
+		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(1)).Return(nil, errors.New("STORAGE"))
+		},
+		func(s *TrillianLogRPCServer) error {
+			_, err := s.GetLeavesByRange(context.Background(), req)
+			return err
+		})
+
+	test.executeStorageFailureTest(t, logID1)
+}
+
+// rangeLeaves returns count leaves with consecutive indexes from start.
+func rangeLeaves(start, count int64) []*trillian.LogLeaf {
+	leaves := make([]*trillian.LogLeaf, 0, count)
+	for i := start; i < start+count; i++ {
+		leaves = append(leaves, &trillian.LogLeaf{LeafIndex: i, LeafValue: []byte(fmt.Sprintf("leaf%d", i))})
+	}
+	return leaves
+}
+
+func TestGetLeavesByRange(t *testing.T) {
+	for _, test := range []struct {
+		desc       string
+		start      int64
+		count      int64
+		treeSize   int64
+		wantCount  int64 // The count passed to storage, if called.
+		leaves     []*trillian.LogLeaf
+		wantLeaves int
+		wantCode   codes.Code
+	}{
+		{desc: "inTree", start: 1, count: 3, treeSize: 7, wantCount: 3, leaves: rangeLeaves(1, 3), wantLeaves: 3},
+		{desc: "clampedToTreeSize", start: 5, count: 10, treeSize: 7, wantCount: 2, leaves: rangeLeaves(5, 2), wantLeaves: 2},
+		{desc: "clampedToMax", start: 0, count: 5000, treeSize: 5000, wantCount: maxLeavesPerRangeResponse, leaves: rangeLeaves(0, maxLeavesPerRangeResponse), wantLeaves: maxLeavesPerRangeResponse},
+		{desc: "beyondTree", start: 7, count: 1, treeSize: 7},
+		{desc: "missingLeaves", start: 0, count: 3, treeSize: 7, wantCount: 3, leaves: rangeLeaves(0, 2), wantCode: codes.Internal},
+		{desc: "wrongIndex", start: 0, count: 2, treeSize: 7, wantCount: 2, leaves: rangeLeaves(1, 2), wantCode: codes.Internal},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := storage.NewMockLogStorage(ctrl)
+			mockTx := storage.NewMockLogTreeTX(ctrl)
+			mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(trillian.SignedLogRoot{TreeSize: test.treeSize}, nil)
+			if test.wantCount > 0 {
+				mockTx.EXPECT().GetLeavesByRange(gomock.Any(), test.start, test.wantCount).Return(test.leaves, nil)
+			}
+			if test.wantCode == codes.OK {
+				mockTx.EXPECT().Commit().Return(nil)
+			}
+			mockTx.EXPECT().Close().Return(nil)
+			mockTx.EXPECT().IsOpen().AnyTimes().Return(false)
+
+			registry := extension.Registry{
+				AdminStorage: mockAdminStorage(ctrl, logID1),
+				LogStorage:   mockStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+			req := &trillian.GetLeavesByRangeRequest{LogId: logID1, StartIndex: test.start, Count: test.count}
+			resp, err := server.GetLeavesByRange(context.Background(), req)
+			if test.wantCode != codes.OK {
+				if s, ok := status.FromError(err); !ok || s.Code() != test.wantCode {
+					t.Fatalf("GetLeavesByRange(): %v, want code %v", err, test.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLeavesByRange(): %v", err)
+			}
+			if got := len(resp.Leaves); got != test.wantLeaves {
+				t.Errorf("len(GetLeavesByRange().Leaves): %v, want %v", got, test.wantLeaves)
+			}
+		})
+	}
+}
+
+// fakeLeavesByRangeStream collects the responses sent by StreamLeavesByRange.
+type fakeLeavesByRangeStream struct {
+	grpc.ServerStream
+	resps []*trillian.GetLeavesByRangeResponse
+}
+
+func (s *fakeLeavesByRangeStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeLeavesByRangeStream) Send(resp *trillian.GetLeavesByRangeResponse) error {
+	s.resps = append(s.resps, resp)
+	return nil
+}
+
+func TestStreamLeavesByRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// One transaction for the tree size, then one per response.
+	const treeSize = 2*maxLeavesPerRangeResponse + 10
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	rootTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(rootTx, nil)
+	rootTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(trillian.SignedLogRoot{TreeSize: treeSize}, nil)
+	rootTx.EXPECT().Commit().Return(nil)
+	rootTx.EXPECT().Close().Return(nil)
+	rootTx.EXPECT().IsOpen().AnyTimes().Return(false)
+	for _, r := range []struct{ start, count int64 }{
+		{start: 5, count: maxLeavesPerRangeResponse},
+		{start: 5 + maxLeavesPerRangeResponse, count: maxLeavesPerRangeResponse},
+		{start: 5 + 2*maxLeavesPerRangeResponse, count: 5},
+	} {
+		mockTx := storage.NewMockLogTreeTX(ctrl)
+		mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+		mockTx.EXPECT().GetLeavesByRange(gomock.Any(), r.start, r.count).Return(rangeLeaves(r.start, r.count), nil)
+		mockTx.EXPECT().Commit().Return(nil)
+		mockTx.EXPECT().Close().Return(nil)
+		mockTx.EXPECT().IsOpen().AnyTimes().Return(false)
+	}
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	stream := &fakeLeavesByRangeStream{}
+	req := &trillian.GetLeavesByRangeRequest{LogId: logID1, StartIndex: 5, Count: 3 * maxLeavesPerRangeResponse}
+	if err := server.StreamLeavesByRange(req, stream); err != nil {
+		t.Fatalf("StreamLeavesByRange(): %v", err)
+	}
+	if got, want := len(stream.resps), 3; got != want {
+		t.Fatalf("StreamLeavesByRange() sent %v responses, want %v", got, want)
+	}
+	next := int64(5)
+	for _, resp := range stream.resps {
+		for _, leaf := range resp.Leaves {
+			if leaf.LeafIndex != next {
+				t.Fatalf("StreamLeavesByRange() sent leaf %v, want %v", leaf.LeafIndex, next)
+			}
+			next++
+		}
+	}
+	if next != treeSize {
+		t.Errorf("StreamLeavesByRange() stopped at leaf %v, want %v", next, treeSize)
+	}
+}
+
 func TestQueueLeavesStorageError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -321,6 +541,75 @@ func TestQueueLeaves(t *testing.T) {
 	}
 }
 
+func TestQueueLeavesSignedEntryTimestamp(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var keyProto ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(stestonly.LogTree.PrivateKey, &keyProto); err != nil {
+		t.Fatalf("Failed to unmarshal stestonly.LogTree.PrivateKey: %v", err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	keys.RegisterHandler(fakeKeyProtoHandler(keyProto.Message, key, nil))
+	defer keys.UnregisterHandler(keyProto.Message)
+
+	newLeaf := &trillian.LogLeaf{LeafValue: []byte("new"), LeafIdentityHash: []byte("new")}
+	oldLeaf := &trillian.LogLeaf{LeafValue: []byte("old"), LeafIdentityHash: []byte("old")}
+	req := &trillian.QueueLeavesRequest{
+		LogId:                       logID1,
+		Leaves:                      []*trillian.LogLeaf{newLeaf, oldLeaf},
+		IncludeSignedEntryTimestamp: true,
+	}
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().BeginForTree(gomock.Any(), logID1).Return(mockTx, nil)
+	mockTx.EXPECT().QueueLeaves(gomock.Any(), req.Leaves, fakeTime).Return([]*trillian.LogLeaf{nil, oldLeaf}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockTx.EXPECT().IsOpen().AnyTimes().Return(false)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	rsp, err := server.QueueLeaves(ctx, req)
+	if err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if len(rsp.QueuedLeaves) != 2 {
+		t.Fatalf("QueueLeaves() returns %d leaves; want 2", len(rsp.QueuedLeaves))
+	}
+	if set := rsp.QueuedLeaves[1].SignedEntryTimestamp; set != nil {
+		t.Errorf("QueueLeaves().QueuedLeaves[1].SignedEntryTimestamp=%v; want nil for an existing leaf", set)
+	}
+
+	set := rsp.QueuedLeaves[0].SignedEntryTimestamp
+	if set == nil {
+		t.Fatal("QueueLeaves().QueuedLeaves[0].SignedEntryTimestamp=nil; want a timestamp for a new leaf")
+	}
+	want := trillian.SignedEntryTimestamp{
+		TimestampNanos: fakeTime.UnixNano(),
+		LogId:          logID1,
+		MerkleLeafHash: th.HashLeaf(newLeaf.LeafValue),
+	}
+	if got := *set; got.TimestampNanos != want.TimestampNanos || got.LogId != want.LogId || !bytes.Equal(got.MerkleLeafHash, want.MerkleLeafHash) {
+		t.Errorf("QueueLeaves().QueuedLeaves[0].SignedEntryTimestamp=%v; want %v", set, want)
+	}
+	if err := tcrypto.Verify(key.Public(), tcrypto.HashSignedEntryTimestamp(want), set.Signature); err != nil {
+		t.Errorf("Verify(SignedEntryTimestamp): %v", err)
+	}
+	if got, want := rsp.QueuedLeaves[0].Leaf.QueueTimestampNanos, fakeTime.UnixNano(); got != want {
+		t.Errorf("QueueLeaves().QueuedLeaves[0].Leaf.QueueTimestampNanos=%v; want %v", got, want)
+	}
+}
+
 func TestQueueLeavesNoLeavesRejected(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -347,6 +636,99 @@ func TestQueueLeavesBeginFailsCausesError(t *testing.T) {
 	test.executeBeginFailsTest(t, queueRequest0.LogId)
 }
 
+func TestAddSequencedLeavesInvalidRequest(t *testing.T) {
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+
+	for _, test := range []struct {
+		desc   string
+		leaves []*trillian.LogLeaf
+	}{
+		{desc: "noLeaves", leaves: []*trillian.LogLeaf{}},
+		{desc: "negativeIndex", leaves: []*trillian.LogLeaf{{LeafIndex: -1, LeafValue: leaf1Data}}},
+		{desc: "repeatedIndex", leaves: []*trillian.LogLeaf{{LeafIndex: 1, LeafValue: leaf1Data}, {LeafIndex: 1, LeafValue: leaf3Data}}},
+	} {
+		req := &trillian.AddSequencedLeavesRequest{LogId: logID1, Leaves: test.leaves}
+		_, err := server.AddSequencedLeaves(context.Background(), req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("%v: AddSequencedLeaves(): %v, want code %v", test.desc, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestAddSequencedLeavesRejectsLogTrees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorageForTree(ctrl, stestonly.LogTree, logID1),
+		LogStorage:   storage.NewMockLogStorage(ctrl),
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	req := &trillian.AddSequencedLeavesRequest{LogId: logID1, Leaves: []*trillian.LogLeaf{leaf1}}
+	if _, err := server.AddSequencedLeaves(context.Background(), req); err == nil {
+		t.Error("AddSequencedLeaves() on a LOG tree: nil, want error")
+	}
+}
+
+func TestAddSequencedLeavesStorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().BeginForTree(gomock.Any(), logID1).Return(mockTx, nil)
+	mockTx.EXPECT().AddSequencedLeaves(gomock.Any(), []*trillian.LogLeaf{leaf1}).Return(nil, errors.New("STORAGE"))
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorageForTree(ctrl, stestonly.PreorderedLogTree, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	req := &trillian.AddSequencedLeavesRequest{LogId: logID1, Leaves: []*trillian.LogLeaf{leaf1}}
+	if _, err := server.AddSequencedLeaves(context.Background(), req); err == nil || !strings.Contains(err.Error(), "STORAGE") {
+		t.Errorf("AddSequencedLeaves(): %v, want storage error", err)
+	}
+}
+
+func TestAddSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conflict := status.New(codes.FailedPrecondition, "conflicting LeafIndex").Proto()
+	leaves := []*trillian.LogLeaf{leaf1, leaf3}
+	results := []*trillian.QueuedLogLeaf{{Leaf: leaf1}, {Leaf: leaf3, Status: conflict}}
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().BeginForTree(gomock.Any(), logID1).Return(mockTx, nil)
+	mockTx.EXPECT().AddSequencedLeaves(gomock.Any(), leaves).Return(results, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorageForTree(ctrl, stestonly.PreorderedLogTree, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	rsp, err := server.AddSequencedLeaves(ctx, &trillian.AddSequencedLeavesRequest{LogId: logID1, Leaves: leaves})
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves(): %v", err)
+	}
+	if got, want := len(rsp.Results), len(results); got != want {
+		t.Fatalf("AddSequencedLeaves() returned %d results, want %d", got, want)
+	}
+	for i, result := range rsp.Results {
+		if !proto.Equal(result, results[i]) {
+			t.Errorf("AddSequencedLeaves().Results[%d]: %v, want %v", i, result, results[i])
+		}
+	}
+}
+
 func TestGetLatestSignedLogRootBeginFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -490,7 +872,8 @@ func TestGetLeavesByHashStorageFails(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetLeavesByHash", readOnly,
 		func(t *storage.MockLogTreeTX) {
-			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false).Return(nil, errors.New("STORAGE"))
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false, DefaultMaxResultsPerHash+1, 0).Return(nil, errors.New("STORAGE"))
 		},
 		func(s *TrillianLogRPCServer) error {
 			_, err := s.GetLeavesByHash(context.Background(), &getByHashRequest1)
@@ -506,7 +889,8 @@ func TestLeavesByHashCommitFails(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetLeavesByHash", readOnly,
 		func(t *storage.MockLogTreeTX) {
-			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false).Return(nil, nil)
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false, DefaultMaxResultsPerHash+1, 0).Return(nil, nil)
 		},
 		func(s *TrillianLogRPCServer) error {
 			_, err := s.GetLeavesByHash(context.Background(), &getByHashRequest1)
@@ -537,7 +921,8 @@ func TestGetLeavesByHash(t *testing.T) {
 	mockStorage := storage.NewMockLogStorage(ctrl)
 	mockTx := storage.NewMockLogTreeTX(ctrl)
 	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), getByHashRequest1.LogId).Return(mockTx, nil)
-	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false).Return([]*trillian.LogLeaf{leaf1, leaf3}, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("test"), []byte("data")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{leaf1, leaf3}, nil)
 	mockTx.EXPECT().Commit().Return(nil)
 	mockTx.EXPECT().Close().Return(nil)
 
@@ -557,6 +942,390 @@ func TestGetLeavesByHash(t *testing.T) {
 	}
 }
 
+func TestGetLeavesByHashPaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashes := [][]byte{[]byte("test"), []byte("data")}
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	for _, page := range []struct {
+		offset int
+		leaves []*trillian.LogLeaf
+	}{
+		{offset: 0, leaves: []*trillian.LogLeaf{leaf1, leaf3, leaf1}},
+		{offset: 2, leaves: []*trillian.LogLeaf{leaf1}},
+	} {
+		mockTx := storage.NewMockLogTreeTX(ctrl)
+		mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+		mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+		mockTx.EXPECT().GetLeavesByHash(gomock.Any(), hashes, false, 3, page.offset).Return(page.leaves, nil)
+		mockTx.EXPECT().Commit().Return(nil)
+		mockTx.EXPECT().Close().Return(nil)
+	}
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	server.MaxResultsPerHash = 2
+
+	req := &trillian.GetLeavesByHashRequest{LogId: logID1, LeafHash: hashes}
+	resp, err := server.GetLeavesByHash(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetLeavesByHash(): %v", err)
+	}
+	if got, want := len(resp.Leaves), 2; got != want {
+		t.Errorf("len(GetLeavesByHash().Leaves): %v, want %v", got, want)
+	}
+	if got, want := resp.NextPageToken, "2"; got != want {
+		t.Fatalf("GetLeavesByHash().NextPageToken: %q, want %q", got, want)
+	}
+
+	req.PageToken = resp.NextPageToken
+	resp, err = server.GetLeavesByHash(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetLeavesByHash(%q): %v", req.PageToken, err)
+	}
+	if len(resp.Leaves) != 1 || !proto.Equal(resp.Leaves[0], leaf1) {
+		t.Errorf("GetLeavesByHash(%q).Leaves: %v, want [%v]", req.PageToken, resp.Leaves, leaf1)
+	}
+	if got := resp.NextPageToken; got != "" {
+		t.Errorf("GetLeavesByHash(%q).NextPageToken: %q, want none", req.PageToken, got)
+	}
+}
+
+func TestGetLeavesByHashNotIntegrated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Leaves stored at their indices, but not yet covered by the signed log root.
+	pending := []*trillian.LogLeaf{
+		{LeafIndex: signedRoot1.TreeSize, LeafValue: []byte("pending")},
+		{LeafIndex: signedRoot1.TreeSize + 2, LeafValue: []byte("after a gap")},
+	}
+	hashes := [][]byte{[]byte("test"), []byte("data")}
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), hashes, false, 3, 0).Return(append([]*trillian.LogLeaf{leaf1}, pending...), nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	server.MaxResultsPerHash = 2
+
+	resp, err := server.GetLeavesByHash(context.Background(), &trillian.GetLeavesByHashRequest{LogId: logID1, LeafHash: hashes})
+	if err != nil {
+		t.Fatalf("GetLeavesByHash(): %v", err)
+	}
+	if len(resp.Leaves) != 1 || !proto.Equal(resp.Leaves[0], leaf1) {
+		t.Errorf("GetLeavesByHash().Leaves: %v, want [%v]", resp.Leaves, leaf1)
+	}
+	if got := resp.NextPageToken; got != "" {
+		t.Errorf("GetLeavesByHash().NextPageToken: %q, want none", got)
+	}
+}
+
+func TestGetLeavesByHashInvalidPageToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+
+	for _, token := range []string{"abc", "-1"} {
+		req := &trillian.GetLeavesByHashRequest{LogId: logID1, LeafHash: getByHashRequest1.LeafHash, PageToken: token}
+		_, err := server.GetLeavesByHash(context.Background(), req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("GetLeavesByHash(%q): %v, want code %v", token, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestGetLeavesByIdentityHashInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+
+	for _, req := range []*trillian.GetLeavesByIdentityHashRequest{
+		{LogId: logID1},
+		{LogId: logID1, LeafIdentityHash: [][]byte{[]byte("id"), {}}},
+		{LogId: logID1, LeafIdentityHash: [][]byte{[]byte("id")}, IncludeProof: true, TreeSize: -1},
+		{LogId: logID1, LeafIdentityHash: [][]byte{[]byte("id")}, PageToken: "abc"},
+	} {
+		_, err := server.GetLeavesByIdentityHash(context.Background(), req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("GetLeavesByIdentityHash(%+v): %v, want code %v", req, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestGetLeavesByIdentityHashStorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &trillian.GetLeavesByIdentityHashRequest{LogId: logID1, LeafIdentityHash: [][]byte{[]byte("id")}}
+	test := newParameterizedTest(ctrl, "GetLeavesByIdentityHash", readOnly,
+		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeavesByIdentityHash(gomock.Any(), req.LeafIdentityHash, DefaultMaxResultsPerHash+1, 0).Return(nil, errors.New("STORAGE"))
+		},
+		func(s *TrillianLogRPCServer) error {
+			_, err := s.GetLeavesByIdentityHash(context.Background(), req)
+			return err
+		})
+
+	test.executeStorageFailureTest(t, logID1)
+}
+
+func TestGetLeavesByIdentityHash(t *testing.T) {
+	ids := [][]byte{[]byte("id1"), []byte("id3")}
+	for _, test := range []struct {
+		desc      string
+		req       *trillian.GetLeavesByIdentityHashRequest
+		wantProof bool
+		wantCode  codes.Code
+	}{
+		{
+			desc: "noProof",
+			req:  &trillian.GetLeavesByIdentityHashRequest{LogId: logID1, LeafIdentityHash: ids},
+		},
+		{
+			desc:      "proofAtLatest",
+			req:       &trillian.GetLeavesByIdentityHashRequest{LogId: logID1, LeafIdentityHash: ids, IncludeProof: true},
+			wantProof: true,
+		},
+		{
+			desc:     "leafOutsideTreeSize",
+			req:      &trillian.GetLeavesByIdentityHashRequest{LogId: logID1, LeafIdentityHash: ids, IncludeProof: true, TreeSize: 3},
+			wantCode: codes.OutOfRange,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := storage.NewMockLogStorage(ctrl)
+			mockTx := storage.NewMockLogTreeTX(ctrl)
+			mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			mockTx.EXPECT().GetLeavesByIdentityHash(gomock.Any(), ids, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{leaf1, leaf3}, nil)
+			mockTx.EXPECT().ReadRevision().AnyTimes().Return(signedRoot1.TreeRevision)
+			if test.wantProof {
+				for _, leaf := range []*trillian.LogLeaf{leaf1, leaf3} {
+					fetches, err := merkle.CalcInclusionProofNodeAddresses(signedRoot1.TreeSize, leaf.LeafIndex, signedRoot1.TreeSize, proofMaxBitLen)
+					if err != nil {
+						t.Fatalf("CalcInclusionProofNodeAddresses(): %v", err)
+					}
+					// Any node hashes will do, as the proofs aren't verified here.
+					ids := make([]storage.NodeID, 0, len(fetches))
+					nodes := make([]storage.Node, 0, len(fetches))
+					for _, f := range fetches {
+						ids = append(ids, f.NodeID)
+						nodes = append(nodes, storage.Node{NodeID: f.NodeID, NodeRevision: revision1, Hash: []byte("nodehash")})
+					}
+					mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, ids).Return(nodes, nil)
+				}
+			}
+			if test.wantCode == codes.OK {
+				mockTx.EXPECT().Commit().Return(nil)
+			}
+			mockTx.EXPECT().Close().Return(nil)
+			mockTx.EXPECT().IsOpen().AnyTimes().Return(false)
+
+			registry := extension.Registry{
+				AdminStorage: mockAdminStorage(ctrl, logID1),
+				LogStorage:   mockStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+			resp, err := server.GetLeavesByIdentityHash(context.Background(), test.req)
+			if test.wantCode != codes.OK {
+				if s, ok := status.FromError(err); !ok || s.Code() != test.wantCode {
+					t.Fatalf("GetLeavesByIdentityHash(): %v, want code %v", err, test.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLeavesByIdentityHash(): %v", err)
+			}
+			if len(resp.Leaves) != 2 || !proto.Equal(resp.Leaves[0], leaf1) || !proto.Equal(resp.Leaves[1], leaf3) {
+				t.Errorf("GetLeavesByIdentityHash().Leaves: %v, want [%v %v]", resp.Leaves, leaf1, leaf3)
+			}
+			wantProofs := 0
+			if test.wantProof {
+				wantProofs = len(resp.Leaves)
+			}
+			if got := len(resp.Proof); got != wantProofs {
+				t.Errorf("len(GetLeavesByIdentityHash().Proof): %v, want %v", got, wantProofs)
+			}
+			for i, proof := range resp.Proof {
+				if got, want := proof.LeafIndex, resp.Leaves[i].LeafIndex; got != want {
+					t.Errorf("GetLeavesByIdentityHash().Proof[%v].LeafIndex: %v, want %v", i, got, want)
+				}
+			}
+			if !proto.Equal(resp.SignedLogRoot, &signedRoot1) {
+				t.Errorf("GetLeavesByIdentityHash().SignedLogRoot: %v, want %v", resp.SignedLogRoot, signedRoot1)
+			}
+		})
+	}
+}
+
+func TestGetLeavesByIdentityHashPaged(t *testing.T) {
+	ids := [][]byte{[]byte("id1"), []byte("id3")}
+	for _, test := range []struct {
+		desc          string
+		pageToken     string
+		offset        int
+		stored        []*trillian.LogLeaf
+		want          *trillian.LogLeaf
+		wantPageToken string
+	}{
+		{desc: "firstPage", offset: 0, stored: []*trillian.LogLeaf{leaf1, leaf3}, want: leaf1, wantPageToken: "1"},
+		{desc: "lastPage", pageToken: "1", offset: 1, stored: []*trillian.LogLeaf{leaf3}, want: leaf3},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := storage.NewMockLogStorage(ctrl)
+			mockTx := storage.NewMockLogTreeTX(ctrl)
+			mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			mockTx.EXPECT().GetLeavesByIdentityHash(gomock.Any(), ids, 2, test.offset).Return(test.stored, nil)
+			mockTx.EXPECT().Commit().Return(nil)
+			mockTx.EXPECT().Close().Return(nil)
+
+			registry := extension.Registry{
+				AdminStorage: mockAdminStorage(ctrl, logID1),
+				LogStorage:   mockStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+			server.MaxResultsPerHash = 1
+
+			req := &trillian.GetLeavesByIdentityHashRequest{LogId: logID1, LeafIdentityHash: ids, PageToken: test.pageToken}
+			resp, err := server.GetLeavesByIdentityHash(context.Background(), req)
+			if err != nil {
+				t.Fatalf("GetLeavesByIdentityHash(%q): %v", test.pageToken, err)
+			}
+			if len(resp.Leaves) != 1 || !proto.Equal(resp.Leaves[0], test.want) {
+				t.Errorf("GetLeavesByIdentityHash(%q).Leaves: %v, want [%v]", test.pageToken, resp.Leaves, test.want)
+			}
+			if got, want := resp.NextPageToken, test.wantPageToken; got != want {
+				t.Errorf("GetLeavesByIdentityHash(%q).NextPageToken: %q, want %q", test.pageToken, got, want)
+			}
+		})
+	}
+}
+
+func TestGetLeafStatusInvalidRequest(t *testing.T) {
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+
+	_, err := server.GetLeafStatus(context.Background(), &trillian.GetLeafStatusRequest{LogId: logID1})
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+		t.Errorf("GetLeafStatus(): %v, want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestGetLeafStatusStorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &trillian.GetLeafStatusRequest{LogId: logID1, LeafIdentityHash: []byte("id")}
+	test := newParameterizedTest(ctrl, "GetLeafStatus", readOnly,
+		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeafStatus(gomock.Any(), req.LeafIdentityHash).Return(storage.LeafStatus{}, errors.New("STORAGE"))
+		},
+		func(s *TrillianLogRPCServer) error {
+			_, err := s.GetLeafStatus(context.Background(), req)
+			return err
+		})
+
+	test.executeStorageFailureTest(t, logID1)
+}
+
+func TestGetLeafStatusCommitFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &trillian.GetLeafStatusRequest{LogId: logID1, LeafIdentityHash: []byte("id")}
+	test := newParameterizedTest(ctrl, "GetLeafStatus", readOnly,
+		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeafStatus(gomock.Any(), req.LeafIdentityHash).Return(storage.LeafStatus{}, nil)
+		},
+		func(s *TrillianLogRPCServer) error {
+			_, err := s.GetLeafStatus(context.Background(), req)
+			return err
+		})
+
+	test.executeCommitFailsTest(t, logID1)
+}
+
+func TestGetLeafStatus(t *testing.T) {
+	for _, test := range []struct {
+		desc       string
+		leafStatus storage.LeafStatus
+		want       *trillian.GetLeafStatusResponse
+	}{
+		{
+			desc:       "unknown",
+			leafStatus: storage.LeafStatus{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS},
+			want:       &trillian.GetLeafStatusResponse{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS},
+		},
+		{
+			desc:       "queued",
+			leafStatus: storage.LeafStatus{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: 12345},
+			want:       &trillian.GetLeafStatusResponse{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: 12345},
+		},
+		{
+			desc:       "sequenced",
+			leafStatus: storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: 3},
+			want:       &trillian.GetLeafStatusResponse{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: 3},
+		},
+		{
+			desc:       "notIntegrated",
+			leafStatus: storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: signedRoot1.TreeSize},
+			want:       &trillian.GetLeafStatusResponse{Status: trillian.LeafStatus_QUEUED},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := storage.NewMockLogStorage(ctrl)
+			mockTx := storage.NewMockLogTreeTX(ctrl)
+			mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			mockTx.EXPECT().GetLeafStatus(gomock.Any(), []byte("id")).Return(test.leafStatus, nil)
+			mockTx.EXPECT().Commit().Return(nil)
+			mockTx.EXPECT().Close().Return(nil)
+
+			registry := extension.Registry{
+				AdminStorage: mockAdminStorage(ctrl, logID1),
+				LogStorage:   mockStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+			resp, err := server.GetLeafStatus(context.Background(), &trillian.GetLeafStatusRequest{LogId: logID1, LeafIdentityHash: []byte("id")})
+			if err != nil {
+				t.Fatalf("GetLeafStatus(): %v", err)
+			}
+			if !proto.Equal(resp, test.want) {
+				t.Errorf("GetLeafStatus(): %v, want %v", resp, test.want)
+			}
+		})
+	}
+}
+
 func TestGetProofByHashBeginTXFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -577,7 +1346,8 @@ func TestGetProofByHashNoLeafForHash(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetInclusionProofByHash", readOnly,
 		func(t *storage.MockLogTreeTX) {
-			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return(nil, errors.New("STORAGE"))
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return(nil, errors.New("STORAGE"))
 		},
 		func(s *TrillianLogRPCServer) error {
 			_, err := s.GetInclusionProofByHash(context.Background(), &getInclusionProofByHashRequest25)
@@ -595,7 +1365,7 @@ func TestGetProofByHashGetNodesFails(t *testing.T) {
 		func(t *storage.MockLogTreeTX) {
 			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
-			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
 			t.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{}, errors.New("STORAGE"))
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -616,7 +1386,7 @@ func TestGetProofByHashWrongNodeCountFetched(t *testing.T) {
 
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 	mockTx.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
-	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
 	// The server expects three nodes from storage but we return only two
 	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{{NodeRevision: 3}, {NodeRevision: 2}}, nil)
 	mockTx.EXPECT().Close().Return(nil)
@@ -643,7 +1413,7 @@ func TestGetProofByHashWrongNodeReturned(t *testing.T) {
 
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 	mockTx.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
-	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
 	// We set this up so one of the returned nodes has the wrong ID
 	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3}, {NodeID: stestonly.MustCreateNodeIDForTreeCoords(4, 5, 64), NodeRevision: 2}, {NodeID: nodeIdsInclusionSize7Index2[2], NodeRevision: 3}}, nil)
 	mockTx.EXPECT().Close().Return(nil)
@@ -668,7 +1438,7 @@ func TestGetProofByHashCommitFails(t *testing.T) {
 		func(t *storage.MockLogTreeTX) {
 			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
-			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+			t.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
 			t.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3}, {NodeID: nodeIdsInclusionSize7Index2[1], NodeRevision: 2}, {NodeID: nodeIdsInclusionSize7Index2[2], NodeRevision: 3}}, nil)
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -689,7 +1459,7 @@ func TestGetProofByHash(t *testing.T) {
 
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 	mockTx.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
-	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
 	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{
 		{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3, Hash: []byte("nodehash0")},
 		{NodeID: nodeIdsInclusionSize7Index2[1], NodeRevision: 2, Hash: []byte("nodehash1")},
@@ -726,6 +1496,175 @@ func TestGetProofByHash(t *testing.T) {
 	}
 }
 
+func TestGetProofByHashPaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), getInclusionProofByHashRequest7.LogId).Return(mockTx, nil)
+
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().ReadRevision().Return(signedRoot1.TreeRevision)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, 2, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}, {LeafIndex: 5}}, nil)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{
+		{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+		{NodeID: nodeIdsInclusionSize7Index2[1], NodeRevision: 2, Hash: []byte("nodehash1")},
+		{NodeID: nodeIdsInclusionSize7Index2[2], NodeRevision: 3, Hash: []byte("nodehash2")}}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, getInclusionProofByHashRequest7.LogId),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	server.MaxResultsPerHash = 1
+
+	proofResponse, err := server.GetInclusionProofByHash(context.Background(), &getInclusionProofByHashRequest7)
+	if err != nil {
+		t.Fatalf("GetInclusionProofByHash(): %v", err)
+	}
+	if got, want := len(proofResponse.Proof), 1; got != want {
+		t.Errorf("len(GetInclusionProofByHash().Proof): %v, want %v", got, want)
+	}
+	if got, want := proofResponse.NextPageToken, "1"; got != want {
+		t.Errorf("GetInclusionProofByHash().NextPageToken: %q, want %q", got, want)
+	}
+}
+
+func TestGetProofByHashLatestWithConsistency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().ReadRevision().AnyTimes().Return(signedRoot1.TreeRevision)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{
+		{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+		{NodeID: nodeIdsInclusionSize7Index2[1], NodeRevision: 2, Hash: []byte("nodehash1")},
+		{NodeID: nodeIdsInclusionSize7Index2[2], NodeRevision: 3, Hash: []byte("nodehash2")}}, nil)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsConsistencySize4ToSize7).Return([]storage.Node{
+		{NodeID: nodeIdsConsistencySize4ToSize7[0], NodeRevision: 3, Hash: []byte("nodehash")}}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	// A zero TreeSize asks for proofs in the latest root, which has size 7.
+	req := &trillian.GetInclusionProofByHashRequest{LogId: logID1, LeafHash: []byte("ahash"), FirstTreeSize: 4}
+	resp, err := server.GetInclusionProofByHash(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetInclusionProofByHash(): %v", err)
+	}
+	if got, want := len(resp.Proof), 1; got != want {
+		t.Fatalf("len(GetInclusionProofByHash().Proof): %v, want %v", got, want)
+	}
+	if got, want := len(resp.Proof[0].Hashes), 3; got != want {
+		t.Errorf("len(GetInclusionProofByHash().Proof[0].Hashes): %v, want %v", got, want)
+	}
+	if !proto.Equal(resp.SignedLogRoot, &signedRoot1) {
+		t.Errorf("GetInclusionProofByHash().SignedLogRoot: %v, want %v", resp.SignedLogRoot, signedRoot1)
+	}
+	wantConsistency := &trillian.Proof{Hashes: [][]byte{[]byte("nodehash")}}
+	if !proto.Equal(resp.ConsistencyProof, wantConsistency) {
+		t.Errorf("GetInclusionProofByHash().ConsistencyProof: %v, want %v", resp.ConsistencyProof, wantConsistency)
+	}
+}
+
+func TestGetProofByHashLaggingServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().ReadRevision().AnyTimes().Return(signedRoot1.TreeRevision)
+	mockTx.EXPECT().GetLeavesByHash(gomock.Any(), [][]byte{[]byte("ahash")}, false, DefaultMaxResultsPerHash+1, 0).Return([]*trillian.LogLeaf{{LeafIndex: 2}}, nil)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize7Index2).Return([]storage.Node{
+		{NodeID: nodeIdsInclusionSize7Index2[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+		{NodeID: nodeIdsInclusionSize7Index2[1], NodeRevision: 2, Hash: []byte("nodehash1")},
+		{NodeID: nodeIdsInclusionSize7Index2[2], NodeRevision: 3, Hash: []byte("nodehash2")}}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	// The caller already trusts a root of size 9, which this server hasn't
+	// caught up with: that's not an error, but there's no consistency proof.
+	req := &trillian.GetInclusionProofByHashRequest{LogId: logID1, LeafHash: []byte("ahash"), FirstTreeSize: 9}
+	resp, err := server.GetInclusionProofByHash(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetInclusionProofByHash(): %v", err)
+	}
+	if !proto.Equal(resp.SignedLogRoot, &signedRoot1) {
+		t.Errorf("GetInclusionProofByHash().SignedLogRoot: %v, want %v", resp.SignedLogRoot, signedRoot1)
+	}
+	if resp.ConsistencyProof != nil {
+		t.Errorf("GetInclusionProofByHash().ConsistencyProof: %v, want nil", resp.ConsistencyProof)
+	}
+}
+
+func TestGetProofByIndexWithConsistency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeIdsInclusionSize4Index2 := []storage.NodeID{
+		stestonly.MustCreateNodeIDForTreeCoords(0, 3, 64),
+		stestonly.MustCreateNodeIDForTreeCoords(1, 0, 64)}
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockTx := storage.NewMockLogTreeTX(ctrl)
+	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTx.EXPECT().ReadRevision().AnyTimes().Return(signedRoot1.TreeRevision)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsInclusionSize4Index2).Return([]storage.Node{
+		{NodeID: nodeIdsInclusionSize4Index2[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+		{NodeID: nodeIdsInclusionSize4Index2[1], NodeRevision: 2, Hash: []byte("nodehash1")}}, nil)
+	mockTx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIdsConsistencySize4ToSize7).Return([]storage.Node{
+		{NodeID: nodeIdsConsistencySize4ToSize7[0], NodeRevision: 3, Hash: []byte("nodehash")}}, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdminStorage(ctrl, logID1),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	// The proof is for size 4, but the latest root has size 7.
+	req := &trillian.GetInclusionProofRequest{LogId: logID1, TreeSize: 4, LeafIndex: 2}
+	resp, err := server.GetInclusionProof(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetInclusionProof(): %v", err)
+	}
+	if got, want := len(resp.Proof.Hashes), 2; got != want {
+		t.Errorf("len(GetInclusionProof().Proof.Hashes): %v, want %v", got, want)
+	}
+	if !proto.Equal(resp.SignedLogRoot, &signedRoot1) {
+		t.Errorf("GetInclusionProof().SignedLogRoot: %v, want %v", resp.SignedLogRoot, signedRoot1)
+	}
+	wantConsistency := &trillian.Proof{Hashes: [][]byte{[]byte("nodehash")}}
+	if !proto.Equal(resp.ConsistencyProof, wantConsistency) {
+		t.Errorf("GetInclusionProof().ConsistencyProof: %v, want %v", resp.ConsistencyProof, wantConsistency)
+	}
+}
+
 func TestGetProofByIndexBeginTXFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -872,6 +1811,9 @@ func TestGetProofByIndex(t *testing.T) {
 	if !proto.Equal(proofResponse.Proof, &expectedProof) {
 		t.Fatalf("expected proof: %v but got: %v", expectedProof, proofResponse.Proof)
 	}
+	if !proto.Equal(proofResponse.SignedLogRoot, &signedRoot1) {
+		t.Errorf("expected root: %v but got: %v", signedRoot1, proofResponse.SignedLogRoot)
+	}
 }
 
 func TestGetEntryAndProofBeginTXFails(t *testing.T) {
@@ -1053,6 +1995,9 @@ func TestGetEntryAndProof(t *testing.T) {
 	if !proto.Equal(response.Leaf, leaf1) {
 		t.Fatalf("Expected leaf %v but got: %v", leaf1, response.Leaf)
 	}
+	if !proto.Equal(response.SignedLogRoot, &signedRoot1) {
+		t.Errorf("expected root: %v but got: %v", signedRoot1, response.SignedLogRoot)
+	}
 }
 
 func TestGetSequencedLeafCountBeginTXFails(t *testing.T) {
@@ -1075,6 +2020,7 @@ func TestGetSequencedLeafCountStorageFails(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetSequencedLeafCount", readOnly,
 		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().GetSequencedLeafCount(gomock.Any()).Return(int64(0), errors.New("STORAGE"))
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -1091,6 +2037,7 @@ func TestGetSequencedLeafCountCommitFails(t *testing.T) {
 
 	test := newParameterizedTest(ctrl, "GetSequencedLeafCount", readOnly,
 		func(t *storage.MockLogTreeTX) {
+			t.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
 			t.EXPECT().GetSequencedLeafCount(gomock.Any()).Return(int64(27), nil)
 		},
 		func(s *TrillianLogRPCServer) error {
@@ -1102,30 +2049,43 @@ func TestGetSequencedLeafCountCommitFails(t *testing.T) {
 }
 
 func TestGetSequencedLeafCount(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockStorage := storage.NewMockLogStorage(ctrl)
-	mockTx := storage.NewMockLogTreeTX(ctrl)
-	mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
-
-	mockTx.EXPECT().GetSequencedLeafCount(gomock.Any()).Return(int64(268), nil)
-	mockTx.EXPECT().Commit().Return(nil)
-	mockTx.EXPECT().Close().Return(nil)
-
-	registry := extension.Registry{
-		AdminStorage: mockAdminStorage(ctrl, logID1),
-		LogStorage:   mockStorage,
-	}
-	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
-
-	response, err := server.GetSequencedLeafCount(context.Background(), &trillian.GetSequencedLeafCountRequest{LogId: logID1})
-	if err != nil {
-		t.Fatalf("expected no error getting leaf count but got: %v", err)
-	}
-
-	if got, want := response.LeafCount, int64(268); got != want {
-		t.Fatalf("expected leaf count: %d but got: %d", want, got)
+	for _, test := range []struct {
+		desc     string
+		treeSize int64
+		stored   int64
+		want     int64
+	}{
+		{desc: "integrated", treeSize: 268, stored: 268, want: 268},
+		{desc: "pending", treeSize: 268, stored: 271, want: 268},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := storage.NewMockLogStorage(ctrl)
+			mockTx := storage.NewMockLogTreeTX(ctrl)
+			mockStorage.EXPECT().SnapshotForTree(gomock.Any(), logID1).Return(mockTx, nil)
+
+			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(trillian.SignedLogRoot{TreeSize: test.treeSize}, nil)
+			mockTx.EXPECT().GetSequencedLeafCount(gomock.Any()).Return(test.stored, nil)
+			mockTx.EXPECT().Commit().Return(nil)
+			mockTx.EXPECT().Close().Return(nil)
+
+			registry := extension.Registry{
+				AdminStorage: mockAdminStorage(ctrl, logID1),
+				LogStorage:   mockStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+			response, err := server.GetSequencedLeafCount(context.Background(), &trillian.GetSequencedLeafCountRequest{LogId: logID1})
+			if err != nil {
+				t.Fatalf("expected no error getting leaf count but got: %v", err)
+			}
+
+			if got, want := response.LeafCount, test.want; got != want {
+				t.Fatalf("expected leaf count: %d but got: %d", want, got)
+			}
+		})
 	}
 }
 
@@ -1264,6 +2224,9 @@ func TestGetConsistencyProof(t *testing.T) {
 	if !proto.Equal(response.Proof, &expectedProof) {
 		t.Fatalf("expected proof: %v but got: %v", expectedProof, response.Proof)
 	}
+	if !proto.Equal(response.SignedLogRoot, &signedRoot1) {
+		t.Errorf("expected root: %v but got: %v", signedRoot1, response.SignedLogRoot)
+	}
 }
 
 type prepareMockTXFunc func(*storage.MockLogTreeTX)
@@ -1389,7 +2352,13 @@ func (p *parameterizedTest) executeBeginFailsTest(t *testing.T, logID int64) {
 }
 
 func mockAdminStorage(ctrl *gomock.Controller, treeID int64) storage.AdminStorage {
-	tree := *stestonly.LogTree
+	return mockAdminStorageForTree(ctrl, stestonly.LogTree, treeID)
+}
+
+// mockAdminStorageForTree returns an AdminStorage that serves a copy of base
+// as the tree with treeID.
+func mockAdminStorageForTree(ctrl *gomock.Controller, base *trillian.Tree, treeID int64) storage.AdminStorage {
+	tree := *base
 	tree.TreeId = treeID
 
 	adminStorage := storage.NewMockAdminStorage(ctrl)
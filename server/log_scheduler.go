@@ -0,0 +1,141 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+)
+
+// logLoad describes the work waiting for a log that is due for processing.
+type logLoad struct {
+	logID int64
+	// tree is the log's tree, or nil if it isn't known to admin storage.
+	tree *trillian.Tree
+	// backlog is the number of leaves queued for the log.
+	backlog int64
+	// pending is the number of leaves stored beyond the tree size of root, which are waiting
+	// to be integrated: pre-ordered leaves, or leaves dequeued by pipelined sequencing.
+	pending int64
+	// root is the latest signed root of the log, which has no RootHash if the log has never
+	// been signed.
+	root trillian.SignedLogRoot
+}
+
+// scheduleByLoad orders logIDs so that the logs with the most urgent work are processed first,
+// and drops the logs that have nothing to do. If the load of the logs can't be read, logIDs are
+// returned as they are.
+func (l *LogOperationManager) scheduleByLoad(ctx context.Context, logIDs []int64, logTrees map[int64]*trillian.Tree, now time.Time) []int64 {
+	loads, err := l.getLogLoads(ctx, logIDs, logTrees)
+	if err != nil {
+		glog.Warningf("failed to read log loads, scheduling logs unweighted: %v", err)
+		return logIDs
+	}
+
+	weights := make(map[int64]float64, len(loads))
+	scheduled := make([]int64, 0, len(loads))
+	for _, load := range loads {
+		if l.isIdle(load, now) {
+			glog.V(1).Infof("%v: log is idle, skipping", load.logID)
+			continue
+		}
+		weights[load.logID] = l.weight(load, now)
+		scheduled = append(scheduled, load.logID)
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return weights[scheduled[i]] > weights[scheduled[j]]
+	})
+	return scheduled
+}
+
+// getLogLoads reads the backlog, pending leaves and latest root of each of logIDs in a single
+// snapshot. GetUnsequencedCounts is expensive, so it's called once for all logs.
+func (l *LogOperationManager) getLogLoads(ctx context.Context, logIDs []int64, logTrees map[int64]*trillian.Tree) ([]logLoad, error) {
+	tx, err := l.info.Registry.LogStorage.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx for retrieving log loads: %v", err)
+	}
+	defer tx.Close()
+
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unsequenced counts: %v", err)
+	}
+	roots, err := tx.GetLatestSignedLogRoots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest roots: %v", err)
+	}
+	ends, err := tx.GetSequencedLeafEnds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sequenced leaf ends: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit getting log loads: %v", err)
+	}
+
+	loads := make([]logLoad, 0, len(logIDs))
+	for _, logID := range logIDs {
+		root := roots[logID]
+		var pending int64
+		if end := ends[logID]; end > root.TreeSize {
+			pending = end - root.TreeSize
+		}
+		loads = append(loads, logLoad{logID: logID, tree: logTrees[logID], backlog: counts[logID], pending: pending, root: root})
+	}
+	return loads, nil
+}
+
+// isIdle reports whether a pass over the log would neither integrate leaves nor sign a root.
+func (l *LogOperationManager) isIdle(load logLoad, now time.Time) bool {
+	tree := load.tree
+	if tree == nil || load.root.RootHash == nil || load.pending > 0 {
+		return false
+	}
+	if load.backlog > 0 && !tree.SequencingDisabled {
+		return false
+	}
+	// Like the sequencer, treat a missing or malformed max_root_duration as disabled.
+	maxRootDuration, err := ptypes.Duration(tree.MaxRootDuration)
+	if err != nil || maxRootDuration == 0 {
+		return true
+	}
+	return now.Sub(time.Unix(0, load.root.TimestampNanos)) < maxRootDuration
+}
+
+// weight returns how urgently the log needs processing: its backlog and pending leaves in
+// batches plus the age of its latest root in run intervals, multiplied by 1 + the tree's
+// scheduling_priority.
+func (l *LogOperationManager) weight(load logLoad, now time.Time) float64 {
+	batchSize := l.info.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	interval := l.runInterval(load.tree)
+	if interval <= 0 {
+		interval = time.Second
+	}
+	age := now.Sub(time.Unix(0, load.root.TimestampNanos))
+	w := float64(load.backlog+load.pending)/float64(batchSize) + age.Seconds()/interval.Seconds()
+	if load.tree != nil {
+		w *= float64(1 + load.tree.SchedulingPriority)
+	}
+	return w
+}
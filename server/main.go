@@ -36,6 +36,16 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+const (
+	// DefaultTreeDeleteThreshold is the suggested minimum time a tree has to
+	// remain soft-deleted before it's hard-deleted.
+	DefaultTreeDeleteThreshold = 7 * 24 * time.Hour
+
+	// DefaultTreeDeleteMinInterval is the suggested minimum interval between
+	// deleted tree garbage collection sweeps.
+	DefaultTreeDeleteMinInterval = 4 * time.Hour
+)
+
 // Main encapsulates the data and logic to start a Trillian server (Log or Map).
 type Main struct {
 	// Endpoints for RPC and HTTP/REST servers.
@@ -48,6 +58,13 @@ type Main struct {
 	RegisterHandlerFn func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error
 	// RegisterServerFn is called to register RPC servers.
 	RegisterServerFn func(*grpc.Server, extension.Registry) error
+
+	// TreeGCEnabled controls whether soft-deleted trees are periodically
+	// hard-deleted, according to TreeDeleteThreshold and
+	// TreeDeleteMinInterval. See admin.DeletedTreeGC.
+	TreeGCEnabled         bool
+	TreeDeleteThreshold   time.Duration
+	TreeDeleteMinInterval time.Duration
 }
 
 // Run starts the configured server. Blocks until the server exits.
@@ -60,7 +77,11 @@ func (m *Main) Run(ctx context.Context) error {
 	if err := m.RegisterServerFn(m.Server, m.Registry); err != nil {
 		return err
 	}
-	trillian.RegisterTrillianAdminServer(m.Server, admin.New(m.Registry))
+	adminServer := admin.New(m.Registry)
+	if m.TreeGCEnabled {
+		adminServer.SetUndeleteWindow(m.TreeDeleteThreshold)
+	}
+	trillian.RegisterTrillianAdminServer(m.Server, adminServer)
 	reflection.Register(m.Server)
 
 	if endpoint := m.HTTPEndpoint; endpoint != "" {
@@ -84,6 +105,14 @@ func (m *Main) Run(ctx context.Context) error {
 		}))
 	}
 
+	if m.TreeGCEnabled {
+		go func() {
+			glog.Info("Deleted tree GC started")
+			gc := admin.NewDeletedTreeGC(m.Registry.AdminStorage, m.TreeDeleteThreshold, m.TreeDeleteMinInterval, m.Registry.MetricFactory)
+			gc.Run(ctx)
+		}()
+	}
+
 	glog.Infof("RPC server starting on %v", m.RPCEndpoint)
 	lis, err := net.Listen("tcp", m.RPCEndpoint)
 	if err != nil {
@@ -15,7 +15,9 @@
 package server
 
 import (
+	"bytes"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/trillian"
@@ -83,23 +85,35 @@ func (t *TrillianMapServer) GetLeaves(ctx context.Context, req *trillian.GetMapL
 		root = &r
 	}
 
-	smtReader := merkle.NewSparseMerkleTreeReader(root.MapRevision, hasher, tx)
-
-	inclusions := make([]*trillian.MapLeafInclusion, 0, len(req.Index))
-	found := 0
 	for _, index := range req.Index {
 		if got, want := len(index), hasher.Size(); got != want {
 			return nil, status.Errorf(codes.InvalidArgument,
 				"index len(%x): %v, want %v", index, got, want)
 		}
-		// Fetch the leaf if it exists.
-		leaves, err := tx.Get(ctx, root.MapRevision, [][]byte{index})
-		if err != nil {
-			return nil, fmt.Errorf("could not fetch leaf %x: %v", index, err)
-		}
-		var leaf *trillian.MapLeaf
-		if len(leaves) == 1 {
-			leaf = &leaves[0]
+	}
+
+	// Fetch the leaves that exist.
+	leaves, err := tx.Get(ctx, root.MapRevision, req.Index)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch leaves: %v", err)
+	}
+	leafByIndex := make(map[string]*trillian.MapLeaf, len(leaves))
+	for i := range leaves {
+		leafByIndex[string(leaves[i].Index)] = &leaves[i]
+	}
+
+	// Fetch the proofs regardless of whether the leaves exist.
+	smtReader := merkle.NewSparseMerkleTreeReader(root.MapRevision, hasher, tx)
+	proofs, err := smtReader.BatchInclusionProof(ctx, root.MapRevision, req.Index)
+	if err != nil {
+		return nil, fmt.Errorf("could not get inclusion proofs: %v", err)
+	}
+
+	inclusions := make([]*trillian.MapLeafInclusion, 0, len(req.Index))
+	found := 0
+	for i, index := range req.Index {
+		leaf, ok := leafByIndex[string(index)]
+		if ok {
 			found++
 		} else {
 			// Empty leaf for proof of non-existence.
@@ -109,17 +123,15 @@ func (t *TrillianMapServer) GetLeaves(ctx context.Context, req *trillian.GetMapL
 				LeafHash:  hasher.HashLeaf(mapID, index, nil),
 			}
 		}
-
-		// Fetch the proof regardless of whether the leaf exists.
-		proof, err := smtReader.InclusionProof(ctx, root.MapRevision, index)
-		if err != nil {
-			return nil, fmt.Errorf("could not get inclusion proof for leaf %x: %v", index, err)
-		}
-
-		inclusions = append(inclusions, &trillian.MapLeafInclusion{
+		inclusion := &trillian.MapLeafInclusion{
 			Leaf:      leaf,
-			Inclusion: proof,
-		})
+			Inclusion: proofs[i],
+			Absent:    !ok,
+		}
+		if req.CompressProofs {
+			inclusion.InclusionBitmap, inclusion.Inclusion = merkle.CompressMapInclusionProof(proofs[i])
+		}
+		inclusions = append(inclusions, inclusion)
 	}
 	glog.Infof("%v: wanted %v leaves, found %v", mapID, len(req.Index), found)
 
@@ -133,6 +145,123 @@ func (t *TrillianMapServer) GetLeaves(ctx context.Context, req *trillian.GetMapL
 	}, nil
 }
 
+// maxLeafHistoryPageSize is the maximum (and default) number of revisions
+// returned by each GetLeafHistory call.
+const maxLeafHistoryPageSize = 100
+
+// GetLeafHistory implements the GetLeafHistory RPC method.
+func (t *TrillianMapServer) GetLeafHistory(ctx context.Context, req *trillian.GetLeafHistoryRequest) (*trillian.GetLeafHistoryResponse, error) {
+	mapID := req.MapId
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, mapID, true /* readonly */)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", mapID, err)
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	if got, want := len(req.Index), hasher.Size(); got != want {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"index len(%x): %v, want %v", req.Index, got, want)
+	}
+	if req.FromRevision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "from_revision: %v, want >= 0", req.FromRevision)
+	}
+	pageSize := int(req.PageSize)
+	if pageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size: %v, want >= 0", req.PageSize)
+	}
+	if pageSize == 0 || pageSize > maxLeafHistoryPageSize {
+		pageSize = maxLeafHistoryPageSize
+	}
+	from := req.FromRevision
+	if req.PageToken != "" {
+		// Page tokens hold the first revision to search next.
+		next, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || next < from {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", req.PageToken)
+		}
+		from = next
+	}
+
+	tx, err := t.registry.MapStorage.SnapshotForTree(ctx, mapID)
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer tx.Close()
+
+	latest, err := tx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the latest SignedMapRoot: %v", err)
+	}
+	to := req.ToRevision
+	if to < 0 || to > latest.MapRevision {
+		to = latest.MapRevision
+	}
+
+	var history []storage.MapLeafRevision
+	if from <= to {
+		if history, err = tx.GetHistory(ctx, req.Index, from, to, pageSize); err != nil {
+			return nil, fmt.Errorf("could not fetch leaf history: %v", err)
+		}
+	}
+
+	// Revisions which rewrite the leaf with the value it already had are
+	// skipped, so the value before the page is needed to check the first one.
+	prevAbsent, prevValue := true, []byte(nil)
+	if len(history) > 0 && from > 0 {
+		leaves, err := tx.Get(ctx, from-1, [][]byte{req.Index})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch leaf at revision %v: %v", from-1, err)
+		}
+		if len(leaves) > 0 {
+			prevAbsent, prevValue = false, leaves[0].LeafValue
+		}
+	}
+
+	revisions := make([]*trillian.MapLeafRevision, 0, len(history))
+	for _, h := range history {
+		leaf := h.Leaf
+		absent := len(leaf.LeafHash) == 0
+		if absent == prevAbsent && bytes.Equal(leaf.LeafValue, prevValue) {
+			continue
+		}
+		prevAbsent, prevValue = absent, leaf.LeafValue
+
+		root, err := tx.GetSignedMapRoot(ctx, h.Revision)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch SignedMapRoot %v: %v", h.Revision, err)
+		}
+		proof, err := merkle.NewSparseMerkleTreeReader(h.Revision, hasher, tx).InclusionProof(ctx, h.Revision, req.Index)
+		if err != nil {
+			return nil, fmt.Errorf("could not get inclusion proof at revision %v: %v", h.Revision, err)
+		}
+		if absent {
+			// Deleted leaf, as returned by GetLeaves for absent leaves.
+			leaf.LeafHash = hasher.HashLeaf(mapID, req.Index, nil)
+			leaf.ExtraData = nil
+		}
+		revisions = append(revisions, &trillian.MapLeafRevision{
+			Revision: h.Revision,
+			MapLeafInclusion: &trillian.MapLeafInclusion{
+				Leaf:      &leaf,
+				Inclusion: proof,
+				Absent:    absent,
+			},
+			MapRoot: &root,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit db transaction: %v", err)
+	}
+
+	resp := &trillian.GetLeafHistoryResponse{Revisions: revisions}
+	if len(history) == pageSize {
+		resp.NextPageToken = strconv.FormatInt(history[len(history)-1].Revision+1, 10)
+	}
+	return resp, nil
+}
+
 // SetLeaves implements the SetLeaves RPC method.
 func (t *TrillianMapServer) SetLeaves(ctx context.Context, req *trillian.SetMapLeavesRequest) (*trillian.SetMapLeavesResponse, error) {
 	mapID := req.MapId
@@ -161,31 +290,55 @@ func (t *TrillianMapServer) SetLeaves(ctx context.Context, req *trillian.SetMapL
 		return nil, err
 	}
 
+	leaves := make([]trillian.MapLeaf, 0, len(req.Leaves)+len(req.DeleteIndex))
+	kvs := make([]merkle.HashKeyValue, 0, len(req.Leaves)+len(req.DeleteIndex))
+	seen := make(map[string]bool)
+	checkIndex := func(index []byte) error {
+		if got, want := len(index), hasher.Size(); got != want {
+			return status.Errorf(codes.InvalidArgument,
+				"len(%x): %v, want %v", index, got, want)
+		}
+		if seen[string(index)] {
+			return status.Errorf(codes.InvalidArgument, "duplicate index %x", index)
+		}
+		seen[string(index)] = true
+		return nil
+	}
 	for _, l := range req.Leaves {
-		if got, want := len(l.Index), hasher.Size(); got != want {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"len(%x): %v, want %v", l.Index, got, want)
+		if err := checkIndex(l.Index); err != nil {
+			return nil, err
 		}
-		if l.LeafValue == nil {
-			// Leaves are empty by default. Do not allow clients to store
-			// empty leaf values as this messes up the calculation of empty
-			// branches.
-			continue
+		if len(l.LeafValue) == 0 {
+			// proto3 can't tell an empty value from a missing one, and an empty
+			// value reads back as an absent leaf. Leaves are deleted through
+			// delete_index instead.
+			return nil, status.Errorf(codes.InvalidArgument, "empty leaf value at index %x, use delete_index to delete leaves", l.Index)
 		}
 		// TODO(gbelvin) use LeafHash rather than computing here. #423
 		l.LeafHash = hasher.HashLeaf(mapID, l.Index, l.LeafValue)
-
-		if err = tx.Set(ctx, l.Index, *l); err != nil {
-			return nil, err
-		}
-		if err = smtWriter.SetLeaves(ctx, []merkle.HashKeyValue{
-			{
-				HashedKey:   l.Index,
-				HashedValue: l.LeafHash,
-			},
-		}); err != nil {
+		leaves = append(leaves, *l)
+		kvs = append(kvs, merkle.HashKeyValue{
+			HashedKey:   l.Index,
+			HashedValue: l.LeafHash,
+		})
+	}
+	for _, index := range req.DeleteIndex {
+		if err := checkIndex(index); err != nil {
 			return nil, err
 		}
+		// A tombstone is stored, so later revisions read the leaf as absent,
+		// and the leaf is reset to its empty value in the sparse Merkle tree.
+		leaves = append(leaves, trillian.MapLeaf{Index: index})
+		kvs = append(kvs, merkle.HashKeyValue{HashedKey: index})
+	}
+
+	if err = tx.SetBatch(ctx, leaves); err != nil {
+		return nil, err
+	}
+	// All leaves are handed over at once, so the subtree writers for different
+	// prefixes work on them in parallel.
+	if err = smtWriter.SetLeaves(ctx, kvs); err != nil {
+		return nil, err
 	}
 
 	rootHash, err := smtWriter.CalculateRoot()
@@ -277,7 +430,7 @@ func (t *TrillianMapServer) getTreeAndHasher(ctx context.Context, treeID int64,
 		ctx,
 		t.registry.AdminStorage,
 		treeID,
-		trees.GetOpts{TreeType: trillian.TreeType_MAP, Readonly: readonly})
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_MAP}, Readonly: readonly})
 	if err != nil {
 		return nil, nil, err
 	}
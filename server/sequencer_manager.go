@@ -36,6 +36,8 @@ type SequencerManager struct {
 	registry     extension.Registry
 	signers      map[int64]*crypto.Signer
 	signersMutex sync.Mutex
+	treeCache    *log.CompactTreeCache
+	batchSizer   *log.BatchSizer
 }
 
 // NewSequencerManager creates a new SequencerManager instance based on the provided KeyManager instance
@@ -45,6 +47,8 @@ func NewSequencerManager(registry extension.Registry, gw time.Duration) *Sequenc
 		guardWindow: gw,
 		registry:    registry,
 		signers:     make(map[int64]*crypto.Signer),
+		treeCache:   log.NewCompactTreeCache(),
+		batchSizer:  log.NewBatchSizer(),
 	}
 }
 
@@ -54,18 +58,25 @@ func (s *SequencerManager) Name() string {
 }
 
 // ExecutePass performs sequencing for the specified Log.
+// If the log has sequencing disabled no leaves are integrated, but a new root
+// is still signed if required by the log's MaxRootDuration.
+// FROZEN logs are left untouched: no leaves are integrated and no roots are
+// signed.
 func (s *SequencerManager) ExecutePass(ctx context.Context, logID int64, info *LogOperationInfo) (int, error) {
-	// TODO(Martin2112): Honor the sequencing enabled in log parameters, needs an API change
-	// so deferring it
-
+	// The tree is read as readonly so that FROZEN logs can be skipped, rather
+	// than reported as errors.
 	tree, err := trees.GetTree(
 		ctx,
 		s.registry.AdminStorage,
 		logID,
-		trees.GetOpts{TreeType: trillian.TreeType_LOG})
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}, Readonly: true})
 	if err != nil {
 		return 0, fmt.Errorf("error retrieving log %v: %v", logID, err)
 	}
+	if tree.TreeState == trillian.TreeState_FROZEN {
+		glog.V(1).Infof("%v: log is frozen, skipping", logID)
+		return 0, nil
+	}
 	ctx = trees.NewContext(ctx, tree)
 
 	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
@@ -78,20 +89,43 @@ func (s *SequencerManager) ExecutePass(ctx context.Context, logID int64, info *L
 		return 0, fmt.Errorf("error getting signer for log %v: %v", logID, err)
 	}
 
-	sequencer := log.NewSequencer(hasher, info.TimeSource, s.registry.LogStorage, signer, s.registry.MetricFactory, s.registry.QuotaManager)
+	sequencer := log.NewSequencer(hasher, info.TimeSource, s.registry.LogStorage, signer, s.registry.MetricFactory, s.registry.QuotaManager, s.treeCache)
+	sequencer.SetPipelined(info.PipelineSequencing)
+	if info.MaxBatchSizeFactor > 1 {
+		sequencer.SetBatchSizer(s.batchSizer, info.MaxBatchSizeFactor)
+	}
 
 	maxRootDuration, err := ptypes.Duration(tree.MaxRootDuration)
 	if err != nil {
 		glog.Warning("failed to parse tree.MaxRootDuration, using zero")
 		maxRootDuration = 0
 	}
-	leaves, err := sequencer.SequenceBatch(ctx, logID, info.BatchSize, s.guardWindow, maxRootDuration)
+	maxMergeDelay := info.MaxMergeDelay
+	if tree.MaxMergeDelay != nil {
+		if d, err := ptypes.Duration(tree.MaxMergeDelay); err != nil {
+			glog.Warningf("%v: failed to parse tree.MaxMergeDelay, using %v", logID, maxMergeDelay)
+		} else if d > 0 {
+			maxMergeDelay = d
+		}
+	}
+	batchSize := info.BatchSize
+	if tree.SequencingDisabled {
+		glog.V(1).Infof("%v: sequencing disabled, not dequeuing leaves", logID)
+		batchSize = 0
+	}
+	leaves, err := sequencer.SequenceBatch(ctx, tree, batchSize, s.guardWindow, maxRootDuration, maxMergeDelay)
 	if err != nil {
 		return 0, fmt.Errorf("failed to sequence batch for %v: %v", logID, err)
 	}
 	return leaves, nil
 }
 
+// MastershipLost drops the cached compact Merkle tree of the log, as another
+// instance may sequence it before this one is master again.
+func (s *SequencerManager) MastershipLost(logID int64) {
+	s.treeCache.Drop(logID)
+}
+
 // getSigner returns a signer for the given tree.
 // Signers are cached, so only one will be created per tree.
 func (s *SequencerManager) getSigner(ctx context.Context, tree *trillian.Tree) (*crypto.Signer, error) {
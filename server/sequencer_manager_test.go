@@ -128,6 +128,8 @@ func TestSequencerManagerSingleLogNoLeaves(t *testing.T) {
 	mockTx.EXPECT().Close().Return(nil)
 	mockTx.EXPECT().WriteRevision().AnyTimes().Return(writeRev)
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil)
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(time.Time{}, nil)
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(51)).Return(nil, nil)
 	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 50, fakeTime).Return([]*trillian.LogLeaf{}, nil)
 
 	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
@@ -145,6 +147,146 @@ func TestSequencerManagerSingleLogNoLeaves(t *testing.T) {
 	sm.ExecutePass(ctx, logID, createTestInfo(registry))
 }
 
+func TestSequencerManagerSequencingDisabled(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tree := *stestonly.LogTree
+	tree.SequencingDisabled = true
+	logID := tree.GetTreeId()
+	mockAdmin := storage.NewMockAdminStorage(mockCtrl)
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(mockCtrl)
+	mockStorage := storage.NewMockLogStorage(mockCtrl)
+	mockTx := storage.NewMockLogTreeTX(mockCtrl)
+
+	var keyProto ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(tree.PrivateKey, &keyProto); err != nil {
+		t.Fatalf("Failed to unmarshal tree.PrivateKey: %v", err)
+	}
+
+	signer, err := newSignerWithFixedSig(updatedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create fake signer: %v", err)
+	}
+
+	keys.RegisterHandler(fakeKeyProtoHandler(keyProto.Message, signer, nil))
+	defer keys.UnregisterHandler(keyProto.Message)
+
+	// No leaves should be dequeued for a log with sequencing disabled.
+	mockStorage.EXPECT().BeginForTree(gomock.Any(), logID).Return(mockTx, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockTx.EXPECT().WriteRevision().AnyTimes().Return(writeRev)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil)
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(time.Time{}, nil)
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(1)).Return(nil, nil)
+	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 0, fakeTime).Return([]*trillian.LogLeaf{}, nil)
+
+	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
+	mockAdminTx.EXPECT().GetTree(gomock.Any(), logID).Return(&tree, nil)
+	mockAdminTx.EXPECT().Commit().Return(nil)
+	mockAdminTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   mockStorage,
+		QuotaManager: quota.Noop(),
+	}
+
+	sm := NewSequencerManager(registry, zeroDuration)
+	if _, err := sm.ExecutePass(ctx, logID, createTestInfo(registry)); err != nil {
+		t.Errorf("ExecutePass() = (_, %v), want = (_, nil)", err)
+	}
+}
+
+func TestSequencerManagerMaxMergeDelay(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tree := *stestonly.LogTree
+	tree.MaxMergeDelay = ptypes.DurationProto(time.Hour)
+	logID := tree.GetTreeId()
+	mockAdmin := storage.NewMockAdminStorage(mockCtrl)
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(mockCtrl)
+	mockStorage := storage.NewMockLogStorage(mockCtrl)
+	mockTx := storage.NewMockLogTreeTX(mockCtrl)
+
+	var keyProto ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(tree.PrivateKey, &keyProto); err != nil {
+		t.Fatalf("Failed to unmarshal tree.PrivateKey: %v", err)
+	}
+
+	signer, err := newSignerWithFixedSig(updatedRoot.Signature)
+	if err != nil {
+		t.Fatalf("Failed to create fake signer: %v", err)
+	}
+
+	keys.RegisterHandler(fakeKeyProtoHandler(keyProto.Message, signer, nil))
+	defer keys.UnregisterHandler(keyProto.Message)
+
+	// The log's own maximum merge delay overrides the signer's, and a queue past half of it
+	// is dequeued in larger batches.
+	mockStorage.EXPECT().BeginForTree(gomock.Any(), logID).Return(mockTx, nil)
+	mockTx.EXPECT().Commit().Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockTx.EXPECT().WriteRevision().AnyTimes().Return(writeRev)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil)
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(fakeTime.Add(-40*time.Minute), nil)
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(201)).Return(nil, nil)
+	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 200, fakeTime).Return([]*trillian.LogLeaf{}, nil)
+
+	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
+	mockAdminTx.EXPECT().GetTree(gomock.Any(), logID).Return(&tree, nil)
+	mockAdminTx.EXPECT().Commit().Return(nil)
+	mockAdminTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   mockStorage,
+		QuotaManager: quota.Noop(),
+	}
+
+	sm := NewSequencerManager(registry, zeroDuration)
+	info := createTestInfo(registry)
+	info.MaxMergeDelay = 24 * time.Hour
+	if _, err := sm.ExecutePass(ctx, logID, info); err != nil {
+		t.Errorf("ExecutePass() = (_, %v), want = (_, nil)", err)
+	}
+}
+
+func TestSequencerManagerFrozenLog(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tree := *stestonly.LogTree
+	tree.TreeState = trillian.TreeState_FROZEN
+	logID := tree.GetTreeId()
+	mockAdmin := storage.NewMockAdminStorage(mockCtrl)
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(mockCtrl)
+	// No log storage calls are expected: frozen logs are neither sequenced nor
+	// signed.
+	mockStorage := storage.NewMockLogStorage(mockCtrl)
+
+	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
+	mockAdminTx.EXPECT().GetTree(gomock.Any(), logID).Return(&tree, nil)
+	mockAdminTx.EXPECT().Commit().Return(nil)
+	mockAdminTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   mockStorage,
+		QuotaManager: quota.Noop(),
+	}
+
+	sm := NewSequencerManager(registry, zeroDuration)
+	if got, err := sm.ExecutePass(ctx, logID, createTestInfo(registry)); got != 0 || err != nil {
+		t.Errorf("ExecutePass() = (%v, %v), want = (0, nil)", got, err)
+	}
+}
+
 func TestSequencerManagerCachesSigners(t *testing.T) {
 	ctx := context.Background()
 	mockCtrl := gomock.NewController(t)
@@ -186,8 +328,10 @@ func TestSequencerManagerCachesSigners(t *testing.T) {
 
 		gomock.InOrder(
 			mockStorage.EXPECT().BeginForTree(gomock.Any(), logID).Return(mockTx, nil),
-			mockTx.EXPECT().DequeueLeaves(gomock.Any(), 50, fakeTime).Return([]*trillian.LogLeaf{}, nil),
+			mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(time.Time{}, nil),
 			mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil),
+			mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(51)).Return(nil, nil),
+			mockTx.EXPECT().DequeueLeaves(gomock.Any(), 50, fakeTime).Return([]*trillian.LogLeaf{}, nil),
 			mockTx.EXPECT().WriteRevision().AnyTimes().Return(writeRev),
 			mockTx.EXPECT().Commit().Return(nil),
 			mockTx.EXPECT().Close().Return(nil),
@@ -272,6 +416,8 @@ func TestSequencerManagerSingleLogOneLeaf(t *testing.T) {
 	mockTx.EXPECT().Commit().Return(nil)
 	mockTx.EXPECT().Close().Return(nil)
 	mockTx.EXPECT().WriteRevision().AnyTimes().Return(testRoot0.TreeRevision + 1)
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(time.Time{}, nil)
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(51)).Return(nil, nil)
 	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 50, fakeTime).Return([]*trillian.LogLeaf{testLeaf0}, nil)
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil)
 	mockTx.EXPECT().UpdateSequencedLeaves(gomock.Any(), []*trillian.LogLeaf{testLeaf0Updated}).Return(nil)
@@ -323,7 +469,9 @@ func TestSequencerManagerGuardWindow(t *testing.T) {
 	mockTx.EXPECT().Close().Return(nil)
 	mockTx.EXPECT().WriteRevision().AnyTimes().Return(writeRev)
 	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testRoot0, nil)
+	mockTx.EXPECT().GetOldestQueueTimestamp(gomock.Any()).Return(time.Time{}, nil)
 	// Expect a 5 second guard window to be passed from manager -> sequencer -> storage
+	mockTx.EXPECT().GetLeavesByRange(gomock.Any(), int64(0), int64(51)).Return(nil, nil)
 	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 50, fakeTime.Add(-time.Second*5)).Return([]*trillian.LogLeaf{}, nil)
 
 	mockAdmin.EXPECT().Snapshot(gomock.Any()).Return(mockAdminTx, nil)
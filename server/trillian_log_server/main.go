@@ -59,6 +59,11 @@ var (
 	etcdHTTPService    = flag.String("etcd_http_service", "trillian-logserver-http", "Service name to announce our HTTP endpoint under")
 	maxUnsequencedRows = flag.Int("max_unsequenced_rows", mysqlq.DefaultMaxUnsequenced, "Max number of unsequenced rows before rate limiting kicks in")
 	quotaDryRun        = flag.Bool("quota_dry_run", false, "If true no requests are blocked due to lack of tokens")
+	maxResultsPerHash  = flag.Int("max_results_per_hash", server.DefaultMaxResultsPerHash, "Max number of leaves or proofs returned by a single GetLeavesByHash, GetLeavesByIdentityHash or GetInclusionProofByHash response")
+
+	treeGCEnabled         = flag.Bool("tree_gc", true, "If true, soft-deleted trees are periodically hard-deleted")
+	treeDeleteThreshold   = flag.Duration("tree_delete_threshold", server.DefaultTreeDeleteThreshold, "Minimum period a tree has to remain soft-deleted before being hard-deleted")
+	treeDeleteMinInterval = flag.Duration("tree_delete_min_run_interval", server.DefaultTreeDeleteMinInterval, "Minimum interval between deleted tree garbage collection sweeps")
 
 	configFile = flag.String("config", "", "Config file containing flags, file contents can be overridden by command line flags")
 )
@@ -110,7 +115,7 @@ func main() {
 	ti := interceptor.New(
 		registry.AdminStorage, registry.QuotaManager, *quotaDryRun, registry.MetricFactory)
 	netInterceptor := interceptor.Combine(stats.Interceptor(), interceptor.ErrorWrapper, ti.UnaryInterceptor)
-	s := grpc.NewServer(grpc.UnaryInterceptor(netInterceptor))
+	s := grpc.NewServer(grpc.UnaryInterceptor(netInterceptor), grpc.StreamInterceptor(ti.StreamInterceptor))
 	// No defer: server ownership is delegated to server.Main
 
 	m := server.Main{
@@ -122,12 +127,16 @@ func main() {
 		RegisterHandlerFn: trillian.RegisterTrillianLogHandlerFromEndpoint,
 		RegisterServerFn: func(s *grpc.Server, registry extension.Registry) error {
 			logServer := server.NewTrillianLogRPCServer(registry, ts)
+			logServer.MaxResultsPerHash = *maxResultsPerHash
 			if err := logServer.IsHealthy(); err != nil {
 				return err
 			}
 			trillian.RegisterTrillianLogServer(s, logServer)
 			return err
 		},
+		TreeGCEnabled:         *treeGCEnabled,
+		TreeDeleteThreshold:   *treeDeleteThreshold,
+		TreeDeleteMinInterval: *treeDeleteMinInterval,
 	}
 
 	if err := m.Run(ctx); err != nil {
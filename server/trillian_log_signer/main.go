@@ -49,10 +49,15 @@ import (
 var (
 	mySQLURI                 = flag.String("mysql_uri", "test:zaphod@tcp(127.0.0.1:3306)/test", "Connection URI for MySQL database")
 	httpEndpoint             = flag.String("http_endpoint", "localhost:8091", "Endpoint for HTTP (host:port, empty means disabled)")
-	sequencerIntervalFlag    = flag.Duration("sequencer_interval", time.Second*10, "Time between each sequencing pass through all logs")
+	sequencerIntervalFlag    = flag.Duration("sequencer_interval", time.Second*10, "Time between each sequencing pass through all logs, unless overridden by the log's sequence_interval")
 	batchSizeFlag            = flag.Int("batch_size", 50, "Max number of leaves to process per batch")
+	pipelineSequencingFlag   = flag.Bool("pipeline_sequencing", false, "If true, dequeue the next batch of a log while integrating the previous one, so leaves are integrated one sequencing pass after being dequeued")
+	maxBatchSizeFactorFlag   = flag.Int("max_batch_size_factor", 1, "If above 1, the batch size of a log with a large backlog grows up to this multiple of --batch_size")
+	scheduleByLoadFlag       = flag.Bool("schedule_by_load", false, "If true, skip idle logs and sequence the others in order of backlog, root age and scheduling_priority")
 	numSeqFlag               = flag.Int("num_sequencers", 10, "Number of sequencer workers to run in parallel")
 	sequencerGuardWindowFlag = flag.Duration("sequencer_guard_window", 0, "If set, the time elapsed before submitted leaves are eligible for sequencing")
+	maxMergeDelayFlag        = flag.Duration("max_merge_delay", 0, "If set, leaves integrated later than this after being queued are reported as maximum merge delay violations, unless overridden by the log's max_merge_delay")
+	drainTimeoutFlag         = flag.Duration("drain_timeout", 10*time.Second, "On shutdown, how long to let in-flight sequencing passes finish before they're cancelled and mastership is handed off")
 	forceMaster              = flag.Bool("force_master", false, "If true, assume master for all logs")
 	etcdServers              = flag.String("etcd_servers", "", "A comma-separated list of etcd servers")
 	etcdHTTPService          = flag.String("etcd_http_service", "trillian-logsigner-http", "Service name to announce our HTTP endpoint under")
@@ -126,14 +131,20 @@ func main() {
 	}
 
 	// Start the sequencing loop, which will run until we terminate the process. This controls
-	// both sequencing and signing.
-	// TODO(Martin2112): Should respect read only mode and the flags in tree control etc
+	// both sequencing and signing. FROZEN logs are neither sequenced nor signed.
+	// On SIGINT or SIGTERM the loop lets in-flight passes drain, and resigns mastership of
+	// the logs it holds before returning.
 	log.QuotaIncreaseFactor = *quotaIncreaseFactor
 	sequencerManager := server.NewSequencerManager(registry, *sequencerGuardWindowFlag)
 	info := server.LogOperationInfo{
 		Registry:            registry,
 		BatchSize:           *batchSizeFlag,
+		PipelineSequencing:  *pipelineSequencingFlag,
+		MaxBatchSizeFactor:  *maxBatchSizeFactorFlag,
+		MaxMergeDelay:       *maxMergeDelayFlag,
 		NumWorkers:          *numSeqFlag,
+		ScheduleByLoad:      *scheduleByLoadFlag,
+		DrainTimeout:        *drainTimeoutFlag,
 		RunInterval:         *sequencerIntervalFlag,
 		TimeSource:          util.SystemTimeSource{},
 		PreElectionPause:    *preElectionPause,
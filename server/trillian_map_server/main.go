@@ -55,6 +55,10 @@ var (
 	maxUnsequencedRows = flag.Int("max_unsequenced_rows", mysqlq.DefaultMaxUnsequenced, "Max number of unsequenced rows before rate limiting kicks in")
 	quotaDryRun        = flag.Bool("quota_dry_run", false, "If true no requests are blocked due to lack of tokens")
 
+	treeGCEnabled         = flag.Bool("tree_gc", true, "If true, soft-deleted trees are periodically hard-deleted")
+	treeDeleteThreshold   = flag.Duration("tree_delete_threshold", server.DefaultTreeDeleteThreshold, "Minimum period a tree has to remain soft-deleted before being hard-deleted")
+	treeDeleteMinInterval = flag.Duration("tree_delete_min_run_interval", server.DefaultTreeDeleteMinInterval, "Minimum interval between deleted tree garbage collection sweeps")
+
 	configFile = flag.String("config", "", "Config file containing flags, file contents can be overridden by command line flags")
 )
 
@@ -106,6 +110,9 @@ func main() {
 			trillian.RegisterTrillianMapServer(s, mapServer)
 			return err
 		},
+		TreeGCEnabled:         *treeGCEnabled,
+		TreeDeleteThreshold:   *treeDeleteThreshold,
+		TreeDeleteMinInterval: *treeDeleteMinInterval,
 	}
 
 	ctx := context.Background()
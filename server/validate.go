@@ -35,8 +35,14 @@ func validateGetInclusionProofRequest(req *trillian.GetInclusionProofRequest) er
 }
 
 func validateGetInclusionProofByHashRequest(req *trillian.GetInclusionProofByHashRequest) error {
-	if req.TreeSize <= 0 {
-		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.TreeSize: %v, want > 0", req.TreeSize)
+	if req.TreeSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.TreeSize: %v, want >= 0", req.TreeSize)
+	}
+	if req.FirstTreeSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.FirstTreeSize: %v, want >= 0", req.FirstTreeSize)
+	}
+	if req.TreeSize > 0 && req.FirstTreeSize > req.TreeSize {
+		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.FirstTreeSize: %v > TreeSize: %v, want <= ", req.FirstTreeSize, req.TreeSize)
 	}
 	if len(req.LeafHash) == 0 {
 		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.LeafHash empty")
@@ -70,9 +76,58 @@ func validateGetEntryAndProofRequest(req *trillian.GetEntryAndProofRequest) erro
 	return nil
 }
 
+func validateGetLeavesByRangeRequest(req *trillian.GetLeavesByRangeRequest) error {
+	if req.StartIndex < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeRequest.StartIndex: %v, want >= 0", req.StartIndex)
+	}
+	if req.Count <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeRequest.Count: %v, want > 0", req.Count)
+	}
+	return nil
+}
+
 func validateQueueLeavesRequest(req *trillian.QueueLeavesRequest) error {
 	if len(req.Leaves) == 0 {
 		return status.Errorf(codes.InvalidArgument, "len(QueueLeavesRequest.Leaves)=0, want > 0")
 	}
 	return nil
 }
+
+func validateAddSequencedLeavesRequest(req *trillian.AddSequencedLeavesRequest) error {
+	if len(req.Leaves) == 0 {
+		return status.Errorf(codes.InvalidArgument, "len(AddSequencedLeavesRequest.Leaves)=0, want > 0")
+	}
+	indices := make(map[int64]bool)
+	for i, leaf := range req.Leaves {
+		if leaf.LeafIndex < 0 {
+			return status.Errorf(codes.InvalidArgument, "AddSequencedLeavesRequest.Leaves[%v].LeafIndex: %v, want >= 0", i, leaf.LeafIndex)
+		}
+		if indices[leaf.LeafIndex] {
+			return status.Errorf(codes.InvalidArgument, "AddSequencedLeavesRequest.Leaves[%v].LeafIndex: %v repeated", i, leaf.LeafIndex)
+		}
+		indices[leaf.LeafIndex] = true
+	}
+	return nil
+}
+
+func validateGetLeavesByIdentityHashRequest(req *trillian.GetLeavesByIdentityHashRequest) error {
+	if len(req.LeafIdentityHash) == 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByIdentityHashRequest.LeafIdentityHash empty")
+	}
+	for i, hash := range req.LeafIdentityHash {
+		if len(hash) == 0 {
+			return status.Errorf(codes.InvalidArgument, "GetLeavesByIdentityHashRequest.LeafIdentityHash[%v] empty", i)
+		}
+	}
+	if req.TreeSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByIdentityHashRequest.TreeSize: %v, want >= 0", req.TreeSize)
+	}
+	return nil
+}
+
+func validateGetLeafStatusRequest(req *trillian.GetLeafStatusRequest) error {
+	if len(req.LeafIdentityHash) == 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeafStatusRequest.LeafIdentityHash empty")
+	}
+	return nil
+}
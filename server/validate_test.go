@@ -129,6 +129,14 @@ func TestGetProofByHashInvalidRequests(t *testing.T) {
 				LeafHash: []byte{},
 			},
 		},
+		{
+			hReq: &trillian.GetInclusionProofByHashRequest{
+				LogId:         logID1,
+				TreeSize:      50,
+				LeafHash:      []byte("data"),
+				FirstTreeSize: 51,
+			},
+		},
 	} {
 		if err := validateGetInclusionProofByHashRequest(test.hReq); err == nil {
 			t.Errorf("verifyGetInclusionProofByHash(%v): %v, want nil",
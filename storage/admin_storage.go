@@ -81,10 +81,64 @@ type AdminReader interface {
 	// so it should be used with caution in production code.
 	ListTreeIDs(ctx context.Context) ([]int64, error)
 
-	// ListTrees returns all trees in storage.
+	// ListTrees returns the trees in storage that match opts, ordered by
+	// TreeId.
 	// Note that there's no authorization restriction on the trees returned,
 	// so it should be used with caution in production code.
-	ListTrees(ctx context.Context) ([]*trillian.Tree, error)
+	ListTrees(ctx context.Context, opts ListTreesOptions) ([]*trillian.Tree, error)
+}
+
+// ListTreesOptions holds the filtering and pagination options of
+// AdminReader.ListTrees.
+// The zero value matches all non-deleted trees.
+type ListTreesOptions struct {
+	// AfterTreeID restricts results to trees whose TreeId is greater than it.
+	// Since results are ordered by TreeId, it may be set to the last ID of a
+	// previous call to retrieve the next page.
+	AfterTreeID int64
+
+	// PageSize is the maximum number of trees returned. Zero means no limit.
+	PageSize int
+
+	// TreeType, if not UNKNOWN_TREE_TYPE, restricts results to trees of the
+	// specified type.
+	TreeType trillian.TreeType
+
+	// TreeState, if not UNKNOWN_TREE_STATE, restricts results to trees in the
+	// specified state.
+	TreeState trillian.TreeState
+
+	// ShowDeleted makes SOFT_DELETED and HARD_DELETED trees eligible for
+	// results. If false, deleted trees are never returned, regardless of
+	// TreeState.
+	ShowDeleted bool
+}
+
+// Matches returns true if tree passes all filters in opts.
+// Pagination options (AfterTreeID and PageSize) are not considered.
+func (opts ListTreesOptions) Matches(tree *trillian.Tree) bool {
+	if opts.TreeType != trillian.TreeType_UNKNOWN_TREE_TYPE && tree.TreeType != opts.TreeType {
+		return false
+	}
+	if opts.TreeState != trillian.TreeState_UNKNOWN_TREE_STATE && tree.TreeState != opts.TreeState {
+		return false
+	}
+	return opts.ShowDeleted || !IsDeleted(tree)
+}
+
+// IsDeleted returns true if tree is either SOFT_DELETED or HARD_DELETED.
+func IsDeleted(tree *trillian.Tree) bool {
+	return tree.TreeState == trillian.TreeState_SOFT_DELETED || tree.TreeState == trillian.TreeState_HARD_DELETED
+}
+
+// IsFreezing returns true if an update from storedTree to newTree freezes a
+// log, either a LOG or a PREORDERED_LOG tree. Logs may only be frozen once
+// their unsequenced queue is empty and their latest signed root covers every
+// stored leaf, so that their final signed root covers every accepted leaf.
+func IsFreezing(storedTree, newTree *trillian.Tree) bool {
+	return (newTree.TreeType == trillian.TreeType_LOG || newTree.TreeType == trillian.TreeType_PREORDERED_LOG) &&
+		storedTree.TreeState != trillian.TreeState_FROZEN &&
+		newTree.TreeState == trillian.TreeState_FROZEN
 }
 
 // AdminWriter provides a write-only interface for tree data.
@@ -103,6 +157,27 @@ type AdminWriter interface {
 	// to trillian.Tree for details on which fields are mutable and what is
 	// considered valid.
 	// Returns an error if the tree is invalid or the update cannot be
-	// performed.
+	// performed. Freezing a log with unsequenced leaves fails with
+	// FailedPrecondition.
 	UpdateTree(ctx context.Context, treeID int64, updateFunc func(*trillian.Tree)) (*trillian.Tree, error)
+
+	// SoftDeleteTree marks the specified tree as SOFT_DELETED, recording the
+	// time of deletion in delete_time. The state the tree was in before
+	// deletion is kept, so UndeleteTree can restore it.
+	// Returns an error if the tree is already deleted or cannot be found.
+	SoftDeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error)
+
+	// UndeleteTree restores a SOFT_DELETED tree to the state it was in before
+	// it was deleted (ACTIVE or FROZEN).
+	// Returns an error if the tree isn't soft-deleted or cannot be found.
+	UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error)
+
+	// HardDeleteTree permanently deletes all data held by a SOFT_DELETED
+	// tree (nodes, leaves, tree heads, etc) and marks it as HARD_DELETED.
+	// The tree itself is kept in storage, so its ID is never reused.
+	// Implementations may purge large trees in bounded chunks, each
+	// committed on its own, so a failed call may leave a SOFT_DELETED tree
+	// with part of its data purged. The call can then be retried.
+	// Returns an error if the tree isn't soft-deleted or cannot be found.
+	HardDeleteTree(ctx context.Context, treeID int64) error
 }
@@ -327,13 +327,26 @@ func (s *SubtreeCache) SetNodeHash(id storage.NodeID, h []byte, getSubtree GetSu
 		return fmt.Errorf("nil prefix for %v (key %v)", id.String(), prefixKey)
 	}
 	s.dirtyPrefixes[prefixKey] = true
+	// Subtrees without any leaves are read back from storage with nil maps.
+	if c.Leaves == nil {
+		c.Leaves = make(map[string][]byte)
+	}
+	if c.InternalNodes == nil {
+		c.InternalNodes = make(map[string][]byte)
+	}
 	// Determine whether we're being asked to store a leaf node, or an internal
 	// node, and store it accordingly.
+	// A nil hash means the node is empty (e.g. a deleted map leaf), so it's
+	// removed from the subtree.
 	sfxKey := sx.String()
+	nodes := c.InternalNodes
 	if int32(sx.Bits) == c.Depth {
-		c.Leaves[sfxKey] = h
+		nodes = c.Leaves
+	}
+	if h == nil {
+		delete(nodes, sfxKey)
 	} else {
-		c.InternalNodes[sfxKey] = h
+		nodes[sfxKey] = h
 	}
 	if glog.V(4) {
 		b, err := base64.StdEncoding.DecodeString(sfxKey)
@@ -361,13 +374,13 @@ func (s *SubtreeCache) Flush(setSubtrees SetSubtreesFunc) error {
 			// subtree root value here during tree update calculations.
 			v.RootHash = nil
 
-			if len(v.Leaves) > 0 {
-				// prepare internal nodes ready for the write (tree type specific)
-				if err := s.prepare(v); err != nil {
-					return err
-				}
-				treesToWrite = append(treesToWrite, v)
+			// Subtrees with no leaves left (e.g. after deleting map leaves) are
+			// written too, so they replace the previous revision of the subtree.
+			// prepare internal nodes ready for the write (tree type specific)
+			if err := s.prepare(v); err != nil {
+				return err
 			}
+			treesToWrite = append(treesToWrite, v)
 		}
 	}
 	if len(treesToWrite) == 0 {
@@ -247,6 +247,45 @@ func TestCacheFlush(t *testing.T) {
 	}
 }
 
+func TestCacheFlushEmptiedSubtree(t *testing.T) {
+	nodeID := storage.NewNodeIDFromHash([]byte("0123456789abcdef0123456789abcdef"))
+	c := NewMapSubtreeCache(defaultMapStrata, treeID, maphasher.Default)
+	px, _ := c.splitNodeID(nodeID)
+
+	// Write a leaf, then delete it with a later cache, as the map does in
+	// subsequent revisions.
+	var stored *storagepb.SubtreeProto
+	for _, h := range [][]byte{[]byte("leaf hash"), nil} {
+		c := NewMapSubtreeCache(defaultMapStrata, treeID, maphasher.Default)
+		getSubtree := func(id storage.NodeID) (*storagepb.SubtreeProto, error) {
+			if stored != nil && bytes.Equal(id.Path[:id.PrefixLenBits/8], stored.Prefix) {
+				return stored, nil
+			}
+			return nil, nil
+		}
+		if err := c.SetNodeHash(nodeID, h, getSubtree); err != nil {
+			t.Fatalf("SetNodeHash(%x): %v", h, err)
+		}
+		stored = nil
+		if err := c.Flush(func(subtrees []*storagepb.SubtreeProto) error {
+			for _, st := range subtrees {
+				if bytes.Equal(st.Prefix, px) {
+					stored = st
+				}
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("Flush(): %v", err)
+		}
+		if stored == nil {
+			t.Fatalf("Flush() didn't write subtree %x after setting %x", px, h)
+		}
+	}
+	if got := len(stored.Leaves); got != 0 {
+		t.Errorf("len(Leaves) after delete = %v, want 0", got)
+	}
+}
+
 func TestRepopulateLogSubtree(t *testing.T) {
 	populateTheThing := populateLogSubtreeNodes(rfc6962.DefaultHasher)
 	cmt := merkle.NewCompactMerkleTree(rfc6962.DefaultHasher)
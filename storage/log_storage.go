@@ -94,6 +94,13 @@ type LeafQueuer interface {
 	// Duplicates are only reported if the underlying tree does not permit duplicates, and are
 	// considered duplicate if their leaf.LeafIdentityHash matches.
 	QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error)
+	// AddSequencedLeaves stores leaves of a PREORDERED_LOG tree at the LeafIndex set by the
+	// caller, for later integration into the tree. If error is nil, the returned slice will be
+	// the same size as the input, and each entry will hold the leaf along with:
+	//  - a nil Status if the leaf was added
+	//  - an ALREADY_EXISTS Status if the same leaf is already stored at its index
+	//  - a FAILED_PRECONDITION Status if its index or LeafIdentityHash is taken by another leaf.
+	AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) ([]*trillian.QueuedLogLeaf, error)
 }
 
 // LeafDequeuer provides an interface for reading previously queued leaves for integration into the tree.
@@ -101,9 +108,15 @@ type LeafDequeuer interface {
 	// DequeueLeaves will return between [0, limit] leaves from the queue.
 	// Leaves which have been dequeued within a Rolled-back Tx will become available for dequeing again.
 	// Leaves queued more recently than the cutoff time will not be returned. This allows for
-	// guard intervals to be configured.
+	// guard intervals to be configured. The returned leaves have QueueTimestampNanos set.
+	// For PREORDERED_LOG trees the leaves are instead those added by AddSequencedLeaves at
+	// the indices following the current tree size, up to the first missing index, with
+	// LeafIndex set; cutoffTime doesn't apply to them.
 	DequeueLeaves(ctx context.Context, limit int, cutoffTime time.Time) ([]*trillian.LogLeaf, error)
 	UpdateSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error
+	// GetOldestQueueTimestamp returns when the oldest leaf still in the queue was queued, or
+	// the zero time if the queue is empty.
+	GetOldestQueueTimestamp(ctx context.Context) (time.Time, error)
 }
 
 // LeafReader provides a read only interface to stored tree leaves
@@ -113,11 +126,33 @@ type LeafReader interface {
 	GetSequencedLeafCount(ctx context.Context) (int64, error)
 	// GetLeavesByIndex returns leaf metadata and data for a set of specified sequenced leaf indexes.
 	GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*trillian.LogLeaf, error)
+	// GetLeavesByRange returns the sequenced leaves with indexes start to start+count-1, in
+	// ascending index order. Leaves that haven't been sequenced yet are left out, so fewer than
+	// count leaves may be returned.
+	GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error)
 	// GetLeavesByHash looks up sequenced leaf metadata and data by their Merkle leaf hash. If the
 	// tree permits duplicate leaves callers must be prepared to handle multiple results with the
 	// same hash but different sequence numbers. If orderBySequence is true then the returned data
-	// will be in ascending sequence number order.
-	GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool) ([]*trillian.LogLeaf, error)
+	// will be in ascending sequence number order. If limit is positive, at most limit leaves are
+	// returned after skipping the first offset matches; the results are then always in sequence
+	// number order so that successive pages are consistent.
+	GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool, limit, offset int) ([]*trillian.LogLeaf, error)
+	// GetLeavesByIdentityHash looks up sequenced leaf metadata and data by their LeafIdentityHash,
+	// in ascending sequence number order. Leaves that haven't been sequenced yet are left out. If
+	// limit is positive, at most limit leaves are returned after skipping the first offset matches.
+	GetLeavesByIdentityHash(ctx context.Context, identityHashes [][]byte, limit, offset int) ([]*trillian.LogLeaf, error)
+	// GetLeafStatus reports whether the leaf with the given LeafIdentityHash is queued or has been
+	// sequenced. If it has been sequenced more than once the lowest index is reported.
+	GetLeafStatus(ctx context.Context, identityHash []byte) (LeafStatus, error)
+}
+
+// LeafStatus is the result of a GetLeafStatus lookup.
+type LeafStatus struct {
+	Status trillian.LeafStatus
+	// QueueTimestampNanos is when the leaf was queued, if Status is QUEUED.
+	QueueTimestampNanos int64
+	// LeafIndex is the index of the leaf, if Status is SEQUENCED.
+	LeafIndex int64
 }
 
 // LogRootReader provides an interface for reading SignedLogRoots.
@@ -146,4 +181,13 @@ type LogMetadata interface {
 	// This call is likely to be VERY expensive and take a long time to complete.
 	// Consider carefully whether you really need to call it!
 	GetUnsequencedCounts(ctx context.Context) (CountByLogID, error)
+
+	// GetLatestSignedLogRoots returns the latest signed root of each log, keyed
+	// by log ID. Logs that have never been signed are left out.
+	GetLatestSignedLogRoots(ctx context.Context) (map[int64]trillian.SignedLogRoot, error)
+
+	// GetSequencedLeafEnds returns, by log ID, one more than the highest index
+	// at which a leaf is stored. Leaves at or above the tree size of the log's
+	// latest signed root are still waiting to be integrated.
+	GetSequencedLeafEnds(ctx context.Context) (CountByLogID, error)
 }
@@ -81,8 +81,15 @@ type MapStorage interface {
 
 // Setter allows the setting of key->value pairs on the map.
 type Setter interface {
-	// Set sets key to leaf
+	// Set sets key to leaf.
+	// A leaf without a LeafHash is a tombstone: it deletes the key, which is
+	// absent from this revision until set again. Leaves with an empty
+	// LeafValue are stored like any other.
 	Set(ctx context.Context, keyHash []byte, value trillian.MapLeaf) error
+
+	// SetBatch sets each leaf at its Index, writing as many leaves as possible
+	// per storage operation. Tombstones are handled as for Set.
+	SetBatch(ctx context.Context, leaves []trillian.MapLeaf) error
 }
 
 // Getter allows access to the values stored in the map.
@@ -91,9 +98,22 @@ type Getter interface {
 	// specified revision.
 	// Setting revision to -1 will fetch the latest revision.
 	// The returned array of MapLeaves will only contain entries for which values
-	// exist.  i.e. requesting a set of unknown keys would result in a
-	// zero-length array being returned.
+	// exist.  i.e. requesting a set of unknown (or deleted) keys would result in
+	// a zero-length array being returned.
 	Get(ctx context.Context, revision int64, keyHashes [][]byte) ([]trillian.MapLeaf, error)
+
+	// GetHistory returns the values written to keyHash in revisions
+	// fromRevision to toRevision (inclusive), in increasing revision order.
+	// At most limit values are returned. Tombstones are included, so
+	// deletions are part of the history.
+	GetHistory(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]MapLeafRevision, error)
+}
+
+// MapLeafRevision is the value of a map leaf written in a given revision.
+type MapLeafRevision struct {
+	Revision int64
+	// Leaf has no LeafHash if the leaf was deleted in Revision.
+	Leaf trillian.MapLeaf
 }
 
 // MapRootReader provides access to the map roots.
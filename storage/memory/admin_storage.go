@@ -15,14 +15,19 @@
 package memory
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/google/btree"
 	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
 	"github.com/google/trillian/storage"
 )
 
@@ -32,6 +37,12 @@ func NewAdminStorage(ms storage.LogStorage) storage.AdminStorage {
 	return &memoryAdminStorage{ms.(*memoryLogStorage).memoryTreeStorage}
 }
 
+// NewMapAdminStorage returns a storage.AdminStorage implementation backed by
+// the memoryTreeStorage of ms, which must have been created by NewMapStorage.
+func NewMapAdminStorage(ms storage.MapStorage) storage.AdminStorage {
+	return &memoryAdminStorage{ms.(*memoryMapStorage).memoryTreeStorage}
+}
+
 // memoryAdminStorage implements storage.AdminStorage
 type memoryAdminStorage struct {
 	ms *memoryTreeStorage
@@ -100,12 +111,11 @@ func (t *adminTX) Close() error {
 
 func (t *adminTX) GetTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
 	tree := t.ms.getTree(treeID)
-	tree.RLock()
-	defer tree.RUnlock()
-
 	if tree == nil {
-		return nil, fmt.Errorf("no such treeID %d", treeID)
+		return nil, errors.Errorf(errors.NotFound, "no such treeID %d", treeID)
 	}
+	tree.RLock()
+	defer tree.RUnlock()
 	return tree.meta, nil
 }
 
@@ -120,14 +130,21 @@ func (t *adminTX) ListTreeIDs(ctx context.Context) ([]int64, error) {
 	return ret, nil
 }
 
-func (t *adminTX) ListTrees(ctx context.Context) ([]*trillian.Tree, error) {
+func (t *adminTX) ListTrees(ctx context.Context, opts storage.ListTreesOptions) ([]*trillian.Tree, error) {
 	t.ms.mu.RLock()
 	defer t.ms.mu.RUnlock()
 
 	var ret []*trillian.Tree
 	for _, v := range t.ms.trees {
+		if v.meta.TreeId <= opts.AfterTreeID || !opts.Matches(v.meta) {
+			continue
+		}
 		ret = append(ret, v.meta)
 	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].TreeId < ret[j].TreeId })
+	if opts.PageSize > 0 && len(ret) > opts.PageSize {
+		ret = ret[:opts.PageSize]
+	}
 	return ret, nil
 }
 
@@ -148,6 +165,7 @@ func (t *adminTX) CreateTree(ctx context.Context, tr *trillian.Tree) (*trillian.
 
 	meta := *tr
 	meta.TreeId = id
+	meta.DeleteTime = nil
 	meta.CreateTime, err = ptypes.TimestampProto(now)
 	if err != nil {
 		return nil, err
@@ -168,25 +186,110 @@ func (t *adminTX) CreateTree(ctx context.Context, tr *trillian.Tree) (*trillian.
 
 func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(*trillian.Tree)) (*trillian.Tree, error) {
 	mTree := t.ms.getTree(treeID)
+	if mTree == nil {
+		return nil, errors.Errorf(errors.NotFound, "no such treeID %d", treeID)
+	}
 	mTree.mu.Lock()
 	defer mTree.mu.Unlock()
 
-	tree := mTree.meta
-	beforeUpdate := *tree
-	updateFunc(tree)
-	if err := storage.ValidateTreeForUpdate(&beforeUpdate, tree); err != nil {
+	// Update a copy, so the stored tree is left untouched if validation fails.
+	tree := *mTree.meta
+	updateFunc(&tree)
+	if err := storage.ValidateTreeForUpdate(mTree.meta, &tree); err != nil {
 		return nil, err
 	}
-	if err := validateStorageSettings(tree); err != nil {
+	if err := validateStorageSettings(&tree); err != nil {
 		return nil, err
 	}
+	if storage.IsFreezing(mTree.meta, &tree) {
+		if q := mTree.store.Get(unseqKey(treeID)).(*kv).v.(*list.List); q.Len() > 0 {
+			return nil, errors.Errorf(errors.FailedPrecondition, "cannot freeze tree %v: %v unsequenced leaves", treeID, q.Len())
+		}
+		if n := mTree.pendingLeaves(); n > 0 {
+			return nil, errors.Errorf(errors.FailedPrecondition, "cannot freeze tree %v: %v leaves not yet integrated", treeID, n)
+		}
+	}
 
 	var err error
 	tree.UpdateTime, err = ptypes.TimestampProto(time.Now())
 	if err != nil {
 		return nil, err
 	}
-	return tree, nil
+	*mTree.meta = tree
+	return &tree, nil
+}
+
+// pendingLeaves returns the number of leaves stored at or past the size of the
+// tree's latest signed root, such as those assigned by a pipelined sequencer
+// pass or added to a PREORDERED_LOG tree, but not yet integrated.
+// The caller must hold the tree's lock.
+func (t *tree) pendingLeaves() int {
+	var size int64
+	if r := t.store.Get(sthKey(t.meta.TreeId, t.currentSTH)); r != nil {
+		size = r.(*kv).v.(trillian.SignedLogRoot).TreeSize
+	}
+	n := 0
+	t.store.AscendRange(seqLeafKey(t.meta.TreeId, size), seqLeafKey(t.meta.TreeId, math.MaxInt64), func(btree.Item) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func (t *adminTX) SoftDeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	return t.updateTreeState(treeID, storage.ValidateTreeForSoftDelete, func(mTree *tree, now time.Time) error {
+		var err error
+		mTree.preDeleteState = mTree.meta.TreeState
+		mTree.meta.TreeState = trillian.TreeState_SOFT_DELETED
+		mTree.meta.DeleteTime, err = ptypes.TimestampProto(now)
+		return err
+	})
+}
+
+func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	return t.updateTreeState(treeID, storage.ValidateTreeIsSoftDeleted, func(mTree *tree, now time.Time) error {
+		mTree.meta.TreeState = mTree.preDeleteState
+		mTree.meta.DeleteTime = nil
+		return nil
+	})
+}
+
+func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
+	_, err := t.updateTreeState(treeID, storage.ValidateTreeIsSoftDeleted, func(mTree *tree, now time.Time) error {
+		// Drop all tree data, but keep the tree itself so its ID isn't reused.
+		mTree.store = newTreeStore(treeID)
+		mTree.currentSTH = 0
+		mTree.meta.TreeState = trillian.TreeState_HARD_DELETED
+		return nil
+	})
+	return err
+}
+
+// updateTreeState applies updateFunc to the specified tree, provided that the
+// tree passes validateFunc, and bumps its update time.
+func (t *adminTX) updateTreeState(treeID int64, validateFunc func(*trillian.Tree) error, updateFunc func(*tree, time.Time) error) (*trillian.Tree, error) {
+	mTree := t.ms.getTree(treeID)
+	if mTree == nil {
+		return nil, errors.Errorf(errors.NotFound, "no such treeID %d", treeID)
+	}
+	mTree.Lock()
+	defer mTree.Unlock()
+
+	if err := validateFunc(mTree.meta); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := updateFunc(mTree, now); err != nil {
+		return nil, err
+	}
+	var err error
+	mTree.meta.UpdateTime, err = ptypes.TimestampProto(now)
+	if err != nil {
+		return nil, err
+	}
+	// Return a copy, so callers can't modify the stored tree.
+	ret := *mTree.meta
+	return &ret, nil
 }
 
 func validateStorageSettings(tree *trillian.Tree) error {
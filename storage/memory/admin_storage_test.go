@@ -0,0 +1,209 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+)
+
+func TestMemoryAdminStorage(t *testing.T) {
+	var ls storage.LogStorage
+	tester := &testonly.AdminStorageTester{
+		NewAdminStorage: func() storage.AdminStorage {
+			ls = NewLogStorage(nil)
+			return NewAdminStorage(ls)
+		},
+		LogStorage: func() storage.LogStorage { return ls },
+	}
+	// TestAdminTXClose is omitted, as the memory AdminStorage isn't
+	// transactional (see adminTX.Rollback).
+	t.Run("TestCreateTree", tester.TestCreateTree)
+	t.Run("TestUpdateTree", tester.TestUpdateTree)
+	t.Run("TestFreezeLog", tester.TestFreezeLog)
+	t.Run("TestListTrees", tester.TestListTrees)
+	t.Run("TestListTreesOptions", tester.TestListTreesOptions)
+	t.Run("TestSoftDeleteTree", tester.TestSoftDeleteTree)
+	t.Run("TestUndeleteTree", tester.TestUndeleteTree)
+	t.Run("TestHardDeleteTree", tester.TestHardDeleteTree)
+	t.Run("TestDeleteTreeLifecycle", tester.TestDeleteTreeLifecycle)
+}
+
+func TestHardDeleteTree_PurgesData(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+	as := NewAdminStorage(ls)
+
+	tx, err := as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	tree, err := tx.CreateTree(ctx, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	// Add some data to the tree: a queued leaf and a signed root.
+	ltx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, time.Now()); err != nil {
+		t.Fatalf("QueueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	if err := ltx.StoreSignedLogRoot(ctx, trillian.SignedLogRoot{TimestampNanos: 1, RootHash: hash[:]}); err != nil {
+		t.Fatalf("StoreSignedLogRoot() = %v, want = nil", err)
+	}
+	if err := ltx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	for _, fn := range []func(storage.AdminTX) error{
+		func(tx storage.AdminTX) error {
+			_, err := tx.SoftDeleteTree(ctx, tree.TreeId)
+			return err
+		},
+		func(tx storage.AdminTX) error {
+			return tx.HardDeleteTree(ctx, tree.TreeId)
+		},
+	} {
+		tx, err := as.Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+		}
+		if err := fn(tx); err != nil {
+			t.Fatalf("delete err = %v, want = nil", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit() = %v, want = nil", err)
+		}
+	}
+
+	mTree := ls.(*memoryLogStorage).getTree(tree.TreeId)
+	if mTree == nil {
+		t.Fatalf("getTree(%v) = nil, want hard-deleted tree", tree.TreeId)
+	}
+	if got, want := mTree.store.Len(), newTreeStore(tree.TreeId).Len(); got != want {
+		t.Errorf("store.Len() = %v, want = %v", got, want)
+	}
+	if got := mTree.store.Get(unseqKey(tree.TreeId)).(*kv).v.(*list.List).Len(); got != 0 {
+		t.Errorf("unsequenced queue length = %v, want = 0", got)
+	}
+}
+
+func TestUpdateTree_FreezeRequiresIntegratedLeaves(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+	as := NewAdminStorage(ls)
+
+	tx, err := as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	tree, err := tx.CreateTree(ctx, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	ltx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, time.Now()); err != nil {
+		t.Fatalf("QueueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	if err := ltx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	freeze := func(tx storage.AdminTX) (*trillian.Tree, error) {
+		return tx.UpdateTree(ctx, tree.TreeId, func(tree *trillian.Tree) {
+			tree.TreeState = trillian.TreeState_FROZEN
+		})
+	}
+
+	tx, err = as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := freeze(tx); errors.ErrorCode(err) != errors.FailedPrecondition {
+		t.Errorf("UpdateTree() with unsequenced leaves returned err = %v, want code %s", err, errors.FailedPrecondition)
+	}
+	if got, err := tx.GetTree(ctx, tree.TreeId); err != nil || got.TreeState != trillian.TreeState_ACTIVE {
+		t.Errorf("GetTree() = (%v, %v), want = (ACTIVE tree, nil)", got, err)
+	}
+	tx.Close()
+
+	// Drain the queue. The leaf isn't covered by a signed root yet, as if it had
+	// been assigned by a pipelined sequencer pass, so the tree can't be frozen.
+	ltx, err = ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := ltx.UpdateSequencedLeaves(ctx, []*trillian.LogLeaf{leaf}); err != nil {
+		t.Fatalf("UpdateSequencedLeaves() = %v, want = nil", err)
+	}
+	if err := ltx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	tx, err = as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := freeze(tx); errors.ErrorCode(err) != errors.FailedPrecondition {
+		t.Errorf("UpdateTree() with pending leaves returned err = %v, want code %s", err, errors.FailedPrecondition)
+	}
+	tx.Close()
+
+	// Integrate the leaf, after which the tree may be frozen.
+	ltx, err = ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := ltx.StoreSignedLogRoot(ctx, trillian.SignedLogRoot{TimestampNanos: 1, TreeSize: 1, RootHash: hash[:]}); err != nil {
+		t.Fatalf("StoreSignedLogRoot() = %v, want = nil", err)
+	}
+	if err := ltx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	tx, err = as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	if _, err := freeze(tx); err != nil {
+		t.Errorf("UpdateTree() with empty queue = (_, %v), want = (_, nil)", err)
+	}
+}
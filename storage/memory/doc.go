@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package memory provides a simple in-process implementation of the tree- and
-// log-storage interfaces.
+// Package memory provides a simple in-process implementation of the tree-,
+// log- and map-storage interfaces.
 //
 // This implementation is intended SOLELY for use in integration tests which
 // exercise properties of the higher levels of Trillian componened - e.g.
@@ -25,9 +25,10 @@
 // scan ranges of keys in order.
 //
 // The implementation does provide transaction-like semantics for the
-// LogStorage interface, although conflict is avoided by each writable
-// transaction exclusively locking the tree until it's committed or
-// rolled-back.
+// LogStorage and MapStorage interfaces. For logs, conflict is avoided by each
+// writable transaction exclusively locking the tree until it's committed or
+// rolled-back. Map transactions work on a snapshot of the tree instead, and
+// fail to commit if they'd overwrite data written by a concurrent transaction.
 //
 // Currently, the Admin Storage does not honor transactional semantics.
 package memory
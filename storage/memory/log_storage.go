@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -32,6 +33,8 @@ import (
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/cache"
 	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const logIDLabel = "logid"
@@ -54,11 +57,17 @@ func labelForTX(t *logTreeTX) string {
 }
 
 // unseqKey formats a key for use in a tree's BTree store.
-// The associated Item value will be a list of unsequenced entries.
+// The associated Item value will be a list of unsequenced entries, each a *queuedLeaf.
 func unseqKey(treeID int64) btree.Item {
 	return &kv{k: fmt.Sprintf("/%d/unseq", treeID)}
 }
 
+// queuedLeaf is an unsequenced entry, along with the time it was queued.
+type queuedLeaf struct {
+	leaf           *trillian.LogLeaf
+	queueTimestamp time.Time
+}
+
 // seqLeafKey formats a key for use in a tree's BTree store.
 // The associated Item value will be the leaf at the given sequence number.
 func seqLeafKey(treeID, seq int64) btree.Item {
@@ -72,6 +81,13 @@ func hashToSeqKey(treeID int64) btree.Item {
 	return &kv{k: fmt.Sprintf("/%d/h2s", treeID)}
 }
 
+// idToSeqKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the sequence numbers of the leaves with
+// the given LeafIdentityHash.
+func idToSeqKey(treeID int64) btree.Item {
+	return &kv{k: fmt.Sprintf("/%d/id2s", treeID)}
+}
+
 // sthKey formats a key for use in a tree's BTree store.
 // The associated Item value will be the STH with the given timestamp.
 func sthKey(treeID, timestamp int64) btree.Item {
@@ -126,8 +142,14 @@ func (t *readOnlyLogTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
 	defer t.ms.mu.RUnlock()
 
 	ret := make([]int64, 0, len(t.ms.trees))
-	for k := range t.ms.trees {
-		ret = append(ret, k)
+	for k, tree := range t.ms.trees {
+		tree.RLock()
+		isLog := tree.meta.TreeType == trillian.TreeType_LOG || tree.meta.TreeType == trillian.TreeType_PREORDERED_LOG
+		active := isLog && tree.meta.TreeState == trillian.TreeState_ACTIVE
+		tree.RUnlock()
+		if active {
+			ret = append(ret, k)
+		}
 	}
 	return ret, nil
 }
@@ -140,7 +162,7 @@ func (m *memoryLogStorage) beginInternal(ctx context.Context, treeID int64, read
 		ctx,
 		m.admin,
 		treeID,
-		trees.GetOpts{TreeType: trillian.TreeType_LOG, Readonly: readonly})
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}, Readonly: readonly})
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +178,9 @@ func (m *memoryLogStorage) beginInternal(ctx context.Context, treeID int64, read
 	}
 
 	ltx := &logTreeTX{
-		treeTX: ttx,
-		ls:     m,
+		treeTX:   ttx,
+		ls:       m,
+		treeType: tree.TreeType,
 	}
 
 	ltx.root, err = ltx.fetchLatestRoot(ctx)
@@ -184,8 +207,9 @@ func (m *memoryLogStorage) SnapshotForTree(ctx context.Context, treeID int64) (s
 
 type logTreeTX struct {
 	treeTX
-	ls   *memoryLogStorage
-	root trillian.SignedLogRoot
+	ls       *memoryLogStorage
+	root     trillian.SignedLogRoot
+	treeType trillian.TreeType
 }
 
 func (t *logTreeTX) ReadRevision() int64 {
@@ -197,13 +221,19 @@ func (t *logTreeTX) WriteRevision() int64 {
 }
 
 func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime time.Time) ([]*trillian.LogLeaf, error) {
+	if t.treeType == trillian.TreeType_PREORDERED_LOG {
+		return t.dequeueSequencedLeaves(ctx, limit)
+	}
 	leaves := make([]*trillian.LogLeaf, 0, limit)
 
 	q := t.tx.Get(unseqKey(t.treeID)).(*kv).v.(*list.List)
 	e := q.Front()
 	for i := 0; i < limit && e != nil; i++ {
 		// TODO(al): consider cutoffTime
-		leaves = append(leaves, e.Value.(*trillian.LogLeaf))
+		ql := e.Value.(*queuedLeaf)
+		leaf := *ql.leaf
+		leaf.QueueTimestampNanos = ql.queueTimestamp.UnixNano()
+		leaves = append(leaves, &leaf)
 		e = e.Next()
 	}
 
@@ -211,6 +241,23 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 	return leaves, nil
 }
 
+// dequeueSequencedLeaves returns the leaves of a PREORDERED_LOG tree following the current tree
+// size, up to the first missing index.
+func (t *logTreeTX) dequeueSequencedLeaves(ctx context.Context, limit int) ([]*trillian.LogLeaf, error) {
+	leaves, err := t.GetLeavesByRange(ctx, t.root.TreeSize, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	for i, leaf := range leaves {
+		if leaf.LeafIndex != t.root.TreeSize+int64(i) {
+			leaves = leaves[:i]
+			break
+		}
+	}
+	dequeuedCounter.Add(float64(len(leaves)), labelForTX(t))
+	return leaves, nil
+}
+
 func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error) {
 	// Don't accept batches if any of the leaves are invalid.
 	for _, leaf := range leaves {
@@ -218,16 +265,75 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 			return nil, fmt.Errorf("queued leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
 		}
 	}
+	if err := storage.ValidateTreeForLeafWrites(t.treeID, t.tree.meta.TreeState); err != nil {
+		return nil, err
+	}
 	queuedCounter.Add(float64(len(leaves)), labelForTX(t))
 	// No deduping in this storage!
 	k := unseqKey(t.treeID)
 	q := t.tx.Get(k).(*kv).v.(*list.List)
 	for _, l := range leaves {
-		q.PushBack(l)
+		q.PushBack(&queuedLeaf{leaf: l, queueTimestamp: queueTimestamp})
 	}
 	return []*trillian.LogLeaf{}, nil
 }
 
+func (t *logTreeTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) ([]*trillian.QueuedLogLeaf, error) {
+	// Don't accept batches if any of the leaves are invalid.
+	for _, leaf := range leaves {
+		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
+			return nil, fmt.Errorf("sequenced leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
+		}
+	}
+	if err := storage.ValidateTreeForLeafWrites(t.treeID, t.tree.meta.TreeState); err != nil {
+		return nil, err
+	}
+	queuedCounter.Add(float64(len(leaves)), labelForTX(t))
+
+	results := make([]*trillian.QueuedLogLeaf, len(leaves))
+	for i, leaf := range leaves {
+		if existing := t.tx.Get(seqLeafKey(t.treeID, leaf.LeafIndex)); existing != nil {
+			if stored := existing.(*kv).v.(*trillian.LogLeaf); bytes.Equal(stored.LeafIdentityHash, leaf.LeafIdentityHash) {
+				results[i] = &trillian.QueuedLogLeaf{
+					Leaf:   stored,
+					Status: status.Newf(codes.AlreadyExists, "Leaf already exists at index %v", leaf.LeafIndex).Proto(),
+				}
+			} else {
+				results[i] = &trillian.QueuedLogLeaf{
+					Leaf:   leaf,
+					Status: status.Newf(codes.FailedPrecondition, "conflicting LeafIndex: %v", leaf.LeafIndex).Proto(),
+				}
+			}
+			continue
+		}
+		others, err := t.GetLeavesByIdentityHash(ctx, [][]byte{leaf.LeafIdentityHash}, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(others) > 0 {
+			results[i] = &trillian.QueuedLogLeaf{
+				Leaf:   leaf,
+				Status: status.Newf(codes.FailedPrecondition, "conflicting LeafIdentityHash: %x", leaf.LeafIdentityHash).Proto(),
+			}
+			continue
+		}
+		t.storeSequencedLeaf(leaf)
+		results[i] = &trillian.QueuedLogLeaf{Leaf: leaf}
+	}
+	return results, nil
+}
+
+func (t *logTreeTX) GetOldestQueueTimestamp(ctx context.Context) (time.Time, error) {
+	var oldest time.Time
+	q := t.tx.Get(unseqKey(t.treeID)).(*kv).v.(*list.List)
+	for e := q.Front(); e != nil; e = e.Next() {
+		if ts := e.Value.(*queuedLeaf).queueTimestamp; oldest.IsZero() || ts.Before(oldest) {
+			oldest = ts
+		}
+	}
+	return oldest, nil
+}
+
 func (t *logTreeTX) GetSequencedLeafCount(ctx context.Context) (int64, error) {
 	var sequencedLeafCount int64
 
@@ -249,11 +355,31 @@ func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*tr
 	return ret, nil
 }
 
-func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool) ([]*trillian.LogLeaf, error) {
-	m := t.tx.Get(hashToSeqKey(t.treeID)).(*kv).v.(map[string][]int64)
+func (t *logTreeTX) GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	ret := make([]*trillian.LogLeaf, 0)
+	// Sequenced leaves are keyed by zero-padded index, so key order is index order.
+	t.tx.AscendRange(seqLeafKey(t.treeID, start), seqLeafKey(t.treeID, start+count), func(i btree.Item) bool {
+		ret = append(ret, i.(*kv).v.(*trillian.LogLeaf))
+		return true
+	})
+	return ret, nil
+}
+
+func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool, limit, offset int) ([]*trillian.LogLeaf, error) {
+	return t.getLeavesByIndexedHash(hashToSeqKey(t.treeID), leafHashes, orderBySequence, limit, offset), nil
+}
+
+func (t *logTreeTX) GetLeavesByIdentityHash(ctx context.Context, identityHashes [][]byte, limit, offset int) ([]*trillian.LogLeaf, error) {
+	return t.getLeavesByIndexedHash(idToSeqKey(t.treeID), identityHashes, true, limit, offset), nil
+}
 
-	ret := make([]*trillian.LogLeaf, 0, len(leafHashes))
-	for hash := range leafHashes {
+// getLeavesByIndexedHash returns the sequenced leaves that the hash index stored at indexKey maps
+// any of hashes to, paged as described by GetLeavesByHash.
+func (t *logTreeTX) getLeavesByIndexedHash(indexKey btree.Item, hashes [][]byte, orderBySequence bool, limit, offset int) []*trillian.LogLeaf {
+	m := t.tx.Get(indexKey).(*kv).v.(map[string][]int64)
+
+	ret := make([]*trillian.LogLeaf, 0, len(hashes))
+	for _, hash := range hashes {
 		seq, ok := m[string(hash)]
 		if !ok {
 			continue
@@ -266,7 +392,37 @@ func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, or
 			ret = append(ret, l.(*kv).v.(*trillian.LogLeaf))
 		}
 	}
-	return ret, nil
+	if orderBySequence || limit > 0 {
+		sort.Slice(ret, func(i, j int) bool { return ret[i].LeafIndex < ret[j].LeafIndex })
+	}
+	if limit > 0 {
+		if offset > len(ret) {
+			offset = len(ret)
+		}
+		ret = ret[offset:]
+		if limit < len(ret) {
+			ret = ret[:limit]
+		}
+	}
+	return ret
+}
+
+func (t *logTreeTX) GetLeafStatus(ctx context.Context, identityHash []byte) (storage.LeafStatus, error) {
+	leaves, err := t.GetLeavesByIdentityHash(ctx, [][]byte{identityHash}, 1, 0)
+	if err != nil {
+		return storage.LeafStatus{}, err
+	}
+	if len(leaves) > 0 {
+		return storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: leaves[0].LeafIndex}, nil
+	}
+
+	q := t.tx.Get(unseqKey(t.treeID)).(*kv).v.(*list.List)
+	for e := q.Front(); e != nil; e = e.Next() {
+		if ql := e.Value.(*queuedLeaf); bytes.Equal(ql.leaf.LeafIdentityHash, identityHash) {
+			return storage.LeafStatus{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: ql.queueTimestamp.UnixNano()}, nil
+		}
+	}
+	return storage.LeafStatus{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS}, nil
 }
 
 func (t *logTreeTX) LatestSignedLogRoot(ctx context.Context) (trillian.SignedLogRoot, error) {
@@ -303,21 +459,13 @@ func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillia
 		}
 		mh := string(leaf.MerkleLeafHash)
 		countByMerkleHash[mh]++
-		// insert sequenced leaf:
-		k := seqLeafKey(t.treeID, leaf.LeafIndex)
-		k.(*kv).v = leaf
-		t.tx.ReplaceOrInsert(k)
-		// update merkle-to-seq mapping:
-		m := t.tx.Get(hashToSeqKey(t.treeID))
-		l := m.(*kv).v.(map[string][]int64)[string(leaf.MerkleLeafHash)]
-		l = append(l, leaf.LeafIndex)
-		m.(*kv).v.(map[string][]int64)[string(leaf.MerkleLeafHash)] = l
+		t.storeSequencedLeaf(leaf)
 	}
 
 	q := t.tx.Get(unseqKey(t.treeID)).(*kv).v.(*list.List)
 	toRemove := make([]*list.Element, 0, q.Len())
 	for e := q.Front(); e != nil && len(countByMerkleHash) > 0; e = e.Next() {
-		h := e.Value.(*trillian.LogLeaf).MerkleLeafHash
+		h := e.Value.(*queuedLeaf).leaf.MerkleLeafHash
 		mh := string(h)
 		if countByMerkleHash[mh] > 0 {
 			countByMerkleHash[mh]--
@@ -338,6 +486,24 @@ func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillia
 	return nil
 }
 
+// storeSequencedLeaf stores leaf at its LeafIndex, and maps its Merkle leaf hash and
+// LeafIdentityHash to the index.
+func (t *logTreeTX) storeSequencedLeaf(leaf *trillian.LogLeaf) {
+	k := seqLeafKey(t.treeID, leaf.LeafIndex)
+	k.(*kv).v = leaf
+	t.tx.ReplaceOrInsert(k)
+	for _, idx := range []struct {
+		key  btree.Item
+		hash []byte
+	}{
+		{hashToSeqKey(t.treeID), leaf.MerkleLeafHash},
+		{idToSeqKey(t.treeID), leaf.LeafIdentityHash},
+	} {
+		m := t.tx.Get(idx.key).(*kv).v.(map[string][]int64)
+		m[string(idx.hash)] = append(m[string(idx.hash)], leaf.LeafIndex)
+	}
+}
+
 func (t *logTreeTX) getActiveLogIDs(ctx context.Context) ([]int64, error) {
 	var ret []int64
 	for k := range t.ts.trees {
@@ -367,6 +533,39 @@ func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.Count
 	return ret, nil
 }
 
+func (t *readOnlyLogTX) GetLatestSignedLogRoots(ctx context.Context) (map[int64]trillian.SignedLogRoot, error) {
+	t.ms.mu.RLock()
+	defer t.ms.mu.RUnlock()
+
+	ret := make(map[int64]trillian.SignedLogRoot)
+	for id, tree := range t.ms.trees {
+		tree.RLock()
+		defer tree.RUnlock() // OK to hold until method returns.
+
+		if r := tree.store.Get(sthKey(id, tree.currentSTH)); r != nil {
+			ret[id] = r.(*kv).v.(trillian.SignedLogRoot)
+		}
+	}
+	return ret, nil
+}
+
+func (t *readOnlyLogTX) GetSequencedLeafEnds(ctx context.Context) (storage.CountByLogID, error) {
+	t.ms.mu.RLock()
+	defer t.ms.mu.RUnlock()
+
+	ret := make(map[int64]int64)
+	for id, tree := range t.ms.trees {
+		tree.RLock()
+		defer tree.RUnlock() // OK to hold until method returns.
+
+		tree.store.DescendRange(seqLeafKey(id, math.MaxInt64), seqLeafKey(id, 0), func(i btree.Item) bool {
+			ret[id] = i.(*kv).v.(*trillian.LogLeaf).LeafIndex + 1
+			return false
+		})
+	}
+	return ret, nil
+}
+
 // byLeafIdentityHash allows sorting of leaves by their identity hash, so DB
 // operations always happen in a consistent order.
 type byLeafIdentityHash []*trillian.LogLeaf
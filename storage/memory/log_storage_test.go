@@ -0,0 +1,294 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLeafStatus(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+
+	tx, err := NewAdminStorage(ls).Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	tree, err := tx.CreateTree(ctx, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	queueTime := time.Unix(1500000000, 123)
+
+	// getStatus reads the status of leaf in a transaction of its own, after running fn in the
+	// same transaction.
+	getStatus := func(fn func(storage.LogTreeTX) error) storage.LeafStatus {
+		ltx, err := ls.BeginForTree(ctx, tree.TreeId)
+		if err != nil {
+			t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+		}
+		defer ltx.Close()
+		if err := fn(ltx); err != nil {
+			t.Fatalf("fn() = %v, want = nil", err)
+		}
+		got, err := ltx.GetLeafStatus(ctx, leaf.LeafIdentityHash)
+		if err != nil {
+			t.Fatalf("GetLeafStatus() = (_, %v), want = (_, nil)", err)
+		}
+		if err := ltx.Commit(); err != nil {
+			t.Fatalf("Commit() = %v, want = nil", err)
+		}
+		return got
+	}
+
+	for _, test := range []struct {
+		desc string
+		fn   func(storage.LogTreeTX) error
+		want storage.LeafStatus
+	}{
+		{
+			desc: "unknown",
+			fn:   func(storage.LogTreeTX) error { return nil },
+			want: storage.LeafStatus{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS},
+		},
+		{
+			desc: "queued",
+			fn: func(ltx storage.LogTreeTX) error {
+				_, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, queueTime)
+				return err
+			},
+			want: storage.LeafStatus{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: queueTime.UnixNano()},
+		},
+		{
+			desc: "sequenced",
+			fn: func(ltx storage.LogTreeTX) error {
+				sequenced := *leaf
+				sequenced.LeafIndex = 0
+				return ltx.UpdateSequencedLeaves(ctx, []*trillian.LogLeaf{&sequenced})
+			},
+			want: storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: 0},
+		},
+	} {
+		if got := getStatus(test.fn); got != test.want {
+			t.Errorf("%v: GetLeafStatus() = %+v, want = %+v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestDequeueLeavesQueueTimestamp(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+
+	tx, err := NewAdminStorage(ls).Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	tree, err := tx.CreateTree(ctx, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	ltx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	defer ltx.Close()
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	queueTime := time.Unix(1500000000, 123)
+	if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, queueTime); err != nil {
+		t.Fatalf("QueueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	leaves, err := ltx.DequeueLeaves(ctx, 1, queueTime)
+	if err != nil {
+		t.Fatalf("DequeueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	if len(leaves) != 1 {
+		t.Fatalf("DequeueLeaves() returned %v leaves, want = 1", len(leaves))
+	}
+	if got, want := leaves[0].QueueTimestampNanos, queueTime.UnixNano(); got != want {
+		t.Errorf("DequeueLeaves()[0].QueueTimestampNanos = %v, want = %v", got, want)
+	}
+}
+
+func TestGetOldestQueueTimestamp(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+
+	tx, err := NewAdminStorage(ls).Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	tree, err := tx.CreateTree(ctx, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	ltx, err := ls.BeginForTree(ctx, tree.TreeId)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	defer ltx.Close()
+	if got, err := ltx.GetOldestQueueTimestamp(ctx); err != nil || !got.IsZero() {
+		t.Errorf("GetOldestQueueTimestamp() = (%v, %v), want = (zero time, nil)", got, err)
+	}
+
+	oldest := time.Unix(1500000000, 0)
+	for i, queueTime := range []time.Time{oldest.Add(time.Minute), oldest, oldest.Add(time.Hour)} {
+		hash := sha256.Sum256([]byte{byte(i)})
+		leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte{byte(i)}}
+		if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, queueTime); err != nil {
+			t.Fatalf("QueueLeaves() = (_, %v), want = (_, nil)", err)
+		}
+	}
+	got, err := ltx.GetOldestQueueTimestamp(ctx)
+	if err != nil {
+		t.Fatalf("GetOldestQueueTimestamp() = (_, %v), want = (_, nil)", err)
+	}
+	if !got.Equal(oldest) {
+		t.Errorf("GetOldestQueueTimestamp() = (%v, _), want = (%v, _)", got, oldest)
+	}
+}
+
+func TestQueueLeavesFrozenTree(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+	as := NewAdminStorage(ls)
+	treeID := createTreeForTests(ctx, t, as, testonly.LogTree)
+
+	// Start a TX while the tree is still ACTIVE, then freeze the tree. The TX
+	// holds the tree's lock, so the freeze must wait for it to end.
+	ltx, err := ls.BeginForTree(ctx, treeID)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	frozen := make(chan error)
+	go func() {
+		tx, err := as.Begin(ctx)
+		if err != nil {
+			frozen <- err
+			return
+		}
+		defer tx.Close()
+		if _, err := tx.UpdateTree(ctx, treeID, func(tree *trillian.Tree) {
+			tree.TreeState = trillian.TreeState_FROZEN
+		}); err != nil {
+			frozen <- err
+			return
+		}
+		frozen <- tx.Commit()
+	}()
+
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, time.Now()); err != nil {
+		t.Fatalf("QueueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	if err := ltx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+	// The leaf was queued before the freeze, so the freeze must fail.
+	if err := <-frozen; err == nil {
+		t.Fatal("UpdateTree(FROZEN) with queued leaves returned err = nil, want non-nil")
+	}
+
+	// A freeze may also land between BeginForTree checking the tree's state
+	// and the TX locking the tree. Simulate it by freezing the tree under the
+	// TX's lock, after which no leaves may be queued.
+	ltx, err = ls.BeginForTree(ctx, treeID)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	defer ltx.Close()
+	ltx.(*logTreeTX).tree.meta.TreeState = trillian.TreeState_FROZEN
+	if _, err := ltx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, time.Now()); errors.ErrorCode(err) != errors.FailedPrecondition {
+		t.Errorf("QueueLeaves() on FROZEN tree = (_, %v), want code = %v", err, errors.FailedPrecondition)
+	}
+}
+
+func TestAddSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	ls := NewLogStorage(nil)
+	treeID := createTreeForTests(ctx, t, NewAdminStorage(ls), testonly.PreorderedLogTree)
+
+	newLeaf := func(index int64, value string) *trillian.LogLeaf {
+		hash := sha256.Sum256([]byte(value))
+		return &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte(value), LeafIndex: index}
+	}
+
+	ltx, err := ls.BeginForTree(ctx, treeID)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	defer ltx.Close()
+
+	// Index 2 is left empty, so only indices 0 and 1 may be dequeued.
+	if _, err := ltx.AddSequencedLeaves(ctx, []*trillian.LogLeaf{newLeaf(0, "a"), newLeaf(1, "b"), newLeaf(3, "d")}); err != nil {
+		t.Fatalf("AddSequencedLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	results, err := ltx.AddSequencedLeaves(ctx, []*trillian.LogLeaf{
+		newLeaf(0, "a"), // Same leaf at the same index.
+		newLeaf(1, "x"), // Index taken by another leaf.
+		newLeaf(4, "a"), // Leaf already sequenced at another index.
+		newLeaf(5, "e"),
+	})
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	wantCodes := []codes.Code{codes.AlreadyExists, codes.FailedPrecondition, codes.FailedPrecondition, codes.OK}
+	if got, want := len(results), len(wantCodes); got != want {
+		t.Fatalf("AddSequencedLeaves() returned %v results, want = %v", got, want)
+	}
+	for i, result := range results {
+		if got := status.FromProto(result.Status).Code(); got != wantCodes[i] {
+			t.Errorf("AddSequencedLeaves()[%v].Status = %v, want = %v", i, got, wantCodes[i])
+		}
+	}
+
+	leaves, err := ltx.DequeueLeaves(ctx, 10, time.Now())
+	if err != nil {
+		t.Fatalf("DequeueLeaves() = (_, %v), want = (_, nil)", err)
+	}
+	var got []int64
+	for _, leaf := range leaves {
+		got = append(got, leaf.LeafIndex)
+	}
+	if want := []int64{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DequeueLeaves() returned indices %v, want = %v", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/btree"
+	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/trees"
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+// mapLeafPrefix formats the common prefix of the keys of all revisions of the
+// leaf at keyHash.
+func mapLeafPrefix(treeID int64, keyHash []byte) string {
+	return fmt.Sprintf("/%d/mapleaf/%x/", treeID, keyHash)
+}
+
+// mapLeafKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the MapLeaf set at keyHash in the given
+// revision.
+func mapLeafKey(treeID int64, keyHash []byte, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("%s%020d", mapLeafPrefix(treeID, keyHash), rev)}
+}
+
+// mapRootKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the SignedMapRoot with the given revision.
+func mapRootKey(treeID, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("/%d/maproot/%020d", treeID, rev)}
+}
+
+type memoryMapStorage struct {
+	*memoryTreeStorage
+	admin storage.AdminStorage
+}
+
+// NewMapStorage creates an in-memory MapStorage instance.
+func NewMapStorage() storage.MapStorage {
+	ret := &memoryMapStorage{
+		memoryTreeStorage: newTreeStorage(),
+	}
+	ret.admin = NewMapAdminStorage(ret)
+	return ret
+}
+
+func (m *memoryMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return nil
+}
+
+type readOnlyMapTX struct {
+	ms *memoryTreeStorage
+}
+
+func (m *memoryMapStorage) Snapshot(ctx context.Context) (storage.ReadOnlyMapTX, error) {
+	return &readOnlyMapTX{m.memoryTreeStorage}, nil
+}
+
+func (t *readOnlyMapTX) Commit() error {
+	return nil
+}
+
+func (t *readOnlyMapTX) Rollback() error {
+	return nil
+}
+
+func (t *readOnlyMapTX) Close() error {
+	return nil
+}
+
+func (m *memoryMapStorage) begin(ctx context.Context, treeID int64, readonly bool) (storage.MapTreeTX, error) {
+	tree, err := trees.GetTree(
+		ctx,
+		m.admin,
+		treeID,
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_MAP}, Readonly: readonly})
+	if err != nil {
+		return nil, err
+	}
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The map server writes each revision through several concurrent TXs (see
+	// merkle.SparseMerkleTreeWriter), so map TXs can't lock the tree.
+	stCache := cache.NewMapSubtreeCache(defaultMapStrata, treeID, hasher)
+	ttx, err := m.memoryTreeStorage.beginMergingTreeTX(ctx, treeID, hasher.Size(), stCache)
+	if err != nil {
+		return nil, err
+	}
+
+	mtx := &mapTreeTX{
+		treeTX: ttx,
+		ms:     m,
+		hasher: hasher,
+	}
+
+	mtx.root, err = mtx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		ttx.Rollback()
+		return nil, err
+	}
+	mtx.treeTX.writeRevision = mtx.root.MapRevision + 1
+
+	return mtx, nil
+}
+
+func (m *memoryMapStorage) BeginForTree(ctx context.Context, treeID int64) (storage.MapTreeTX, error) {
+	return m.begin(ctx, treeID, false /* readonly */)
+}
+
+func (m *memoryMapStorage) SnapshotForTree(ctx context.Context, treeID int64) (storage.ReadOnlyMapTreeTX, error) {
+	return m.begin(ctx, treeID, true /* readonly */)
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms     *memoryMapStorage
+	hasher hashers.MapHasher
+	root   trillian.SignedMapRoot
+
+	// revCaches holds the subtree caches of revisions older than the read
+	// revision, which can't share the TX's subtree cache.
+	revCachesMu sync.Mutex
+	revCaches   map[int64]*cache.SubtreeCache
+}
+
+// GetMerkleNodes returns the nodes at (or below) treeRevision. Subtree caches
+// don't distinguish between revisions, so nodes of revisions older than the
+// read revision are read through a cache of their own.
+func (t *mapTreeTX) GetMerkleNodes(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]storage.Node, error) {
+	if treeRevision >= t.root.MapRevision {
+		return t.treeTX.GetMerkleNodes(ctx, treeRevision, nodeIDs)
+	}
+	return t.revCache(treeRevision).GetNodes(nodeIDs, t.getSubtreesAtRev(ctx, treeRevision))
+}
+
+func (t *mapTreeTX) revCache(rev int64) *cache.SubtreeCache {
+	t.revCachesMu.Lock()
+	defer t.revCachesMu.Unlock()
+	c, ok := t.revCaches[rev]
+	if !ok {
+		stCache := cache.NewMapSubtreeCache(defaultMapStrata, t.treeID, t.hasher)
+		c = &stCache
+		if t.revCaches == nil {
+			t.revCaches = make(map[int64]*cache.SubtreeCache)
+		}
+		t.revCaches[rev] = c
+	}
+	return c
+}
+
+func (t *mapTreeTX) ReadRevision() int64 {
+	return t.root.MapRevision
+}
+
+func (t *mapTreeTX) WriteRevision() int64 {
+	return t.treeTX.writeRevision
+}
+
+func (t *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapLeaf) error {
+	k := mapLeafKey(t.treeID, keyHash, t.writeRevision)
+	if t.tx.Has(k) {
+		return fmt.Errorf("key %x already set in revision %d", keyHash, t.writeRevision)
+	}
+	// Tombstones are stored as leaves without a LeafHash, which Get skips.
+	k.(*kv).v = value
+	t.put(k)
+	return nil
+}
+
+func (t *mapTreeTX) SetBatch(ctx context.Context, leaves []trillian.MapLeaf) error {
+	for _, l := range leaves {
+		if err := t.Set(ctx, l.Index, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (t *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]trillian.MapLeaf, error) {
+	if revision < 0 {
+		revision = math.MaxInt64
+	}
+
+	ret := make([]trillian.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		prefix := mapLeafPrefix(t.treeID, index)
+		var leaf *trillian.MapLeaf
+		// The first key at or below revision is the latest value of the leaf, if
+		// it has the right prefix.
+		t.tx.DescendLessOrEqual(mapLeafKey(t.treeID, index, revision), func(i btree.Item) bool {
+			if strings.HasPrefix(i.(*kv).k, prefix) {
+				l := i.(*kv).v.(trillian.MapLeaf)
+				leaf = &l
+			}
+			return false
+		})
+		if leaf == nil || len(leaf.LeafHash) == 0 {
+			continue
+		}
+		leaf.Index = index
+		ret = append(ret, *leaf)
+	}
+	return ret, nil
+}
+
+func (t *mapTreeTX) GetHistory(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]storage.MapLeafRevision, error) {
+	prefix := mapLeafPrefix(t.treeID, keyHash)
+	ret := make([]storage.MapLeafRevision, 0)
+	var err error
+	t.tx.AscendGreaterOrEqual(mapLeafKey(t.treeID, keyHash, fromRevision), func(i btree.Item) bool {
+		if len(ret) >= limit || !strings.HasPrefix(i.(*kv).k, prefix) {
+			return false
+		}
+		var rev int64
+		if rev, err = strconv.ParseInt(strings.TrimPrefix(i.(*kv).k, prefix), 10, 64); err != nil || rev > toRevision {
+			return false
+		}
+		leaf := i.(*kv).v.(trillian.MapLeaf)
+		leaf.Index = keyHash
+		ret = append(ret, storage.MapLeafRevision{Revision: rev, Leaf: leaf})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (t *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
+	r := t.tx.Get(mapRootKey(t.treeID, revision))
+	if r == nil {
+		return trillian.SignedMapRoot{}, errors.Errorf(errors.NotFound, "no SignedMapRoot for revision %d", revision)
+	}
+	return r.(*kv).v.(trillian.SignedMapRoot), nil
+}
+
+func (t *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (trillian.SignedMapRoot, error) {
+	var root trillian.SignedMapRoot
+	t.tx.DescendLessOrEqual(mapRootKey(t.treeID, math.MaxInt64), func(i btree.Item) bool {
+		// Any item below the first root belongs to another 'table'.
+		if r, ok := i.(*kv).v.(trillian.SignedMapRoot); ok {
+			root = r
+		}
+		return false
+	})
+	return root, nil
+}
+
+func (t *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root trillian.SignedMapRoot) error {
+	k := mapRootKey(t.treeID, root.MapRevision)
+	if t.tx.Has(k) {
+		return fmt.Errorf("SignedMapRoot already stored for revision %d", root.MapRevision)
+	}
+	k.(*kv).v = root
+	t.put(k)
+	return nil
+}
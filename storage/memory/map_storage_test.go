@@ -0,0 +1,202 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+)
+
+var (
+	keyHash = []byte("A Key Hash")
+	mapLeaf = trillian.MapLeaf{
+		Index:     keyHash,
+		LeafHash:  []byte("A Hash"),
+		LeafValue: []byte("A Value"),
+		ExtraData: []byte("Some Extra Data"),
+	}
+	dummyRootHash = []byte("root hash")
+)
+
+// newMapForTests returns a new MapStorage containing a single map, along with
+// the map's ID.
+func newMapForTests(ctx context.Context, t *testing.T) (storage.MapStorage, int64) {
+	s := NewMapStorage()
+	return s, createTreeForTests(ctx, t, NewMapAdminStorage(s), testonly.MapTree)
+}
+
+func createTreeForTests(ctx context.Context, t *testing.T, as storage.AdminStorage, tree *trillian.Tree) int64 {
+	tx, err := as.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	tree, err = tx.CreateTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+	return tree.TreeId
+}
+
+func TestMemoryMapStorage(t *testing.T) {
+	tester := &testonly.MapStorageTester{NewMapStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		s := NewMapStorage()
+		return s, NewMapAdminStorage(s)
+	}}
+	tester.RunAllTests(t)
+}
+
+func TestMapSetMerkleNodes(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := newMapForTests(ctx, t)
+
+	nodeID := storage.NewNodeIDFromHash(make([]byte, 32))
+	nodeID.PrefixLenBits = 8
+	node := storage.Node{NodeID: nodeID, Hash: dummyRootHash, NodeRevision: 1}
+
+	tx := beginMapTx(ctx, s, mapID, t)
+	if err := tx.SetMerkleNodes(ctx, []storage.Node{node}); err != nil {
+		t.Fatalf("SetMerkleNodes(): %v", err)
+	}
+	commit(tx, t)
+
+	for _, tc := range []struct {
+		rev       int64
+		wantNodes int
+	}{
+		{rev: 0, wantNodes: 0},
+		{rev: 1, wantNodes: 1},
+		{rev: 2, wantNodes: 1},
+	} {
+		tx := beginMapTx(ctx, s, mapID, t)
+		nodes, err := tx.GetMerkleNodes(ctx, tc.rev, []storage.NodeID{nodeID})
+		commit(tx, t)
+		if err != nil {
+			t.Fatalf("GetMerkleNodes(%v): %v", tc.rev, err)
+		}
+		if got := len(nodes); got != tc.wantNodes {
+			t.Errorf("len(GetMerkleNodes(%v)): %v, want %v", tc.rev, got, tc.wantNodes)
+		}
+	}
+}
+
+func TestGetSignedMapRootNotExist(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := newMapForTests(ctx, t)
+
+	tx := beginMapTx(ctx, s, mapID, t)
+	defer commit(tx, t)
+	root, err := tx.GetSignedMapRoot(ctx, 10)
+	if got, want := errors.ErrorCode(err), errors.NotFound; got != want {
+		t.Fatalf("GetSignedMapRoot: %v, want code %v", err, want)
+	}
+	if root.MapId != 0 || len(root.RootHash) != 0 || root.Signature != nil {
+		t.Fatalf("Read a root with contents when it should be empty: %v", root)
+	}
+}
+
+func TestMapRollback(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := newMapForTests(ctx, t)
+
+	tx := beginMapTx(ctx, s, mapID, t)
+	if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
+		t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
+	}
+	if err := tx.StoreSignedMapRoot(ctx, trillian.SignedMapRoot{MapId: mapID, MapRevision: 1}); err != nil {
+		t.Fatalf("Failed to store signed map root: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback(): %v", err)
+	}
+
+	tx = beginMapTx(ctx, s, mapID, t)
+	defer commit(tx, t)
+	if got, want := tx.ReadRevision(), int64(0); got != want {
+		t.Errorf("ReadRevision(): %v, want %v", got, want)
+	}
+	readValues, err := tx.Get(ctx, -1, [][]byte{keyHash})
+	if err != nil {
+		t.Fatalf("Failed to get %v: %v", keyHash, err)
+	}
+	if got, want := len(readValues), 0; got != want {
+		t.Errorf("Got %d values after rollback, want %d", got, want)
+	}
+}
+
+func TestMapConcurrentTXs(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := newMapForTests(ctx, t)
+
+	// Both TXs write revision 1, but only the leaves of the first one to
+	// commit may be stored.
+	tx1 := beginMapTx(ctx, s, mapID, t)
+	tx2 := beginMapTx(ctx, s, mapID, t)
+	for _, tx := range []storage.MapTreeTX{tx1, tx2} {
+		if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
+			t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
+		}
+	}
+	if err := tx2.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want nil", err)
+	}
+	if err := tx1.Commit(); err == nil {
+		t.Error("Commit() of conflicting TX = nil, want err")
+	}
+
+	// TXs writing different keys don't conflict.
+	tx1 = beginMapTx(ctx, s, mapID, t)
+	tx2 = beginMapTx(ctx, s, mapID, t)
+	if err := tx1.Set(ctx, []byte("key 1"), mapLeaf); err != nil {
+		t.Fatalf("Failed to set key 1: %v", err)
+	}
+	if err := tx2.Set(ctx, []byte("key 2"), mapLeaf); err != nil {
+		t.Fatalf("Failed to set key 2: %v", err)
+	}
+	commit(tx1, t)
+	commit(tx2, t)
+
+	tx := beginMapTx(ctx, s, mapID, t)
+	defer commit(tx, t)
+	readValues, err := tx.Get(ctx, 1, [][]byte{keyHash, []byte("key 1"), []byte("key 2")})
+	if err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	if got, want := len(readValues), 3; got != want {
+		t.Errorf("Got %d values, want %d", got, want)
+	}
+}
+
+func beginMapTx(ctx context.Context, s storage.MapStorage, mapID int64, t *testing.T) storage.MapTreeTX {
+	tx, err := s.BeginForTree(ctx, mapID)
+	if err != nil {
+		t.Fatalf("Failed to begin map tx: %v", err)
+	}
+	return tx
+}
+
+func commit(tx storage.ReadOnlyTreeTX, t *testing.T) {
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Failed to commit tx: %v", err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/google/btree"
 	"github.com/google/trillian"
+	"github.com/google/trillian/errors"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/cache"
 	"github.com/google/trillian/storage/storagepb"
@@ -59,6 +60,8 @@ type tree struct {
 	// currentSTH is the timestamp of the current STH.
 	currentSTH int64
 	meta       *trillian.Tree
+	// preDeleteState is the state of a soft-deleted tree before its deletion.
+	preDeleteState trillian.TreeState
 }
 
 func (t *tree) Lock() {
@@ -77,8 +80,8 @@ func (t *tree) RUnlock() {
 	t.mu.RUnlock()
 }
 
-// memoryTreeStorage is shared between the memoryLog and (forthcoming) memoryMap-
-// Storage implementations, and contains functionality which is common to both,
+// memoryTreeStorage is shared between the memoryLog and memoryMapStorage
+// implementations, and contains functionality which is common to both,
 type memoryTreeStorage struct {
 	// mu only protects access to the trees map.
 	mu    sync.RWMutex
@@ -111,19 +114,28 @@ func (a kv) Less(b btree.Item) bool {
 
 // newTree creates and initializes a tree struct.
 func newTree(t trillian.Tree) *tree {
-	ret := &tree{
-		store: btree.New(degree),
+	return &tree{
+		store: newTreeStore(t.TreeId),
 		meta:  &t,
 	}
-	k := unseqKey(t.TreeId)
+}
+
+// newTreeStore creates the store of an empty tree.
+func newTreeStore(treeID int64) *btree.BTree {
+	store := btree.New(degree)
+	k := unseqKey(treeID)
 	k.(*kv).v = list.New()
-	ret.store.ReplaceOrInsert(k)
+	store.ReplaceOrInsert(k)
+
+	k = hashToSeqKey(treeID)
+	k.(*kv).v = make(map[string][]int64)
+	store.ReplaceOrInsert(k)
 
-	k = hashToSeqKey(t.TreeId)
+	k = idToSeqKey(treeID)
 	k.(*kv).v = make(map[string][]int64)
-	ret.store.ReplaceOrInsert(k)
+	store.ReplaceOrInsert(k)
 
-	return ret
+	return store
 }
 
 func (m *memoryTreeStorage) beginTreeTX(ctx context.Context, readonly bool, treeID int64, hashSizeBytes int, cache cache.SubtreeCache) (treeTX, error) {
@@ -150,6 +162,31 @@ func (m *memoryTreeStorage) beginTreeTX(ctx context.Context, readonly bool, tree
 	}, nil
 }
 
+// beginMergingTreeTX starts a TX which doesn't lock the tree for its duration,
+// so that several TXs may write to the tree concurrently. The TX works on a
+// snapshot of the tree, and its writes are merged into the tree on Commit,
+// which fails if any of the written keys have been written by another TX.
+func (m *memoryTreeStorage) beginMergingTreeTX(ctx context.Context, treeID int64, hashSizeBytes int, cache cache.SubtreeCache) (treeTX, error) {
+	tree := m.getTree(treeID)
+	if tree == nil {
+		// The tree may have been hard-deleted since it was read.
+		return treeTX{}, errors.Errorf(errors.NotFound, "no such treeID %d", treeID)
+	}
+	tree.Lock()
+	defer tree.Unlock()
+	return treeTX{
+		ts:            m,
+		tx:            tree.store.Clone(),
+		tree:          tree,
+		treeID:        treeID,
+		hashSizeBytes: hashSizeBytes,
+		subtreeCache:  cache,
+		writeRevision: -1,
+		unlock:        func() {},
+		merge:         true,
+	}, nil
+}
+
 type treeTX struct {
 	closed        bool
 	tx            *btree.BTree
@@ -160,6 +197,33 @@ type treeTX struct {
 	subtreeCache  cache.SubtreeCache
 	writeRevision int64
 	unlock        func()
+	// merge is set if the TX's writes are merged into the tree on Commit,
+	// rather than replacing its store, in which case writes holds them.
+	merge  bool
+	writes []btree.Item
+}
+
+// put stores item in the TX.
+func (t *treeTX) put(item btree.Item) {
+	t.tx.ReplaceOrInsert(item)
+	if t.merge {
+		t.writes = append(t.writes, item)
+	}
+}
+
+// mergeWrites adds the items written by the TX to the tree's store.
+func (t *treeTX) mergeWrites() error {
+	t.tree.Lock()
+	defer t.tree.Unlock()
+	for _, item := range t.writes {
+		if t.tree.store.Has(item) {
+			return fmt.Errorf("%s was written by a concurrent TX", item.(*kv).k)
+		}
+	}
+	for _, item := range t.writes {
+		t.tree.store.ReplaceOrInsert(item)
+	}
+	return nil
 }
 
 func (t *treeTX) getSubtree(ctx context.Context, treeRevision int64, nodeID storage.NodeID) (*storagepb.SubtreeProto, error) {
@@ -198,6 +262,9 @@ func (t *treeTX) getSubtrees(ctx context.Context, treeRevision int64, nodeIDs []
 			// Return a copy of the proto to protect against the caller modifying the stored one.
 			p := s.(*kv).v.(*storagepb.SubtreeProto)
 			v := proto.Clone(p).(*storagepb.SubtreeProto)
+			if v.Prefix == nil {
+				v.Prefix = []byte{}
+			}
 			ret = append(ret, v)
 			break
 		}
@@ -221,7 +288,7 @@ func (t *treeTX) storeSubtrees(ctx context.Context, subtrees []*storagepb.Subtre
 		}
 		k := subtreeKey(t.treeID, t.writeRevision, storage.NewNodeIDFromHash(s.Prefix))
 		k.(*kv).v = s
-		t.tx.ReplaceOrInsert(k)
+		t.put(k)
 	}
 	return nil
 }
@@ -263,6 +330,9 @@ func (t *treeTX) Commit() error {
 		}
 	}
 	t.closed = true
+	if t.merge {
+		return t.mergeWrites()
+	}
 	// update the shared view of the tree post TX:
 	t.tree.store = t.tx
 	return nil
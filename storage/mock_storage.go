@@ -145,6 +145,18 @@ func (_mr *MockAdminTXMockRecorder) GetTree(arg0, arg1 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetTree", reflect.TypeOf((*MockAdminTX)(nil).GetTree), arg0, arg1)
 }
 
+// HardDeleteTree mocks base method
+func (_m *MockAdminTX) HardDeleteTree(_param0 context.Context, _param1 int64) error {
+	ret := _m.ctrl.Call(_m, "HardDeleteTree", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HardDeleteTree indicates an expected call of HardDeleteTree
+func (_mr *MockAdminTXMockRecorder) HardDeleteTree(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "HardDeleteTree", reflect.TypeOf((*MockAdminTX)(nil).HardDeleteTree), arg0, arg1)
+}
+
 // IsClosed mocks base method
 func (_m *MockAdminTX) IsClosed() bool {
 	ret := _m.ctrl.Call(_m, "IsClosed")
@@ -171,16 +183,16 @@ func (_mr *MockAdminTXMockRecorder) ListTreeIDs(arg0 interface{}) *gomock.Call {
 }
 
 // ListTrees mocks base method
-func (_m *MockAdminTX) ListTrees(_param0 context.Context) ([]*trillian.Tree, error) {
-	ret := _m.ctrl.Call(_m, "ListTrees", _param0)
+func (_m *MockAdminTX) ListTrees(_param0 context.Context, _param1 ListTreesOptions) ([]*trillian.Tree, error) {
+	ret := _m.ctrl.Call(_m, "ListTrees", _param0, _param1)
 	ret0, _ := ret[0].([]*trillian.Tree)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTrees indicates an expected call of ListTrees
-func (_mr *MockAdminTXMockRecorder) ListTrees(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "ListTrees", reflect.TypeOf((*MockAdminTX)(nil).ListTrees), arg0)
+func (_mr *MockAdminTXMockRecorder) ListTrees(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "ListTrees", reflect.TypeOf((*MockAdminTX)(nil).ListTrees), arg0, arg1)
 }
 
 // Rollback mocks base method
@@ -195,6 +207,32 @@ func (_mr *MockAdminTXMockRecorder) Rollback() *gomock.Call {
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Rollback", reflect.TypeOf((*MockAdminTX)(nil).Rollback))
 }
 
+// SoftDeleteTree mocks base method
+func (_m *MockAdminTX) SoftDeleteTree(_param0 context.Context, _param1 int64) (*trillian.Tree, error) {
+	ret := _m.ctrl.Call(_m, "SoftDeleteTree", _param0, _param1)
+	ret0, _ := ret[0].(*trillian.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteTree indicates an expected call of SoftDeleteTree
+func (_mr *MockAdminTXMockRecorder) SoftDeleteTree(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "SoftDeleteTree", reflect.TypeOf((*MockAdminTX)(nil).SoftDeleteTree), arg0, arg1)
+}
+
+// UndeleteTree mocks base method
+func (_m *MockAdminTX) UndeleteTree(_param0 context.Context, _param1 int64) (*trillian.Tree, error) {
+	ret := _m.ctrl.Call(_m, "UndeleteTree", _param0, _param1)
+	ret0, _ := ret[0].(*trillian.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteTree indicates an expected call of UndeleteTree
+func (_mr *MockAdminTXMockRecorder) UndeleteTree(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "UndeleteTree", reflect.TypeOf((*MockAdminTX)(nil).UndeleteTree), arg0, arg1)
+}
+
 // UpdateTree mocks base method
 func (_m *MockAdminTX) UpdateTree(_param0 context.Context, _param1 int64, _param2 func(*trillian.Tree)) (*trillian.Tree, error) {
 	ret := _m.ctrl.Call(_m, "UpdateTree", _param0, _param1, _param2)
@@ -305,6 +343,19 @@ func (_m *MockLogTreeTX) EXPECT() *MockLogTreeTXMockRecorder {
 	return _m.recorder
 }
 
+// AddSequencedLeaves mocks base method
+func (_m *MockLogTreeTX) AddSequencedLeaves(_param0 context.Context, _param1 []*trillian.LogLeaf) ([]*trillian.QueuedLogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "AddSequencedLeaves", _param0, _param1)
+	ret0, _ := ret[0].([]*trillian.QueuedLogLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSequencedLeaves indicates an expected call of AddSequencedLeaves
+func (_mr *MockLogTreeTXMockRecorder) AddSequencedLeaves(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "AddSequencedLeaves", reflect.TypeOf((*MockLogTreeTX)(nil).AddSequencedLeaves), arg0, arg1)
+}
+
 // Close mocks base method
 func (_m *MockLogTreeTX) Close() error {
 	ret := _m.ctrl.Call(_m, "Close")
@@ -342,17 +393,43 @@ func (_mr *MockLogTreeTXMockRecorder) DequeueLeaves(arg0, arg1, arg2 interface{}
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "DequeueLeaves", reflect.TypeOf((*MockLogTreeTX)(nil).DequeueLeaves), arg0, arg1, arg2)
 }
 
+// GetLeafStatus mocks base method
+func (_m *MockLogTreeTX) GetLeafStatus(_param0 context.Context, _param1 []byte) (LeafStatus, error) {
+	ret := _m.ctrl.Call(_m, "GetLeafStatus", _param0, _param1)
+	ret0, _ := ret[0].(LeafStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeafStatus indicates an expected call of GetLeafStatus
+func (_mr *MockLogTreeTXMockRecorder) GetLeafStatus(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeafStatus", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeafStatus), arg0, arg1)
+}
+
 // GetLeavesByHash mocks base method
-func (_m *MockLogTreeTX) GetLeavesByHash(_param0 context.Context, _param1 [][]byte, _param2 bool) ([]*trillian.LogLeaf, error) {
-	ret := _m.ctrl.Call(_m, "GetLeavesByHash", _param0, _param1, _param2)
+func (_m *MockLogTreeTX) GetLeavesByHash(_param0 context.Context, _param1 [][]byte, _param2 bool, _param3 int, _param4 int) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByHash", _param0, _param1, _param2, _param3, _param4)
 	ret0, _ := ret[0].([]*trillian.LogLeaf)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLeavesByHash indicates an expected call of GetLeavesByHash
-func (_mr *MockLogTreeTXMockRecorder) GetLeavesByHash(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByHash", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeavesByHash), arg0, arg1, arg2)
+func (_mr *MockLogTreeTXMockRecorder) GetLeavesByHash(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByHash", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeavesByHash), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetLeavesByIdentityHash mocks base method
+func (_m *MockLogTreeTX) GetLeavesByIdentityHash(_param0 context.Context, _param1 [][]byte, _param2 int, _param3 int) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByIdentityHash", _param0, _param1, _param2, _param3)
+	ret0, _ := ret[0].([]*trillian.LogLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeavesByIdentityHash indicates an expected call of GetLeavesByIdentityHash
+func (_mr *MockLogTreeTXMockRecorder) GetLeavesByIdentityHash(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByIdentityHash", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeavesByIdentityHash), arg0, arg1, arg2, arg3)
 }
 
 // GetLeavesByIndex mocks base method
@@ -368,6 +445,19 @@ func (_mr *MockLogTreeTXMockRecorder) GetLeavesByIndex(arg0, arg1 interface{}) *
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByIndex", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeavesByIndex), arg0, arg1)
 }
 
+// GetLeavesByRange mocks base method
+func (_m *MockLogTreeTX) GetLeavesByRange(_param0 context.Context, _param1 int64, _param2 int64) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByRange", _param0, _param1, _param2)
+	ret0, _ := ret[0].([]*trillian.LogLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeavesByRange indicates an expected call of GetLeavesByRange
+func (_mr *MockLogTreeTXMockRecorder) GetLeavesByRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByRange", reflect.TypeOf((*MockLogTreeTX)(nil).GetLeavesByRange), arg0, arg1, arg2)
+}
+
 // GetMerkleNodes mocks base method
 func (_m *MockLogTreeTX) GetMerkleNodes(_param0 context.Context, _param1 int64, _param2 []NodeID) ([]Node, error) {
 	ret := _m.ctrl.Call(_m, "GetMerkleNodes", _param0, _param1, _param2)
@@ -381,6 +471,19 @@ func (_mr *MockLogTreeTXMockRecorder) GetMerkleNodes(arg0, arg1, arg2 interface{
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetMerkleNodes", reflect.TypeOf((*MockLogTreeTX)(nil).GetMerkleNodes), arg0, arg1, arg2)
 }
 
+// GetOldestQueueTimestamp mocks base method
+func (_m *MockLogTreeTX) GetOldestQueueTimestamp(_param0 context.Context) (time.Time, error) {
+	ret := _m.ctrl.Call(_m, "GetOldestQueueTimestamp", _param0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestQueueTimestamp indicates an expected call of GetOldestQueueTimestamp
+func (_mr *MockLogTreeTXMockRecorder) GetOldestQueueTimestamp(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetOldestQueueTimestamp", reflect.TypeOf((*MockLogTreeTX)(nil).GetOldestQueueTimestamp), arg0)
+}
+
 // GetSequencedLeafCount mocks base method
 func (_m *MockLogTreeTX) GetSequencedLeafCount(_param0 context.Context) (int64, error) {
 	ret := _m.ctrl.Call(_m, "GetSequencedLeafCount", _param0)
@@ -638,6 +741,19 @@ func (_mr *MockMapTreeTXMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Get", reflect.TypeOf((*MockMapTreeTX)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetHistory mocks base method
+func (_m *MockMapTreeTX) GetHistory(_param0 context.Context, _param1 []byte, _param2 int64, _param3 int64, _param4 int) ([]MapLeafRevision, error) {
+	ret := _m.ctrl.Call(_m, "GetHistory", _param0, _param1, _param2, _param3, _param4)
+	ret0, _ := ret[0].([]MapLeafRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory
+func (_mr *MockMapTreeTXMockRecorder) GetHistory(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetHistory", reflect.TypeOf((*MockMapTreeTX)(nil).GetHistory), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetMerkleNodes mocks base method
 func (_m *MockMapTreeTX) GetMerkleNodes(_param0 context.Context, _param1 int64, _param2 []NodeID) ([]Node, error) {
 	ret := _m.ctrl.Call(_m, "GetMerkleNodes", _param0, _param1, _param2)
@@ -725,6 +841,18 @@ func (_mr *MockMapTreeTXMockRecorder) Set(arg0, arg1, arg2 interface{}) *gomock.
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Set", reflect.TypeOf((*MockMapTreeTX)(nil).Set), arg0, arg1, arg2)
 }
 
+// SetBatch mocks base method
+func (_m *MockMapTreeTX) SetBatch(_param0 context.Context, _param1 []trillian.MapLeaf) error {
+	ret := _m.ctrl.Call(_m, "SetBatch", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBatch indicates an expected call of SetBatch
+func (_mr *MockMapTreeTXMockRecorder) SetBatch(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "SetBatch", reflect.TypeOf((*MockMapTreeTX)(nil).SetBatch), arg0, arg1)
+}
+
 // SetMerkleNodes mocks base method
 func (_m *MockMapTreeTX) SetMerkleNodes(_param0 context.Context, _param1 []Node) error {
 	ret := _m.ctrl.Call(_m, "SetMerkleNodes", _param0, _param1)
@@ -847,16 +975,16 @@ func (_mr *MockReadOnlyAdminTXMockRecorder) ListTreeIDs(arg0 interface{}) *gomoc
 }
 
 // ListTrees mocks base method
-func (_m *MockReadOnlyAdminTX) ListTrees(_param0 context.Context) ([]*trillian.Tree, error) {
-	ret := _m.ctrl.Call(_m, "ListTrees", _param0)
+func (_m *MockReadOnlyAdminTX) ListTrees(_param0 context.Context, _param1 ListTreesOptions) ([]*trillian.Tree, error) {
+	ret := _m.ctrl.Call(_m, "ListTrees", _param0, _param1)
 	ret0, _ := ret[0].([]*trillian.Tree)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTrees indicates an expected call of ListTrees
-func (_mr *MockReadOnlyAdminTXMockRecorder) ListTrees(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "ListTrees", reflect.TypeOf((*MockReadOnlyAdminTX)(nil).ListTrees), arg0)
+func (_mr *MockReadOnlyAdminTXMockRecorder) ListTrees(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "ListTrees", reflect.TypeOf((*MockReadOnlyAdminTX)(nil).ListTrees), arg0, arg1)
 }
 
 // Rollback mocks base method
@@ -931,6 +1059,32 @@ func (_mr *MockReadOnlyLogTXMockRecorder) GetActiveLogIDs(arg0 interface{}) *gom
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetActiveLogIDs", reflect.TypeOf((*MockReadOnlyLogTX)(nil).GetActiveLogIDs), arg0)
 }
 
+// GetLatestSignedLogRoots mocks base method
+func (_m *MockReadOnlyLogTX) GetLatestSignedLogRoots(_param0 context.Context) (map[int64]trillian.SignedLogRoot, error) {
+	ret := _m.ctrl.Call(_m, "GetLatestSignedLogRoots", _param0)
+	ret0, _ := ret[0].(map[int64]trillian.SignedLogRoot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestSignedLogRoots indicates an expected call of GetLatestSignedLogRoots
+func (_mr *MockReadOnlyLogTXMockRecorder) GetLatestSignedLogRoots(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLatestSignedLogRoots", reflect.TypeOf((*MockReadOnlyLogTX)(nil).GetLatestSignedLogRoots), arg0)
+}
+
+// GetSequencedLeafEnds mocks base method
+func (_m *MockReadOnlyLogTX) GetSequencedLeafEnds(_param0 context.Context) (CountByLogID, error) {
+	ret := _m.ctrl.Call(_m, "GetSequencedLeafEnds", _param0)
+	ret0, _ := ret[0].(CountByLogID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSequencedLeafEnds indicates an expected call of GetSequencedLeafEnds
+func (_mr *MockReadOnlyLogTXMockRecorder) GetSequencedLeafEnds(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetSequencedLeafEnds", reflect.TypeOf((*MockReadOnlyLogTX)(nil).GetSequencedLeafEnds), arg0)
+}
+
 // GetUnsequencedCounts mocks base method
 func (_m *MockReadOnlyLogTX) GetUnsequencedCounts(_param0 context.Context) (CountByLogID, error) {
 	ret := _m.ctrl.Call(_m, "GetUnsequencedCounts", _param0)
@@ -1003,17 +1157,43 @@ func (_mr *MockReadOnlyLogTreeTXMockRecorder) Commit() *gomock.Call {
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Commit", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).Commit))
 }
 
+// GetLeafStatus mocks base method
+func (_m *MockReadOnlyLogTreeTX) GetLeafStatus(_param0 context.Context, _param1 []byte) (LeafStatus, error) {
+	ret := _m.ctrl.Call(_m, "GetLeafStatus", _param0, _param1)
+	ret0, _ := ret[0].(LeafStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeafStatus indicates an expected call of GetLeafStatus
+func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeafStatus(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeafStatus", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeafStatus), arg0, arg1)
+}
+
 // GetLeavesByHash mocks base method
-func (_m *MockReadOnlyLogTreeTX) GetLeavesByHash(_param0 context.Context, _param1 [][]byte, _param2 bool) ([]*trillian.LogLeaf, error) {
-	ret := _m.ctrl.Call(_m, "GetLeavesByHash", _param0, _param1, _param2)
+func (_m *MockReadOnlyLogTreeTX) GetLeavesByHash(_param0 context.Context, _param1 [][]byte, _param2 bool, _param3 int, _param4 int) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByHash", _param0, _param1, _param2, _param3, _param4)
 	ret0, _ := ret[0].([]*trillian.LogLeaf)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLeavesByHash indicates an expected call of GetLeavesByHash
-func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeavesByHash(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByHash", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeavesByHash), arg0, arg1, arg2)
+func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeavesByHash(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByHash", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeavesByHash), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetLeavesByIdentityHash mocks base method
+func (_m *MockReadOnlyLogTreeTX) GetLeavesByIdentityHash(_param0 context.Context, _param1 [][]byte, _param2 int, _param3 int) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByIdentityHash", _param0, _param1, _param2, _param3)
+	ret0, _ := ret[0].([]*trillian.LogLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeavesByIdentityHash indicates an expected call of GetLeavesByIdentityHash
+func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeavesByIdentityHash(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByIdentityHash", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeavesByIdentityHash), arg0, arg1, arg2, arg3)
 }
 
 // GetLeavesByIndex mocks base method
@@ -1029,6 +1209,19 @@ func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeavesByIndex(arg0, arg1 interf
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByIndex", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeavesByIndex), arg0, arg1)
 }
 
+// GetLeavesByRange mocks base method
+func (_m *MockReadOnlyLogTreeTX) GetLeavesByRange(_param0 context.Context, _param1 int64, _param2 int64) ([]*trillian.LogLeaf, error) {
+	ret := _m.ctrl.Call(_m, "GetLeavesByRange", _param0, _param1, _param2)
+	ret0, _ := ret[0].([]*trillian.LogLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeavesByRange indicates an expected call of GetLeavesByRange
+func (_mr *MockReadOnlyLogTreeTXMockRecorder) GetLeavesByRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetLeavesByRange", reflect.TypeOf((*MockReadOnlyLogTreeTX)(nil).GetLeavesByRange), arg0, arg1, arg2)
+}
+
 // GetMerkleNodes mocks base method
 func (_m *MockReadOnlyLogTreeTX) GetMerkleNodes(_param0 context.Context, _param1 int64, _param2 []NodeID) ([]Node, error) {
 	ret := _m.ctrl.Call(_m, "GetMerkleNodes", _param0, _param1, _param2)
@@ -1164,6 +1357,19 @@ func (_mr *MockReadOnlyMapTreeTXMockRecorder) Get(arg0, arg1, arg2 interface{})
 	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Get", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetHistory mocks base method
+func (_m *MockReadOnlyMapTreeTX) GetHistory(_param0 context.Context, _param1 []byte, _param2 int64, _param3 int64, _param4 int) ([]MapLeafRevision, error) {
+	ret := _m.ctrl.Call(_m, "GetHistory", _param0, _param1, _param2, _param3, _param4)
+	ret0, _ := ret[0].([]MapLeafRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory
+func (_mr *MockReadOnlyMapTreeTXMockRecorder) GetHistory(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "GetHistory", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).GetHistory), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetMerkleNodes mocks base method
 func (_m *MockReadOnlyMapTreeTX) GetMerkleNodes(_param0 context.Context, _param1 int64, _param2 []NodeID) ([]Node, error) {
 	ret := _m.ctrl.Call(_m, "GetMerkleNodes", _param0, _param1, _param2)
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,8 +34,7 @@ import (
 )
 
 const (
-	defaultSequenceIntervalSeconds = 60
-	selectTrees                    = `
+	selectTrees = `
 		SELECT
 			TreeId,
 			TreeState,
@@ -48,11 +48,21 @@ const (
 			UpdateTimeMillis,
 			PrivateKey,
 			PublicKey,
-			MaxRootDurationMillis
-		FROM Trees`
+			MaxRootDurationMillis,
+			DeleteTimeMillis,
+			SigningEnabled,
+			SequencingEnabled,
+			SequenceIntervalSeconds,
+			MaxMergeDelaySeconds,
+			SchedulingPriority
+		FROM Trees LEFT JOIN TreeControl USING(TreeId)`
 	selectTreeByID = selectTrees + " WHERE TreeId = ?"
 )
 
+// hardDeleteBatchSize is the maximum number of rows deleted by each statement
+// when a tree's data is purged by HardDeleteTree.
+var hardDeleteBatchSize int64 = 1000
+
 // NewAdminStorage returns a MySQL storage.AdminStorage implementation backed by DB.
 func NewAdminStorage(db *sql.DB) storage.AdminStorage {
 	return &mysqlAdminStorage{db}
@@ -72,7 +82,7 @@ func (s *mysqlAdminStorage) Begin(ctx context.Context) (storage.AdminTX, error)
 	if err != nil {
 		return nil, err
 	}
-	return &adminTX{tx: tx}, nil
+	return &adminTX{db: s.db, tx: tx}, nil
 }
 
 func (s *mysqlAdminStorage) CheckDatabaseAccessible(ctx context.Context) error {
@@ -80,6 +90,8 @@ func (s *mysqlAdminStorage) CheckDatabaseAccessible(ctx context.Context) error {
 }
 
 type adminTX struct {
+	// db is used by HardDeleteTree to purge tree data outside of tx.
+	db *sql.DB
 	tx *sql.Tx
 
 	// mu guards *direct* reads/writes on closed, which happen only on
@@ -159,6 +171,8 @@ func readTree(row row) (*trillian.Tree, error) {
 	// Enums and Datetimes need an extra conversion step
 	var treeState, treeType, hashStrategy, hashAlgorithm, signatureAlgorithm string
 	var createMillis, updateMillis, maxRootDurationMillis int64
+	var deleteMillis, sequenceIntervalSeconds, maxMergeDelaySeconds, schedulingPriority sql.NullInt64
+	var signingEnabled, sequencingEnabled sql.NullBool
 	var displayName, description sql.NullString
 	var privateKey, publicKey []byte
 	err := row.Scan(
@@ -175,6 +189,12 @@ func readTree(row row) (*trillian.Tree, error) {
 		&privateKey,
 		&publicKey,
 		&maxRootDurationMillis,
+		&deleteMillis,
+		&signingEnabled,
+		&sequencingEnabled,
+		&sequenceIntervalSeconds,
+		&maxMergeDelaySeconds,
+		&schedulingPriority,
 	)
 	if err != nil {
 		return nil, err
@@ -232,6 +252,26 @@ func readTree(row row) (*trillian.Tree, error) {
 		return nil, fmt.Errorf("failed to parse update time: %v", err)
 	}
 	tree.MaxRootDuration = ptypes.DurationProto(time.Duration(maxRootDurationMillis * int64(time.Millisecond)))
+	if deleteMillis.Valid {
+		tree.DeleteTime, err = ptypes.TimestampProto(fromMillisSinceEpoch(deleteMillis.Int64))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse delete time: %v", err)
+		}
+	}
+
+	// TreeControl columns are NULL if there's no TreeControl row for the tree,
+	// in which case the defaults (everything enabled) apply.
+	tree.SigningDisabled = signingEnabled.Valid && !signingEnabled.Bool
+	tree.SequencingDisabled = sequencingEnabled.Valid && !sequencingEnabled.Bool
+	if sequenceIntervalSeconds.Valid && sequenceIntervalSeconds.Int64 > 0 {
+		tree.SequenceInterval = ptypes.DurationProto(time.Duration(sequenceIntervalSeconds.Int64) * time.Second)
+	}
+	if maxMergeDelaySeconds.Valid && maxMergeDelaySeconds.Int64 > 0 {
+		tree.MaxMergeDelay = ptypes.DurationProto(time.Duration(maxMergeDelaySeconds.Int64) * time.Second)
+	}
+	if schedulingPriority.Valid {
+		tree.SchedulingPriority = int32(schedulingPriority.Int64)
+	}
 
 	tree.PrivateKey = &any.Any{}
 	if err := proto.Unmarshal(privateKey, tree.PrivateKey); err != nil {
@@ -273,13 +313,14 @@ func (t *adminTX) ListTreeIDs(ctx context.Context) ([]int64, error) {
 	return treeIDs, nil
 }
 
-func (t *adminTX) ListTrees(ctx context.Context) ([]*trillian.Tree, error) {
-	stmt, err := t.tx.PrepareContext(ctx, selectTrees)
+func (t *adminTX) ListTrees(ctx context.Context, opts storage.ListTreesOptions) ([]*trillian.Tree, error) {
+	query, args := listTreesQuery(opts)
+	stmt, err := t.tx.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	rows, err := stmt.QueryContext(ctx)
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +336,32 @@ func (t *adminTX) ListTrees(ctx context.Context) ([]*trillian.Tree, error) {
 	return trees, nil
 }
 
+// listTreesQuery returns a query, and its arguments, that selects the trees
+// matching opts.
+func listTreesQuery(opts storage.ListTreesOptions) (string, []interface{}) {
+	conds := []string{"TreeId > ?"}
+	args := []interface{}{opts.AfterTreeID}
+	if opts.TreeType != trillian.TreeType_UNKNOWN_TREE_TYPE {
+		conds = append(conds, "TreeType = ?")
+		args = append(args, opts.TreeType.String())
+	}
+	if opts.TreeState != trillian.TreeState_UNKNOWN_TREE_STATE {
+		conds = append(conds, "TreeState = ?")
+		args = append(args, opts.TreeState.String())
+	}
+	if !opts.ShowDeleted {
+		conds = append(conds, "TreeState NOT IN (?, ?)")
+		args = append(args, trillian.TreeState_SOFT_DELETED.String(), trillian.TreeState_HARD_DELETED.String())
+	}
+
+	query := selectTrees + " WHERE " + strings.Join(conds, " AND ") + " ORDER BY TreeId"
+	if opts.PageSize > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.PageSize)
+	}
+	return query, args
+}
+
 func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillian.Tree, error) {
 	if err := storage.ValidateTreeForCreation(tree); err != nil {
 		return nil, err
@@ -314,6 +381,7 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 
 	newTree := *tree
 	newTree.TreeId = id
+	newTree.DeleteTime = nil
 	newTree.CreateTime, err = ptypes.TimestampProto(now)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build create time: %v", err)
@@ -383,15 +451,16 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 		return nil, fmt.Errorf("enum truncated: %v", err)
 	}
 
-	// TODO(codingllama): There's a strong disconnect between trillian.Tree and TreeControl. Are we OK with that?
 	insertControlStmt, err := t.tx.PrepareContext(
 		ctx,
 		`INSERT INTO TreeControl(
 			TreeId,
 			SigningEnabled,
 			SequencingEnabled,
-			SequenceIntervalSeconds)
-		VALUES(?, ?, ?, ?)`)
+			SequenceIntervalSeconds,
+			MaxMergeDelaySeconds,
+			SchedulingPriority)
+		VALUES(?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return nil, err
 	}
@@ -399,9 +468,11 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 	_, err = insertControlStmt.ExecContext(
 		ctx,
 		newTree.TreeId,
-		true, /* SigningEnabled */
-		true, /* SequencingEnabled */
-		defaultSequenceIntervalSeconds,
+		!newTree.SigningDisabled,
+		!newTree.SequencingDisabled,
+		sequenceIntervalSeconds(&newTree),
+		maxMergeDelaySeconds(&newTree),
+		newTree.SchedulingPriority,
 	)
 	if err != nil {
 		return nil, err
@@ -424,6 +495,11 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err := validateStorageSettings(tree); err != nil {
 		return nil, err
 	}
+	if storage.IsFreezing(&beforeUpdate, tree) {
+		if err := t.checkQueueDrained(ctx, treeID); err != nil {
+			return nil, err
+		}
+	}
 
 	// Use the time truncated-to-millis throughout, as that's what's stored.
 	nowMillis := toMillisSinceEpoch(time.Now())
@@ -458,9 +534,195 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 		return nil, err
 	}
 
+	controlStmt, err := t.tx.PrepareContext(
+		ctx,
+		`UPDATE TreeControl
+		SET SigningEnabled = ?, SequencingEnabled = ?, SequenceIntervalSeconds = ?, MaxMergeDelaySeconds = ?,
+			SchedulingPriority = ?
+		WHERE TreeId = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer controlStmt.Close()
+
+	if _, err = controlStmt.ExecContext(
+		ctx,
+		!tree.SigningDisabled,
+		!tree.SequencingDisabled,
+		sequenceIntervalSeconds(tree),
+		maxMergeDelaySeconds(tree),
+		tree.SchedulingPriority,
+		tree.TreeId); err != nil {
+		return nil, err
+	}
+
 	return tree, nil
 }
 
+func (t *adminTX) SoftDeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	tree, err := t.GetTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.ValidateTreeForSoftDelete(tree); err != nil {
+		return nil, err
+	}
+	nowMillis := toMillisSinceEpoch(time.Now())
+	deleteMillis := sql.NullInt64{Int64: nowMillis, Valid: true}
+	preDeleteState := sql.NullString{String: tree.TreeState.String(), Valid: true}
+	if err := t.updateTreeState(ctx, treeID, trillian.TreeState_SOFT_DELETED, nowMillis, deleteMillis, preDeleteState); err != nil {
+		return nil, err
+	}
+	return t.GetTree(ctx, treeID)
+}
+
+func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	tree, err := t.GetTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.ValidateTreeIsSoftDeleted(tree); err != nil {
+		return nil, err
+	}
+	var preDeleteState sql.NullString
+	if err := t.tx.QueryRowContext(ctx, "SELECT PreDeleteTreeState FROM Trees WHERE TreeId = ?", treeID).Scan(&preDeleteState); err != nil {
+		return nil, err
+	}
+	// Trees deleted before PreDeleteTreeState was recorded are restored to ACTIVE.
+	state := trillian.TreeState_ACTIVE
+	if preDeleteState.Valid {
+		ts, ok := trillian.TreeState_value[preDeleteState.String]
+		if !ok {
+			return nil, fmt.Errorf("unknown TreeState: %v", preDeleteState.String)
+		}
+		state = trillian.TreeState(ts)
+	}
+	nowMillis := toMillisSinceEpoch(time.Now())
+	if err := t.updateTreeState(ctx, treeID, state, nowMillis, sql.NullInt64{}, sql.NullString{}); err != nil {
+		return nil, err
+	}
+	return t.GetTree(ctx, treeID)
+}
+
+func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
+	// Unknown trees are reported by GetTree.
+	var id int64
+	if err := t.tx.QueryRowContext(ctx, "SELECT TreeId FROM Trees WHERE TreeId = ? FOR UPDATE", treeID).Scan(&id); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error locking tree %v: %v", treeID, err)
+	}
+	tree, err := t.GetTree(ctx, treeID)
+	if err != nil {
+		return err
+	}
+	if err := storage.ValidateTreeIsSoftDeleted(tree); err != nil {
+		return err
+	}
+
+	// Tables are purged child-first, so foreign keys are never violated.
+	// The Trees and TreeControl rows are kept, which stops the tree ID from
+	// being reused.
+	// The tree's row stays locked by tx while its data is purged, so it can't
+	// be undeleted halfway through. Rows are deleted in bounded chunks, each
+	// committed on its own, so a large tree doesn't hold locks on its data
+	// for the whole purge. If tx is rolled back, the tree remains
+	// SOFT_DELETED and HardDeleteTree may be retried.
+	for _, table := range []string{
+		"SequencedLeafData",
+		"LeafData",
+		"Unsequenced",
+		"Subtree",
+		"TreeHead",
+		"MapLeaf",
+		"MapHead",
+	} {
+		if err := t.purgeTable(ctx, table, treeID); err != nil {
+			return err
+		}
+	}
+
+	deleteMillis := sql.NullInt64{Valid: tree.DeleteTime != nil}
+	if deleteMillis.Valid {
+		deleteTime, err := ptypes.Timestamp(tree.DeleteTime)
+		if err != nil {
+			return fmt.Errorf("failed to parse delete time: %v", err)
+		}
+		deleteMillis.Int64 = toMillisSinceEpoch(deleteTime)
+	}
+	return t.updateTreeState(ctx, treeID, trillian.TreeState_HARD_DELETED, toMillisSinceEpoch(time.Now()), deleteMillis, sql.NullString{})
+}
+
+// purgeTable deletes all rows of the specified tree from table, at most
+// hardDeleteBatchSize rows per statement. Each statement runs in a transaction
+// of its own, outside of t.tx.
+func (t *adminTX) purgeTable(ctx context.Context, table string, treeID int64) error {
+	query := fmt.Sprintf("DELETE FROM %v WHERE TreeId = ? LIMIT ?", table)
+	for {
+		res, err := t.db.ExecContext(ctx, query, treeID, hardDeleteBatchSize)
+		if err != nil {
+			return fmt.Errorf("error purging %v for tree %v: %v", table, treeID, err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error purging %v for tree %v: %v", table, treeID, err)
+		}
+		if rows < hardDeleteBatchSize {
+			return nil
+		}
+	}
+}
+
+// checkQueueDrained returns a FailedPrecondition error if the specified tree
+// has unsequenced leaves, or sequenced leaves that its latest signed root
+// doesn't cover yet, such as those assigned by a pipelined sequencer pass or
+// added to a PREORDERED_LOG tree.
+// The tree's row is locked first, which conflicts with the shared lock taken by
+// QueueLeaves: leaves queued by TXs still in flight are either counted here, or
+// are rejected once those TXs see the tree's new state.
+func (t *adminTX) checkQueueDrained(ctx context.Context, treeID int64) error {
+	var id int64
+	if err := t.tx.QueryRowContext(ctx, "SELECT TreeId FROM Trees WHERE TreeId = ? FOR UPDATE", treeID).Scan(&id); err != nil {
+		return fmt.Errorf("error locking tree %v: %v", treeID, err)
+	}
+	var count int64
+	if err := t.tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM Unsequenced WHERE TreeId = ? FOR UPDATE",
+		treeID).Scan(&count); err != nil {
+		return fmt.Errorf("error counting unsequenced leaves of tree %v: %v", treeID, err)
+	}
+	if count > 0 {
+		return errors.Errorf(errors.FailedPrecondition, "cannot freeze tree %v: %v unsequenced leaves", treeID, count)
+	}
+	if err := t.tx.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM SequencedLeafData
+		WHERE TreeId = ? AND SequenceNumber >= COALESCE(
+			(SELECT TreeSize FROM TreeHead WHERE TreeId = ? ORDER BY TreeHeadTimestamp DESC LIMIT 1), 0)`,
+		treeID, treeID).Scan(&count); err != nil {
+		return fmt.Errorf("error counting pending leaves of tree %v: %v", treeID, err)
+	}
+	if count > 0 {
+		return errors.Errorf(errors.FailedPrecondition, "cannot freeze tree %v: %v leaves not yet integrated", treeID, count)
+	}
+	return nil
+}
+
+// updateTreeState sets the state, update and delete times of the specified
+// tree, along with the state it had before being deleted.
+func (t *adminTX) updateTreeState(ctx context.Context, treeID int64, state trillian.TreeState, updateMillis int64, deleteMillis sql.NullInt64, preDeleteState sql.NullString) error {
+	stmt, err := t.tx.PrepareContext(
+		ctx,
+		`UPDATE Trees
+		SET TreeState = ?, UpdateTimeMillis = ?, DeleteTimeMillis = ?, PreDeleteTreeState = ?
+		WHERE TreeId = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, state.String(), updateMillis, deleteMillis, preDeleteState, treeID)
+	return err
+}
+
 func toMillisSinceEpoch(t time.Time) int64 {
 	return t.UnixNano() / 1000000
 }
@@ -477,3 +739,25 @@ func validateStorageSettings(tree *trillian.Tree) error {
 	}
 	return nil
 }
+
+// sequenceIntervalSeconds returns the tree's sequence_interval in seconds, as
+// stored in TreeControl. Zero means the signer's default interval.
+// The tree is assumed to be valid.
+func sequenceIntervalSeconds(tree *trillian.Tree) int64 {
+	if tree.SequenceInterval == nil {
+		return 0
+	}
+	interval, _ := ptypes.Duration(tree.SequenceInterval)
+	return int64(interval / time.Second)
+}
+
+// maxMergeDelaySeconds returns the tree's max_merge_delay in seconds, as stored
+// in TreeControl. Zero means the signer's default maximum merge delay.
+// The tree is assumed to be valid.
+func maxMergeDelaySeconds(tree *trillian.Tree) int64 {
+	if tree.MaxMergeDelay == nil {
+		return 0
+	}
+	mmd, _ := ptypes.Duration(tree.MaxMergeDelay)
+	return int64(mmd / time.Second)
+}
@@ -19,21 +19,26 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/errors"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/testonly"
 )
 
-const selectTreeControlByID = "SELECT SigningEnabled, SequencingEnabled, SequenceIntervalSeconds FROM TreeControl WHERE TreeId = ?"
+const selectTreeControlByID = "SELECT SigningEnabled, SequencingEnabled, SequenceIntervalSeconds, MaxMergeDelaySeconds, SchedulingPriority FROM TreeControl WHERE TreeId = ?"
 
 func TestMysqlAdminStorage(t *testing.T) {
-	tester := &testonly.AdminStorageTester{NewAdminStorage: func() storage.AdminStorage {
-		cleanTestDB(DB)
-		return NewAdminStorage(DB)
-	}}
+	tester := &testonly.AdminStorageTester{
+		NewAdminStorage: func() storage.AdminStorage {
+			cleanTestDB(DB)
+			return NewAdminStorage(DB)
+		},
+		LogStorage: func() storage.LogStorage { return NewLogStorage(DB, nil) },
+	}
 	tester.RunAllTests(t)
 }
 
@@ -49,14 +54,77 @@ func TestAdminTX_CreateTree_InitializesStorageStructures(t *testing.T) {
 
 	// Check if TreeControl is correctly written.
 	var signingEnabled, sequencingEnabled bool
-	var sequenceIntervalSeconds int
-	if err := DB.QueryRowContext(ctx, selectTreeControlByID, tree.TreeId).Scan(&signingEnabled, &sequencingEnabled, &sequenceIntervalSeconds); err != nil {
+	var sequenceIntervalSeconds, maxMergeDelaySeconds, schedulingPriority int
+	if err := DB.QueryRowContext(ctx, selectTreeControlByID, tree.TreeId).Scan(&signingEnabled, &sequencingEnabled, &sequenceIntervalSeconds, &maxMergeDelaySeconds, &schedulingPriority); err != nil {
+		t.Fatalf("Failed to read TreeControl: %v", err)
+	}
+	// testonly.LogTree doesn't set any controls, so everything should be
+	// enabled and use the default interval, maximum merge delay and priority.
+	if !signingEnabled || !sequencingEnabled {
+		t.Errorf("signingEnabled = %v, sequencingEnabled = %v, want both true", signingEnabled, sequencingEnabled)
+	}
+	if sequenceIntervalSeconds != 0 {
+		t.Errorf("sequenceIntervalSeconds = %v, want = 0", sequenceIntervalSeconds)
+	}
+	if maxMergeDelaySeconds != 0 {
+		t.Errorf("maxMergeDelaySeconds = %v, want = 0", maxMergeDelaySeconds)
+	}
+	if schedulingPriority != 0 {
+		t.Errorf("schedulingPriority = %v, want = 0", schedulingPriority)
+	}
+}
+
+func TestAdminTX_TreeControl(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	tree, err := createTreeInternal(ctx, s, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("createTree() failed: %v", err)
+	}
+
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	if _, err := tx.UpdateTree(ctx, tree.TreeId, func(tree *trillian.Tree) {
+		tree.SigningDisabled = true
+		tree.SequenceInterval = ptypes.DurationProto(30 * time.Second)
+		tree.MaxMergeDelay = ptypes.DurationProto(time.Hour)
+		tree.SchedulingPriority = 4
+	}); err != nil {
+		t.Fatalf("UpdateTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	var signingEnabled, sequencingEnabled bool
+	var sequenceIntervalSeconds, maxMergeDelaySeconds, schedulingPriority int
+	if err := DB.QueryRowContext(ctx, selectTreeControlByID, tree.TreeId).Scan(&signingEnabled, &sequencingEnabled, &sequenceIntervalSeconds, &maxMergeDelaySeconds, &schedulingPriority); err != nil {
 		t.Fatalf("Failed to read TreeControl: %v", err)
 	}
-	// We don't mind about specific values, defaults change, but let's check
-	// that important numbers are not zeroed.
-	if sequenceIntervalSeconds <= 0 {
-		t.Errorf("sequenceIntervalSeconds = %v, want > 0", sequenceIntervalSeconds)
+	if signingEnabled || !sequencingEnabled || sequenceIntervalSeconds != 30 || maxMergeDelaySeconds != 3600 || schedulingPriority != 4 {
+		t.Errorf("TreeControl = (%v, %v, %v, %v, %v), want = (false, true, 30, 3600, 4)", signingEnabled, sequencingEnabled, sequenceIntervalSeconds, maxMergeDelaySeconds, schedulingPriority)
+	}
+
+	// Sub-second intervals are rejected, as TreeControl stores seconds.
+	tx, err = s.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	if _, err := tx.UpdateTree(ctx, tree.TreeId, func(tree *trillian.Tree) {
+		tree.SequenceInterval = ptypes.DurationProto(1500 * time.Millisecond)
+	}); err == nil {
+		t.Error("UpdateTree() with sub-second sequence_interval returned err = nil, want non-nil")
+	}
+	if _, err := tx.UpdateTree(ctx, tree.TreeId, func(tree *trillian.Tree) {
+		tree.MaxMergeDelay = ptypes.DurationProto(1500 * time.Millisecond)
+	}); err == nil {
+		t.Error("UpdateTree() with sub-second max_merge_delay returned err = nil, want non-nil")
 	}
 }
 
@@ -107,7 +175,7 @@ func TestAdminTX_TreeWithNulls(t *testing.T) {
 		{
 			desc: "ListTrees",
 			fn: func(ctx context.Context, tx storage.AdminTX, treeID int64) error {
-				trees, err := tx.ListTrees(ctx)
+				trees, err := tx.ListTrees(ctx, storage.ListTreesOptions{})
 				if err != nil {
 					return err
 				}
@@ -181,6 +249,127 @@ func TestAdminTX_StorageSettingsNotSupported(t *testing.T) {
 	}
 }
 
+func TestAdminTX_HardDeleteTree_PurgesData(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	// Purge a row at a time, so that tables are purged over several rounds.
+	defer func(size int64) { hardDeleteBatchSize = size }(hardDeleteBatchSize)
+	hardDeleteBatchSize = 1
+
+	tree, err := createTreeInternal(ctx, s, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("createTree() failed: %v", err)
+	}
+	createFakeLeaf(ctx, DB, tree.TreeId, dummyRawHash, dummyHash, []byte("leaf"), someExtraData, sequenceNumber, t)
+	createFakeLeaf(ctx, DB, tree.TreeId, dummyHash2, dummyHash, []byte("leaf2"), someExtraData, sequenceNumber+1, t)
+	for _, stmt := range []string{
+		"INSERT INTO Unsequenced(TreeId, Bucket, LeafIdentityHash, MerkleLeafHash, QueueTimestampNanos) VALUES(?, 0, ?, ?, 0)",
+		"INSERT INTO Subtree(TreeId, SubtreeId, Nodes, SubtreeRevision) VALUES(?, ?, ?, 0)",
+		"INSERT INTO TreeHead(TreeId, TreeHeadTimestamp, TreeSize, RootHash, RootSignature, TreeRevision) VALUES(?, 0, 1, ?, ?, 0)",
+	} {
+		if _, err := DB.ExecContext(ctx, stmt, tree.TreeId, dummyHash2, dummyHash3); err != nil {
+			t.Fatalf("Failed to insert test data: %v", err)
+		}
+	}
+
+	if _, err := softDeleteTreeInternal(ctx, s, tree.TreeId); err != nil {
+		t.Fatalf("SoftDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	if err := tx.HardDeleteTree(ctx, tree.TreeId); err != nil {
+		t.Fatalf("HardDeleteTree() = %v, want = nil", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want = nil", err)
+	}
+
+	for _, table := range []string{"LeafData", "SequencedLeafData", "Unsequenced", "Subtree", "TreeHead"} {
+		var count int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE TreeId = ?", table)
+		if err := DB.QueryRowContext(ctx, query, tree.TreeId).Scan(&count); err != nil {
+			t.Errorf("%v: Failed to count rows: %v", table, err)
+			continue
+		}
+		if count != 0 {
+			t.Errorf("%v: got %v rows, want = 0", table, count)
+		}
+	}
+
+	// The tree itself must still exist, reserving its ID.
+	var count int
+	if err := DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM Trees WHERE TreeId = ?", tree.TreeId).Scan(&count); err != nil {
+		t.Fatalf("Failed to count Trees rows: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Trees: got %v rows, want = 1", count)
+	}
+}
+
+func TestAdminTX_UpdateTree_FreezeRequiresEmptyQueue(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	tree, err := createTreeInternal(ctx, s, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("createTree() failed: %v", err)
+	}
+	if _, err := DB.ExecContext(
+		ctx,
+		"INSERT INTO Unsequenced(TreeId, Bucket, LeafIdentityHash, MerkleLeafHash, QueueTimestampNanos) VALUES(?, 0, ?, ?, 0)",
+		tree.TreeId, dummyHash2, dummyHash3); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	freeze := func(tree *trillian.Tree) { tree.TreeState = trillian.TreeState_FROZEN }
+	_, err = updateTreeInternal(ctx, s, tree.TreeId, freeze)
+	if got, want := errors.ErrorCode(err), errors.FailedPrecondition; got != want {
+		t.Errorf("UpdateTree() with unsequenced leaves returned err = %v, want code %s", err, want)
+	}
+
+	if _, err := DB.ExecContext(ctx, "DELETE FROM Unsequenced WHERE TreeId = ?", tree.TreeId); err != nil {
+		t.Fatalf("Failed to delete test data: %v", err)
+	}
+	if _, err := updateTreeInternal(ctx, s, tree.TreeId, freeze); err != nil {
+		t.Errorf("UpdateTree() with empty queue = (_, %v), want = (_, nil)", err)
+	}
+}
+
+func TestAdminTX_UpdateTree_FreezeRequiresIntegratedLeaves(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	tree, err := createTreeInternal(ctx, s, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("createTree() failed: %v", err)
+	}
+	// The leaf has been assigned an index by a pipelined pass, but no root covers it yet.
+	createFakeLeaf(ctx, DB, tree.TreeId, dummyRawHash, dummyHash, []byte("leaf"), someExtraData, 0, t)
+
+	freeze := func(tree *trillian.Tree) { tree.TreeState = trillian.TreeState_FROZEN }
+	_, err = updateTreeInternal(ctx, s, tree.TreeId, freeze)
+	if got, want := errors.ErrorCode(err), errors.FailedPrecondition; got != want {
+		t.Errorf("UpdateTree() with pending leaves returned err = %v, want code %s", err, want)
+	}
+
+	if _, err := DB.ExecContext(
+		ctx,
+		"INSERT INTO TreeHead(TreeId, TreeHeadTimestamp, TreeSize, RootHash, RootSignature, TreeRevision) VALUES(?, 0, 1, ?, ?, 0)",
+		tree.TreeId, dummyHash2, dummyHash3); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	if _, err := updateTreeInternal(ctx, s, tree.TreeId, freeze); err != nil {
+		t.Errorf("UpdateTree() with integrated leaves = (_, %v), want = (_, nil)", err)
+	}
+}
+
 func TestCheckDatabaseAccessible_Fails(t *testing.T) {
 	// Pass in a closed database to provoke a failure.
 	db := openTestDBOrDie()
@@ -234,6 +423,22 @@ func updateTreeInternal(ctx context.Context, s storage.AdminStorage, treeID int6
 	return newTree, nil
 }
 
+func softDeleteTreeInternal(ctx context.Context, s storage.AdminStorage, treeID int64) (*trillian.Tree, error) {
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	tree, err := tx.SoftDeleteTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
 func setNulls(ctx context.Context, db *sql.DB, treeID int64) error {
 	stmt, err := db.PrepareContext(ctx, "UPDATE Trees SET DisplayName = NULL, Description = NULL WHERE TreeId = ?")
 	if err != nil {
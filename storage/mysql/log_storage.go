@@ -34,6 +34,8 @@ import (
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/cache"
 	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	spb "github.com/google/trillian/crypto/sigpb"
 )
@@ -50,21 +52,35 @@ const (
 			VALUES(?,?,?,?)`
 	insertUnsequencedEntrySQL = `INSERT INTO Unsequenced(TreeId,Bucket,LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos)
 			VALUES(?,0,?,?,?)`
-	insertSequencedLeafSQL = `INSERT INTO SequencedLeafData(TreeId,LeafIdentityHash,MerkleLeafHash,SequenceNumber)
-			VALUES(?,?,?,?)`
+	insertSequencedLeafSQL = `INSERT INTO SequencedLeafData(TreeId,LeafIdentityHash,MerkleLeafHash,SequenceNumber,QueueTimestampNanos)
+			VALUES(?,?,?,?,?)`
 	selectSequencedLeafCountSQL   = "SELECT COUNT(*) FROM SequencedLeafData WHERE TreeId=?"
+	selectActiveLogIDsSQL         = "SELECT TreeId FROM Trees WHERE TreeType IN(?,?) AND TreeState=?"
 	selectUnsequencedLeafCountSQL = "SELECT TreeId, COUNT(1) FROM Unsequenced GROUP BY TreeId"
+	selectTreeStateForShareSQL    = "SELECT TreeState FROM Trees WHERE TreeId=? LOCK IN SHARE MODE"
+	selectOldestQueueTimestampSQL = "SELECT MIN(QueueTimestampNanos) FROM Unsequenced WHERE TreeId=? AND Bucket=0"
 	selectLatestSignedLogRootSQL  = `SELECT TreeHeadTimestamp,TreeSize,RootHash,TreeRevision,RootSignature
 			FROM TreeHead WHERE TreeId=?
 			ORDER BY TreeHeadTimestamp DESC LIMIT 1`
-	deleteUnsequencedSQL = "DELETE FROM Unsequenced WHERE TreeId=? AND Bucket=0 AND QueueTimestampNanos=? AND LeafIdentityHash=?"
+	selectLatestSignedLogRootsSQL = `SELECT h.TreeId,h.TreeHeadTimestamp,h.TreeSize,h.RootHash,h.TreeRevision,h.RootSignature
+			FROM TreeHead h,(SELECT TreeId,MAX(TreeHeadTimestamp) AS Latest FROM TreeHead GROUP BY TreeId) l
+			WHERE h.TreeId=l.TreeId AND h.TreeHeadTimestamp=l.Latest`
+	selectSequencedLeafEndsSQL = "SELECT TreeId, MAX(SequenceNumber)+1 FROM SequencedLeafData GROUP BY TreeId"
+	deleteUnsequencedSQL       = "DELETE FROM Unsequenced WHERE TreeId=? AND Bucket=0 AND QueueTimestampNanos=? AND LeafIdentityHash=?"
 
 	// These statements need to be expanded to provide the correct number of parameter placeholders.
-	selectLeavesByIndexSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData
+	selectLeavesByIndexSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData,s.QueueTimestampNanos
 			FROM LeafData l,SequencedLeafData s
 			WHERE l.LeafIdentityHash = s.LeafIdentityHash
 			AND s.SequenceNumber IN (` + placeholderSQL + `) AND l.TreeId = ? AND s.TreeId = l.TreeId`
-	selectLeavesByMerkleHashSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData
+	// Uses the SequencedLeafData primary key for a range scan, so no placeholder expansion is
+	// needed however large the range is.
+	selectLeavesByRangeSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData,s.QueueTimestampNanos
+			FROM LeafData l,SequencedLeafData s
+			WHERE l.LeafIdentityHash = s.LeafIdentityHash
+			AND s.TreeId = ? AND s.SequenceNumber >= ? AND s.SequenceNumber < ? AND l.TreeId = s.TreeId
+			ORDER BY s.SequenceNumber`
+	selectLeavesByMerkleHashSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData,s.QueueTimestampNanos
 			FROM LeafData l,SequencedLeafData s
 			WHERE l.LeafIdentityHash = s.LeafIdentityHash
 			AND s.MerkleLeafHash IN (` + placeholderSQL + `) AND l.TreeId = ? AND s.TreeId = l.TreeId`
@@ -73,13 +89,28 @@ const (
 	// This statement returns a dummy Merkle leaf hash value (which must be
 	// of the right size) so that its signature matches that of the other
 	// leaf-selection statements.
-	selectLeavesByLeafIdentityHashSQL = `SELECT '` + dummyMerkleLeafHash + `',l.LeafIdentityHash,l.LeafValue,-1,l.ExtraData
+	selectLeavesByLeafIdentityHashSQL = `SELECT '` + dummyMerkleLeafHash + `',l.LeafIdentityHash,l.LeafValue,-1,l.ExtraData,0
 			FROM LeafData l
 			WHERE l.LeafIdentityHash IN (` + placeholderSQL + `) AND l.TreeId = ?`
+	// Unlike the statement above, this only returns leaves that have been sequenced.
+	selectSequencedLeavesByLeafIdentityHashSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData,s.QueueTimestampNanos
+			FROM LeafData l,SequencedLeafData s
+			WHERE l.LeafIdentityHash = s.LeafIdentityHash
+			AND l.LeafIdentityHash IN (` + placeholderSQL + `) AND l.TreeId = ? AND s.TreeId = l.TreeId
+			ORDER BY s.SequenceNumber`
+	selectLeafSequenceNumberSQL = `SELECT SequenceNumber FROM SequencedLeafData
+			WHERE TreeId = ? AND LeafIdentityHash = ?
+			ORDER BY SequenceNumber LIMIT 1`
+	selectLeafQueueTimestampSQL = `SELECT QueueTimestampNanos FROM Unsequenced
+			WHERE TreeId = ? AND LeafIdentityHash = ?
+			ORDER BY QueueTimestampNanos LIMIT 1`
 
 	// Same as above except with leaves ordered by sequence so we only incur this cost when necessary
 	orderBySequenceNumberSQL                     = " ORDER BY s.SequenceNumber"
 	selectLeavesByMerkleHashOrderedBySequenceSQL = selectLeavesByMerkleHashSQL + orderBySequenceNumberSQL
+	// Pages of results must be in a consistent order, so are always ordered by sequence.
+	selectLeavesByMerkleHashPageSQL                = selectLeavesByMerkleHashOrderedBySequenceSQL + " LIMIT ? OFFSET ?"
+	selectSequencedLeavesByLeafIdentityHashPageSQL = selectSequencedLeavesByLeafIdentityHashSQL + " LIMIT ? OFFSET ?"
 
 	// Error code returned by driver when inserting a duplicate row
 	errNumDuplicate = 1062
@@ -156,7 +187,10 @@ func (m *mySQLLogStorage) getLeavesByIndexStmt(ctx context.Context, num int) (*s
 	return m.getStmt(ctx, selectLeavesByIndexSQL, num, "?", "?")
 }
 
-func (m *mySQLLogStorage) getLeavesByMerkleHashStmt(ctx context.Context, num int, orderBySequence bool) (*sql.Stmt, error) {
+func (m *mySQLLogStorage) getLeavesByMerkleHashStmt(ctx context.Context, num int, orderBySequence, paged bool) (*sql.Stmt, error) {
+	if paged {
+		return m.getStmt(ctx, selectLeavesByMerkleHashPageSQL, num, "?", "?")
+	}
 	if orderBySequence {
 		return m.getStmt(ctx, selectLeavesByMerkleHashOrderedBySequenceSQL, num, "?", "?")
 	}
@@ -168,6 +202,13 @@ func (m *mySQLLogStorage) getLeavesByLeafIdentityHashStmt(ctx context.Context, n
 	return m.getStmt(ctx, selectLeavesByLeafIdentityHashSQL, num, "?", "?")
 }
 
+func (m *mySQLLogStorage) getSequencedLeavesByLeafIdentityHashStmt(ctx context.Context, num int, paged bool) (*sql.Stmt, error) {
+	if paged {
+		return m.getStmt(ctx, selectSequencedLeavesByLeafIdentityHashPageSQL, num, "?", "?")
+	}
+	return m.getStmt(ctx, selectSequencedLeavesByLeafIdentityHashSQL, num, "?", "?")
+}
+
 // readOnlyLogTX implements storage.ReadOnlyLogTX
 type readOnlyLogTX struct {
 	tx *sql.Tx
@@ -200,7 +241,7 @@ func (t *readOnlyLogTX) Close() error {
 
 func (t *readOnlyLogTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
 	rows, err := t.tx.QueryContext(
-		ctx, selectTreeIDByTypeAndStateSQL, trillian.TreeType_LOG.String(), trillian.TreeState_ACTIVE.String())
+		ctx, selectActiveLogIDsSQL, trillian.TreeType_LOG.String(), trillian.TreeType_PREORDERED_LOG.String(), trillian.TreeState_ACTIVE.String())
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +265,7 @@ func (m *mySQLLogStorage) beginInternal(ctx context.Context, treeID int64, reado
 		ctx,
 		m.admin,
 		treeID,
-		trees.GetOpts{TreeType: trillian.TreeType_LOG, Readonly: readonly})
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}, Readonly: readonly})
 	if err != nil {
 		return nil, err
 	}
@@ -240,8 +281,9 @@ func (m *mySQLLogStorage) beginInternal(ctx context.Context, treeID int64, reado
 	}
 
 	ltx := &logTreeTX{
-		treeTX: ttx,
-		ls:     m,
+		treeTX:   ttx,
+		ls:       m,
+		treeType: tree.TreeType,
 	}
 
 	ltx.root, err = ltx.fetchLatestRoot(ctx)
@@ -268,8 +310,9 @@ func (m *mySQLLogStorage) SnapshotForTree(ctx context.Context, treeID int64) (st
 
 type logTreeTX struct {
 	treeTX
-	ls   *mySQLLogStorage
-	root trillian.SignedLogRoot
+	ls       *mySQLLogStorage
+	root     trillian.SignedLogRoot
+	treeType trillian.TreeType
 }
 
 func (t *logTreeTX) ReadRevision() int64 {
@@ -287,6 +330,9 @@ type dequeuedLeaf struct {
 }
 
 func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime time.Time) ([]*trillian.LogLeaf, error) {
+	if t.treeType == trillian.TreeType_PREORDERED_LOG {
+		return t.dequeueSequencedLeaves(ctx, limit)
+	}
 	start := time.Now()
 	stx, err := t.tx.PrepareContext(ctx, selectQueuedLeavesSQL)
 
@@ -326,8 +372,9 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 		// sequencer. The sequencer only writes to the SequencedLeafData table and the client
 		// supplied data was already written to LeafData as part of queueing the leaf.
 		leaf := &trillian.LogLeaf{
-			LeafIdentityHash: leafIDHash,
-			MerkleLeafHash:   merkleHash,
+			LeafIdentityHash:    leafIDHash,
+			MerkleLeafHash:      merkleHash,
+			QueueTimestampNanos: queueTimeNanos,
 		}
 		leaves = append(leaves, leaf)
 		dql = append(dql, &dequeuedLeaf{queueTimestampNanos: queueTimeNanos, leafIdentityHash: leafIDHash})
@@ -359,6 +406,40 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 	return leaves, nil
 }
 
+// dequeueSequencedLeaves returns the leaves of a PREORDERED_LOG tree that can be integrated next,
+// which are the ones following the current tree size up to the first missing index.
+func (t *logTreeTX) dequeueSequencedLeaves(ctx context.Context, limit int) ([]*trillian.LogLeaf, error) {
+	leaves, err := t.GetLeavesByRange(ctx, t.root.TreeSize, int64(limit))
+	if err != nil {
+		glog.Warningf("Failed to select sequenced leaves for integration: %s", err)
+		return nil, err
+	}
+	for i, leaf := range leaves {
+		if leaf.LeafIndex != t.root.TreeSize+int64(i) {
+			leaves = leaves[:i]
+			break
+		}
+	}
+	dequeuedCounter.Add(float64(len(leaves)), labelForTX(t))
+	return leaves, nil
+}
+
+// checkTreeWritable returns an error if the tree doesn't accept new leaves.
+// The Trees row is read with a shared lock, which an UpdateTree freezing the
+// tree conflicts with: either the freeze waits for this TX and then finds its
+// leaves queued, or this TX waits for the freeze and then finds the tree FROZEN.
+func (t *logTreeTX) checkTreeWritable(ctx context.Context) error {
+	var state string
+	if err := t.tx.QueryRowContext(ctx, selectTreeStateForShareSQL, t.treeID).Scan(&state); err != nil {
+		return fmt.Errorf("error reading state of tree %v: %v", t.treeID, err)
+	}
+	ts, ok := trillian.TreeState_value[state]
+	if !ok {
+		return fmt.Errorf("unknown TreeState: %v", state)
+	}
+	return storage.ValidateTreeForLeafWrites(t.treeID, trillian.TreeState(ts))
+}
+
 func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error) {
 	// Don't accept batches if any of the leaves are invalid.
 	for _, leaf := range leaves {
@@ -366,6 +447,9 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 			return nil, fmt.Errorf("queued leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
 		}
 	}
+	if err := t.checkTreeWritable(ctx); err != nil {
+		return nil, err
+	}
 	start := time.Now()
 	label := labelForTX(t)
 
@@ -460,6 +544,104 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 	return existingLeaves, nil
 }
 
+func (t *logTreeTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) ([]*trillian.QueuedLogLeaf, error) {
+	// Don't accept batches if any of the leaves are invalid.
+	for _, leaf := range leaves {
+		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
+			return nil, fmt.Errorf("sequenced leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
+		}
+	}
+	if err := t.checkTreeWritable(ctx); err != nil {
+		return nil, err
+	}
+
+	// As in QueueLeaves, insert in a deterministic order to reduce the chance of deadlocks.
+	orderedLeaves := make([]leafAndPosition, len(leaves))
+	for i, leaf := range leaves {
+		orderedLeaves[i] = leafAndPosition{leaf: leaf, idx: i}
+	}
+	sort.Slice(orderedLeaves, func(i, j int) bool {
+		return orderedLeaves[i].leaf.LeafIndex < orderedLeaves[j].leaf.LeafIndex
+	})
+
+	results := make([]*trillian.QueuedLogLeaf, len(leaves))
+	for _, leafPos := range orderedLeaves {
+		result, err := t.addSequencedLeaf(ctx, leafPos.leaf)
+		if err != nil {
+			return nil, err
+		}
+		results[leafPos.idx] = result
+	}
+	queuedCounter.Add(float64(len(leaves)), labelForTX(t))
+	return results, nil
+}
+
+// addSequencedLeaf stores leaf at its LeafIndex, unless either the index or the LeafIdentityHash
+// of the leaf is already taken.
+func (t *logTreeTX) addSequencedLeaf(ctx context.Context, leaf *trillian.LogLeaf) (*trillian.QueuedLogLeaf, error) {
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT SequencedLeaf"); err != nil {
+		glog.Warningf("Error creating savepoint: %s", err)
+		return nil, err
+	}
+	_, err := t.tx.ExecContext(ctx, insertUnsequencedLeafSQL, t.treeID, leaf.LeafIdentityHash, leaf.LeafValue, leaf.ExtraData)
+	if isDuplicateErr(err) {
+		// Adding the same leaf again is fine, but not at a different index.
+		var seq int64
+		switch err := t.tx.QueryRowContext(ctx, selectLeafSequenceNumberSQL, t.treeID, leaf.LeafIdentityHash).Scan(&seq); {
+		case err == sql.ErrNoRows || (err == nil && seq != leaf.LeafIndex):
+			return &trillian.QueuedLogLeaf{
+				Leaf:   leaf,
+				Status: status.Newf(codes.FailedPrecondition, "conflicting LeafIdentityHash: %x", leaf.LeafIdentityHash).Proto(),
+			}, nil
+		case err != nil:
+			return nil, err
+		}
+		existing, err := t.GetLeavesByIndex(ctx, []int64{seq})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve existing leaf: %v", err)
+		}
+		return &trillian.QueuedLogLeaf{
+			Leaf:   existing[0],
+			Status: status.Newf(codes.AlreadyExists, "Leaf already exists at index %v", seq).Proto(),
+		}, nil
+	}
+	if err != nil {
+		glog.Warningf("Error inserting into LeafData: %s", err)
+		return nil, err
+	}
+
+	// Pre-ordered leaves are never queued.
+	_, err = t.tx.ExecContext(ctx, insertSequencedLeafSQL, t.treeID, leaf.LeafIdentityHash, leaf.MerkleLeafHash, leaf.LeafIndex, 0)
+	if isDuplicateErr(err) {
+		// Drop the LeafData row again, so the LeafIdentityHash isn't taken by a rejected leaf.
+		if _, err := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT SequencedLeaf"); err != nil {
+			glog.Warningf("Error rolling back to savepoint: %s", err)
+			return nil, err
+		}
+		return &trillian.QueuedLogLeaf{
+			Leaf:   leaf,
+			Status: status.Newf(codes.FailedPrecondition, "conflicting LeafIndex: %v", leaf.LeafIndex).Proto(),
+		}, nil
+	}
+	if err != nil {
+		glog.Warningf("Error inserting into SequencedLeafData: %s", err)
+		return nil, err
+	}
+	return &trillian.QueuedLogLeaf{Leaf: leaf}, nil
+}
+
+func (t *logTreeTX) GetOldestQueueTimestamp(ctx context.Context) (time.Time, error) {
+	var oldest sql.NullInt64
+	if err := t.tx.QueryRowContext(ctx, selectOldestQueueTimestampSQL, t.treeID).Scan(&oldest); err != nil {
+		glog.Warningf("Failed to get oldest queue timestamp: %v", err)
+		return time.Time{}, err
+	}
+	if !oldest.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, oldest.Int64), nil
+}
+
 func (t *logTreeTX) GetSequencedLeafCount(ctx context.Context) (int64, error) {
 	var sequencedLeafCount int64
 
@@ -498,7 +680,8 @@ func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*tr
 			&leaf.LeafIdentityHash,
 			&leaf.LeafValue,
 			&leaf.LeafIndex,
-			&leaf.ExtraData); err != nil {
+			&leaf.ExtraData,
+			&leaf.QueueTimestampNanos); err != nil {
 			glog.Warningf("Failed to scan merkle leaves: %s", err)
 			return nil, err
 		}
@@ -511,13 +694,89 @@ func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*tr
 	return ret, nil
 }
 
-func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool) ([]*trillian.LogLeaf, error) {
-	tmpl, err := t.ls.getLeavesByMerkleHashStmt(ctx, len(leafHashes), orderBySequence)
+func (t *logTreeTX) GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	stmt, err := t.tx.PrepareContext(ctx, selectLeavesByRangeSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, t.treeID, start, start+count)
+	if err != nil {
+		glog.Warningf("Failed to get leaves by range: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]*trillian.LogLeaf, 0)
+	for rows.Next() {
+		leaf := &trillian.LogLeaf{}
+		if err := rows.Scan(
+			&leaf.MerkleLeafHash,
+			&leaf.LeafIdentityHash,
+			&leaf.LeafValue,
+			&leaf.LeafIndex,
+			&leaf.ExtraData,
+			&leaf.QueueTimestampNanos); err != nil {
+			glog.Warningf("Failed to scan merkle leaves: %s", err)
+			return nil, err
+		}
+		ret = append(ret, leaf)
+	}
+	return ret, rows.Err()
+}
+
+func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool, limit, offset int) ([]*trillian.LogLeaf, error) {
+	paged := limit > 0
+	tmpl, err := t.ls.getLeavesByMerkleHashStmt(ctx, len(leafHashes), orderBySequence, paged)
 	if err != nil {
 		return nil, err
 	}
 
-	return t.getLeavesByHashInternal(ctx, leafHashes, tmpl, "merkle")
+	var extraArgs []interface{}
+	if paged {
+		extraArgs = []interface{}{limit, offset}
+	}
+	return t.getLeavesByHashInternal(ctx, leafHashes, tmpl, "merkle", extraArgs...)
+}
+
+func (t *logTreeTX) GetLeavesByIdentityHash(ctx context.Context, identityHashes [][]byte, limit, offset int) ([]*trillian.LogLeaf, error) {
+	paged := limit > 0
+	tmpl, err := t.ls.getSequencedLeavesByLeafIdentityHashStmt(ctx, len(identityHashes), paged)
+	if err != nil {
+		return nil, err
+	}
+
+	var extraArgs []interface{}
+	if paged {
+		extraArgs = []interface{}{limit, offset}
+	}
+	return t.getLeavesByHashInternal(ctx, identityHashes, tmpl, "sequenced-leaf-identity", extraArgs...)
+}
+
+func (t *logTreeTX) GetLeafStatus(ctx context.Context, identityHash []byte) (storage.LeafStatus, error) {
+	// In logs that allow duplicates a leaf can be both sequenced and queued again; report the
+	// sequenced copy, as that's the one a client can get a proof for.
+	var seq int64
+	err := t.tx.QueryRowContext(ctx, selectLeafSequenceNumberSQL, t.treeID, identityHash).Scan(&seq)
+	switch {
+	case err == nil:
+		return storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: seq}, nil
+	case err != sql.ErrNoRows:
+		glog.Warningf("Failed to get sequence number for leaf: %v", err)
+		return storage.LeafStatus{}, err
+	}
+
+	var queueTimestamp int64
+	err = t.tx.QueryRowContext(ctx, selectLeafQueueTimestampSQL, t.treeID, identityHash).Scan(&queueTimestamp)
+	switch {
+	case err == nil:
+		return storage.LeafStatus{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: queueTimestamp}, nil
+	case err != sql.ErrNoRows:
+		glog.Warningf("Failed to get queue timestamp for leaf: %v", err)
+		return storage.LeafStatus{}, err
+	}
+	return storage.LeafStatus{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS}, nil
 }
 
 // getLeafDataByIdentityHash retrieves leaf data by LeafIdentityHash, returned
@@ -606,7 +865,8 @@ func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillia
 			t.treeID,
 			leaf.LeafIdentityHash,
 			leaf.MerkleLeafHash,
-			leaf.LeafIndex)
+			leaf.LeafIndex,
+			leaf.QueueTimestampNanos)
 		if err != nil {
 			glog.Warningf("Failed to update sequenced leaves: %s", err)
 			return err
@@ -638,13 +898,16 @@ func (t *logTreeTX) removeSequencedLeaves(ctx context.Context, leaves []*dequeue
 	return nil
 }
 
-func (t *logTreeTX) getLeavesByHashInternal(ctx context.Context, leafHashes [][]byte, tmpl *sql.Stmt, desc string) ([]*trillian.LogLeaf, error) {
+// getLeavesByHashInternal runs tmpl with the hashes and tree ID as arguments, followed by any
+// extraArgs.
+func (t *logTreeTX) getLeavesByHashInternal(ctx context.Context, leafHashes [][]byte, tmpl *sql.Stmt, desc string, extraArgs ...interface{}) ([]*trillian.LogLeaf, error) {
 	stx := t.tx.StmtContext(ctx, tmpl)
 	var args []interface{}
 	for _, hash := range leafHashes {
 		args = append(args, interface{}([]byte(hash)))
 	}
 	args = append(args, interface{}(t.treeID))
+	args = append(args, extraArgs...)
 	rows, err := stx.QueryContext(ctx, args...)
 	if err != nil {
 		glog.Warningf("Query() %s hash = %v", desc, err)
@@ -658,7 +921,7 @@ func (t *logTreeTX) getLeavesByHashInternal(ctx context.Context, leafHashes [][]
 	for rows.Next() {
 		leaf := &trillian.LogLeaf{}
 
-		if err := rows.Scan(&leaf.MerkleLeafHash, &leaf.LeafIdentityHash, &leaf.LeafValue, &leaf.LeafIndex, &leaf.ExtraData); err != nil {
+		if err := rows.Scan(&leaf.MerkleLeafHash, &leaf.LeafIdentityHash, &leaf.LeafValue, &leaf.LeafIndex, &leaf.ExtraData, &leaf.QueueTimestampNanos); err != nil {
 			glog.Warningf("LogID: %d Scan() %s = %s", t.treeID, desc, err)
 			return nil, err
 		}
@@ -694,6 +957,63 @@ func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.Count
 	return ret, nil
 }
 
+func (t *readOnlyLogTX) GetLatestSignedLogRoots(ctx context.Context) (map[int64]trillian.SignedLogRoot, error) {
+	stx, err := t.tx.PrepareContext(ctx, selectLatestSignedLogRootsSQL)
+	if err != nil {
+		glog.Warningf("Failed to prep latest signed log roots statement: %v", err)
+		return nil, err
+	}
+	rows, err := stx.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[int64]trillian.SignedLogRoot)
+	for rows.Next() {
+		var logID, timestamp, treeSize, treeRevision int64
+		var rootHash, rootSignatureBytes []byte
+		if err := rows.Scan(&logID, &timestamp, &treeSize, &rootHash, &treeRevision, &rootSignatureBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan row from latest signed log roots: %v", err)
+		}
+		var rootSignature spb.DigitallySigned
+		if err := proto.Unmarshal(rootSignatureBytes, &rootSignature); err != nil {
+			glog.Warningf("Failed to unmarshall root signature: %v", err)
+			return nil, err
+		}
+		ret[logID] = trillian.SignedLogRoot{
+			RootHash:       rootHash,
+			TimestampNanos: timestamp,
+			TreeRevision:   treeRevision,
+			Signature:      &rootSignature,
+			LogId:          logID,
+			TreeSize:       treeSize,
+		}
+	}
+	return ret, rows.Err()
+}
+
+func (t *readOnlyLogTX) GetSequencedLeafEnds(ctx context.Context) (storage.CountByLogID, error) {
+	stx, err := t.tx.PrepareContext(ctx, selectSequencedLeafEndsSQL)
+	if err != nil {
+		glog.Warningf("Failed to prep sequenced leaf ends statement: %v", err)
+		return nil, err
+	}
+	rows, err := stx.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[int64]int64)
+	for rows.Next() {
+		var logID, end int64
+		if err := rows.Scan(&logID, &end); err != nil {
+			return nil, fmt.Errorf("failed to scan row from sequenced leaf ends: %v", err)
+		}
+		ret[logID] = end
+	}
+	return ret, rows.Err()
+}
+
 // byLeafIdentityHash allows sorting of leaves by their identity hash, so DB
 // operations always happen in a consistent order.
 type byLeafIdentityHash []*trillian.LogLeaf
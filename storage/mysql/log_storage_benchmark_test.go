@@ -0,0 +1,146 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/storage"
+)
+
+// newBenchmarkLog returns a log with numLeaves leaves worth of Merkle nodes,
+// along with the revision they were written at and the tree's root hash.
+func newBenchmarkLog(ctx context.Context, b *testing.B, s storage.LogStorage, numLeaves int) (int64, int64, []byte) {
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+
+	tx, err := s.BeginForTree(ctx, logID)
+	if err != nil {
+		b.Fatalf("BeginForTree(): %v", err)
+	}
+	defer tx.Close()
+	rev := tx.WriteRevision()
+
+	nodes := make(map[string]storage.Node)
+	cmt := merkle.NewCompactMerkleTree(rfc6962.DefaultHasher)
+	for i := 0; i < numLeaves; i++ {
+		if _, _, err := cmt.AddLeaf([]byte(fmt.Sprintf("leaf-%d", i)), func(depth int, index int64, hash []byte) error {
+			nodeID, err := storage.NewNodeIDForTreeCoords(int64(depth), index, 64)
+			if err != nil {
+				return err
+			}
+			nodes[nodeID.String()] = storage.Node{NodeID: nodeID, Hash: hash, NodeRevision: rev}
+			return nil
+		}); err != nil {
+			b.Fatalf("AddLeaf(): %v", err)
+		}
+	}
+	toStore := make([]storage.Node, 0, len(nodes))
+	for _, node := range nodes {
+		toStore = append(toStore, node)
+	}
+	if err := tx.SetMerkleNodes(ctx, toStore); err != nil {
+		b.Fatalf("SetMerkleNodes(): %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("Commit(): %v", err)
+	}
+	return logID, rev, cmt.CurrentRoot()
+}
+
+// BenchmarkLoadCompactMerkleTree compares fetching the nodes needed to
+// rebuild a CompactMerkleTree one at a time, as the sequencer used to, against
+// a single batch.
+func BenchmarkLoadCompactMerkleTree(b *testing.B) {
+	ctx := context.Background()
+	s := NewLogStorage(DB, nil)
+
+	nodeIDs := func(depths []int, indices []int64) []storage.NodeID {
+		ids := make([]storage.NodeID, len(depths))
+		for i, depth := range depths {
+			id, err := storage.NewNodeIDForTreeCoords(int64(depth), indices[i], 64)
+			if err != nil {
+				b.Fatalf("NewNodeIDForTreeCoords(): %v", err)
+			}
+			ids[i] = id
+		}
+		return ids
+	}
+
+	// Sizes of the form 2^n-1 need the most nodes.
+	for _, numLeaves := range []int{255, 4095, 65535} {
+		logID, rev, rootHash := newBenchmarkLog(ctx, b, s, numLeaves)
+
+		for _, bc := range []struct {
+			desc string
+			get  func(tx storage.ReadOnlyLogTreeTX) merkle.GetNodesFunc
+		}{
+			{
+				desc: "perNode",
+				get: func(tx storage.ReadOnlyLogTreeTX) merkle.GetNodesFunc {
+					return func(depths []int, indices []int64) ([][]byte, error) {
+						hashes := make([][]byte, 0, len(depths))
+						for _, id := range nodeIDs(depths, indices) {
+							nodes, err := tx.GetMerkleNodes(ctx, rev, []storage.NodeID{id})
+							if err != nil {
+								return nil, err
+							}
+							hashes = append(hashes, nodes[0].Hash)
+						}
+						return hashes, nil
+					}
+				},
+			},
+			{
+				desc: "batch",
+				get: func(tx storage.ReadOnlyLogTreeTX) merkle.GetNodesFunc {
+					return func(depths []int, indices []int64) ([][]byte, error) {
+						ids := nodeIDs(depths, indices)
+						nodes, err := tx.GetMerkleNodes(ctx, rev, ids)
+						if err != nil {
+							return nil, err
+						}
+						byID := make(map[string][]byte)
+						for _, node := range nodes {
+							byID[node.NodeID.String()] = node.Hash
+						}
+						hashes := make([][]byte, len(ids))
+						for i, id := range ids {
+							hashes[i] = byID[id.String()]
+						}
+						return hashes, nil
+					}
+				},
+			},
+		} {
+			b.Run(fmt.Sprintf("%d/%s", numLeaves, bc.desc), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tx, err := s.SnapshotForTree(ctx, logID)
+					if err != nil {
+						b.Fatalf("SnapshotForTree(): %v", err)
+					}
+					if _, err := merkle.NewCompactMerkleTreeWithState(rfc6962.DefaultHasher, int64(numLeaves), bc.get(tx), rootHash); err != nil {
+						b.Fatalf("%v: NewCompactMerkleTreeWithState(): %v", bc.desc, err)
+					}
+					tx.Close()
+				}
+			})
+		}
+	}
+}
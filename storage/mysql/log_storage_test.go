@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"sort"
 	"testing"
 	"time"
@@ -29,6 +30,8 @@ import (
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/testonly"
 	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	spb "github.com/google/trillian/crypto/sigpb"
 
@@ -61,7 +64,7 @@ const sequenceNumber int64 = 237
 
 func createFakeLeaf(ctx context.Context, db *sql.DB, logID int64, rawHash, hash, data, extraData []byte, seq int64, t *testing.T) *trillian.LogLeaf {
 	_, err := db.ExecContext(ctx, "INSERT INTO LeafData(TreeId, LeafIdentityHash, LeafValue, ExtraData) VALUES(?,?,?,?)", logID, rawHash, data, extraData)
-	_, err2 := db.ExecContext(ctx, "INSERT INTO SequencedLeafData(TreeId, SequenceNumber, LeafIdentityHash, MerkleLeafHash) VALUES(?,?,?,?)", logID, seq, rawHash, hash)
+	_, err2 := db.ExecContext(ctx, "INSERT INTO SequencedLeafData(TreeId, SequenceNumber, LeafIdentityHash, MerkleLeafHash, QueueTimestampNanos) VALUES(?,?,?,?,0)", logID, seq, rawHash, hash)
 
 	if err != nil || err2 != nil {
 		t.Fatalf("Failed to create test leaves: %v %v", err, err2)
@@ -398,6 +401,11 @@ func TestDequeueLeaves(t *testing.T) {
 			t.Fatalf("Dequeued %d leaves but expected to get %d", len(leaves2), leavesToInsert)
 		}
 		ensureAllLeavesDistinct(leaves2, t)
+		for i, leaf := range leaves2 {
+			if got, want := leaf.QueueTimestampNanos, fakeDequeueCutoffTime.UnixNano(); got != want {
+				t.Errorf("leaves2[%d].QueueTimestampNanos=%d, want %d", i, got, want)
+			}
+		}
 		commit(tx2, t)
 	}
 
@@ -606,7 +614,7 @@ func TestGetLeavesByHashNotPresent(t *testing.T) {
 	defer tx.Close()
 
 	hashes := [][]byte{[]byte("thisdoesn'texist")}
-	leaves, err := tx.GetLeavesByHash(ctx, hashes, false)
+	leaves, err := tx.GetLeavesByHash(ctx, hashes, false, 0 /* limit */, 0 /* offset */)
 	if err != nil {
 		t.Fatalf("Error getting leaves by hash: %v", err)
 	}
@@ -647,7 +655,7 @@ func TestGetLeavesByHash(t *testing.T) {
 	defer tx.Close()
 
 	hashes := [][]byte{dummyHash}
-	leaves, err := tx.GetLeavesByHash(ctx, hashes, false)
+	leaves, err := tx.GetLeavesByHash(ctx, hashes, false, 0 /* limit */, 0 /* offset */)
 	if err != nil {
 		t.Fatalf("Unexpected error getting leaf by hash: %v", err)
 	}
@@ -658,6 +666,253 @@ func TestGetLeavesByHash(t *testing.T) {
 	commit(tx, t)
 }
 
+func TestGetLeavesByHashPaged(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	// Five leaves with the same Merkle hash, as a log allowing duplicates could hold.
+	for _, seq := range []int64{3, 0, 4, 1, 2} {
+		rawHash := sha256.Sum256([]byte(fmt.Sprintf("leaf %d", seq)))
+		createFakeLeaf(ctx, DB, logID, rawHash[:], dummyHash, []byte("data"), someExtraData, seq, t)
+	}
+
+	tx := beginLogTx(s, logID, t)
+	defer tx.Close()
+
+	for _, test := range []struct {
+		limit, offset int
+		want          []int64
+	}{
+		{limit: 0, offset: 0, want: []int64{0, 1, 2, 3, 4}},
+		{limit: 2, offset: 0, want: []int64{0, 1}},
+		{limit: 2, offset: 2, want: []int64{2, 3}},
+		{limit: 2, offset: 4, want: []int64{4}},
+		{limit: 2, offset: 5, want: []int64{}},
+	} {
+		leaves, err := tx.GetLeavesByHash(ctx, [][]byte{dummyHash}, true, test.limit, test.offset)
+		if err != nil {
+			t.Fatalf("GetLeavesByHash(%v, %v): %v", test.limit, test.offset, err)
+		}
+		got := make([]int64, 0, len(leaves))
+		for _, leaf := range leaves {
+			got = append(got, leaf.LeafIndex)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GetLeavesByHash(%v, %v): indexes %v, want %v", test.limit, test.offset, got, test.want)
+		}
+	}
+	commit(tx, t)
+}
+
+func TestGetLeavesByIdentityHash(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	data := []byte("some data")
+	createFakeLeaf(ctx, DB, logID, dummyRawHash, dummyHash, data, someExtraData, sequenceNumber, t)
+	// A queued leaf has leaf data, but mustn't be returned before it's sequenced.
+	queued := createTestLeaves(1, 0)[0]
+	tx := beginLogTx(s, logID, t)
+	if _, err := tx.QueueLeaves(ctx, []*trillian.LogLeaf{queued}, fakeQueueTime); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	commit(tx, t)
+
+	tx = beginLogTx(s, logID, t)
+	defer tx.Close()
+
+	leaves, err := tx.GetLeavesByIdentityHash(ctx, [][]byte{dummyRawHash, queued.LeafIdentityHash}, 0, 0)
+	if err != nil {
+		t.Fatalf("GetLeavesByIdentityHash(): %v", err)
+	}
+	if len(leaves) != 1 {
+		t.Fatalf("Got %d leaves but expected one", len(leaves))
+	}
+	checkLeafContents(leaves[0], sequenceNumber, dummyRawHash, dummyHash, data, someExtraData, t)
+	commit(tx, t)
+}
+
+func TestGetLeavesByIdentityHashPaged(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	var ids [][]byte
+	for _, seq := range []int64{3, 0, 4, 1, 2} {
+		rawHash := sha256.Sum256([]byte(fmt.Sprintf("leaf %d", seq)))
+		createFakeLeaf(ctx, DB, logID, rawHash[:], dummyHash, []byte("data"), someExtraData, seq, t)
+		ids = append(ids, rawHash[:])
+	}
+
+	tx := beginLogTx(s, logID, t)
+	defer tx.Close()
+
+	for _, test := range []struct {
+		limit, offset int
+		want          []int64
+	}{
+		{limit: 0, offset: 0, want: []int64{0, 1, 2, 3, 4}},
+		{limit: 2, offset: 0, want: []int64{0, 1}},
+		{limit: 2, offset: 2, want: []int64{2, 3}},
+		{limit: 2, offset: 4, want: []int64{4}},
+		{limit: 2, offset: 5, want: []int64{}},
+	} {
+		leaves, err := tx.GetLeavesByIdentityHash(ctx, ids, test.limit, test.offset)
+		if err != nil {
+			t.Fatalf("GetLeavesByIdentityHash(%v, %v): %v", test.limit, test.offset, err)
+		}
+		got := make([]int64, 0, len(leaves))
+		for _, leaf := range leaves {
+			got = append(got, leaf.LeafIndex)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GetLeavesByIdentityHash(%v, %v): indexes %v, want %v", test.limit, test.offset, got, test.want)
+		}
+	}
+	commit(tx, t)
+}
+
+func TestGetOldestQueueTimestamp(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	tx := beginLogTx(s, logID, t)
+	defer tx.Close()
+	if got, err := tx.GetOldestQueueTimestamp(ctx); err != nil || !got.IsZero() {
+		t.Errorf("GetOldestQueueTimestamp() = (%v, %v), want = (zero time, nil)", got, err)
+	}
+	if _, err := tx.QueueLeaves(ctx, createTestLeaves(5, 0), fakeDequeueCutoffTime); err != nil {
+		t.Fatalf("Failed to queue leaves: %v", err)
+	}
+	if _, err := tx.QueueLeaves(ctx, createTestLeaves(5, 5), fakeDequeueCutoffTime.Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to queue leaves: %v", err)
+	}
+	got, err := tx.GetOldestQueueTimestamp(ctx)
+	if err != nil {
+		t.Fatalf("GetOldestQueueTimestamp() = (_, %v), want = (_, nil)", err)
+	}
+	if !got.Equal(fakeDequeueCutoffTime) {
+		t.Errorf("GetOldestQueueTimestamp() = (%v, _), want = (%v, _)", got, fakeDequeueCutoffTime)
+	}
+	commit(tx, t)
+}
+
+func TestAddSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	tree, err := createTree(DB, testonly.PreorderedLogTree)
+	if err != nil {
+		t.Fatalf("Failed to create pre-ordered log: %v", err)
+	}
+	s := NewLogStorage(DB, nil)
+
+	// Index 2 is left empty, so only indices 0 and 1 may be dequeued.
+	leaves := createTestLeaves(4, 0)
+	tx := beginLogTx(s, tree.TreeId, t)
+	if _, err := tx.AddSequencedLeaves(ctx, []*trillian.LogLeaf{leaves[0], leaves[1], leaves[3]}); err != nil {
+		t.Fatalf("AddSequencedLeaves(): %v", err)
+	}
+	commit(tx, t)
+
+	conflicting := *createTestLeaves(1, 10)[0]
+	conflicting.LeafIndex = 1
+	moved := *leaves[0]
+	moved.LeafIndex = 5
+	tx = beginLogTx(s, tree.TreeId, t)
+	defer tx.Close()
+	results, err := tx.AddSequencedLeaves(ctx, []*trillian.LogLeaf{leaves[0], &conflicting, &moved})
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves(): %v", err)
+	}
+	wantCodes := []codes.Code{codes.AlreadyExists, codes.FailedPrecondition, codes.FailedPrecondition}
+	if got, want := len(results), len(wantCodes); got != want {
+		t.Fatalf("AddSequencedLeaves() returned %d results, want %d", got, want)
+	}
+	for i, result := range results {
+		if got := status.FromProto(result.Status).Code(); got != wantCodes[i] {
+			t.Errorf("AddSequencedLeaves()[%d].Status: %v, want %v", i, got, wantCodes[i])
+		}
+	}
+
+	dequeued, err := tx.DequeueLeaves(ctx, 10, fakeDequeueCutoffTime)
+	if err != nil {
+		t.Fatalf("DequeueLeaves(): %v", err)
+	}
+	if got, want := len(dequeued), 2; got != want {
+		t.Fatalf("DequeueLeaves() returned %d leaves, want %d", got, want)
+	}
+	for i, leaf := range dequeued {
+		if got, want := leaf.LeafIndex, int64(i); got != want {
+			t.Errorf("DequeueLeaves()[%d].LeafIndex: %v, want %v", i, got, want)
+		}
+	}
+	commit(tx, t)
+}
+
+func TestGetLeafStatus(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	createFakeLeaf(ctx, DB, logID, dummyRawHash, dummyHash, []byte("some data"), someExtraData, sequenceNumber, t)
+	queued := createTestLeaves(1, 0)[0]
+	tx := beginLogTx(s, logID, t)
+	if _, err := tx.QueueLeaves(ctx, []*trillian.LogLeaf{queued}, fakeQueueTime); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	commit(tx, t)
+
+	for _, test := range []struct {
+		desc         string
+		identityHash []byte
+		want         storage.LeafStatus
+	}{
+		{
+			desc:         "sequenced",
+			identityHash: dummyRawHash,
+			want:         storage.LeafStatus{Status: trillian.LeafStatus_SEQUENCED, LeafIndex: sequenceNumber},
+		},
+		{
+			desc:         "queued",
+			identityHash: queued.LeafIdentityHash,
+			want:         storage.LeafStatus{Status: trillian.LeafStatus_QUEUED, QueueTimestampNanos: fakeQueueTime.UnixNano()},
+		},
+		{
+			desc:         "unknown",
+			identityHash: dummyHash2,
+			want:         storage.LeafStatus{Status: trillian.LeafStatus_UNKNOWN_LEAF_STATUS},
+		},
+	} {
+		func() {
+			tx := beginLogTx(s, logID, t)
+			defer tx.Close()
+
+			got, err := tx.GetLeafStatus(ctx, test.identityHash)
+			if err != nil {
+				t.Fatalf("%v: GetLeafStatus(): %v", test.desc, err)
+			}
+			if got != test.want {
+				t.Errorf("%v: GetLeafStatus(): %+v, want %+v", test.desc, got, test.want)
+			}
+			commit(tx, t)
+		}()
+	}
+}
+
 func TestGetLeafDataByIdentityHash(t *testing.T) {
 	ctx := context.Background()
 
@@ -745,6 +1000,48 @@ func TestGetLeavesByIndex(t *testing.T) {
 	commit(tx, t)
 }
 
+func TestGetLeavesByRange(t *testing.T) {
+	ctx := context.Background()
+
+	cleanTestDB(DB)
+	logID := createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	// Leaves 0 to 5, out of order, with a gap at 3.
+	for _, seq := range []int64{4, 0, 2, 1, 5} {
+		data := []byte(fmt.Sprintf("leaf %d", seq))
+		hash := sha256.Sum256(data)
+		createFakeLeaf(ctx, DB, logID, hash[:], hash[:], data, someExtraData, seq, t)
+	}
+
+	tx := beginLogTx(s, logID, t)
+	defer tx.Close()
+
+	for _, test := range []struct {
+		start, count int64
+		want         []int64
+	}{
+		{start: 0, count: 3, want: []int64{0, 1, 2}},
+		{start: 1, count: 1, want: []int64{1}},
+		{start: 2, count: 3, want: []int64{2, 4}},
+		{start: 4, count: 10, want: []int64{4, 5}},
+		{start: 6, count: 1, want: []int64{}},
+	} {
+		leaves, err := tx.GetLeavesByRange(ctx, test.start, test.count)
+		if err != nil {
+			t.Fatalf("GetLeavesByRange(%v, %v): %v", test.start, test.count, err)
+		}
+		got := make([]int64, 0, len(leaves))
+		for _, leaf := range leaves {
+			got = append(got, leaf.LeafIndex)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GetLeavesByRange(%v, %v): indexes %v, want %v", test.start, test.count, got, test.want)
+		}
+	}
+	commit(tx, t)
+}
+
 func TestLatestSignedRootNoneWritten(t *testing.T) {
 	ctx := context.Background()
 
@@ -1002,6 +1299,83 @@ func TestGetUnsequencedCounts(t *testing.T) {
 	}
 }
 
+func TestGetLatestSignedLogRoots(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	logID1 := createLogForTests(DB)
+	logID2 := createLogForTests(DB)
+	// The third log is never signed, so it has no root.
+	createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	var want []trillian.SignedLogRoot
+	for _, root := range []trillian.SignedLogRoot{
+		{LogId: logID1, TimestampNanos: 100, TreeSize: 1, TreeRevision: 1, RootHash: []byte(dummyHash)},
+		{LogId: logID1, TimestampNanos: 200, TreeSize: 2, TreeRevision: 2, RootHash: []byte(dummyHash2)},
+		{LogId: logID2, TimestampNanos: 150, TreeSize: 5, TreeRevision: 1, RootHash: []byte(dummyHash3)},
+	} {
+		root.Signature = &spb.DigitallySigned{Signature: []byte("notempty")}
+		tx := beginLogTx(s, root.LogId, t)
+		defer tx.Close()
+		if err := tx.StoreSignedLogRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed root: %v", err)
+		}
+		commit(tx, t)
+		if root.TimestampNanos != 100 {
+			want = append(want, root)
+		}
+	}
+
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() = (_, %v), want no error", err)
+	}
+	defer tx.Close()
+	got, err := tx.GetLatestSignedLogRoots(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestSignedLogRoots() = (_, %v), want no error", err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("GetLatestSignedLogRoots() returned %d roots, want %d", len(got), len(want))
+	}
+	for _, root := range want {
+		gotRoot := got[root.LogId]
+		if !proto.Equal(&gotRoot, &root) {
+			t.Errorf("GetLatestSignedLogRoots()[%v] = %v, want %v", root.LogId, gotRoot, root)
+		}
+	}
+	commit(tx, t)
+}
+
+func TestGetSequencedLeafEnds(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	logID1 := createLogForTests(DB)
+	logID2 := createLogForTests(DB)
+	// The third log has no sequenced leaves.
+	createLogForTests(DB)
+	s := NewLogStorage(DB, nil)
+
+	createFakeLeaf(ctx, DB, logID1, dummyHash, dummyRawHash, []byte("some data"), someExtraData, 0, t)
+	createFakeLeaf(ctx, DB, logID1, dummyHash2, dummyRawHash, []byte("some data 2"), someExtraData, 5, t)
+	createFakeLeaf(ctx, DB, logID2, dummyHash3, dummyRawHash, []byte("some data 3"), someExtraData, 2, t)
+
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() = (_, %v), want no error", err)
+	}
+	defer tx.Close()
+	got, err := tx.GetSequencedLeafEnds(ctx)
+	if err != nil {
+		t.Fatalf("GetSequencedLeafEnds() = (_, %v), want no error", err)
+	}
+	want := storage.CountByLogID{logID1: 6, logID2: 3}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("GetSequencedLeafEnds() = diff -want +got:\n%s", diff)
+	}
+	commit(tx, t)
+}
+
 func TestReadOnlyLogTX_Rollback(t *testing.T) {
 	ctx := context.Background()
 	cleanTestDB(DB)
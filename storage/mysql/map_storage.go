@@ -17,6 +17,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"sync"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle/hashers"
@@ -38,8 +39,9 @@ const (
 		 ORDER BY MapHeadTimestamp DESC LIMIT 1`
 	selectGetSignedMapRootSQL = `SELECT MapHeadTimestamp, RootHash, MapRevision, RootSignature, MapperData
 		 FROM MapHead WHERE TreeId=? AND MapRevision=?`
-	insertMapLeafSQL = `INSERT INTO MapLeaf(TreeId, KeyHash, MapRevision, LeafValue) VALUES (?, ?, ?, ?)`
-	selectMapLeafSQL = `
+	insertMapLeafSQL      = `INSERT INTO MapLeaf(TreeId, KeyHash, MapRevision, LeafValue) VALUES (?, ?, ?, ?)`
+	insertMapLeafMultiSQL = `INSERT INTO MapLeaf(TreeId, KeyHash, MapRevision, LeafValue) ` + placeholderSQL
+	selectMapLeafSQL      = `
  SELECT t1.KeyHash, t1.MapRevision, t1.LeafValue
  FROM MapLeaf t1
  INNER JOIN
@@ -53,10 +55,17 @@ const (
  ON t1.TreeId=t2.TreeId
  AND t1.KeyHash=t2.KeyHash
  AND t1.MapRevision=t2.maxrev`
+	selectMapLeafHistorySQL = `SELECT MapRevision, LeafValue FROM MapLeaf
+		 WHERE TreeId = ? AND KeyHash = ? AND MapRevision >= ? AND MapRevision <= ?
+		 ORDER BY MapRevision LIMIT ?`
 )
 
 var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
 
+// maxLeavesPerInsert bounds the rows written by each INSERT in SetBatch, which
+// keeps statements well under MySQL's limit of 65535 placeholders.
+const maxLeavesPerInsert = 1000
+
 type mySQLMapStorage struct {
 	*mySQLTreeStorage
 	admin storage.AdminStorage
@@ -100,7 +109,7 @@ func (m *mySQLMapStorage) begin(ctx context.Context, treeID int64, readonly bool
 		ctx,
 		m.admin,
 		treeID,
-		trees.GetOpts{TreeType: trillian.TreeType_MAP, Readonly: readonly})
+		trees.GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_MAP}, Readonly: readonly})
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +127,7 @@ func (m *mySQLMapStorage) begin(ctx context.Context, treeID int64, readonly bool
 	mtx := &mapTreeTX{
 		treeTX: ttx,
 		ms:     m,
+		hasher: hasher,
 	}
 
 	mtx.root, err = mtx.LatestSignedMapRoot(ctx)
@@ -139,8 +149,39 @@ func (m *mySQLMapStorage) SnapshotForTree(ctx context.Context, treeID int64) (st
 
 type mapTreeTX struct {
 	treeTX
-	ms   *mySQLMapStorage
-	root trillian.SignedMapRoot
+	ms     *mySQLMapStorage
+	hasher hashers.MapHasher
+	root   trillian.SignedMapRoot
+
+	// revCaches holds the subtree caches of revisions older than the read
+	// revision, which can't share the TX's subtree cache.
+	revCachesMu sync.Mutex
+	revCaches   map[int64]*cache.SubtreeCache
+}
+
+// GetMerkleNodes returns the nodes at (or below) treeRevision. Subtree caches
+// don't distinguish between revisions, so nodes of revisions older than the
+// read revision are read through a cache of their own.
+func (m *mapTreeTX) GetMerkleNodes(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]storage.Node, error) {
+	if treeRevision >= m.root.MapRevision {
+		return m.treeTX.GetMerkleNodes(ctx, treeRevision, nodeIDs)
+	}
+	return m.revCache(treeRevision).GetNodes(nodeIDs, m.getSubtreesAtRev(ctx, treeRevision))
+}
+
+func (m *mapTreeTX) revCache(rev int64) *cache.SubtreeCache {
+	m.revCachesMu.Lock()
+	defer m.revCachesMu.Unlock()
+	c, ok := m.revCaches[rev]
+	if !ok {
+		stCache := cache.NewMapSubtreeCache(defaultMapStrata, m.treeID, m.hasher)
+		c = &stCache
+		if m.revCaches == nil {
+			m.revCaches = make(map[int64]*cache.SubtreeCache)
+		}
+		m.revCaches[rev] = c
+	}
+	return c
 }
 
 func (m *mapTreeTX) ReadRevision() int64 {
@@ -156,9 +197,9 @@ func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapL
 	//           That way, if this attempt partially fails (i.e. because some subset of the in-the-future Merkle
 	//           nodes do get written), we can enforce that future map update attempts are a complete replay of
 	//           the failed set.
-	flatValue, err := proto.Marshal(&value)
+	flatValue, err := flattenMapLeaf(value)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	stmt, err := m.tx.PrepareContext(ctx, insertMapLeafSQL)
@@ -171,6 +212,53 @@ func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapL
 	return err
 }
 
+// SetBatch writes the leaves with multi-row INSERTs of up to
+// maxLeavesPerInsert rows each.
+func (m *mapTreeTX) SetBatch(ctx context.Context, leaves []trillian.MapLeaf) error {
+	for len(leaves) > 0 {
+		n := len(leaves)
+		if n > maxLeavesPerInsert {
+			n = maxLeavesPerInsert
+		}
+		if err := m.insertLeaves(ctx, leaves[:n]); err != nil {
+			return err
+		}
+		leaves = leaves[n:]
+	}
+	return nil
+}
+
+func (m *mapTreeTX) insertLeaves(ctx context.Context, leaves []trillian.MapLeaf) error {
+	args := make([]interface{}, 0, len(leaves)*4)
+	for _, l := range leaves {
+		flatValue, err := flattenMapLeaf(l)
+		if err != nil {
+			return err
+		}
+		args = append(args, m.treeID, l.Index, m.writeRevision, flatValue)
+	}
+
+	stmt, err := m.ms.getStmt(ctx, insertMapLeafMultiSQL, len(leaves), "VALUES(?, ?, ?, ?)", "(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	stx := m.tx.StmtContext(ctx, stmt)
+	defer stx.Close()
+
+	_, err = stx.ExecContext(ctx, args...)
+	return err
+}
+
+// flattenMapLeaf returns the LeafValue column for value.
+// Tombstones are stored as empty values, which Get skips. Any other leaf has a
+// LeafHash, so it's never flattened to an empty value.
+func flattenMapLeaf(value trillian.MapLeaf) ([]byte, error) {
+	if len(value.LeafHash) == 0 {
+		return []byte{}, nil
+	}
+	return proto.Marshal(&value)
+}
+
 // Get returns a list of map leaves indicated by indexes.
 // If an index is not found, no corresponding entry is returned.
 // Each MapLeaf.Index is overwritten with the index the leaf was found at.
@@ -228,6 +316,39 @@ func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) (
 	return ret, nil
 }
 
+func (m *mapTreeTX) GetHistory(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]storage.MapLeafRevision, error) {
+	stmt, err := m.tx.PrepareContext(ctx, selectMapLeafHistorySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, m.treeID, keyHash, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]storage.MapLeafRevision, 0)
+	for rows.Next() {
+		var mapRevision int64
+		var flatData []byte
+		if err := rows.Scan(&mapRevision, &flatData); err != nil {
+			return nil, err
+		}
+		var mapLeaf trillian.MapLeaf
+		// Tombstones have no data, and stay as leaves without a LeafHash.
+		if len(flatData) > 0 {
+			if err := proto.Unmarshal(flatData, &mapLeaf); err != nil {
+				return nil, err
+			}
+		}
+		mapLeaf.Index = keyHash
+		ret = append(ret, storage.MapLeafRevision{Revision: mapRevision, Leaf: mapLeaf})
+	}
+	return ret, rows.Err()
+}
+
 func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
 	var timestamp, mapRevision int64
 	var rootHash, rootSignatureBytes []byte
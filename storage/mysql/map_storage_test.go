@@ -17,13 +17,12 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
-	spb "github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/storage"
-	"github.com/kylelemons/godebug/pretty"
+	"github.com/google/trillian/storage/testonly"
 )
 
 func TestMySQLMapStorage_CheckDatabaseAccessible(t *testing.T) {
@@ -34,347 +33,56 @@ func TestMySQLMapStorage_CheckDatabaseAccessible(t *testing.T) {
 	}
 }
 
-func TestMapBeginSnapshot(t *testing.T) {
-	cleanTestDB(DB)
-
-	frozenMapID := createMapForTests(DB)
-	updateTree(DB, frozenMapID, func(tree *trillian.Tree) {
-		tree.TreeState = trillian.TreeState_FROZEN
-	})
-
-	activeMapID := createMapForTests(DB)
-	logID := createLogForTests(DB)
-
-	tests := []struct {
-		desc  string
-		mapID int64
-		// snapshot defines whether BeginForTree or SnapshotForTree is used for the test.
-		snapshot, wantErr bool
-	}{
-		{
-			desc:    "unknownBegin",
-			mapID:   -1,
-			wantErr: true,
-		},
-		{
-			desc:     "unknownSnapshot",
-			mapID:    -1,
-			snapshot: true,
-			wantErr:  true,
-		},
-		{
-			desc:  "activeMapBegin",
-			mapID: activeMapID,
-		},
-		{
-			desc:     "activeMapSnapshot",
-			mapID:    activeMapID,
-			snapshot: true,
-		},
-		{
-			desc:    "frozenBegin",
-			mapID:   frozenMapID,
-			wantErr: true,
-		},
-		{
-			desc:     "frozenSnapshot",
-			mapID:    frozenMapID,
-			snapshot: true,
-		},
-		{
-			desc:    "logBegin",
-			mapID:   logID,
-			wantErr: true,
-		},
-		{
-			desc:     "logSnapshot",
-			mapID:    logID,
-			snapshot: true,
-			wantErr:  true,
-		},
-	}
-
-	ctx := context.Background()
-	s := NewMapStorage(DB)
-	for _, test := range tests {
-		func() {
-			var tx rootReaderMapTX
-			var err error
-			if test.snapshot {
-				tx, err = s.SnapshotForTree(ctx, test.mapID)
-			} else {
-				tx, err = s.BeginForTree(ctx, test.mapID)
-			}
-
-			if hasErr := err != nil; hasErr != test.wantErr {
-				t.Errorf("%v: err = %q, wantErr = %v", test.desc, err, test.wantErr)
-				return
-			} else if hasErr {
-				return
-			}
-			defer tx.Close()
-
-			root, err := tx.LatestSignedMapRoot(ctx)
-			if err != nil {
-				t.Errorf("%v: LatestSignedMapRoot() returned err = %v", test.desc, err)
-			}
-			if err := tx.Commit(); err != nil {
-				t.Errorf("%v: Commit() returned err = %v", test.desc, err)
-			}
-
-			if !test.snapshot {
-				tx := tx.(storage.TreeTX)
-				if got, want := tx.WriteRevision(), root.MapRevision+1; got != want {
-					t.Errorf("%v: WriteRevision() = %v, want = %v", test.desc, got, want)
-				}
-			}
-		}()
-	}
+func TestMySQLMapStorage(t *testing.T) {
+	tester := &testonly.MapStorageTester{NewMapStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		cleanTestDB(DB)
+		return NewMapStorage(DB), NewAdminStorage(DB)
+	}}
+	tester.RunAllTests(t)
 }
 
-type rootReaderMapTX interface {
-	storage.ReadOnlyTreeTX
-	storage.MapRootReader
-}
-
-func TestMapRootUpdate(t *testing.T) {
+func TestMapSetBatch_MultipleInserts(t *testing.T) {
 	cleanTestDB(DB)
 	mapID := createMapForTests(DB)
 	s := NewMapStorage(DB)
-
 	ctx := context.Background()
 
-	for _, tc := range []struct {
-		desc string
-		root trillian.SignedMapRoot
-	}{
-		{
-			desc: "Initial root",
-			root: trillian.SignedMapRoot{
-				MapId:          mapID,
-				TimestampNanos: 98765,
-				MapRevision:    5,
-				RootHash:       []byte(dummyHash),
-				Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-			}},
-		{
-			desc: "Root update",
-			root: trillian.SignedMapRoot{
-				MapId:          mapID,
-				TimestampNanos: 98766,
-				MapRevision:    6,
-				RootHash:       []byte(dummyHash),
-				Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-			}},
-		{
-			desc: "Root with default MapperMetadata",
-			root: trillian.SignedMapRoot{
-				MapId:          mapID,
-				TimestampNanos: 98768,
-				MapRevision:    7,
-				RootHash:       []byte(dummyHash),
-				Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-				Metadata: &trillian.MapperMetadata{
-					HighestFullyCompletedSeq: 0,
-				},
-			}},
-		{
-			desc: "Root with non-default MapperMetadata",
-			root: trillian.SignedMapRoot{
-				MapId:          mapID,
-				TimestampNanos: 98769,
-				MapRevision:    8,
-				RootHash:       []byte(dummyHash),
-				Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-				Metadata: &trillian.MapperMetadata{
-					HighestFullyCompletedSeq: 1,
-				},
-			}},
-	} {
-		{
-			tx := beginMapTx(ctx, s, mapID, t)
-			defer tx.Close()
-			if err := tx.StoreSignedMapRoot(ctx, tc.root); err != nil {
-				t.Fatalf("%v: Failed to store signed map root: %v", tc.desc, err)
-			}
-			if err := tx.Commit(); err != nil {
-				t.Fatalf("%v: Failed to commit new map roots: %v", tc.desc, err)
-			}
-		}
-		{
-			tx := beginMapTx(ctx, s, mapID, t)
-			defer tx.Close()
-			root, err := tx.LatestSignedMapRoot(ctx)
-			if err != nil {
-				t.Fatalf("%v: Failed to read back new map root: %v", tc.desc, err)
-			}
-			if got, want := &root, &tc.root; !proto.Equal(got, want) {
-				t.Fatalf("%v: LatestSignedMapRoot(): %v, diff(-got, +want) \n%v", tc.desc,
-					pretty.Sprint(got), pretty.Compare(got, want))
-			}
-			commit(tx, t)
-		}
+	// Enough leaves to need more than one INSERT.
+	numLeaves := maxLeavesPerInsert + 10
+	leaves := make([]trillian.MapLeaf, 0, numLeaves)
+	indexes := make([][]byte, 0, numLeaves)
+	for i := 0; i < numLeaves; i++ {
+		index := []byte(fmt.Sprintf("Key Hash %d", i))
+		leaves = append(leaves, trillian.MapLeaf{
+			Index:     index,
+			LeafHash:  []byte(fmt.Sprintf("Hash %d", i)),
+			LeafValue: []byte(fmt.Sprintf("Value %d", i)),
+		})
+		indexes = append(indexes, index)
 	}
-}
-
-var keyHash = []byte([]byte("A Key Hash"))
-var mapLeaf = trillian.MapLeaf{
-	Index:     keyHash,
-	LeafHash:  []byte("A Hash"),
-	LeafValue: []byte("A Value"),
-	ExtraData: []byte("Some Extra Data"),
-}
-
-func TestMapSetGetRoundTrip(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	readRev := int64(1)
-	ctx := context.Background()
 	{
 		tx := beginMapTx(ctx, s, mapID, t)
 		defer tx.Close()
-		if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
-			t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
-		}
-		if err := tx.Commit(); err != nil {
-			t.Fatalf("Failed to commit: %v", err)
+		if err := tx.SetBatch(ctx, leaves); err != nil {
+			t.Fatalf("SetBatch(): %v", err)
 		}
+		commit(tx, t)
 	}
 
 	{
 		tx := beginMapTx(ctx, s, mapID, t)
 		defer tx.Close()
-		readValues, err := tx.Get(ctx, readRev, [][]byte{keyHash})
+		readValues, err := tx.Get(ctx, 1, indexes)
 		if err != nil {
-			t.Fatalf("Failed to get %v:  %v", keyHash, err)
+			t.Fatalf("Get(): %v", err)
 		}
-		if got, want := len(readValues), 1; got != want {
+		if got, want := len(readValues), numLeaves; got != want {
 			t.Fatalf("Got %d values, expected %d", got, want)
 		}
-		if got, want := &readValues[0], &mapLeaf; !proto.Equal(got, want) {
-			t.Fatalf("Read back %v, but expected %v", got, want)
-		}
 		commit(tx, t)
 	}
 }
 
-func TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-
-	{
-		tx := beginMapTx(ctx, s, mapID, t)
-		defer tx.Close()
-		if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
-			t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
-		}
-		if err := tx.Commit(); err != nil {
-			t.Fatalf("Failed to commit: %v", err)
-		}
-	}
-
-	{
-		tx := beginMapTx(ctx, s, mapID, t)
-		defer tx.Close()
-		if err := tx.Set(ctx, keyHash, mapLeaf); err == nil {
-			t.Fatalf("Unexpectedly succeeded in setting %v to %v", keyHash, mapLeaf)
-		}
-		commit(tx, t)
-	}
-}
-
-func TestMapGet0Results(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-	for _, tc := range []struct {
-		index [][]byte
-	}{
-		{index: nil}, //empty list.
-		{index: [][]byte{[]byte("This doesn't exist.")}},
-	} {
-		tx := beginMapTx(ctx, s, mapID, t)
-		defer tx.Close()
-		defer commit(tx, t)
-		readValues, err := tx.Get(ctx, 1, tc.index)
-		if err != nil {
-			t.Errorf("tx.Get(%s): %v", tc.index, err)
-			continue
-		}
-		if got, want := len(readValues), 0; got != want {
-			t.Errorf("len(tx.Get(%s)): %d, want %d", tc.index, got, want)
-		}
-	}
-}
-
-func TestMapSetGetMultipleRevisions(t *testing.T) {
-	// Write two roots for a map and make sure the one with the newest timestamp supersedes
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	tests := []struct {
-		rev  int64
-		leaf trillian.MapLeaf
-	}{
-		{0, trillian.MapLeaf{Index: keyHash, LeafHash: []byte{0}, LeafValue: []byte{0}, ExtraData: []byte{0}}},
-		{1, trillian.MapLeaf{Index: keyHash, LeafHash: []byte{1}, LeafValue: []byte{1}, ExtraData: []byte{1}}},
-		{2, trillian.MapLeaf{Index: keyHash, LeafHash: []byte{2}, LeafValue: []byte{2}, ExtraData: []byte{2}}},
-		{3, trillian.MapLeaf{Index: keyHash, LeafHash: []byte{3}, LeafValue: []byte{3}, ExtraData: []byte{3}}},
-	}
-
-	ctx := context.Background()
-	for _, tc := range tests {
-		func() {
-			// Write the current test case.
-			tx := beginMapTx(ctx, s, mapID, t)
-			defer tx.Close()
-
-			mapTX := tx.(*mapTreeTX)
-			mapTX.treeTX.writeRevision = tc.rev
-			if err := tx.Set(ctx, keyHash, tc.leaf); err != nil {
-				t.Fatalf("Failed to set %v to %v: %v", keyHash, tc.leaf, err)
-			}
-			if err := tx.Commit(); err != nil {
-				t.Fatalf("Failed to commit: %v", err)
-			}
-
-			// Read at a point in time in the future. Expect to get the latest value.
-			// Read at each point in the past. Expect to get that exact point in history.
-			for i := int64(0); i < int64(len(tests)); i++ {
-				func() {
-					expectRev := i
-					if expectRev > tc.rev {
-						expectRev = tc.rev // For future revisions, expect the current value.
-					}
-
-					tx2 := beginMapTx(ctx, s, mapID, t)
-					defer tx2.Close()
-
-					readValues, err := tx2.Get(ctx, i, [][]byte{keyHash})
-					if err != nil {
-						t.Fatalf("At i %d failed to get %v:  %v", i, keyHash, err)
-					}
-					if got, want := len(readValues), 1; got != want {
-						t.Fatalf("At i %d got %d values, expected %d", i, got, want)
-					}
-					if got, want := &readValues[0], &tests[expectRev].leaf; !proto.Equal(got, want) {
-						t.Fatalf("At i %d read back %v, but expected %v", i, got, want)
-					}
-					commit(tx2, t)
-				}()
-			}
-		}()
-	}
-}
-
 func TestGetSignedMapRootNotExist(t *testing.T) {
 	cleanTestDB(DB)
 	mapID := createMapForTests(DB)
@@ -394,126 +102,6 @@ func TestGetSignedMapRootNotExist(t *testing.T) {
 	commit(tx, t)
 }
 
-func TestLatestSignedMapRootNoneWritten(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-	tx := beginMapTx(ctx, s, mapID, t)
-	defer tx.Close()
-
-	root, err := tx.LatestSignedMapRoot(ctx)
-	if err != nil {
-		t.Fatalf("Failed to read an empty map root: %v", err)
-	}
-	if root.MapId != 0 || len(root.RootHash) != 0 || root.Signature != nil {
-		t.Fatalf("Read a root with contents when it should be empty: %v", root)
-	}
-	commit(tx, t)
-}
-
-func TestGetSignedMapRoot(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-	tx := beginMapTx(ctx, s, mapID, t)
-	defer tx.Close()
-
-	revision := int64(5)
-	root := trillian.SignedMapRoot{
-		MapId:          mapID,
-		TimestampNanos: 98765,
-		MapRevision:    revision,
-		RootHash:       []byte(dummyHash),
-		Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-	}
-	if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
-		t.Fatalf("Failed to store signed root: %v", err)
-	}
-	if err := tx.Commit(); err != nil {
-		t.Fatalf("Failed to commit new map root: %v", err)
-	}
-
-	{
-		tx2 := beginMapTx(ctx, s, mapID, t)
-		defer tx2.Close()
-		root2, err := tx2.GetSignedMapRoot(ctx, revision)
-		if err != nil {
-			t.Fatalf("Failed to get back new map root: %v", err)
-		}
-		if !proto.Equal(&root, &root2) {
-			t.Fatalf("Getting root round trip failed: <%#v> and: <%#v>", root, root2)
-		}
-		commit(tx2, t)
-	}
-}
-
-func TestLatestSignedMapRoot(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-	tx := beginMapTx(ctx, s, mapID, t)
-	defer tx.Close()
-
-	root := trillian.SignedMapRoot{
-		MapId:          mapID,
-		TimestampNanos: 98765,
-		MapRevision:    5,
-		RootHash:       []byte(dummyHash),
-		Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-	}
-	if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
-		t.Fatalf("Failed to store signed root: %v", err)
-	}
-	if err := tx.Commit(); err != nil {
-		t.Fatalf("Failed to commit new map root: %v", err)
-	}
-
-	{
-		tx2 := beginMapTx(ctx, s, mapID, t)
-		defer tx2.Close()
-		root2, err := tx2.LatestSignedMapRoot(ctx)
-		if err != nil {
-			t.Fatalf("Failed to read back new map root: %v", err)
-		}
-		if !proto.Equal(&root, &root2) {
-			t.Fatalf("Root round trip failed: <%#v> and: <%#v>", root, root2)
-		}
-		commit(tx2, t)
-	}
-}
-
-func TestDuplicateSignedMapRoot(t *testing.T) {
-	cleanTestDB(DB)
-	mapID := createMapForTests(DB)
-	s := NewMapStorage(DB)
-
-	ctx := context.Background()
-	tx := beginMapTx(ctx, s, mapID, t)
-	defer tx.Close()
-
-	root := trillian.SignedMapRoot{
-		MapId:          mapID,
-		TimestampNanos: 98765,
-		MapRevision:    5,
-		RootHash:       []byte(dummyHash),
-		Signature:      &spb.DigitallySigned{Signature: []byte("notempty")},
-	}
-	if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
-		t.Fatalf("Failed to store signed map root: %v", err)
-	}
-	// Shouldn't be able to do it again
-	if err := tx.StoreSignedMapRoot(ctx, root); err == nil {
-		t.Fatal("Allowed duplicate signed map root")
-	}
-	commit(tx, t)
-}
-
 func TestReadOnlyMapTX_Rollback(t *testing.T) {
 	cleanTestDB(DB)
 	s := NewMapStorage(DB)
@@ -9,7 +9,7 @@
 CREATE TABLE IF NOT EXISTS Trees(
   TreeId                BIGINT NOT NULL,
   TreeState             ENUM('ACTIVE', 'FROZEN', 'SOFT_DELETED', 'HARD_DELETED') NOT NULL,
-  TreeType              ENUM('LOG', 'MAP') NOT NULL,
+  TreeType              ENUM('LOG', 'MAP', 'PREORDERED_LOG') NOT NULL,
   HashStrategy          ENUM('RFC6962_SHA256', 'TEST_MAP_HASHER', 'OBJECT_RFC6962_SHA256', 'CONIKS_SHA512_256') NOT NULL,
   HashAlgorithm         ENUM('SHA256') NOT NULL,
   SignatureAlgorithm    ENUM('ECDSA', 'RSA') NOT NULL,
@@ -20,6 +20,10 @@ CREATE TABLE IF NOT EXISTS Trees(
   MaxRootDurationMillis BIGINT NOT NULL,
   PrivateKey            MEDIUMBLOB NOT NULL,
   PublicKey             MEDIUMBLOB NOT NULL,
+  -- Set when the tree is soft-deleted, NULL otherwise.
+  DeleteTimeMillis      BIGINT,
+  -- State of a soft-deleted tree before its deletion, NULL otherwise.
+  PreDeleteTreeState    ENUM('ACTIVE', 'FROZEN'),
   PRIMARY KEY(TreeId)
 );
 
@@ -29,7 +33,12 @@ CREATE TABLE IF NOT EXISTS TreeControl(
   TreeId                  BIGINT NOT NULL,
   SigningEnabled          BOOLEAN NOT NULL,
   SequencingEnabled       BOOLEAN NOT NULL,
+  -- Zero means the signer's default interval is used.
   SequenceIntervalSeconds INTEGER NOT NULL,
+  -- Zero means the signer's default maximum merge delay is used.
+  MaxMergeDelaySeconds    INTEGER NOT NULL,
+  -- Zero is the default scheduling priority.
+  SchedulingPriority      INTEGER NOT NULL,
   PRIMARY KEY(TreeId),
   FOREIGN KEY(TreeId) REFERENCES Trees(TreeId)
 );
@@ -100,6 +109,9 @@ CREATE TABLE IF NOT EXISTS SequencedLeafData(
   -- This is a MerkleLeafHash as defined by the treehasher that the log uses. For example for
   -- CT this hash will include the leaf prefix byte as well as the leaf data.
   MerkleLeafHash       VARBINARY(255) NOT NULL,
+  -- When the leaf was queued, so its merge delay can be measured once it's integrated. Zero for
+  -- leaves of PREORDERED_LOG trees, which aren't queued.
+  QueueTimestampNanos  BIGINT NOT NULL,
   PRIMARY KEY(TreeId, SequenceNumber),
   FOREIGN KEY(TreeId) REFERENCES Trees(TreeId) ON DELETE CASCADE,
   FOREIGN KEY(TreeId, LeafIdentityHash) REFERENCES LeafData(TreeId, LeafIdentityHash) ON DELETE CASCADE
@@ -118,7 +130,8 @@ CREATE TABLE IF NOT EXISTS Unsequenced(
   -- CT this hash will include the leaf prefix byte as well as the leaf data.
   MerkleLeafHash       VARBINARY(255) NOT NULL,
   QueueTimestampNanos  BIGINT NOT NULL,
-  PRIMARY KEY (TreeId, Bucket, QueueTimestampNanos, LeafIdentityHash)
+  PRIMARY KEY (TreeId, Bucket, QueueTimestampNanos, LeafIdentityHash),
+  INDEX UnsequencedLeafIdentityHashIdx(TreeId, LeafIdentityHash)
 );
 
 
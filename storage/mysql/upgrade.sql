@@ -0,0 +1,65 @@
+# MySQL / MariaDB upgrades for databases created from an earlier storage.sql.
+#
+# New databases should be created from storage.sql, which already includes
+# every change below. Existing databases need the statements added since they
+# were created applied in order.
+
+-- ---------------------------------------------
+-- Tree deletion
+-- ---------------------------------------------
+
+ALTER TABLE Trees
+  ADD COLUMN DeleteTimeMillis BIGINT AFTER PublicKey,
+  ADD COLUMN PreDeleteTreeState ENUM('ACTIVE', 'FROZEN') AFTER DeleteTimeMillis;
+
+-- ---------------------------------------------
+-- Per-tree sequence interval
+-- ---------------------------------------------
+
+-- Trees used to be created with a SequenceIntervalSeconds of 60, which was
+-- never read. Zero now means the signer's --sequencer_interval is used, so
+-- reset those rows to keep existing trees on the signer's default.
+UPDATE TreeControl SET SequenceIntervalSeconds = 0 WHERE SequenceIntervalSeconds = 60;
+
+-- ---------------------------------------------
+-- Leaf status lookups by identity hash
+-- ---------------------------------------------
+
+CREATE INDEX UnsequencedLeafIdentityHashIdx ON Unsequenced(TreeId, LeafIdentityHash);
+
+-- ---------------------------------------------
+-- Per-tree maximum merge delay
+-- ---------------------------------------------
+
+-- Zero means the signer's default maximum merge delay is used.
+ALTER TABLE TreeControl
+  ADD COLUMN MaxMergeDelaySeconds INTEGER NOT NULL DEFAULT 0 AFTER SequenceIntervalSeconds;
+ALTER TABLE TreeControl ALTER COLUMN MaxMergeDelaySeconds DROP DEFAULT;
+
+-- ---------------------------------------------
+-- Pre-ordered logs
+-- ---------------------------------------------
+
+ALTER TABLE Trees
+  MODIFY COLUMN TreeType ENUM('LOG', 'MAP', 'PREORDERED_LOG') NOT NULL;
+
+-- ---------------------------------------------
+-- Per-tree scheduling priority
+-- ---------------------------------------------
+
+-- Zero is the default scheduling priority.
+ALTER TABLE TreeControl
+  ADD COLUMN SchedulingPriority INTEGER NOT NULL DEFAULT 0 AFTER MaxMergeDelaySeconds;
+ALTER TABLE TreeControl ALTER COLUMN SchedulingPriority DROP DEFAULT;
+
+-- ---------------------------------------------
+-- Pipelined sequencing
+-- ---------------------------------------------
+
+-- Leaves are now stored at their index with the time they were queued, so the
+-- merge delay of leaves assigned indices by a pipelined signer can be measured
+-- once they're integrated. Leaves stored before the upgrade have been
+-- integrated already.
+ALTER TABLE SequencedLeafData
+  ADD COLUMN QueueTimestampNanos BIGINT NOT NULL DEFAULT 0 AFTER MerkleLeafHash;
+ALTER TABLE SequencedLeafData ALTER COLUMN QueueTimestampNanos DROP DEFAULT;
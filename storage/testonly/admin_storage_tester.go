@@ -16,8 +16,10 @@ package testonly
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -28,6 +30,7 @@ import (
 	ktestonly "github.com/google/trillian/crypto/keys/testonly"
 	"github.com/google/trillian/crypto/keyspb"
 	spb "github.com/google/trillian/crypto/sigpb"
+	"github.com/google/trillian/errors"
 	_ "github.com/google/trillian/merkle/maphasher" // TEST_MAP_HASHER
 	"github.com/google/trillian/storage"
 	ttestonly "github.com/google/trillian/testonly"
@@ -62,6 +65,24 @@ var (
 		MaxRootDuration: ptypes.DurationProto(0 * time.Millisecond),
 	}
 
+	// PreorderedLogTree is a valid, PREORDERED_LOG-type trillian.Tree for tests.
+	PreorderedLogTree = &trillian.Tree{
+		TreeState:          trillian.TreeState_ACTIVE,
+		TreeType:           trillian.TreeType_PREORDERED_LOG,
+		HashStrategy:       trillian.HashStrategy_RFC6962_SHA256,
+		HashAlgorithm:      spb.DigitallySigned_SHA256,
+		SignatureAlgorithm: spb.DigitallySigned_ECDSA,
+		DisplayName:        "Llamas Log Mirror",
+		Description:        "Mirror of a registry of publicly-owned llamas",
+		PrivateKey: mustMarshalAny(&keyspb.PrivateKey{
+			Der: ktestonly.MustMarshalPrivatePEMToDER(ttestonly.DemoPrivateKey, ttestonly.DemoPrivateKeyPass),
+		}),
+		PublicKey: &keyspb.PublicKey{
+			Der: ktestonly.MustMarshalPublicPEMToDER(ttestonly.DemoPublicKey),
+		},
+		MaxRootDuration: ptypes.DurationProto(0 * time.Millisecond),
+	}
+
 	// MapTree is a valid, MAP-type trillian.Tree for tests.
 	MapTree = &trillian.Tree{
 		TreeState:          trillian.TreeState_ACTIVE,
@@ -86,14 +107,24 @@ type AdminStorageTester struct {
 	// NewAdminStorage returns an AdminStorage instance pointing to a clean
 	// test database.
 	NewAdminStorage func() storage.AdminStorage
+	// LogStorage returns a LogStorage for the trees of the AdminStorage last
+	// returned by NewAdminStorage. Tests that store log data are skipped if
+	// it isn't set.
+	LogStorage func() storage.LogStorage
 }
 
 // RunAllTests runs all AdminStorage tests.
 func (tester *AdminStorageTester) RunAllTests(t *testing.T) {
 	t.Run("TestCreateTree", tester.TestCreateTree)
 	t.Run("TestUpdateTree", tester.TestUpdateTree)
+	t.Run("TestFreezeLog", tester.TestFreezeLog)
 	t.Run("TestListTrees", tester.TestListTrees)
+	t.Run("TestListTreesOptions", tester.TestListTreesOptions)
 	t.Run("TestAdminTXClose", tester.TestAdminTXClose)
+	t.Run("TestSoftDeleteTree", tester.TestSoftDeleteTree)
+	t.Run("TestUndeleteTree", tester.TestUndeleteTree)
+	t.Run("TestHardDeleteTree", tester.TestHardDeleteTree)
+	t.Run("TestDeleteTreeLifecycle", tester.TestDeleteTreeLifecycle)
 }
 
 // TestCreateTree tests AdminStorage Tree creation.
@@ -110,6 +141,13 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 	validTreeWithoutOptionals.DisplayName = ""
 	validTreeWithoutOptionals.Description = ""
 
+	validTreeWithControls := *LogTree
+	validTreeWithControls.SigningDisabled = true
+	validTreeWithControls.SequencingDisabled = true
+	validTreeWithControls.SequenceInterval = ptypes.DurationProto(30 * time.Second)
+	validTreeWithControls.MaxMergeDelay = ptypes.DurationProto(24 * time.Hour)
+	validTreeWithControls.SchedulingPriority = 2
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -132,6 +170,10 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 			desc: "validTreeWithoutOptionals",
 			tree: &validTreeWithoutOptionals,
 		},
+		{
+			desc: "validTreeWithControls",
+			tree: &validTreeWithControls,
+		},
 	}
 
 	ctx := context.Background()
@@ -225,6 +267,19 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 	validLogWithoutOptionals := referenceLog
 	validLogWithoutOptionalsFunc(&validLogWithoutOptionals)
 
+	validLogControlsFunc := func(t *trillian.Tree) {
+		t.SequencingDisabled = true
+		t.SequenceInterval = ptypes.DurationProto(2 * time.Minute)
+		t.MaxMergeDelay = ptypes.DurationProto(time.Hour)
+		t.SchedulingPriority = 5
+	}
+	validLogControls := referenceLog
+	validLogControlsFunc(&validLogControls)
+
+	referencePreorderedLog := *PreorderedLogTree
+	validPreorderedLog := referencePreorderedLog
+	validLogFunc(&validPreorderedLog)
+
 	invalidLogFunc := func(t *trillian.Tree) {
 		t.TreeState = trillian.TreeState_UNKNOWN_TREE_STATE
 	}
@@ -263,6 +318,18 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 			updateFunc: validLogWithoutOptionalsFunc,
 			want:       &validLogWithoutOptionals,
 		},
+		{
+			desc:       "validLogControls",
+			create:     &referenceLog,
+			updateFunc: validLogControlsFunc,
+			want:       &validLogControls,
+		},
+		{
+			desc:       "validPreorderedLog",
+			create:     &referencePreorderedLog,
+			updateFunc: validLogFunc,
+			want:       &validPreorderedLog,
+		},
 		{
 			desc:       "invalidLog",
 			create:     &referenceLog,
@@ -347,6 +414,98 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 	}
 }
 
+// TestFreezeLog tests that logs may only be frozen once every leaf they
+// accepted is covered by their latest signed root.
+func (tester *AdminStorageTester) TestFreezeLog(t *testing.T) {
+	if tester.LogStorage == nil {
+		t.Skip("LogStorage not set")
+	}
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+	ls := tester.LogStorage()
+
+	freeze := func(tree *trillian.Tree) { tree.TreeState = trillian.TreeState_FROZEN }
+	hash := sha256.Sum256([]byte("leaf"))
+	leaf := &trillian.LogLeaf{LeafIdentityHash: hash[:], MerkleLeafHash: hash[:], LeafValue: []byte("leaf")}
+	root := trillian.SignedLogRoot{TimestampNanos: 1, TreeSize: 1, RootHash: hash[:], Signature: &spb.DigitallySigned{}}
+
+	tests := []struct {
+		desc string
+		tree *trillian.Tree
+		// addLeaf stores leaf in the tree, either queued or at index 0.
+		addLeaf func(storage.LogTreeTX) error
+		// integrate makes leaf part of the tree's latest signed root.
+		integrate func(storage.LogTreeTX) error
+	}{
+		{
+			desc: "log",
+			tree: LogTree,
+			addLeaf: func(tx storage.LogTreeTX) error {
+				_, err := tx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, time.Now())
+				return err
+			},
+			integrate: func(tx storage.LogTreeTX) error {
+				leaves, err := tx.DequeueLeaves(ctx, 1, time.Now())
+				if err != nil {
+					return err
+				}
+				if err := tx.UpdateSequencedLeaves(ctx, leaves); err != nil {
+					return err
+				}
+				return tx.StoreSignedLogRoot(ctx, root)
+			},
+		},
+		{
+			desc: "preorderedLog",
+			tree: PreorderedLogTree,
+			addLeaf: func(tx storage.LogTreeTX) error {
+				_, err := tx.AddSequencedLeaves(ctx, []*trillian.LogLeaf{leaf})
+				return err
+			},
+			integrate: func(tx storage.LogTreeTX) error {
+				return tx.StoreSignedLogRoot(ctx, root)
+			},
+		},
+	}
+	for _, test := range tests {
+		tree, err := createTree(ctx, s, test.tree)
+		if err != nil {
+			t.Fatalf("%v: createTree() = (_, %v), want = (_, nil)", test.desc, err)
+		}
+		if err := runLogTX(ctx, ls, tree.TreeId, test.addLeaf); err != nil {
+			t.Fatalf("%v: failed to add leaf: %v", test.desc, err)
+		}
+		if _, _, err := updateTree(ctx, s, tree.TreeId, freeze); errors.ErrorCode(err) != errors.FailedPrecondition {
+			t.Errorf("%v: updateTree() with a pending leaf = (_, _, %v), want code %s", test.desc, err, errors.FailedPrecondition)
+		}
+
+		if err := runLogTX(ctx, ls, tree.TreeId, test.integrate); err != nil {
+			t.Fatalf("%v: failed to integrate leaf: %v", test.desc, err)
+		}
+		frozen, _, err := updateTree(ctx, s, tree.TreeId, freeze)
+		if err != nil {
+			t.Errorf("%v: updateTree() with integrated leaves = (_, _, %v), want = (_, _, nil)", test.desc, err)
+			continue
+		}
+		if got, want := frozen.TreeState, trillian.TreeState_FROZEN; got != want {
+			t.Errorf("%v: TreeState = %s, want = %s", test.desc, got, want)
+		}
+	}
+}
+
+// runLogTX runs f in a read-write TX of the specified log, and commits it.
+func runLogTX(ctx context.Context, ls storage.LogStorage, treeID int64, f func(storage.LogTreeTX) error) error {
+	tx, err := ls.BeginForTree(ctx, treeID)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func createTree(ctx context.Context, s storage.AdminStorage, tree *trillian.Tree) (*trillian.Tree, error) {
 	tx, err := s.Begin(ctx)
 	if err != nil {
@@ -469,7 +628,7 @@ func runListTreeIDsTest(ctx context.Context, tx storage.ReadOnlyAdminTX, wantTre
 }
 
 func runListTreesTest(ctx context.Context, tx storage.ReadOnlyAdminTX, wantTrees []*trillian.Tree) error {
-	trees, err := tx.ListTrees(ctx)
+	trees, err := tx.ListTrees(ctx, storage.ListTreesOptions{})
 	if err != nil {
 		return fmt.Errorf("ListTrees() = (_, %v), want = (_, nil)", err)
 	}
@@ -499,6 +658,151 @@ func runListTreesTest(ctx context.Context, tx storage.ReadOnlyAdminTX, wantTrees
 	return nil
 }
 
+// TestListTreesOptions tests the filtering and pagination options of
+// ListTrees.
+func (tester *AdminStorageTester) TestListTreesOptions(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	newTree := func(tree *trillian.Tree) int64 {
+		created, err := createTree(ctx, s, tree)
+		if err != nil {
+			t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+		}
+		return created.TreeId
+	}
+	activeLog := newTree(LogTree)
+	activeMap := newTree(MapTree)
+	frozenLog := newTree(LogTree)
+	if _, _, err := updateTree(ctx, s, frozenLog, func(tree *trillian.Tree) {
+		tree.TreeState = trillian.TreeState_FROZEN
+	}); err != nil {
+		t.Fatalf("updateTree() = (_, _, %v), want = (_, _, nil)", err)
+	}
+	softDeletedLog := newTree(LogTree)
+	if _, err := softDeleteTree(ctx, s, softDeletedLog); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+	hardDeletedMap := newTree(MapTree)
+	if _, err := softDeleteTree(ctx, s, hardDeletedMap); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+	if err := hardDeleteTree(ctx, s, hardDeletedMap); err != nil {
+		t.Fatalf("hardDeleteTree() = %v, want = nil", err)
+	}
+
+	tests := []struct {
+		desc    string
+		opts    storage.ListTreesOptions
+		wantIDs []int64
+	}{
+		{
+			desc:    "default",
+			wantIDs: []int64{activeLog, activeMap, frozenLog},
+		},
+		{
+			desc:    "showDeleted",
+			opts:    storage.ListTreesOptions{ShowDeleted: true},
+			wantIDs: []int64{activeLog, activeMap, frozenLog, softDeletedLog, hardDeletedMap},
+		},
+		{
+			desc:    "logs",
+			opts:    storage.ListTreesOptions{TreeType: trillian.TreeType_LOG},
+			wantIDs: []int64{activeLog, frozenLog},
+		},
+		{
+			desc:    "mapsShowDeleted",
+			opts:    storage.ListTreesOptions{TreeType: trillian.TreeType_MAP, ShowDeleted: true},
+			wantIDs: []int64{activeMap, hardDeletedMap},
+		},
+		{
+			desc:    "frozen",
+			opts:    storage.ListTreesOptions{TreeState: trillian.TreeState_FROZEN},
+			wantIDs: []int64{frozenLog},
+		},
+		{
+			desc:    "softDeleted",
+			opts:    storage.ListTreesOptions{TreeState: trillian.TreeState_SOFT_DELETED},
+			wantIDs: []int64{},
+		},
+		{
+			desc:    "softDeletedShowDeleted",
+			opts:    storage.ListTreesOptions{TreeState: trillian.TreeState_SOFT_DELETED, ShowDeleted: true},
+			wantIDs: []int64{softDeletedLog},
+		},
+		{
+			desc:    "softDeletedMaps",
+			opts:    storage.ListTreesOptions{TreeType: trillian.TreeType_MAP, TreeState: trillian.TreeState_SOFT_DELETED, ShowDeleted: true},
+			wantIDs: []int64{},
+		},
+	}
+	for _, test := range tests {
+		trees, err := listTrees(ctx, s, test.opts)
+		if err != nil {
+			t.Errorf("%v: listTrees() = (_, %v), want = (_, nil)", test.desc, err)
+			continue
+		}
+		sortInt64s(test.wantIDs)
+		if diff := pretty.Compare(toIDs(trees), test.wantIDs); diff != "" {
+			t.Errorf("%v: post-listTrees() diff (-got +want):\n%v", test.desc, diff)
+		}
+	}
+
+	// Page through all trees, checking that they're returned in order and
+	// without repetition.
+	wantIDs := []int64{activeLog, activeMap, frozenLog, softDeletedLog, hardDeletedMap}
+	sortInt64s(wantIDs)
+	for _, pageSize := range []int{1, 2, 5, 6} {
+		opts := storage.ListTreesOptions{PageSize: pageSize, ShowDeleted: true}
+		gotIDs := []int64{}
+		for {
+			trees, err := listTrees(ctx, s, opts)
+			if err != nil {
+				t.Fatalf("pageSize = %v: listTrees() = (_, %v), want = (_, nil)", pageSize, err)
+			}
+			if len(trees) > pageSize {
+				t.Errorf("pageSize = %v: got %v trees, want <= %v", pageSize, len(trees), pageSize)
+			}
+			if len(trees) == 0 {
+				break
+			}
+			gotIDs = append(gotIDs, toIDs(trees)...)
+			opts.AfterTreeID = trees[len(trees)-1].TreeId
+		}
+		if diff := pretty.Compare(gotIDs, wantIDs); diff != "" {
+			t.Errorf("pageSize = %v: paginated IDs diff (-got +want):\n%v", pageSize, diff)
+		}
+	}
+}
+
+func listTrees(ctx context.Context, s storage.AdminStorage, opts storage.ListTreesOptions) ([]*trillian.Tree, error) {
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	trees, err := tx.ListTrees(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+func toIDs(trees []*trillian.Tree) []int64 {
+	ids := []int64{}
+	for _, tree := range trees {
+		ids = append(ids, tree.TreeId)
+	}
+	return ids
+}
+
+func sortInt64s(s []int64) {
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+}
+
 func toIntMap(values []int64) map[int64]bool {
 	m := make(map[int64]bool)
 	for _, v := range values {
@@ -579,3 +883,343 @@ func (tester *AdminStorageTester) TestAdminTXClose(t *testing.T) {
 		}()
 	}
 }
+
+// TestSoftDeleteTree tests AdminStorage soft-deletion of trees.
+func (tester *AdminStorageTester) TestSoftDeleteTree(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	activeLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	frozenMap, err := createTree(ctx, s, MapTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	frozenMap, _, err = updateTree(ctx, s, frozenMap.TreeId, func(tree *trillian.Tree) {
+		tree.TreeState = trillian.TreeState_FROZEN
+	})
+	if err != nil {
+		t.Fatalf("updateTree() = (_, _, %v), want = (_, _, nil)", err)
+	}
+	deletedLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := softDeleteTree(ctx, s, deletedLog.TreeId); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+
+	tests := []struct {
+		desc    string
+		treeID  int64
+		wantErr bool
+	}{
+		{desc: "activeTree", treeID: activeLog.TreeId},
+		{desc: "frozenTree", treeID: frozenMap.TreeId},
+		{desc: "alreadyDeleted", treeID: deletedLog.TreeId, wantErr: true},
+		{desc: "unknownTree", treeID: -1, wantErr: true},
+	}
+	for _, test := range tests {
+		deletedTree, err := softDeleteTree(ctx, s, test.treeID)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: softDeleteTree() = (_, %v), wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+
+		if got, want := deletedTree.TreeState, trillian.TreeState_SOFT_DELETED; got != want {
+			t.Errorf("%v: TreeState = %s, want = %s", test.desc, got, want)
+		}
+		if err := checkDeleteTime(deletedTree); err != nil {
+			t.Errorf("%v: %v", test.desc, err)
+		}
+
+		if storedTree, err := getTree(ctx, s, test.treeID); err != nil {
+			t.Errorf("%v: getTree() = (_, %v), want = (_, nil)", test.desc, err)
+		} else if !proto.Equal(storedTree, deletedTree) {
+			diff := pretty.Compare(storedTree, deletedTree)
+			t.Errorf("%v: storedTree doesn't match deletedTree:\n%s", test.desc, diff)
+		}
+	}
+}
+
+// TestUndeleteTree tests AdminStorage undeletion of trees.
+func (tester *AdminStorageTester) TestUndeleteTree(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	activeLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	deletedLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := softDeleteTree(ctx, s, deletedLog.TreeId); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+	frozenLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, _, err := updateTree(ctx, s, frozenLog.TreeId, func(tree *trillian.Tree) {
+		tree.TreeState = trillian.TreeState_FROZEN
+	}); err != nil {
+		t.Fatalf("updateTree() = (_, _, %v), want = (_, _, nil)", err)
+	}
+	if _, err := softDeleteTree(ctx, s, frozenLog.TreeId); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+
+	tests := []struct {
+		desc      string
+		treeID    int64
+		wantState trillian.TreeState
+		wantErr   bool
+	}{
+		{desc: "deletedTree", treeID: deletedLog.TreeId, wantState: trillian.TreeState_ACTIVE},
+		{desc: "deletedFrozenTree", treeID: frozenLog.TreeId, wantState: trillian.TreeState_FROZEN},
+		{desc: "activeTree", treeID: activeLog.TreeId, wantErr: true},
+		{desc: "unknownTree", treeID: -1, wantErr: true},
+	}
+	for _, test := range tests {
+		undeletedTree, err := undeleteTree(ctx, s, test.treeID)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: undeleteTree() = (_, %v), wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+
+		if got, want := undeletedTree.TreeState, test.wantState; got != want {
+			t.Errorf("%v: TreeState = %s, want = %s", test.desc, got, want)
+		}
+		if undeletedTree.DeleteTime != nil {
+			t.Errorf("%v: DeleteTime = %v, want = nil", test.desc, undeletedTree.DeleteTime)
+		}
+
+		if storedTree, err := getTree(ctx, s, test.treeID); err != nil {
+			t.Errorf("%v: getTree() = (_, %v), want = (_, nil)", test.desc, err)
+		} else if !proto.Equal(storedTree, undeletedTree) {
+			diff := pretty.Compare(storedTree, undeletedTree)
+			t.Errorf("%v: storedTree doesn't match undeletedTree:\n%s", test.desc, diff)
+		}
+	}
+}
+
+// TestHardDeleteTree tests AdminStorage hard-deletion of trees.
+func (tester *AdminStorageTester) TestHardDeleteTree(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	activeLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	deletedLog, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := softDeleteTree(ctx, s, deletedLog.TreeId); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+	deletedMap, err := createTree(ctx, s, MapTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, err := softDeleteTree(ctx, s, deletedMap.TreeId); err != nil {
+		t.Fatalf("softDeleteTree() = (_, %v), want = (_, nil)", err)
+	}
+
+	tests := []struct {
+		desc    string
+		treeID  int64
+		wantErr bool
+	}{
+		{desc: "deletedLog", treeID: deletedLog.TreeId},
+		{desc: "deletedMap", treeID: deletedMap.TreeId},
+		{desc: "alreadyHardDeleted", treeID: deletedLog.TreeId, wantErr: true},
+		{desc: "activeTree", treeID: activeLog.TreeId, wantErr: true},
+		{desc: "unknownTree", treeID: -1, wantErr: true},
+	}
+	for _, test := range tests {
+		err := hardDeleteTree(ctx, s, test.treeID)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("%v: hardDeleteTree() = %v, wantErr = %v", test.desc, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+
+		// Hard-deleted trees remain in storage, so their IDs can't be reused.
+		storedTree, err := getTree(ctx, s, test.treeID)
+		if err != nil {
+			t.Errorf("%v: getTree() = (_, %v), want = (_, nil)", test.desc, err)
+			continue
+		}
+		if got, want := storedTree.TreeState, trillian.TreeState_HARD_DELETED; got != want {
+			t.Errorf("%v: TreeState = %s, want = %s", test.desc, got, want)
+		}
+		if err := checkDeleteTime(storedTree); err != nil {
+			t.Errorf("%v: %v", test.desc, err)
+		}
+		if _, err := undeleteTree(ctx, s, test.treeID); err == nil {
+			t.Errorf("%v: undeleteTree() returned err = nil, want non-nil", test.desc)
+		}
+	}
+}
+
+// TestDeleteTreeLifecycle tests a tree going through all deletion states,
+// checking that the tree's readonly fields are preserved throughout.
+func (tester *AdminStorageTester) TestDeleteTreeLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	createdTree, err := createTree(ctx, s, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	treeID := createdTree.TreeId
+
+	steps := []struct {
+		desc      string
+		fn        func() (*trillian.Tree, error)
+		wantState trillian.TreeState
+	}{
+		{
+			desc:      "softDelete",
+			fn:        func() (*trillian.Tree, error) { return softDeleteTree(ctx, s, treeID) },
+			wantState: trillian.TreeState_SOFT_DELETED,
+		},
+		{
+			desc:      "undelete",
+			fn:        func() (*trillian.Tree, error) { return undeleteTree(ctx, s, treeID) },
+			wantState: trillian.TreeState_ACTIVE,
+		},
+		{
+			desc:      "softDeleteAgain",
+			fn:        func() (*trillian.Tree, error) { return softDeleteTree(ctx, s, treeID) },
+			wantState: trillian.TreeState_SOFT_DELETED,
+		},
+		{
+			desc: "hardDelete",
+			fn: func() (*trillian.Tree, error) {
+				if err := hardDeleteTree(ctx, s, treeID); err != nil {
+					return nil, err
+				}
+				return getTree(ctx, s, treeID)
+			},
+			wantState: trillian.TreeState_HARD_DELETED,
+		},
+	}
+	for _, step := range steps {
+		tree, err := step.fn()
+		if err != nil {
+			t.Fatalf("%v: err = %v, want = nil", step.desc, err)
+		}
+		if got, want := tree.TreeState, step.wantState; got != want {
+			t.Errorf("%v: TreeState = %s, want = %s", step.desc, got, want)
+		}
+
+		// Apart from the fields changed by deletion, the tree must be unchanged.
+		wantTree := *createdTree
+		wantTree.TreeState = tree.TreeState
+		wantTree.UpdateTime = tree.UpdateTime
+		wantTree.DeleteTime = tree.DeleteTime
+		if !proto.Equal(tree, &wantTree) {
+			diff := pretty.Compare(tree, &wantTree)
+			t.Errorf("%v: tree diff:\n%s", step.desc, diff)
+		}
+	}
+
+	ids, err := listTreeIDs(ctx, s)
+	if err != nil {
+		t.Fatalf("listTreeIDs() = (_, %v), want = (_, nil)", err)
+	}
+	if !toIntMap(ids)[treeID] {
+		t.Errorf("listTreeIDs() = %v, want hard-deleted tree %v included", ids, treeID)
+	}
+}
+
+// checkDeleteTime returns an error if tree doesn't have a valid delete_time.
+func checkDeleteTime(tree *trillian.Tree) error {
+	if tree.DeleteTime == nil {
+		return fmt.Errorf("DeleteTime = nil, want non-nil")
+	}
+	deleteTime, err := ptypes.Timestamp(tree.DeleteTime)
+	if err != nil {
+		return fmt.Errorf("DeleteTime malformed: %v", err)
+	}
+	createTime, err := ptypes.Timestamp(tree.CreateTime)
+	if err != nil {
+		return fmt.Errorf("CreateTime malformed: %v", err)
+	}
+	if deleteTime.Before(createTime) {
+		return fmt.Errorf("DeleteTime = %v, want >= %v", tree.DeleteTime, tree.CreateTime)
+	}
+	return nil
+}
+
+func softDeleteTree(ctx context.Context, s storage.AdminStorage, treeID int64) (*trillian.Tree, error) {
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	tree, err := tx.SoftDeleteTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func undeleteTree(ctx context.Context, s storage.AdminStorage, treeID int64) (*trillian.Tree, error) {
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	tree, err := tx.UndeleteTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func hardDeleteTree(ctx context.Context, s storage.AdminStorage, treeID int64) error {
+	tx, err := s.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+	if err := tx.HardDeleteTree(ctx, treeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func listTreeIDs(ctx context.Context, s storage.AdminStorage) ([]int64, error) {
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	ids, err := tx.ListTreeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,613 @@
+// Copyright 2017 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testonly
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	spb "github.com/google/trillian/crypto/sigpb"
+	"github.com/google/trillian/storage"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+var (
+	mapKeyHash = []byte("A Key Hash")
+	mapLeaf    = trillian.MapLeaf{
+		Index:     mapKeyHash,
+		LeafHash:  []byte("A Hash"),
+		LeafValue: []byte("A Value"),
+		ExtraData: []byte("Some Extra Data"),
+	}
+	mapRootHash  = []byte("hashxxxxhashxxxxhashxxxxhashxxxx")
+	mapSignature = &spb.DigitallySigned{Signature: []byte("notempty")}
+)
+
+// MapStorageTester runs a suite of tests against MapStorage implementations.
+type MapStorageTester struct {
+	// NewMapStorage returns a MapStorage instance pointing to a clean test
+	// database, along with an AdminStorage instance for the same database.
+	NewMapStorage func() (storage.MapStorage, storage.AdminStorage)
+}
+
+// RunAllTests runs all MapStorage tests.
+func (tester *MapStorageTester) RunAllTests(t *testing.T) {
+	t.Run("TestMapBeginSnapshot", tester.TestMapBeginSnapshot)
+	t.Run("TestMapRootUpdate", tester.TestMapRootUpdate)
+	t.Run("TestMapSetGetRoundTrip", tester.TestMapSetGetRoundTrip)
+	t.Run("TestMapSetSameKeyInSameRevisionFails", tester.TestMapSetSameKeyInSameRevisionFails)
+	t.Run("TestMapSetBatch", tester.TestMapSetBatch)
+	t.Run("TestMapGet0Results", tester.TestMapGet0Results)
+	t.Run("TestMapSetGetMultipleRevisions", tester.TestMapSetGetMultipleRevisions)
+	t.Run("TestMapSetDeletesLeaf", tester.TestMapSetDeletesLeaf)
+	t.Run("TestMapGetHistory", tester.TestMapGetHistory)
+	t.Run("TestLatestSignedMapRootNoneWritten", tester.TestLatestSignedMapRootNoneWritten)
+	t.Run("TestLatestSignedMapRoot", tester.TestLatestSignedMapRoot)
+	t.Run("TestGetSignedMapRoot", tester.TestGetSignedMapRoot)
+	t.Run("TestDuplicateSignedMapRoot", tester.TestDuplicateSignedMapRoot)
+}
+
+// newMap returns a clean MapStorage containing a single map, along with the
+// map's ID.
+func (tester *MapStorageTester) newMap(ctx context.Context, t *testing.T) (storage.MapStorage, int64) {
+	s, as := tester.NewMapStorage()
+	tree, err := createTree(ctx, as, MapTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	return s, tree.TreeId
+}
+
+// TestMapBeginSnapshot tests that map TXs can only be started for the right
+// trees.
+func (tester *MapStorageTester) TestMapBeginSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewMapStorage()
+
+	activeMap, err := createTree(ctx, as, MapTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	frozenMap, err := createTree(ctx, as, MapTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+	if _, _, err := updateTree(ctx, as, frozenMap.TreeId, func(tree *trillian.Tree) {
+		tree.TreeState = trillian.TreeState_FROZEN
+	}); err != nil {
+		t.Fatalf("updateTree() = (_, _, %v), want = (_, _, nil)", err)
+	}
+	log, err := createTree(ctx, as, LogTree)
+	if err != nil {
+		t.Fatalf("createTree() = (_, %v), want = (_, nil)", err)
+	}
+
+	tests := []struct {
+		desc  string
+		mapID int64
+		// snapshot defines whether BeginForTree or SnapshotForTree is used for the test.
+		snapshot, wantErr bool
+	}{
+		{desc: "unknownBegin", mapID: -1, wantErr: true},
+		{desc: "unknownSnapshot", mapID: -1, snapshot: true, wantErr: true},
+		{desc: "activeMapBegin", mapID: activeMap.TreeId},
+		{desc: "activeMapSnapshot", mapID: activeMap.TreeId, snapshot: true},
+		{desc: "frozenBegin", mapID: frozenMap.TreeId, wantErr: true},
+		{desc: "frozenSnapshot", mapID: frozenMap.TreeId, snapshot: true},
+		{desc: "logBegin", mapID: log.TreeId, wantErr: true},
+		{desc: "logSnapshot", mapID: log.TreeId, snapshot: true, wantErr: true},
+	}
+	for _, test := range tests {
+		func() {
+			var tx storage.ReadOnlyMapTreeTX
+			var err error
+			if test.snapshot {
+				tx, err = s.SnapshotForTree(ctx, test.mapID)
+			} else {
+				tx, err = s.BeginForTree(ctx, test.mapID)
+			}
+
+			if hasErr := err != nil; hasErr != test.wantErr {
+				t.Errorf("%v: err = %q, wantErr = %v", test.desc, err, test.wantErr)
+				return
+			} else if hasErr {
+				return
+			}
+			defer tx.Close()
+
+			root, err := tx.LatestSignedMapRoot(ctx)
+			if err != nil {
+				t.Errorf("%v: LatestSignedMapRoot() returned err = %v", test.desc, err)
+			}
+			if err := tx.Commit(); err != nil {
+				t.Errorf("%v: Commit() returned err = %v", test.desc, err)
+			}
+
+			if !test.snapshot {
+				tx := tx.(storage.TreeTX)
+				if got, want := tx.WriteRevision(), root.MapRevision+1; got != want {
+					t.Errorf("%v: WriteRevision() = %v, want = %v", test.desc, got, want)
+				}
+			}
+		}()
+	}
+}
+
+// TestMapRootUpdate tests that stored roots are returned by
+// LatestSignedMapRoot, and advance the write revision.
+func (tester *MapStorageTester) TestMapRootUpdate(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	for _, tc := range []struct {
+		desc string
+		root trillian.SignedMapRoot
+	}{
+		{
+			desc: "Initial root",
+			root: trillian.SignedMapRoot{
+				MapId:          mapID,
+				TimestampNanos: 98765,
+				MapRevision:    5,
+				RootHash:       mapRootHash,
+				Signature:      mapSignature,
+			}},
+		{
+			desc: "Root update",
+			root: trillian.SignedMapRoot{
+				MapId:          mapID,
+				TimestampNanos: 98766,
+				MapRevision:    6,
+				RootHash:       mapRootHash,
+				Signature:      mapSignature,
+			}},
+		{
+			desc: "Root with default MapperMetadata",
+			root: trillian.SignedMapRoot{
+				MapId:          mapID,
+				TimestampNanos: 98768,
+				MapRevision:    7,
+				RootHash:       mapRootHash,
+				Signature:      mapSignature,
+				Metadata: &trillian.MapperMetadata{
+					HighestFullyCompletedSeq: 0,
+				},
+			}},
+		{
+			desc: "Root with non-default MapperMetadata",
+			root: trillian.SignedMapRoot{
+				MapId:          mapID,
+				TimestampNanos: 98769,
+				MapRevision:    8,
+				RootHash:       mapRootHash,
+				Signature:      mapSignature,
+				Metadata: &trillian.MapperMetadata{
+					HighestFullyCompletedSeq: 1,
+				},
+			}},
+	} {
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			if err := tx.StoreSignedMapRoot(ctx, tc.root); err != nil {
+				t.Fatalf("%v: Failed to store signed map root: %v", tc.desc, err)
+			}
+			commitTX(t, tx)
+		}()
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			root, err := tx.LatestSignedMapRoot(ctx)
+			if err != nil {
+				t.Fatalf("%v: Failed to read back new map root: %v", tc.desc, err)
+			}
+			if got, want := &root, &tc.root; !proto.Equal(got, want) {
+				t.Fatalf("%v: LatestSignedMapRoot(): %v, diff(-got, +want) \n%v", tc.desc,
+					pretty.Sprint(got), pretty.Compare(got, want))
+			}
+			if got, want := tx.WriteRevision(), tc.root.MapRevision+1; got != want {
+				t.Errorf("%v: WriteRevision(): %v, want %v", tc.desc, got, want)
+			}
+			commitTX(t, tx)
+		}()
+	}
+}
+
+// TestMapSetGetRoundTrip tests that a leaf can be read back once written.
+func (tester *MapStorageTester) TestMapSetGetRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	rev := setMapLeaves(ctx, t, s, mapID, mapLeaf)
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	readValues, err := tx.Get(ctx, rev, [][]byte{mapKeyHash})
+	if err != nil {
+		t.Fatalf("Failed to get %v: %v", mapKeyHash, err)
+	}
+	if got, want := len(readValues), 1; got != want {
+		t.Fatalf("Got %d values, expected %d", got, want)
+	}
+	if got, want := &readValues[0], &mapLeaf; !proto.Equal(got, want) {
+		t.Fatalf("Read back %v, but expected %v", got, want)
+	}
+	commitTX(t, tx)
+}
+
+// TestMapSetSameKeyInSameRevisionFails tests that a leaf can only be written
+// once per revision.
+func (tester *MapStorageTester) TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	func() {
+		tx := beginMapTX(ctx, t, s, mapID)
+		defer tx.Close()
+		if err := tx.Set(ctx, mapKeyHash, mapLeaf); err != nil {
+			t.Fatalf("Failed to set %v to %v: %v", mapKeyHash, mapLeaf, err)
+		}
+		commitTX(t, tx)
+	}()
+
+	// No root was stored, so the TX writes the same revision again.
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	if err := tx.Set(ctx, mapKeyHash, mapLeaf); err == nil {
+		t.Fatalf("Unexpectedly succeeded in setting %v to %v", mapKeyHash, mapLeaf)
+	}
+}
+
+// TestMapSetBatch tests that SetBatch writes all leaves, including
+// tombstones.
+func (tester *MapStorageTester) TestMapSetBatch(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	leaves := []trillian.MapLeaf{
+		{Index: []byte("A"), LeafHash: []byte("A Hash"), LeafValue: []byte("A Value")},
+		{Index: []byte("B"), LeafHash: []byte("B Hash"), LeafValue: []byte("B Value")},
+		{Index: []byte("C")}, // Tombstone.
+	}
+	func() {
+		tx := beginMapTX(ctx, t, s, mapID)
+		defer tx.Close()
+		if err := tx.SetBatch(ctx, leaves); err != nil {
+			t.Fatalf("SetBatch(): %v", err)
+		}
+		commitTX(t, tx)
+	}()
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	readValues, err := tx.Get(ctx, tx.WriteRevision(), [][]byte{[]byte("A"), []byte("B"), []byte("C")})
+	if err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	if got, want := len(readValues), 2; got != want {
+		t.Fatalf("Got %d values, expected %d", got, want)
+	}
+	byIndex := make(map[string]trillian.MapLeaf)
+	for _, l := range readValues {
+		byIndex[string(l.Index)] = l
+	}
+	for _, want := range leaves[:2] {
+		if got := byIndex[string(want.Index)]; !proto.Equal(&got, &want) {
+			t.Errorf("Read back %v, but expected %v", got, want)
+		}
+	}
+	if err := tx.SetBatch(ctx, leaves[:1]); err == nil {
+		t.Errorf("SetBatch() of an existing key succeeded, want error")
+	}
+}
+
+// TestMapGet0Results tests that Get doesn't return unknown leaves.
+func (tester *MapStorageTester) TestMapGet0Results(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	for _, tc := range []struct {
+		index [][]byte
+	}{
+		{index: nil}, // Empty list.
+		{index: [][]byte{[]byte("This doesn't exist.")}},
+	} {
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			readValues, err := tx.Get(ctx, 1, tc.index)
+			if err != nil {
+				t.Errorf("tx.Get(%s): %v", tc.index, err)
+				return
+			}
+			if got, want := len(readValues), 0; got != want {
+				t.Errorf("len(tx.Get(%s)): %d, want %d", tc.index, got, want)
+			}
+			commitTX(t, tx)
+		}()
+	}
+}
+
+// TestMapSetGetMultipleRevisions tests that Get returns the leaf value at the
+// requested revision.
+func (tester *MapStorageTester) TestMapSetGetMultipleRevisions(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	leaves := []trillian.MapLeaf{
+		{Index: mapKeyHash, LeafHash: []byte{0}, LeafValue: []byte{0}, ExtraData: []byte{0}},
+		{Index: mapKeyHash, LeafHash: []byte{1}, LeafValue: []byte{1}, ExtraData: []byte{1}},
+		{Index: mapKeyHash, LeafHash: []byte{2}, LeafValue: []byte{2}, ExtraData: []byte{2}},
+		{Index: mapKeyHash, LeafHash: []byte{3}, LeafValue: []byte{3}, ExtraData: []byte{3}},
+	}
+	revs := make([]int64, 0, len(leaves))
+	for _, leaf := range leaves {
+		revs = append(revs, setMapLeaves(ctx, t, s, mapID, leaf))
+	}
+
+	// Read at each point in history, and at a revision in the future.
+	for i, rev := range append(revs, revs[len(revs)-1]+1) {
+		want := &leaves[len(leaves)-1] // For future revisions, expect the latest value.
+		if i < len(leaves) {
+			want = &leaves[i]
+		}
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			readValues, err := tx.Get(ctx, rev, [][]byte{mapKeyHash})
+			if err != nil {
+				t.Fatalf("At rev %d failed to get %v: %v", rev, mapKeyHash, err)
+			}
+			if got, want := len(readValues), 1; got != want {
+				t.Fatalf("At rev %d got %d values, expected %d", rev, got, want)
+			}
+			if got := &readValues[0]; !proto.Equal(got, want) {
+				t.Errorf("At rev %d read back %v, but expected %v", rev, got, want)
+			}
+			commitTX(t, tx)
+		}()
+	}
+}
+
+// TestMapSetDeletesLeaf tests that a tombstone hides a leaf from later
+// revisions only.
+func (tester *MapStorageTester) TestMapSetDeletesLeaf(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	setRev := setMapLeaves(ctx, t, s, mapID, mapLeaf)
+	deleteRev := setMapLeaves(ctx, t, s, mapID, trillian.MapLeaf{Index: mapKeyHash}) // Tombstone.
+
+	for _, tc := range []struct {
+		rev  int64
+		want int
+	}{
+		{rev: setRev, want: 1}, // Before the delete.
+		{rev: deleteRev, want: 0},
+		{rev: deleteRev + 1, want: 0},
+	} {
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			readValues, err := tx.Get(ctx, tc.rev, [][]byte{mapKeyHash})
+			if err != nil {
+				t.Fatalf("Get(%v): %v", tc.rev, err)
+			}
+			if got := len(readValues); got != tc.want {
+				t.Errorf("len(Get(%v)): %d, want %d", tc.rev, got, tc.want)
+			}
+			commitTX(t, tx)
+		}()
+	}
+}
+
+// TestMapGetHistory tests that GetHistory returns the revisions in which a
+// leaf was written or deleted.
+func (tester *MapStorageTester) TestMapGetHistory(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	// Another key, written in every revision, which must not show up in the
+	// history.
+	other := func(rev int) trillian.MapLeaf {
+		return trillian.MapLeaf{Index: []byte("Another Key Hash"), LeafHash: []byte{byte(rev)}, LeafValue: []byte{byte(rev)}}
+	}
+	// The leaf is set in two revisions, left alone in one, and then deleted.
+	revA := setMapLeaves(ctx, t, s, mapID, trillian.MapLeaf{Index: mapKeyHash, LeafHash: []byte("A Hash"), LeafValue: []byte("A")}, other(0))
+	revB := setMapLeaves(ctx, t, s, mapID, trillian.MapLeaf{Index: mapKeyHash, LeafHash: []byte("B Hash"), LeafValue: []byte("B")}, other(1))
+	setMapLeaves(ctx, t, s, mapID, other(2))
+	revDel := setMapLeaves(ctx, t, s, mapID, trillian.MapLeaf{Index: mapKeyHash}, other(3)) // Tombstone.
+
+	for _, tc := range []struct {
+		from, to int64
+		limit    int
+		want     []int64
+	}{
+		{from: revA, to: revDel + 10, limit: 10, want: []int64{revA, revB, revDel}},
+		{from: revB, to: revB + 1, limit: 10, want: []int64{revB}},
+		{from: revA, to: revDel + 10, limit: 2, want: []int64{revA, revB}},
+		{from: revDel + 1, to: revDel + 10, limit: 10, want: []int64{}},
+	} {
+		func() {
+			tx := beginMapTX(ctx, t, s, mapID)
+			defer tx.Close()
+			history, err := tx.GetHistory(ctx, mapKeyHash, tc.from, tc.to, tc.limit)
+			if err != nil {
+				t.Fatalf("GetHistory(%v, %v, %v): %v", tc.from, tc.to, tc.limit, err)
+			}
+			commitTX(t, tx)
+			got := make([]int64, 0, len(history))
+			for _, h := range history {
+				if !bytes.Equal(h.Leaf.Index, mapKeyHash) {
+					t.Errorf("GetHistory(): Leaf.Index %x, want %x", h.Leaf.Index, mapKeyHash)
+				}
+				got = append(got, h.Revision)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetHistory(%v, %v, %v): revisions %v, want %v", tc.from, tc.to, tc.limit, got, tc.want)
+			}
+		}()
+	}
+}
+
+// TestLatestSignedMapRootNoneWritten tests that a map without roots returns
+// an empty root.
+func (tester *MapStorageTester) TestLatestSignedMapRootNoneWritten(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	root, err := tx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read an empty map root: %v", err)
+	}
+	if root.MapId != 0 || len(root.RootHash) != 0 || root.Signature != nil {
+		t.Fatalf("Read a root with contents when it should be empty: %v", root)
+	}
+	commitTX(t, tx)
+}
+
+// TestLatestSignedMapRoot tests that a stored root can be read back by
+// LatestSignedMapRoot.
+func (tester *MapStorageTester) TestLatestSignedMapRoot(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	root := trillian.SignedMapRoot{
+		MapId:          mapID,
+		TimestampNanos: 98765,
+		MapRevision:    5,
+		RootHash:       mapRootHash,
+		Signature:      mapSignature,
+	}
+	func() {
+		tx := beginMapTX(ctx, t, s, mapID)
+		defer tx.Close()
+		if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed root: %v", err)
+		}
+		commitTX(t, tx)
+	}()
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	root2, err := tx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read back new map root: %v", err)
+	}
+	if !proto.Equal(&root, &root2) {
+		t.Fatalf("Root round trip failed: <%#v> and: <%#v>", root, root2)
+	}
+	commitTX(t, tx)
+}
+
+// TestGetSignedMapRoot tests that stored roots can be read back by revision.
+func (tester *MapStorageTester) TestGetSignedMapRoot(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	roots := []trillian.SignedMapRoot{
+		{MapId: mapID, TimestampNanos: 98765, MapRevision: 5, RootHash: mapRootHash, Signature: mapSignature},
+		{MapId: mapID, TimestampNanos: 98766, MapRevision: 6, RootHash: mapRootHash, Signature: mapSignature},
+	}
+	func() {
+		tx := beginMapTX(ctx, t, s, mapID)
+		defer tx.Close()
+		for _, root := range roots {
+			if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+				t.Fatalf("Failed to store signed root: %v", err)
+			}
+		}
+		commitTX(t, tx)
+	}()
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	for _, root := range roots {
+		root2, err := tx.GetSignedMapRoot(ctx, root.MapRevision)
+		if err != nil {
+			t.Fatalf("Failed to get back new map root: %v", err)
+		}
+		if !proto.Equal(&root, &root2) {
+			t.Errorf("Getting root round trip failed: <%#v> and: <%#v>", root, root2)
+		}
+	}
+	commitTX(t, tx)
+}
+
+// TestDuplicateSignedMapRoot tests that a root can't be stored twice.
+func (tester *MapStorageTester) TestDuplicateSignedMapRoot(t *testing.T) {
+	ctx := context.Background()
+	s, mapID := tester.newMap(ctx, t)
+
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	root := trillian.SignedMapRoot{
+		MapId:          mapID,
+		TimestampNanos: 98765,
+		MapRevision:    5,
+		RootHash:       mapRootHash,
+		Signature:      mapSignature,
+	}
+	if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+		t.Fatalf("Failed to store signed map root: %v", err)
+	}
+	// Shouldn't be able to do it again
+	if err := tx.StoreSignedMapRoot(ctx, root); err == nil {
+		t.Fatal("Allowed duplicate signed map root")
+	}
+}
+
+// setMapLeaves writes leaves in a new map revision, and stores a root for the
+// revision so that the next TX writes the revision after it. It returns the
+// revision written.
+func setMapLeaves(ctx context.Context, t *testing.T, s storage.MapStorage, mapID int64, leaves ...trillian.MapLeaf) int64 {
+	tx := beginMapTX(ctx, t, s, mapID)
+	defer tx.Close()
+	rev := tx.WriteRevision()
+	if err := tx.SetBatch(ctx, leaves); err != nil {
+		t.Fatalf("SetBatch() at rev %v: %v", rev, err)
+	}
+	if err := tx.StoreSignedMapRoot(ctx, trillian.SignedMapRoot{
+		MapId:          mapID,
+		TimestampNanos: rev,
+		MapRevision:    rev,
+		RootHash:       mapRootHash,
+		Signature:      mapSignature,
+	}); err != nil {
+		t.Fatalf("StoreSignedMapRoot() at rev %v: %v", rev, err)
+	}
+	commitTX(t, tx)
+	return rev
+}
+
+func beginMapTX(ctx context.Context, t *testing.T, s storage.MapStorage, mapID int64) storage.MapTreeTX {
+	tx, err := s.BeginForTree(ctx, mapID)
+	if err != nil {
+		t.Fatalf("BeginForTree() = (_, %v), want = (_, nil)", err)
+	}
+	return tx
+}
+
+func commitTX(t *testing.T, tx storage.ReadOnlyTreeTX) {
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() = %v, want = nil", err)
+	}
+}
@@ -130,9 +130,9 @@ MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEywnWicNEQ8bn3GXcGpA+tiU4VL70
 Ws9xezgQPrg96YGsFrF6KYG68iqyHDlQ+4FWuKfGKXHn3ooVtB/pfawb5Q==
 -----END PUBLIC KEY-----`
 
-func sequence(treeID int64, seq *log.Sequencer, count, batchSize int) {
+func sequence(tree *trillian.Tree, seq *log.Sequencer, count, batchSize int) {
 	glog.Infof("Sequencing batch of size %d", count)
-	sequenced, err := seq.SequenceBatch(context.TODO(), treeID, batchSize, 0, 24*time.Hour)
+	sequenced, err := seq.SequenceBatch(context.TODO(), tree, batchSize, 0, 24*time.Hour, 0)
 
 	if err != nil {
 		glog.Fatalf("SequenceBatch got: %v, want: no err", err)
@@ -227,11 +227,12 @@ func Main(args Options) string {
 		ls,
 		&tc.Signer{Signer: cSigner, Hash: crypto.SHA256},
 		nil,
-		quota.Noop())
+		quota.Noop(),
+		log.NewCompactTreeCache())
 
 	// Create the initial tree head at size 0, which is required. And then sequence the leaves.
-	sequence(tree.TreeId, seq, 0, args.BatchSize)
-	sequenceLeaves(ls, seq, tree.TreeId, args.TreeSize, args.BatchSize, args.LeafFormat)
+	sequence(tree, seq, 0, args.BatchSize)
+	sequenceLeaves(ls, seq, tree, args.TreeSize, args.BatchSize, args.LeafFormat)
 
 	// Read the latest STH back
 	tx, err := ls.BeginForTree(context.TODO(), tree.TreeId)
@@ -356,13 +357,13 @@ func validateFlagsOrDie(summary, recordIO bool) {
 	}
 }
 
-func sequenceLeaves(ls storage.LogStorage, seq *log.Sequencer, treeID int64, treeSize, batchSize int, leafDataFormat string) {
+func sequenceLeaves(ls storage.LogStorage, seq *log.Sequencer, tree *trillian.Tree, treeSize, batchSize int, leafDataFormat string) {
 	glog.Info("Queuing work")
 	for l := 0; l < treeSize; l++ {
 		glog.V(1).Infof("Queuing leaf %d", l)
 
 		leafData := []byte(fmt.Sprintf(leafDataFormat, l))
-		tx, err := ls.BeginForTree(context.TODO(), treeID)
+		tx, err := ls.BeginForTree(context.TODO(), tree.TreeId)
 		if err != nil {
 			glog.Fatalf("BeginForTree got: %v, want: no err", err)
 		}
@@ -381,7 +382,7 @@ func sequenceLeaves(ls storage.LogStorage, seq *log.Sequencer, treeID int64, tre
 		}
 
 		if l > 0 && l%batchSize == 0 {
-			sequence(treeID, seq, batchSize, batchSize)
+			sequence(tree, seq, batchSize, batchSize)
 		}
 	}
 	glog.Info("Finished queueing")
@@ -390,7 +391,7 @@ func sequenceLeaves(ls storage.LogStorage, seq *log.Sequencer, treeID int64, tre
 	if left == 0 {
 		left = batchSize
 	}
-	sequence(treeID, seq, left, batchSize)
+	sequence(tree, seq, left, batchSize)
 	glog.Info("Finished sequencing")
 }
 
@@ -74,7 +74,7 @@ func main() {
 			panic(err)
 		}
 
-		fetchedLeaves, err := tx.GetLeavesByHash(ctx, [][]byte{hash}, false)
+		fetchedLeaves, err := tx.GetLeavesByHash(ctx, [][]byte{hash}, false, 0 /* limit */, 0 /* offset */)
 		if err != nil {
 			panic(err)
 		}
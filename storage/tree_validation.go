@@ -16,6 +16,7 @@ package storage
 
 import (
 	"crypto/x509"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
@@ -92,14 +93,52 @@ func ValidateTreeForUpdate(storedTree, newTree *trillian.Tree) error {
 		return errors.New(errors.InvalidArgument, "readonly field changed: create_time")
 	case storedTree.UpdateTime != newTree.UpdateTime:
 		return errors.New(errors.InvalidArgument, "readonly field changed: update_time")
+	case storedTree.DeleteTime != newTree.DeleteTime:
+		return errors.New(errors.InvalidArgument, "readonly field changed: delete_time")
 	case storedTree.PrivateKey != newTree.PrivateKey:
 		return errors.New(errors.InvalidArgument, "readonly field changed: private_key")
 	case storedTree.PublicKey != newTree.PublicKey:
 		return errors.New(errors.InvalidArgument, "readonly field changed: public_key")
+	case storedTree.TreeState != newTree.TreeState && (IsDeleted(storedTree) || IsDeleted(newTree)):
+		// Deletion goes through DeleteTree / UndeleteTree, which keep delete_time and the
+		// tree's data consistent with its state.
+		return errors.Errorf(errors.InvalidArgument, "tree_state cannot be changed from %s to %s via update", storedTree.TreeState, newTree.TreeState)
 	}
 	return validateMutableTreeFields(newTree)
 }
 
+// ValidateTreeForSoftDelete returns nil if tree may be soft-deleted, error
+// otherwise.
+// Only ACTIVE and FROZEN trees may be soft-deleted.
+func ValidateTreeForSoftDelete(tree *trillian.Tree) error {
+	switch tree.TreeState {
+	case trillian.TreeState_SOFT_DELETED, trillian.TreeState_HARD_DELETED:
+		return errors.Errorf(errors.FailedPrecondition, "tree %v already deleted", tree.TreeId)
+	}
+	return nil
+}
+
+// ValidateTreeIsSoftDeleted returns nil if tree is SOFT_DELETED, error
+// otherwise.
+// Both undeletion and hard-deletion are only allowed for soft-deleted trees.
+func ValidateTreeIsSoftDeleted(tree *trillian.Tree) error {
+	if tree.TreeState != trillian.TreeState_SOFT_DELETED {
+		return errors.Errorf(errors.FailedPrecondition, "tree %v is not soft-deleted (state = %s)", tree.TreeId, tree.TreeState)
+	}
+	return nil
+}
+
+// ValidateTreeForLeafWrites returns nil if leaves may be added to a tree in
+// the given state, error otherwise.
+// Storage implementations check it in the same transaction that adds the
+// leaves, so that leaves can't be added once a tree is frozen or deleted.
+func ValidateTreeForLeafWrites(treeID int64, state trillian.TreeState) error {
+	if state != trillian.TreeState_ACTIVE {
+		return errors.Errorf(errors.FailedPrecondition, "cannot add leaves to %s tree %v", state, treeID)
+	}
+	return nil
+}
+
 func validateMutableTreeFields(tree *trillian.Tree) error {
 	switch {
 	case tree.TreeState == trillian.TreeState_UNKNOWN_TREE_STATE:
@@ -114,6 +153,32 @@ func validateMutableTreeFields(tree *trillian.Tree) error {
 	} else if duration < 0 {
 		return errors.Errorf(errors.InvalidArgument, "max_root_duration negative: %v", tree.MaxRootDuration)
 	}
+	// sequence_interval is optional, nil means the signer's default.
+	// Storage keeps it in seconds, so finer-grained values are rejected rather
+	// than silently truncated.
+	if tree.SequenceInterval != nil {
+		if interval, err := ptypes.Duration(tree.SequenceInterval); err != nil {
+			return errors.Errorf(errors.InvalidArgument, "sequence_interval malformed: %v", tree.SequenceInterval)
+		} else if interval < 0 {
+			return errors.Errorf(errors.InvalidArgument, "sequence_interval negative: %v", tree.SequenceInterval)
+		} else if interval%time.Second != 0 {
+			return errors.Errorf(errors.InvalidArgument, "sequence_interval must be a whole number of seconds, got %v", interval)
+		}
+	}
+	// max_merge_delay is optional, nil means the signer's default.
+	// As with sequence_interval, it must be a whole number of seconds.
+	if tree.MaxMergeDelay != nil {
+		if mmd, err := ptypes.Duration(tree.MaxMergeDelay); err != nil {
+			return errors.Errorf(errors.InvalidArgument, "max_merge_delay malformed: %v", tree.MaxMergeDelay)
+		} else if mmd < 0 {
+			return errors.Errorf(errors.InvalidArgument, "max_merge_delay negative: %v", tree.MaxMergeDelay)
+		} else if mmd%time.Second != 0 {
+			return errors.Errorf(errors.InvalidArgument, "max_merge_delay must be a whole number of seconds, got %v", mmd)
+		}
+	}
+	if tree.SchedulingPriority < 0 {
+		return errors.Errorf(errors.InvalidArgument, "scheduling_priority negative: %v", tree.SchedulingPriority)
+	}
 
 	// Implementations may vary, so let's assume storage_settings is mutable.
 	// Other than checking that it's a valid Any there isn't much to do at this layer, though.
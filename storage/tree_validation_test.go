@@ -96,6 +96,22 @@ func TestValidateTreeForCreation(t *testing.T) {
 	invalidRootDuration := newTree()
 	invalidRootDuration.MaxRootDuration = ptypes.DurationProto(-1 * time.Second)
 
+	treeControl := newTree()
+	treeControl.SigningDisabled = true
+	treeControl.SequencingDisabled = true
+	treeControl.SequenceInterval = ptypes.DurationProto(5 * time.Second)
+	treeControl.MaxMergeDelay = ptypes.DurationProto(time.Hour)
+	treeControl.SchedulingPriority = 3
+
+	invalidSequenceInterval := newTree()
+	invalidSequenceInterval.SequenceInterval = ptypes.DurationProto(-1 * time.Second)
+
+	invalidMaxMergeDelay := newTree()
+	invalidMaxMergeDelay.MaxMergeDelay = ptypes.DurationProto(-1 * time.Hour)
+
+	invalidSchedulingPriority := newTree()
+	invalidSchedulingPriority.SchedulingPriority = -1
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -208,6 +224,25 @@ func TestValidateTreeForCreation(t *testing.T) {
 			tree:    invalidRootDuration,
 			wantErr: true,
 		},
+		{
+			desc: "treeControl",
+			tree: treeControl,
+		},
+		{
+			desc:    "invalidSequenceInterval",
+			tree:    invalidSequenceInterval,
+			wantErr: true,
+		},
+		{
+			desc:    "invalidMaxMergeDelay",
+			tree:    invalidMaxMergeDelay,
+			wantErr: true,
+		},
+		{
+			desc:    "invalidSchedulingPriority",
+			tree:    invalidSchedulingPriority,
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		err := ValidateTreeForCreation(test.tree)
@@ -256,6 +291,51 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "treeControl",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SigningDisabled = true
+				tree.SequencingDisabled = true
+				tree.SequenceInterval = ptypes.DurationProto(5 * time.Second)
+				tree.MaxMergeDelay = ptypes.DurationProto(time.Hour)
+				tree.SchedulingPriority = 3
+			},
+		},
+		{
+			desc: "invalidSequenceInterval",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequenceInterval = ptypes.DurationProto(-5 * time.Second)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "subSecondSequenceInterval",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequenceInterval = ptypes.DurationProto(1500 * time.Millisecond)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalidMaxMergeDelay",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxMergeDelay = ptypes.DurationProto(-5 * time.Second)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "subSecondMaxMergeDelay",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxMergeDelay = ptypes.DurationProto(1500 * time.Millisecond)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalidSchedulingPriority",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SchedulingPriority = -2
+			},
+			wantErr: true,
+		},
 		{
 			desc: "validRootDuration",
 			updatefn: func(tree *trillian.Tree) {
@@ -319,6 +399,13 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "DeleteTime",
+			updatefn: func(tree *trillian.Tree) {
+				tree.DeleteTime, _ = ptypes.TimestampProto(time.Now())
+			},
+			wantErr: true,
+		},
 		{
 			desc: "PrivateKey",
 			updatefn: func(tree *trillian.Tree) {
@@ -332,6 +419,20 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "softDeleted",
+			updatefn: func(tree *trillian.Tree) {
+				tree.TreeState = trillian.TreeState_SOFT_DELETED
+			},
+			wantErr: true,
+		},
+		{
+			desc: "hardDeleted",
+			updatefn: func(tree *trillian.Tree) {
+				tree.TreeState = trillian.TreeState_HARD_DELETED
+			},
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		tree := newTree()
@@ -348,6 +449,62 @@ func TestValidateTreeForUpdate(t *testing.T) {
 	}
 }
 
+func TestValidateTreeForUpdate_DeletedTrees(t *testing.T) {
+	for _, test := range []struct {
+		storedState, newState trillian.TreeState
+		wantErr               bool
+	}{
+		{storedState: trillian.TreeState_SOFT_DELETED, newState: trillian.TreeState_SOFT_DELETED},
+		{storedState: trillian.TreeState_SOFT_DELETED, newState: trillian.TreeState_ACTIVE, wantErr: true},
+		{storedState: trillian.TreeState_SOFT_DELETED, newState: trillian.TreeState_FROZEN, wantErr: true},
+		{storedState: trillian.TreeState_SOFT_DELETED, newState: trillian.TreeState_HARD_DELETED, wantErr: true},
+		{storedState: trillian.TreeState_HARD_DELETED, newState: trillian.TreeState_ACTIVE, wantErr: true},
+		{storedState: trillian.TreeState_HARD_DELETED, newState: trillian.TreeState_SOFT_DELETED, wantErr: true},
+	} {
+		storedTree := newTree()
+		storedTree.TreeState = test.storedState
+		tree := *storedTree
+		tree.TreeState = test.newState
+
+		err := ValidateTreeForUpdate(storedTree, &tree)
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("ValidateTreeForUpdate(%s -> %s) = %v, wantErr = %v", test.storedState, test.newState, err, test.wantErr)
+		}
+	}
+}
+
+func TestValidateTreeDeletion(t *testing.T) {
+	tests := []struct {
+		state                                   trillian.TreeState
+		wantSoftDeleteErr, wantIsSoftDeletedErr bool
+	}{
+		{state: trillian.TreeState_ACTIVE, wantIsSoftDeletedErr: true},
+		{state: trillian.TreeState_FROZEN, wantIsSoftDeletedErr: true},
+		{state: trillian.TreeState_SOFT_DELETED, wantSoftDeleteErr: true},
+		{state: trillian.TreeState_HARD_DELETED, wantSoftDeleteErr: true, wantIsSoftDeletedErr: true},
+	}
+	for _, test := range tests {
+		tree := newTree()
+		tree.TreeState = test.state
+
+		err := ValidateTreeForSoftDelete(tree)
+		switch hasErr := err != nil; {
+		case hasErr != test.wantSoftDeleteErr:
+			t.Errorf("%v: ValidateTreeForSoftDelete() = %v, wantErr = %v", test.state, err, test.wantSoftDeleteErr)
+		case hasErr && errors.ErrorCode(err) != errors.FailedPrecondition:
+			t.Errorf("%v: ValidateTreeForSoftDelete() = %v, wantCode = %d", test.state, err, errors.FailedPrecondition)
+		}
+
+		err = ValidateTreeIsSoftDeleted(tree)
+		switch hasErr := err != nil; {
+		case hasErr != test.wantIsSoftDeletedErr:
+			t.Errorf("%v: ValidateTreeIsSoftDeleted() = %v, wantErr = %v", test.state, err, test.wantIsSoftDeletedErr)
+		case hasErr && errors.ErrorCode(err) != errors.FailedPrecondition:
+			t.Errorf("%v: ValidateTreeIsSoftDeleted() = %v, wantCode = %d", test.state, err, errors.FailedPrecondition)
+		}
+	}
+}
+
 // newTree returns a valid tree for tests.
 func newTree() *trillian.Tree {
 	privateKey, err := ptypes.MarshalAny(&keyspb.PEMKeyFile{
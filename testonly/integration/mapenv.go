@@ -25,6 +25,7 @@ import (
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/server"
 	"github.com/google/trillian/server/admin"
+	"github.com/google/trillian/storage/memory"
 	"github.com/google/trillian/storage/mysql"
 
 	"google.golang.org/grpc"
@@ -81,6 +82,18 @@ func NewMapEnv(ctx context.Context, testID string) (*MapEnv, error) {
 	return ret, nil
 }
 
+// NewInMemoryMapEnv creates a map server backed by in-memory storage, and a
+// client. Unlike NewMapEnv, it doesn't need a database.
+func NewInMemoryMapEnv(ctx context.Context, testID string) (*MapEnv, error) {
+	ms := memory.NewMapStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewMapAdminStorage(ms),
+		MapStorage:   ms,
+		QuotaManager: quota.Noop(),
+	}
+	return NewMapEnvWithRegistry(ctx, testID, registry)
+}
+
 // NewMapEnvWithRegistry uses the passed in Registry to create a map server and
 // client.  testID should be unique to each unittest package so as to allow
 // parallel tests.
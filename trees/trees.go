@@ -46,9 +46,8 @@ func FromContext(ctx context.Context) (*trillian.Tree, bool) {
 
 // GetOpts contains validation options for GetTree.
 type GetOpts struct {
-	// TreeType is the expected type of the tree. Use trillian.TreeType_UNKNOWN_TREE_TYPE to
-	// allow any type.
-	TreeType trillian.TreeType
+	// TreeTypes are the allowed types of the tree. Leave empty to allow any type.
+	TreeTypes []trillian.TreeType
 	// Readonly is whether the tree will be used for read-only purposes.
 	Readonly bool
 }
@@ -69,8 +68,8 @@ func GetTree(ctx context.Context, s storage.AdminStorage, treeID int64, opts Get
 	}
 
 	switch {
-	case opts.TreeType != trillian.TreeType_UNKNOWN_TREE_TYPE && tree.TreeType != opts.TreeType:
-		return nil, errors.Errorf(errors.InvalidArgument, "operation not allowed for %s-type trees (wanted %s-type)", tree.TreeType, opts.TreeType)
+	case !typeAllowed(tree.TreeType, opts.TreeTypes):
+		return nil, errors.Errorf(errors.InvalidArgument, "operation not allowed for %s-type trees (wanted one of %v)", tree.TreeType, opts.TreeTypes)
 	case tree.TreeState == trillian.TreeState_FROZEN && !opts.Readonly:
 		return nil, errors.Errorf(errors.FailedPrecondition, "operation not allowed on %s trees", tree.TreeState)
 	case tree.TreeState == trillian.TreeState_SOFT_DELETED || tree.TreeState == trillian.TreeState_HARD_DELETED:
@@ -80,6 +79,19 @@ func GetTree(ctx context.Context, s storage.AdminStorage, treeID int64, opts Get
 	return tree, nil
 }
 
+// typeAllowed returns whether treeType is one of allowed, or allowed is empty.
+func typeAllowed(treeType trillian.TreeType, allowed []trillian.TreeType) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		if t == treeType {
+			return true
+		}
+	}
+	return false
+}
+
 func getTreeFromStorage(ctx context.Context, s storage.AdminStorage, treeID int64) (*trillian.Tree, error) {
 	tx, err := s.Snapshot(ctx)
 	if err != nil {
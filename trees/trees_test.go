@@ -79,6 +79,10 @@ func TestGetTree(t *testing.T) {
 	hardDeletedTree.TreeId = 5
 	hardDeletedTree.TreeState = trillian.TreeState_HARD_DELETED
 
+	preorderedTree := *testonly.LogTree
+	preorderedTree.TreeId = 6
+	preorderedTree.TreeType = trillian.TreeType_PREORDERED_LOG
+
 	tests := []struct {
 		desc                           string
 		treeID                         int64
@@ -90,70 +94,90 @@ func TestGetTree(t *testing.T) {
 		{
 			desc:        "logTree",
 			treeID:      logTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			storageTree: &logTree,
 			wantTree:    &logTree,
 		},
 		{
 			desc:        "mapTree",
 			treeID:      mapTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_MAP},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_MAP}},
 			storageTree: &mapTree,
 			wantTree:    &mapTree,
 		},
 		{
 			desc:        "wrongType1",
 			treeID:      logTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_MAP},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_MAP}},
 			storageTree: &logTree,
 			wantErr:     true,
 		},
 		{
 			desc:        "wrongType2",
 			treeID:      mapTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			storageTree: &mapTree,
 			wantErr:     true,
 		},
+		{
+			desc:        "preorderedTree",
+			treeID:      preorderedTree.TreeId,
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}},
+			storageTree: &preorderedTree,
+			wantTree:    &preorderedTree,
+		},
+		{
+			desc:        "wrongType3",
+			treeID:      preorderedTree.TreeId,
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
+			storageTree: &preorderedTree,
+			wantErr:     true,
+		},
+		{
+			desc:        "anyType",
+			treeID:      mapTree.TreeId,
+			storageTree: &mapTree,
+			wantTree:    &mapTree,
+		},
 		{
 			desc:        "frozenTree",
 			treeID:      frozenTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG, Readonly: true},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}, Readonly: true},
 			storageTree: &frozenTree,
 			wantTree:    &frozenTree,
 		},
 		{
 			desc:        "frozenTreeNotReadonly",
 			treeID:      frozenTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			storageTree: &frozenTree,
 			wantErr:     true,
 		},
 		{
 			desc:        "softDeleted",
 			treeID:      softDeletedTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			storageTree: &softDeletedTree,
 			wantErr:     true,
 		},
 		{
 			desc:        "hardDeleted",
 			treeID:      hardDeletedTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			storageTree: &hardDeletedTree,
 			wantErr:     true,
 		},
 		{
 			desc:     "treeInCtx",
 			treeID:   logTree.TreeId,
-			opts:     GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:     GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			ctxTree:  &logTree,
 			wantTree: &logTree,
 		},
 		{
 			desc:        "wrongTreeInCtx",
 			treeID:      logTree.TreeId,
-			opts:        GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:        GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			ctxTree:     &mapTree,
 			storageTree: &logTree,
 			wantTree:    &logTree,
@@ -161,21 +185,21 @@ func TestGetTree(t *testing.T) {
 		{
 			desc:     "beginErr",
 			treeID:   logTree.TreeId,
-			opts:     GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:     GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			beginErr: errors.New("begin err"),
 			wantErr:  true,
 		},
 		{
 			desc:    "getErr",
 			treeID:  logTree.TreeId,
-			opts:    GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:    GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			getErr:  errors.New("get err"),
 			wantErr: true,
 		},
 		{
 			desc:      "commitErr",
 			treeID:    logTree.TreeId,
-			opts:      GetOpts{TreeType: trillian.TreeType_LOG},
+			opts:      GetOpts{TreeTypes: []trillian.TreeType{trillian.TreeType_LOG}},
 			commitErr: errors.New("commit err"),
 			wantErr:   true,
 		},
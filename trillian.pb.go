@@ -69,7 +69,8 @@ const (
 	// Active trees are able to respond to both read and write requests.
 	TreeState_ACTIVE TreeState = 1
 	// Frozen trees are only able to respond to read requests, writing to a frozen
-	// tree is forbidden.
+	// tree is forbidden. Frozen logs are neither sequenced nor signed, and logs
+	// may only be frozen once all their leaves are covered by a signed root.
 	TreeState_FROZEN TreeState = 2
 	// Tree was been deleted, therefore is invisible and acts similarly to a
 	// non-existing tree for all requests.
@@ -113,17 +114,22 @@ const (
 	TreeType_LOG TreeType = 1
 	// Tree represents a verifiable map.
 	TreeType_MAP TreeType = 2
+	// Tree represents a verifiable pre-ordered log, whose leaves are added at
+	// indices assigned by the caller rather than by the sequencer.
+	TreeType_PREORDERED_LOG TreeType = 3
 )
 
 var TreeType_name = map[int32]string{
 	0: "UNKNOWN_TREE_TYPE",
 	1: "LOG",
 	2: "MAP",
+	3: "PREORDERED_LOG",
 }
 var TreeType_value = map[string]int32{
 	"UNKNOWN_TREE_TYPE": 0,
 	"LOG":               1,
 	"MAP":               2,
+	"PREORDERED_LOG":    3,
 }
 
 func (x TreeType) String() string {
@@ -190,6 +196,35 @@ type Tree struct {
 	// Time of last tree update.
 	// Readonly (automatically assigned on updates).
 	UpdateTime *google_protobuf2.Timestamp `protobuf:"bytes,17,opt,name=update_time,json=updateTime" json:"update_time,omitempty"`
+	// Time of tree deletion, if the tree is SOFT_DELETED or HARD_DELETED.
+	// Readonly (automatically assigned on deletion).
+	DeleteTime *google_protobuf2.Timestamp `protobuf:"bytes,19,opt,name=delete_time,json=deleteTime" json:"delete_time,omitempty"`
+	// If true, the log signer skips the tree entirely: no leaves are sequenced
+	// and no new roots are signed.
+	// Only applicable to logs.
+	SigningDisabled bool `protobuf:"varint,20,opt,name=signing_disabled,json=signingDisabled" json:"signing_disabled,omitempty"`
+	// If true, the log signer stops integrating queued leaves into the tree.
+	// New (empty) roots are still signed according to max_root_duration, unless
+	// signing_disabled is also set.
+	// Only applicable to logs.
+	SequencingDisabled bool `protobuf:"varint,21,opt,name=sequencing_disabled,json=sequencingDisabled" json:"sequencing_disabled,omitempty"`
+	// Interval between sequencing passes for the tree.
+	// If zero, the log signer's default interval is used.
+	// Only applicable to logs.
+	SequenceInterval *google_protobuf1.Duration `protobuf:"bytes,22,opt,name=sequence_interval,json=sequenceInterval" json:"sequence_interval,omitempty"`
+	// Maximum merge delay: the time within which queued leaves should be
+	// integrated into the tree. The log signer gives priority to leaves that are
+	// close to it, and reports leaves that miss it.
+	// If zero, the log signer's default maximum merge delay is used.
+	// Only applicable to logs.
+	MaxMergeDelay *google_protobuf1.Duration `protobuf:"bytes,23,opt,name=max_merge_delay,json=maxMergeDelay" json:"max_merge_delay,omitempty"`
+	// Priority of the tree when the log signer schedules logs by their load.
+	// The weight of the log, which grows with its backlog of queued leaves and
+	// the age of its latest root, is multiplied by 1 + scheduling_priority, and
+	// logs with larger weights are processed first.
+	// Must not be negative. Zero is the default priority.
+	// Only applicable to logs.
+	SchedulingPriority int32 `protobuf:"varint,24,opt,name=scheduling_priority,json=schedulingPriority" json:"scheduling_priority,omitempty"`
 }
 
 func (m *Tree) Reset()                    { *m = Tree{} }
@@ -302,10 +337,58 @@ func (m *Tree) GetUpdateTime() *google_protobuf2.Timestamp {
 	return nil
 }
 
+func (m *Tree) GetDeleteTime() *google_protobuf2.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
+func (m *Tree) GetSigningDisabled() bool {
+	if m != nil {
+		return m.SigningDisabled
+	}
+	return false
+}
+
+func (m *Tree) GetSequencingDisabled() bool {
+	if m != nil {
+		return m.SequencingDisabled
+	}
+	return false
+}
+
+func (m *Tree) GetSequenceInterval() *google_protobuf1.Duration {
+	if m != nil {
+		return m.SequenceInterval
+	}
+	return nil
+}
+
+func (m *Tree) GetMaxMergeDelay() *google_protobuf1.Duration {
+	if m != nil {
+		return m.MaxMergeDelay
+	}
+	return nil
+}
+
+func (m *Tree) GetSchedulingPriority() int32 {
+	if m != nil {
+		return m.SchedulingPriority
+	}
+	return 0
+}
+
+// SignedEntryTimestamp is a log's promise to integrate a queued leaf, signed with
+// the tree's key when the leaf is queued.
 type SignedEntryTimestamp struct {
-	TimestampNanos int64                  `protobuf:"varint,1,opt,name=timestamp_nanos,json=timestampNanos" json:"timestamp_nanos,omitempty"`
-	LogId          int64                  `protobuf:"varint,2,opt,name=log_id,json=logId" json:"log_id,omitempty"`
-	Signature      *sigpb.DigitallySigned `protobuf:"bytes,3,opt,name=signature" json:"signature,omitempty"`
+	// When the leaf was queued, in epoch nanoseconds.
+	TimestampNanos int64 `protobuf:"varint,1,opt,name=timestamp_nanos,json=timestampNanos" json:"timestamp_nanos,omitempty"`
+	LogId          int64 `protobuf:"varint,2,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	// Signature over the other fields; see crypto.HashSignedEntryTimestamp.
+	Signature *sigpb.DigitallySigned `protobuf:"bytes,3,opt,name=signature" json:"signature,omitempty"`
+	// The Merkle leaf hash of the queued leaf.
+	MerkleLeafHash []byte `protobuf:"bytes,4,opt,name=merkle_leaf_hash,json=merkleLeafHash,proto3" json:"merkle_leaf_hash,omitempty"`
 }
 
 func (m *SignedEntryTimestamp) Reset()                    { *m = SignedEntryTimestamp{} }
@@ -334,6 +417,13 @@ func (m *SignedEntryTimestamp) GetSignature() *sigpb.DigitallySigned {
 	return nil
 }
 
+func (m *SignedEntryTimestamp) GetMerkleLeafHash() []byte {
+	if m != nil {
+		return m.MerkleLeafHash
+	}
+	return nil
+}
+
 // SignedLogRoot represents a commitment by a Log to a particular tree.
 type SignedLogRoot struct {
 	// epoch nanoseconds, good until 2500ish
@@ -497,71 +587,81 @@ func init() {
 func init() { proto.RegisterFile("trillian.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 1055 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5d, 0x6f, 0xdb, 0x36,
-	0x14, 0xad, 0x62, 0xd7, 0xb1, 0xaf, 0x3f, 0xa2, 0xb0, 0x4d, 0xa6, 0xb8, 0xc3, 0x9a, 0x79, 0x03,
-	0x96, 0x65, 0x80, 0xb3, 0xb9, 0x4d, 0x80, 0xa1, 0x18, 0x06, 0xc7, 0x51, 0xea, 0x7c, 0xd9, 0x86,
-	0xa4, 0x6d, 0x68, 0x5e, 0x08, 0xda, 0x66, 0x65, 0xa2, 0x92, 0xa5, 0x4a, 0x74, 0x51, 0xf5, 0x79,
-	0x8f, 0x03, 0xf6, 0x7f, 0xf6, 0x7b, 0xf6, 0x2f, 0xf6, 0x32, 0x90, 0xa2, 0x64, 0x27, 0xe9, 0xd6,
-	0x62, 0xd8, 0x4b, 0x42, 0x9e, 0x7b, 0xce, 0xe1, 0xc7, 0xbd, 0x97, 0x32, 0x34, 0x78, 0xc4, 0x3c,
-	0x8f, 0x91, 0x79, 0x3b, 0x8c, 0x02, 0x1e, 0xa0, 0x72, 0x36, 0x6f, 0x36, 0x27, 0x51, 0x12, 0xf2,
-	0xe0, 0xe0, 0x15, 0x4d, 0xe2, 0x70, 0xac, 0xfe, 0xa5, 0xac, 0xa6, 0xa1, 0x62, 0x31, 0x73, 0xc3,
-	0x71, 0xfa, 0x57, 0x45, 0x76, 0xdc, 0x20, 0x70, 0x3d, 0x7a, 0x20, 0x67, 0xe3, 0xc5, 0xcb, 0x03,
-	0x32, 0x4f, 0x54, 0xe8, 0xb3, 0xdb, 0xa1, 0xe9, 0x22, 0x22, 0x9c, 0x05, 0x6a, 0xe9, 0xe6, 0xe3,
-	0xdb, 0x71, 0xce, 0x7c, 0x1a, 0x73, 0xe2, 0x87, 0x29, 0xa1, 0xf5, 0xfb, 0x3a, 0x14, 0x9d, 0x88,
-	0x52, 0xf4, 0x09, 0xac, 0xf3, 0x88, 0x52, 0xcc, 0xa6, 0x86, 0xb6, 0xab, 0xed, 0x15, 0xac, 0x92,
-	0x98, 0x9e, 0x4d, 0x51, 0x07, 0x40, 0x06, 0x62, 0x4e, 0x38, 0x35, 0xd6, 0x76, 0xb5, 0xbd, 0x46,
-	0xe7, 0x41, 0x3b, 0x3f, 0xa2, 0x10, 0xdb, 0x22, 0x64, 0x55, 0x78, 0x36, 0x44, 0x07, 0x20, 0x27,
-	0x98, 0x27, 0x21, 0x35, 0x0a, 0x52, 0x82, 0x6e, 0x4a, 0x9c, 0x24, 0xa4, 0x56, 0x99, 0xab, 0x11,
-	0x7a, 0x06, 0xf5, 0x19, 0x89, 0x67, 0x38, 0xe6, 0x11, 0xe1, 0xd4, 0x4d, 0x8c, 0xa2, 0x14, 0x6d,
-	0x2f, 0x45, 0x7d, 0x12, 0xcf, 0x6c, 0x15, 0xb5, 0x6a, 0xb3, 0x95, 0x19, 0xba, 0x80, 0x86, 0x14,
-	0x13, 0xcf, 0x0d, 0x22, 0xc6, 0x67, 0xbe, 0x71, 0x5f, 0xaa, 0xbf, 0x6c, 0xa7, 0xb7, 0x78, 0xc2,
-	0x5c, 0xc6, 0x89, 0xe7, 0x25, 0x36, 0x73, 0xe7, 0x74, 0x2a, 0xad, 0xba, 0x19, 0xd7, 0x92, 0x0b,
-	0xe7, 0x53, 0x74, 0x0d, 0x0f, 0x62, 0xe6, 0xce, 0x09, 0x5f, 0x44, 0x74, 0xc5, 0xb1, 0x24, 0x1d,
-	0xbf, 0xfe, 0x07, 0x47, 0x3b, 0x53, 0x2c, 0x6d, 0x51, 0x7c, 0x07, 0x43, 0x04, 0xb6, 0x97, 0xde,
-	0x13, 0x16, 0xce, 0x68, 0x84, 0xe3, 0x05, 0xe3, 0xd4, 0x40, 0xd2, 0xfe, 0x9b, 0x0f, 0xd9, 0xf7,
-	0xa4, 0xc6, 0x16, 0x12, 0xeb, 0x61, 0xfc, 0x1e, 0x14, 0x7d, 0x0e, 0xb5, 0x29, 0x8b, 0x43, 0x8f,
-	0x24, 0x78, 0x4e, 0x7c, 0x6a, 0x94, 0x77, 0xb5, 0xbd, 0x8a, 0x55, 0x55, 0xd8, 0x80, 0xf8, 0x14,
-	0xed, 0x42, 0x75, 0x4a, 0xe3, 0x49, 0xc4, 0x42, 0x51, 0x28, 0x46, 0x45, 0x31, 0x96, 0x10, 0x3a,
-	0x84, 0x6a, 0x18, 0xb1, 0x37, 0x84, 0x53, 0xfc, 0x8a, 0x26, 0x46, 0x6d, 0x57, 0xdb, 0xab, 0x76,
-	0x1e, 0xb6, 0xd3, 0x5a, 0x6a, 0x67, 0xb5, 0xd4, 0xee, 0xce, 0x13, 0x0b, 0x14, 0xf1, 0x82, 0x26,
-	0xe8, 0x47, 0xd0, 0x63, 0x1e, 0x44, 0xc4, 0xa5, 0x38, 0xa6, 0x9c, 0xb3, 0xb9, 0x1b, 0x1b, 0xf5,
-	0x7f, 0xd1, 0x6e, 0x28, 0xb6, 0xad, 0xc8, 0xe8, 0x5b, 0x80, 0x70, 0x31, 0xf6, 0xd8, 0x44, 0x2e,
-	0xdb, 0x90, 0xd2, 0xcd, 0xb6, 0xea, 0x92, 0x91, 0x8c, 0x5c, 0xd0, 0xc4, 0xaa, 0x84, 0xd9, 0x10,
-	0x99, 0xb0, 0xe9, 0x93, 0xb7, 0x38, 0x0a, 0x02, 0x8e, 0xb3, 0xd2, 0x37, 0x36, 0xa4, 0x70, 0xe7,
-	0xce, 0x9a, 0x27, 0x8a, 0x60, 0x6d, 0xf8, 0xe4, 0xad, 0x15, 0x04, 0x3c, 0x03, 0xd0, 0x33, 0xa8,
-	0x4e, 0x22, 0x2a, 0xce, 0x2b, 0xfa, 0xc3, 0xd0, 0xa5, 0x41, 0xf3, 0x8e, 0x81, 0x93, 0x35, 0x8f,
-	0x05, 0x29, 0x5d, 0x00, 0x42, 0xbc, 0x08, 0xa7, 0xb9, 0x78, 0xf3, 0xc3, 0xe2, 0x94, 0x2e, 0x80,
-	0xf3, 0x62, 0x79, 0x5d, 0x2f, 0x9f, 0x17, 0xcb, 0xa0, 0x57, 0xcf, 0x8b, 0xe5, 0xaa, 0x5e, 0x6b,
-	0xfd, 0xa6, 0xc1, 0xc3, 0x34, 0xef, 0xe6, 0x9c, 0x47, 0x49, 0x2e, 0x43, 0x5f, 0xc1, 0x46, 0xde,
-	0xbd, 0x78, 0x4e, 0xe6, 0x41, 0xac, 0x3a, 0xb5, 0x91, 0xc3, 0x03, 0x81, 0xa2, 0x2d, 0x28, 0x79,
-	0x81, 0x2b, 0x3a, 0x79, 0x4d, 0xc6, 0xef, 0x7b, 0x81, 0x7b, 0x36, 0x45, 0x4f, 0xa1, 0x92, 0x97,
-	0x8c, 0x6c, 0xca, 0x6a, 0x67, 0xfb, 0xfd, 0x05, 0x67, 0x2d, 0x89, 0xad, 0x3f, 0x35, 0xa8, 0xa7,
-	0xe8, 0x65, 0xe0, 0x8a, 0x4b, 0xfb, 0xf8, 0x7d, 0x3c, 0x82, 0x8a, 0x4c, 0x8c, 0x68, 0x30, 0xb9,
-	0x95, 0x9a, 0x55, 0x16, 0x80, 0xe8, 0x3f, 0x11, 0x4c, 0x9f, 0x15, 0xf6, 0x2e, 0xdd, 0x4d, 0x21,
-	0x7d, 0x0e, 0x6c, 0xf6, 0x8e, 0xde, 0xdc, 0x6a, 0xf1, 0x23, 0xb7, 0xba, 0x72, 0xee, 0xfb, 0xab,
-	0xe7, 0xfe, 0x02, 0xea, 0x72, 0xa5, 0x88, 0xbe, 0x61, 0xb1, 0xa8, 0x8f, 0x92, 0x8c, 0xd6, 0x04,
-	0x68, 0x29, 0xac, 0xf5, 0x87, 0x06, 0x8d, 0x2b, 0x12, 0x86, 0x34, 0xba, 0xa2, 0x9c, 0x4c, 0x09,
-	0x27, 0xa8, 0x05, 0xf5, 0x38, 0x58, 0x44, 0x13, 0x8a, 0x95, 0xab, 0x26, 0x8f, 0x50, 0x4d, 0xc1,
-	0x4b, 0xe9, 0xfd, 0x03, 0x3c, 0x9a, 0x31, 0x77, 0x46, 0x63, 0x8e, 0x5f, 0x2e, 0x3c, 0x2f, 0xc1,
-	0x93, 0xc0, 0x0f, 0x3d, 0xca, 0xe9, 0x14, 0xc7, 0xf4, 0xb5, 0xba, 0x7f, 0x43, 0x51, 0x4e, 0x05,
-	0xa3, 0x97, 0x11, 0x6c, 0xfa, 0x1a, 0x99, 0xf0, 0x38, 0x93, 0x87, 0x24, 0xe2, 0x8c, 0xdc, 0xb5,
-	0x48, 0xaf, 0xe6, 0x53, 0x45, 0x1b, 0x65, 0xac, 0x55, 0x9b, 0xd6, 0x5f, 0x79, 0x8e, 0xae, 0x48,
-	0xf8, 0x3f, 0xe6, 0xe8, 0x29, 0x94, 0x7d, 0x75, 0x1b, 0xaa, 0x60, 0x8c, 0xe5, 0x83, 0x7c, 0xf3,
-	0xb6, 0xac, 0x9c, 0xf9, 0xdf, 0x93, 0xe7, 0x93, 0x70, 0x25, 0x79, 0x3e, 0x09, 0xcf, 0xa6, 0xe2,
-	0x3d, 0x13, 0xf0, 0xad, 0xdc, 0x55, 0x7d, 0x12, 0x66, 0xa9, 0xdb, 0xff, 0x55, 0x83, 0xda, 0xea,
-	0xd7, 0x01, 0xed, 0xc0, 0xd6, 0x4f, 0x83, 0x8b, 0xc1, 0xf0, 0x97, 0x01, 0xee, 0x77, 0xed, 0x3e,
-	0xb6, 0x1d, 0xab, 0xeb, 0x98, 0xcf, 0x5f, 0xe8, 0xf7, 0x10, 0x82, 0x86, 0x75, 0xda, 0x3b, 0xfa,
-	0xfe, 0xa8, 0x83, 0xed, 0x7e, 0xb7, 0x73, 0x78, 0xa4, 0x6b, 0xe8, 0x01, 0x6c, 0x38, 0xa6, 0xed,
-	0xe0, 0xab, 0xee, 0x48, 0xf2, 0x4d, 0x4b, 0x5f, 0x13, 0x1e, 0xc3, 0xe3, 0x73, 0xb3, 0xe7, 0xe0,
-	0x5b, 0xfc, 0x02, 0xda, 0x82, 0xcd, 0xde, 0x70, 0x70, 0x76, 0x61, 0x0b, 0xe8, 0xf0, 0xbb, 0x0e,
-	0x16, 0x70, 0x71, 0x1f, 0x43, 0x25, 0xff, 0x16, 0xa2, 0x6d, 0x40, 0xd9, 0x16, 0x1c, 0xcb, 0x34,
-	0xb1, 0xed, 0x74, 0x1d, 0x53, 0xbf, 0x87, 0x00, 0x4a, 0xdd, 0x9e, 0x73, 0xf6, 0xb3, 0xa9, 0x6b,
-	0x62, 0x7c, 0x6a, 0x0d, 0xaf, 0xcd, 0x81, 0xbe, 0x86, 0x74, 0xa8, 0xd9, 0xc3, 0x53, 0x07, 0x9f,
-	0x98, 0x97, 0xa6, 0x63, 0x9e, 0xe8, 0x05, 0x81, 0xf4, 0xbb, 0xd6, 0x49, 0x8e, 0x14, 0xf7, 0x9f,
-	0x40, 0x39, 0xfb, 0x72, 0x8a, 0x3d, 0xdc, 0xf0, 0x77, 0x5e, 0x8c, 0x84, 0xfd, 0x3a, 0x14, 0x2e,
-	0x87, 0xcf, 0x75, 0x4d, 0x0c, 0xae, 0xba, 0x23, 0x7d, 0xed, 0xb8, 0x0f, 0x3b, 0x93, 0xc0, 0xcf,
-	0x1e, 0xa3, 0x9b, 0x3f, 0x4c, 0x8e, 0xeb, 0x8e, 0x9a, 0x8f, 0xc4, 0x74, 0xa4, 0x5d, 0x37, 0x5d,
-	0xc6, 0x67, 0x8b, 0x71, 0x7b, 0x12, 0xf8, 0x07, 0xea, 0x97, 0x43, 0x26, 0x19, 0x97, 0xa4, 0xe6,
-	0xc9, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xad, 0xb3, 0x91, 0x8a, 0xde, 0x08, 0x00, 0x00,
+	// 1213 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5d, 0x6f, 0xdb, 0xb6,
+	0x1a, 0xae, 0x12, 0x27, 0xb5, 0x5f, 0x7f, 0x44, 0x61, 0x3e, 0xaa, 0xa4, 0x07, 0xa7, 0x3e, 0x39,
+	0x07, 0x38, 0x6e, 0x07, 0x38, 0x9b, 0xd7, 0x16, 0x18, 0x8a, 0x61, 0x70, 0x63, 0xa5, 0xf9, 0xb4,
+	0x0d, 0x4a, 0xdb, 0xd0, 0xde, 0x10, 0xb4, 0xc5, 0xc8, 0x44, 0x25, 0x4b, 0x95, 0xe8, 0xa2, 0xea,
+	0xf5, 0x7e, 0xd3, 0x2e, 0xf6, 0x7b, 0xfa, 0x2f, 0x76, 0x33, 0x90, 0xa2, 0x6c, 0x27, 0xe9, 0x96,
+	0x62, 0xd8, 0x8d, 0x4d, 0x3e, 0xef, 0xf3, 0x3c, 0x22, 0x5f, 0xf2, 0x7d, 0x25, 0x68, 0x88, 0x84,
+	0x07, 0x01, 0xa7, 0xd3, 0x76, 0x9c, 0x44, 0x22, 0x42, 0xe5, 0x62, 0xbe, 0xbf, 0x3f, 0x4e, 0xb2,
+	0x58, 0x44, 0x87, 0x6f, 0x59, 0x96, 0xc6, 0x23, 0xfd, 0x97, 0xb3, 0xf6, 0x2d, 0x1d, 0x4b, 0xb9,
+	0x1f, 0x8f, 0xf2, 0x5f, 0x1d, 0xd9, 0xf3, 0xa3, 0xc8, 0x0f, 0xd8, 0xa1, 0x9a, 0x8d, 0x66, 0x57,
+	0x87, 0x74, 0x9a, 0xe9, 0xd0, 0xbf, 0x6f, 0x86, 0xbc, 0x59, 0x42, 0x05, 0x8f, 0xf4, 0xa3, 0xf7,
+	0x1f, 0xdd, 0x8c, 0x0b, 0x1e, 0xb2, 0x54, 0xd0, 0x30, 0xce, 0x09, 0x07, 0x9f, 0x2a, 0x50, 0x72,
+	0x13, 0xc6, 0xd0, 0x03, 0xb8, 0x2f, 0x12, 0xc6, 0x08, 0xf7, 0x2c, 0xa3, 0x69, 0xb4, 0x56, 0xf1,
+	0xba, 0x9c, 0x9e, 0x7a, 0xa8, 0x03, 0xa0, 0x02, 0xa9, 0xa0, 0x82, 0x59, 0x2b, 0x4d, 0xa3, 0xd5,
+	0xe8, 0x6c, 0xb5, 0xe7, 0x5b, 0x94, 0x62, 0x47, 0x86, 0x70, 0x45, 0x14, 0x43, 0x74, 0x08, 0x6a,
+	0x42, 0x44, 0x16, 0x33, 0x6b, 0x55, 0x49, 0xd0, 0x75, 0x89, 0x9b, 0xc5, 0x0c, 0x97, 0x85, 0x1e,
+	0xa1, 0x17, 0x50, 0x9f, 0xd0, 0x74, 0x42, 0x52, 0x91, 0x50, 0xc1, 0xfc, 0xcc, 0x2a, 0x29, 0xd1,
+	0xee, 0x42, 0x74, 0x42, 0xd3, 0x89, 0xa3, 0xa3, 0xb8, 0x36, 0x59, 0x9a, 0xa1, 0x73, 0x68, 0x28,
+	0x31, 0x0d, 0xfc, 0x28, 0xe1, 0x62, 0x12, 0x5a, 0x6b, 0x4a, 0xfd, 0xbf, 0x76, 0x9e, 0xc5, 0x1e,
+	0xf7, 0xb9, 0xa0, 0x41, 0x90, 0x39, 0xdc, 0x9f, 0x32, 0x4f, 0x59, 0x75, 0x0b, 0x2e, 0xae, 0x4f,
+	0x96, 0xa7, 0xe8, 0x0d, 0x6c, 0xa5, 0xdc, 0x9f, 0x52, 0x31, 0x4b, 0xd8, 0x92, 0xe3, 0xba, 0x72,
+	0x7c, 0xfc, 0x27, 0x8e, 0x4e, 0xa1, 0x58, 0xd8, 0xa2, 0xf4, 0x16, 0x86, 0x28, 0xec, 0x2e, 0xbc,
+	0xc7, 0x3c, 0x9e, 0xb0, 0x84, 0xa4, 0x33, 0x2e, 0x98, 0x85, 0x94, 0xfd, 0x57, 0x77, 0xd9, 0x1f,
+	0x29, 0x8d, 0x23, 0x25, 0x78, 0x3b, 0xfd, 0x0c, 0x8a, 0xfe, 0x03, 0x35, 0x8f, 0xa7, 0x71, 0x40,
+	0x33, 0x32, 0xa5, 0x21, 0xb3, 0xca, 0x4d, 0xa3, 0x55, 0xc1, 0x55, 0x8d, 0xf5, 0x69, 0xc8, 0x50,
+	0x13, 0xaa, 0x1e, 0x4b, 0xc7, 0x09, 0x8f, 0xe5, 0x45, 0xb1, 0x2a, 0x9a, 0xb1, 0x80, 0xd0, 0x33,
+	0xa8, 0xc6, 0x09, 0x7f, 0x4f, 0x05, 0x23, 0x6f, 0x59, 0x66, 0xd5, 0x9a, 0x46, 0xab, 0xda, 0xd9,
+	0x6e, 0xe7, 0x77, 0xa9, 0x5d, 0xdc, 0xa5, 0x76, 0x77, 0x9a, 0x61, 0xd0, 0xc4, 0x73, 0x96, 0xa1,
+	0x1f, 0xc0, 0x4c, 0x45, 0x94, 0x50, 0x9f, 0x91, 0x94, 0x09, 0xc1, 0xa7, 0x7e, 0x6a, 0xd5, 0xff,
+	0x42, 0xbb, 0xa1, 0xd9, 0x8e, 0x26, 0xa3, 0xaf, 0x01, 0xe2, 0xd9, 0x28, 0xe0, 0x63, 0xf5, 0xd8,
+	0x86, 0x92, 0x6e, 0xb6, 0x75, 0x95, 0x0c, 0x55, 0xe4, 0x9c, 0x65, 0xb8, 0x12, 0x17, 0x43, 0x64,
+	0xc3, 0x66, 0x48, 0x3f, 0x90, 0x24, 0x8a, 0x04, 0x29, 0xae, 0xbe, 0xb5, 0xa1, 0x84, 0x7b, 0xb7,
+	0x9e, 0xd9, 0xd3, 0x04, 0xbc, 0x11, 0xd2, 0x0f, 0x38, 0x8a, 0x44, 0x01, 0xa0, 0x17, 0x50, 0x1d,
+	0x27, 0x4c, 0xee, 0x57, 0xd6, 0x87, 0x65, 0x2a, 0x83, 0xfd, 0x5b, 0x06, 0x6e, 0x51, 0x3c, 0x18,
+	0x72, 0xba, 0x04, 0xa4, 0x78, 0x16, 0x7b, 0x73, 0xf1, 0xe6, 0xdd, 0xe2, 0x9c, 0x5e, 0x88, 0x3d,
+	0x16, 0xb0, 0x42, 0xbc, 0x75, 0xb7, 0x38, 0xa7, 0x2b, 0xf1, 0x63, 0x30, 0xe5, 0x25, 0xe0, 0x53,
+	0x9f, 0x78, 0x3c, 0xa5, 0xa3, 0x80, 0x79, 0xd6, 0x76, 0xd3, 0x68, 0x95, 0xf1, 0x86, 0xc6, 0x7b,
+	0x1a, 0x46, 0x87, 0xb0, 0x95, 0xb2, 0x77, 0x33, 0x36, 0x1d, 0x5f, 0x63, 0xef, 0x28, 0x36, 0x5a,
+	0x84, 0xe6, 0x82, 0x63, 0xd8, 0xd4, 0x28, 0x23, 0x7c, 0x2a, 0x58, 0xf2, 0x9e, 0x06, 0xd6, 0xee,
+	0x5d, 0x99, 0x35, 0x0b, 0xcd, 0xa9, 0x96, 0xa0, 0x2e, 0xc8, 0x6c, 0x93, 0x90, 0x25, 0x3e, 0x23,
+	0x1e, 0x0b, 0x68, 0x66, 0x3d, 0xb8, 0xcb, 0xa5, 0x1e, 0xd2, 0x0f, 0x97, 0x52, 0xd0, 0x93, 0x7c,
+	0xb5, 0xf6, 0xf1, 0x84, 0x79, 0xb3, 0x40, 0xae, 0x3d, 0x4e, 0xb8, 0x2c, 0xa7, 0xcc, 0xb2, 0x9a,
+	0x46, 0x6b, 0x0d, 0xa3, 0x45, 0x68, 0xa8, 0x23, 0x67, 0xa5, 0xf2, 0x7d, 0xb3, 0x7c, 0x56, 0x2a,
+	0x83, 0x59, 0x3d, 0x2b, 0x95, 0xab, 0x66, 0xed, 0xe0, 0x57, 0x03, 0xb6, 0xf3, 0x62, 0xb2, 0xa7,
+	0x22, 0xc9, 0xe6, 0xe9, 0x44, 0xff, 0x87, 0x8d, 0x79, 0x4b, 0x24, 0x53, 0x3a, 0x8d, 0x52, 0xdd,
+	0xfe, 0x1a, 0x73, 0xb8, 0x2f, 0x51, 0xb4, 0x03, 0xeb, 0x41, 0xe4, 0xcb, 0xf6, 0xb8, 0xa2, 0xe2,
+	0x6b, 0x41, 0xe4, 0x9f, 0x7a, 0xe8, 0x29, 0x54, 0xe6, 0x75, 0xa8, 0x3a, 0x5d, 0xb5, 0xb3, 0xfb,
+	0xf9, 0x2a, 0xc6, 0x0b, 0x22, 0x6a, 0x81, 0x19, 0xb2, 0xe4, 0x6d, 0xc0, 0x48, 0xc0, 0xe8, 0x15,
+	0x91, 0x1d, 0x48, 0x75, 0xbc, 0x1a, 0x6e, 0xe4, 0xf8, 0x05, 0xa3, 0x57, 0xb2, 0x4d, 0x1d, 0x7c,
+	0x32, 0xa0, 0x9e, 0xeb, 0x2f, 0x22, 0x5f, 0xde, 0xd9, 0x2f, 0x5f, 0xf1, 0x43, 0xa8, 0xa8, 0xba,
+	0x50, 0xee, 0x2b, 0xca, 0xbd, 0x2c, 0x01, 0xe9, 0x2b, 0x83, 0x79, 0x57, 0xe7, 0x1f, 0xf3, 0x75,
+	0xaf, 0xe6, 0xdd, 0xd8, 0xe1, 0x1f, 0xd9, 0xf5, 0x4d, 0x95, 0xbe, 0x74, 0x53, 0x8b, 0x0c, 0xad,
+	0x2d, 0x67, 0xe8, 0xbf, 0x50, 0x57, 0x4f, 0x4a, 0xd8, 0x7b, 0x9e, 0xca, 0xf2, 0x5c, 0x57, 0xd1,
+	0x9a, 0x04, 0xb1, 0xc6, 0x0e, 0x7e, 0x33, 0xa0, 0x71, 0x49, 0xe3, 0x98, 0x25, 0x97, 0x4c, 0x50,
+	0x8f, 0x0a, 0x8a, 0x0e, 0xa0, 0x9e, 0x46, 0xb3, 0x64, 0xcc, 0x88, 0x76, 0x35, 0xd4, 0x16, 0xaa,
+	0x39, 0x78, 0xa1, 0xbc, 0xbf, 0x87, 0x87, 0x13, 0xee, 0x4f, 0x58, 0x2a, 0xc8, 0xd5, 0x2c, 0x08,
+	0x32, 0x32, 0x8e, 0xc2, 0x58, 0x96, 0x87, 0x47, 0x52, 0xf6, 0x4e, 0x9f, 0x94, 0xa5, 0x29, 0xc7,
+	0x92, 0x71, 0x54, 0x10, 0x1c, 0xf6, 0x0e, 0xd9, 0xf0, 0xa8, 0x90, 0xc7, 0x34, 0x11, 0x9c, 0xde,
+	0xb6, 0xc8, 0x53, 0xf3, 0x2f, 0x4d, 0x1b, 0x16, 0xac, 0x65, 0x9b, 0x83, 0xdf, 0xe7, 0x67, 0x74,
+	0x49, 0xe3, 0x7f, 0xf0, 0x8c, 0x9e, 0x42, 0x39, 0xd4, 0xd9, 0xd0, 0x57, 0xcb, 0x5a, 0xbc, 0x0f,
+	0xaf, 0x67, 0x0b, 0xcf, 0x99, 0x7f, 0xff, 0xf0, 0x42, 0x1a, 0x2f, 0x1d, 0x5e, 0x48, 0xe3, 0x53,
+	0x4f, 0xbe, 0x4e, 0x24, 0x7c, 0xe3, 0xec, 0xaa, 0x21, 0x8d, 0x8b, 0xa3, 0x7b, 0xf2, 0x8b, 0x01,
+	0xb5, 0xe5, 0x97, 0x33, 0xda, 0x83, 0x9d, 0x1f, 0xfb, 0xe7, 0xfd, 0xc1, 0xcf, 0x7d, 0x72, 0xd2,
+	0x75, 0x4e, 0x88, 0xe3, 0xe2, 0xae, 0x6b, 0xbf, 0x7a, 0x6d, 0xde, 0x43, 0x08, 0x1a, 0xf8, 0xf8,
+	0xe8, 0xf9, 0x77, 0xcf, 0x3b, 0xc4, 0x39, 0xe9, 0x76, 0x9e, 0x3d, 0x37, 0x0d, 0xb4, 0x05, 0x1b,
+	0xae, 0xed, 0xb8, 0xe4, 0xb2, 0x3b, 0x54, 0x7c, 0x1b, 0x9b, 0x2b, 0xd2, 0x63, 0xf0, 0xf2, 0xcc,
+	0x3e, 0x72, 0xc9, 0x0d, 0xfe, 0x2a, 0xda, 0x81, 0xcd, 0xa3, 0x41, 0xff, 0xf4, 0xdc, 0x91, 0xd0,
+	0xb3, 0x6f, 0x3a, 0x44, 0xc2, 0xa5, 0x27, 0x04, 0x2a, 0xf3, 0x4f, 0x11, 0xb4, 0x0b, 0xa8, 0x58,
+	0x82, 0x8b, 0x6d, 0x9b, 0x38, 0x6e, 0xd7, 0xb5, 0xcd, 0x7b, 0x08, 0x60, 0xbd, 0x7b, 0xe4, 0x9e,
+	0xfe, 0x64, 0x9b, 0x86, 0x1c, 0x1f, 0xe3, 0xc1, 0x1b, 0xbb, 0x6f, 0xae, 0x20, 0x13, 0x6a, 0xce,
+	0xe0, 0xd8, 0x25, 0x3d, 0xfb, 0xc2, 0x76, 0xed, 0x9e, 0xb9, 0x2a, 0x91, 0x93, 0x2e, 0xee, 0xcd,
+	0x91, 0xd2, 0x93, 0x57, 0x50, 0x2e, 0x3e, 0x5c, 0xe4, 0x1a, 0xae, 0xf9, 0xbb, 0xaf, 0x87, 0xd2,
+	0xfe, 0x3e, 0xac, 0x5e, 0x0c, 0x5e, 0x99, 0x86, 0x1c, 0x5c, 0x76, 0x87, 0xe6, 0x8a, 0xdc, 0xf0,
+	0x10, 0xdb, 0x03, 0xdc, 0xb3, 0xb1, 0xdd, 0x23, 0x32, 0xb8, 0xfa, 0xf2, 0x04, 0xf6, 0xc6, 0x51,
+	0x58, 0x74, 0xbf, 0xeb, 0xdf, 0x8a, 0x2f, 0xeb, 0xae, 0x9e, 0x0f, 0xe5, 0x74, 0x68, 0xbc, 0xd9,
+	0xf7, 0xb9, 0x98, 0xcc, 0x46, 0xed, 0x71, 0x14, 0x1e, 0xea, 0x8f, 0xb9, 0x42, 0x32, 0x5a, 0x57,
+	0x9a, 0x6f, 0xff, 0x18, 0x00, 0x21, 0x37, 0x92, 0x00, 0x71, 0x0a, 0x00, 0x00,
 }
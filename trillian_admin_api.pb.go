@@ -22,8 +22,23 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 // ListTrees request.
-// No filters or pagination options are provided.
 type ListTreesRequest struct {
+	// Maximum number of trees to be returned.
+	// If zero, a server-defined default is used. Values above the server-defined
+	// maximum are capped.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	// Page token, as returned by a previous ListTrees call. If empty, listing
+	// starts from the beginning.
+	// All other request fields must match the call that produced the token.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+	// If set, only trees of the specified type are returned.
+	TreeType TreeType `protobuf:"varint,3,opt,name=tree_type,json=treeType,enum=trillian.TreeType" json:"tree_type,omitempty"`
+	// If set, only trees in the specified state are returned.
+	// Filtering by SOFT_DELETED or HARD_DELETED implies show_deleted.
+	TreeState TreeState `protobuf:"varint,4,opt,name=tree_state,json=treeState,enum=trillian.TreeState" json:"tree_state,omitempty"`
+	// If true, deleted (SOFT_DELETED or HARD_DELETED) trees are returned
+	// alongside non-deleted trees.
+	ShowDeleted bool `protobuf:"varint,5,opt,name=show_deleted,json=showDeleted" json:"show_deleted,omitempty"`
 }
 
 func (m *ListTreesRequest) Reset()                    { *m = ListTreesRequest{} }
@@ -31,12 +46,48 @@ func (m *ListTreesRequest) String() string            { return proto.CompactText
 func (*ListTreesRequest) ProtoMessage()               {}
 func (*ListTreesRequest) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
+func (m *ListTreesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListTreesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListTreesRequest) GetTreeType() TreeType {
+	if m != nil {
+		return m.TreeType
+	}
+	return TreeType_UNKNOWN_TREE_TYPE
+}
+
+func (m *ListTreesRequest) GetTreeState() TreeState {
+	if m != nil {
+		return m.TreeState
+	}
+	return TreeState_UNKNOWN_TREE_STATE
+}
+
+func (m *ListTreesRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
 // ListTrees response.
-// No pagination is provided, all trees the requester has access to are
-// returned.
 type ListTreesResponse struct {
-	// Trees matching the list request filters.
+	// Trees matching the list request filters, ordered by tree_id.
 	Tree []*Tree `protobuf:"bytes,1,rep,name=tree" json:"tree,omitempty"`
+	// Token used to retrieve the next page of results. Empty if there are no
+	// more results.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
 }
 
 func (m *ListTreesResponse) Reset()                    { *m = ListTreesResponse{} }
@@ -51,6 +102,13 @@ func (m *ListTreesResponse) GetTree() []*Tree {
 	return nil
 }
 
+func (m *ListTreesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 // GetTree request.
 type GetTreeRequest struct {
 	// ID of the tree to retrieve.
@@ -143,6 +201,24 @@ func (m *DeleteTreeRequest) GetTreeId() int64 {
 	return 0
 }
 
+// UndeleteTree request.
+type UndeleteTreeRequest struct {
+	// ID of the tree to undelete.
+	TreeId int64 `protobuf:"varint,1,opt,name=tree_id,json=treeId" json:"tree_id,omitempty"`
+}
+
+func (m *UndeleteTreeRequest) Reset()                    { *m = UndeleteTreeRequest{} }
+func (m *UndeleteTreeRequest) String() string            { return proto.CompactTextString(m) }
+func (*UndeleteTreeRequest) ProtoMessage()               {}
+func (*UndeleteTreeRequest) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{6} }
+
+func (m *UndeleteTreeRequest) GetTreeId() int64 {
+	if m != nil {
+		return m.TreeId
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ListTreesRequest)(nil), "trillian.ListTreesRequest")
 	proto.RegisterType((*ListTreesResponse)(nil), "trillian.ListTreesResponse")
@@ -150,6 +226,7 @@ func init() {
 	proto.RegisterType((*CreateTreeRequest)(nil), "trillian.CreateTreeRequest")
 	proto.RegisterType((*UpdateTreeRequest)(nil), "trillian.UpdateTreeRequest")
 	proto.RegisterType((*DeleteTreeRequest)(nil), "trillian.DeleteTreeRequest")
+	proto.RegisterType((*UndeleteTreeRequest)(nil), "trillian.UndeleteTreeRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -163,7 +240,8 @@ const _ = grpc.SupportPackageIsVersion4
 // Client API for TrillianAdmin service
 
 type TrillianAdminClient interface {
-	// Lists all trees the requester has access to.
+	// Lists the trees the requester has access to, filtered and paginated
+	// according to the request.
 	ListTrees(ctx context.Context, in *ListTreesRequest, opts ...grpc.CallOption) (*ListTreesResponse, error)
 	// Retrieves a tree by ID.
 	GetTree(ctx context.Context, in *GetTreeRequest, opts ...grpc.CallOption) (*Tree, error)
@@ -178,8 +256,11 @@ type TrillianAdminClient interface {
 	// Soft-deletes a tree.
 	// A soft-deleted tree may be undeleted for a certain period, after which
 	// it'll be permanently deleted.
-	// TODO(codingllama): Provide an undelete RPC.
 	DeleteTree(ctx context.Context, in *DeleteTreeRequest, opts ...grpc.CallOption) (*google_protobuf5.Empty, error)
+	// Undeletes a soft-deleted tree.
+	// A soft-deleted tree may be undeleted until it's permanently deleted. The
+	// undeleted tree is restored to the state it had before deletion.
+	UndeleteTree(ctx context.Context, in *UndeleteTreeRequest, opts ...grpc.CallOption) (*Tree, error)
 }
 
 type trillianAdminClient struct {
@@ -235,10 +316,20 @@ func (c *trillianAdminClient) DeleteTree(ctx context.Context, in *DeleteTreeRequ
 	return out, nil
 }
 
+func (c *trillianAdminClient) UndeleteTree(ctx context.Context, in *UndeleteTreeRequest, opts ...grpc.CallOption) (*Tree, error) {
+	out := new(Tree)
+	err := grpc.Invoke(ctx, "/trillian.TrillianAdmin/UndeleteTree", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for TrillianAdmin service
 
 type TrillianAdminServer interface {
-	// Lists all trees the requester has access to.
+	// Lists the trees the requester has access to, filtered and paginated
+	// according to the request.
 	ListTrees(context.Context, *ListTreesRequest) (*ListTreesResponse, error)
 	// Retrieves a tree by ID.
 	GetTree(context.Context, *GetTreeRequest) (*Tree, error)
@@ -253,8 +344,11 @@ type TrillianAdminServer interface {
 	// Soft-deletes a tree.
 	// A soft-deleted tree may be undeleted for a certain period, after which
 	// it'll be permanently deleted.
-	// TODO(codingllama): Provide an undelete RPC.
 	DeleteTree(context.Context, *DeleteTreeRequest) (*google_protobuf5.Empty, error)
+	// Undeletes a soft-deleted tree.
+	// A soft-deleted tree may be undeleted until it's permanently deleted. The
+	// undeleted tree is restored to the state it had before deletion.
+	UndeleteTree(context.Context, *UndeleteTreeRequest) (*Tree, error)
 }
 
 func RegisterTrillianAdminServer(s *grpc.Server, srv TrillianAdminServer) {
@@ -351,6 +445,24 @@ func _TrillianAdmin_DeleteTree_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianAdmin_UndeleteTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianAdminServer).UndeleteTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianAdmin/UndeleteTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianAdminServer).UndeleteTree(ctx, req.(*UndeleteTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TrillianAdmin_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianAdmin",
 	HandlerType: (*TrillianAdminServer)(nil),
@@ -375,6 +487,10 @@ var _TrillianAdmin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteTree",
 			Handler:    _TrillianAdmin_DeleteTree_Handler,
 		},
+		{
+			MethodName: "UndeleteTree",
+			Handler:    _TrillianAdmin_UndeleteTree_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "trillian_admin_api.proto",
@@ -383,37 +499,47 @@ var _TrillianAdmin_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("trillian_admin_api.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xd1, 0x6e, 0xd3, 0x30,
-	0x14, 0x25, 0x1b, 0x5a, 0xc7, 0x9d, 0xa8, 0xa8, 0xd1, 0xa0, 0xf3, 0x26, 0x51, 0x59, 0x3c, 0x8c,
-	0x0a, 0x25, 0xac, 0x3c, 0x20, 0x81, 0x78, 0xd8, 0x80, 0x21, 0x24, 0x90, 0xaa, 0x52, 0x84, 0xc4,
-	0x4b, 0xe5, 0xa6, 0xb7, 0xc5, 0x6a, 0x1b, 0x9b, 0xd8, 0x45, 0xaa, 0x10, 0x2f, 0xfc, 0x02, 0x9f,
-	0x06, 0x9f, 0xc0, 0x87, 0x20, 0x3b, 0xce, 0x92, 0x34, 0x1b, 0x0f, 0x3c, 0xc5, 0xf6, 0xb9, 0xf7,
-	0xdc, 0xe3, 0x73, 0x62, 0x68, 0x9b, 0x54, 0x2c, 0x16, 0x82, 0x27, 0x23, 0x3e, 0x59, 0x8a, 0x64,
-	0xc4, 0x95, 0x08, 0x55, 0x2a, 0x8d, 0x24, 0xbb, 0x39, 0x42, 0x9b, 0xf9, 0x2a, 0x43, 0x28, 0x8d,
-	0xd3, 0xb5, 0x32, 0x32, 0x9a, 0xe3, 0x5a, 0xab, 0xb1, 0xff, 0x78, 0xec, 0x68, 0x26, 0xe5, 0x6c,
-	0x81, 0x11, 0x57, 0x22, 0xe2, 0x49, 0x22, 0x0d, 0x37, 0x42, 0x26, 0xda, 0xa3, 0x1d, 0x8f, 0xba,
-	0xdd, 0x78, 0x35, 0x8d, 0xa6, 0x02, 0x17, 0x93, 0xd1, 0x92, 0xeb, 0xb9, 0xaf, 0x38, 0xdc, 0xac,
-	0xc0, 0xa5, 0x32, 0xeb, 0x0c, 0x64, 0x04, 0x6e, 0xbd, 0x15, 0xda, 0x0c, 0x53, 0x44, 0x3d, 0xc0,
-	0x2f, 0x2b, 0xd4, 0x86, 0x3d, 0x81, 0x56, 0xe9, 0x4c, 0x2b, 0x99, 0x68, 0x24, 0x0c, 0xae, 0x9b,
-	0x14, 0xb1, 0x1d, 0x74, 0xb6, 0x8f, 0xf7, 0x7a, 0xcd, 0xf0, 0xe2, 0x02, 0xb6, 0x6c, 0xe0, 0x30,
-	0xf6, 0x00, 0x9a, 0xaf, 0xd1, 0xf5, 0x79, 0x2a, 0x72, 0x17, 0x1a, 0x16, 0x19, 0x89, 0x49, 0x3b,
-	0xe8, 0x04, 0xc7, 0xdb, 0x83, 0x1d, 0xbb, 0x7d, 0x33, 0x61, 0x02, 0x5a, 0x2f, 0x52, 0xe4, 0x06,
-	0xcb, 0xd5, 0xc5, 0x8c, 0xe0, 0xaa, 0x19, 0xe4, 0x11, 0xec, 0xce, 0x71, 0x3d, 0xd2, 0x0a, 0xe3,
-	0xf6, 0x96, 0xab, 0xdb, 0x0f, 0xbd, 0x5d, 0xef, 0x15, 0xc6, 0x62, 0x2a, 0x62, 0xe7, 0xcf, 0xa0,
-	0x31, 0xc7, 0xb5, 0x3d, 0x61, 0x06, 0x5a, 0x1f, 0xd4, 0xe4, 0x3f, 0x46, 0x3d, 0x83, 0xbd, 0x95,
-	0x6b, 0x74, 0x6e, 0xfa, 0x69, 0x34, 0xcc, 0xec, 0x0c, 0x73, 0x3b, 0xc3, 0x73, 0x6b, 0xf8, 0x3b,
-	0xae, 0xe7, 0x03, 0xc8, 0xca, 0xed, 0x9a, 0x3d, 0x84, 0xd6, 0x4b, 0x5c, 0x60, 0x75, 0xea, 0x55,
-	0x76, 0xf4, 0x7e, 0x6f, 0xc3, 0xcd, 0xa1, 0x97, 0x70, 0x6a, 0xff, 0x1a, 0x72, 0x0e, 0x37, 0x2e,
-	0x42, 0x20, 0xb4, 0xd0, 0xb7, 0x99, 0x16, 0x3d, 0xbc, 0x14, 0xcb, 0x52, 0x63, 0xd7, 0xc8, 0x47,
-	0x68, 0xf8, 0x4c, 0x48, 0xbb, 0xa8, 0xac, 0xc6, 0x44, 0x37, 0xee, 0xcf, 0xd8, 0x8f, 0x5f, 0x7f,
-	0x7e, 0x6e, 0x1d, 0x11, 0x1a, 0x7d, 0x3d, 0x19, 0xa3, 0xe1, 0x27, 0x91, 0xd5, 0xa9, 0xa3, 0x6f,
-	0x5e, 0xfd, 0xf3, 0xee, 0x77, 0x32, 0x04, 0x28, 0x12, 0x24, 0x25, 0x15, 0xb5, 0x5c, 0x6b, 0xf4,
-	0x07, 0x8e, 0xfe, 0x36, 0x6b, 0x56, 0xe9, 0x9f, 0x06, 0x5d, 0x82, 0x00, 0x45, 0x58, 0x65, 0xd6,
-	0x5a, 0x84, 0x35, 0xd6, 0xae, 0x63, 0xbd, 0xdf, 0xbb, 0x77, 0x99, 0xe8, 0xb0, 0x50, 0xee, 0xc7,
-	0x14, 0xe9, 0x94, 0xc7, 0xd4, 0x32, 0xa3, 0x77, 0x6a, 0x81, 0xbf, 0xb2, 0xef, 0x27, 0xf7, 0xa8,
-	0xfb, 0x0f, 0x8f, 0xce, 0xfa, 0x70, 0x10, 0xcb, 0x65, 0x4e, 0x50, 0x7d, 0xf3, 0x67, 0xfb, 0x95,
-	0xc0, 0x4f, 0x95, 0xe8, 0xdb, 0xe3, 0x7e, 0xf0, 0x89, 0xce, 0x84, 0xf9, 0xbc, 0x1a, 0x87, 0xb1,
-	0x5c, 0x46, 0xfe, 0xed, 0xe6, 0xad, 0xe3, 0x1d, 0xd7, 0xfb, 0xf8, 0x6f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x69, 0x04, 0x25, 0x0a, 0x65, 0x04, 0x00, 0x00,
+	// 663 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdb, 0x6e, 0xd3, 0x4c,
+	0x10, 0x96, 0x7b, 0x4c, 0x26, 0x6d, 0xfe, 0x66, 0xab, 0xf6, 0x77, 0xdd, 0x56, 0x04, 0x83, 0x50,
+	0x88, 0x90, 0x4d, 0xc3, 0x5d, 0x11, 0x17, 0x2d, 0x27, 0x21, 0x81, 0x14, 0xb9, 0xa9, 0x90, 0x10,
+	0x92, 0xe5, 0xc4, 0xd3, 0x74, 0x95, 0xc4, 0x5e, 0xb2, 0x1b, 0x20, 0x45, 0xdc, 0xf0, 0x0a, 0x3c,
+	0x1a, 0x12, 0x12, 0xf7, 0x3c, 0x08, 0xda, 0xf5, 0xba, 0x76, 0xe2, 0x16, 0x55, 0x5c, 0x65, 0x3c,
+	0xdf, 0xcc, 0x37, 0xa7, 0x2f, 0x0b, 0xa6, 0x18, 0xd3, 0xe1, 0x90, 0x06, 0x91, 0x1f, 0x84, 0x23,
+	0x1a, 0xf9, 0x01, 0xa3, 0x0e, 0x1b, 0xc7, 0x22, 0x26, 0xa5, 0x14, 0xb1, 0xaa, 0xa9, 0x95, 0x20,
+	0x96, 0xd5, 0x1b, 0x4f, 0x99, 0x88, 0xdd, 0x01, 0x4e, 0x39, 0xeb, 0xea, 0x1f, 0x8d, 0xed, 0xf5,
+	0xe3, 0xb8, 0x3f, 0x44, 0x37, 0x60, 0xd4, 0x0d, 0xa2, 0x28, 0x16, 0x81, 0xa0, 0x71, 0xc4, 0x35,
+	0x5a, 0xd7, 0xa8, 0xfa, 0xea, 0x4e, 0xce, 0xdc, 0x33, 0x8a, 0xc3, 0xd0, 0x1f, 0x05, 0x7c, 0xa0,
+	0x23, 0x76, 0xe7, 0x23, 0x70, 0xc4, 0xc4, 0x34, 0x01, 0xed, 0x9f, 0x06, 0x6c, 0xbc, 0xa6, 0x5c,
+	0x74, 0xc6, 0x88, 0xdc, 0xc3, 0x0f, 0x13, 0xe4, 0x82, 0xec, 0x42, 0x99, 0x05, 0x7d, 0xf4, 0x39,
+	0xbd, 0x40, 0xd3, 0xa8, 0x1b, 0x8d, 0x65, 0xaf, 0x24, 0x1d, 0x27, 0xf4, 0x02, 0xc9, 0x3e, 0x80,
+	0x02, 0x45, 0x3c, 0xc0, 0xc8, 0x5c, 0xa8, 0x1b, 0x8d, 0xb2, 0xa7, 0xc2, 0x3b, 0xd2, 0x41, 0x5c,
+	0x28, 0x8b, 0x31, 0xa2, 0x2f, 0xa6, 0x0c, 0xcd, 0xc5, 0xba, 0xd1, 0xa8, 0xb6, 0x88, 0x73, 0x39,
+	0xad, 0x2c, 0xd3, 0x99, 0x32, 0xf4, 0x4a, 0x42, 0x5b, 0xa4, 0x05, 0xa0, 0x12, 0xb8, 0x08, 0x04,
+	0x9a, 0x4b, 0x2a, 0x63, 0x73, 0x36, 0xe3, 0x44, 0x42, 0x5e, 0x59, 0xa4, 0x26, 0xb9, 0x0d, 0x6b,
+	0xfc, 0x3c, 0xfe, 0xe4, 0x87, 0x38, 0x44, 0x81, 0xa1, 0xb9, 0x5c, 0x37, 0x1a, 0x25, 0xaf, 0x22,
+	0x7d, 0xcf, 0x12, 0x97, 0xed, 0x43, 0x2d, 0x37, 0x17, 0x67, 0x71, 0xc4, 0x91, 0xd8, 0xb0, 0x24,
+	0x49, 0x4c, 0xa3, 0xbe, 0xd8, 0xa8, 0xb4, 0xaa, 0xb3, 0x55, 0x3c, 0x85, 0x91, 0x7b, 0xf0, 0x5f,
+	0x84, 0x9f, 0x85, 0x5f, 0x18, 0x72, 0x5d, 0xba, 0xdb, 0xe9, 0xa0, 0xf6, 0x7d, 0xa8, 0xbe, 0x44,
+	0xc5, 0x9f, 0xae, 0xed, 0x7f, 0x58, 0x55, 0x93, 0xd0, 0x50, 0x2d, 0x6d, 0xd1, 0x5b, 0x91, 0x9f,
+	0xaf, 0x42, 0x9b, 0x42, 0xed, 0xe9, 0x18, 0x03, 0x81, 0xf9, 0xe8, 0xac, 0x17, 0xe3, 0xda, 0x5e,
+	0x1e, 0x42, 0x69, 0x80, 0x53, 0x9f, 0x33, 0xec, 0xa9, 0x26, 0x2a, 0xad, 0x2d, 0x47, 0x6b, 0xe3,
+	0x84, 0x61, 0x8f, 0x9e, 0xd1, 0x9e, 0x12, 0x83, 0xb7, 0x3a, 0xc0, 0xa9, 0xf4, 0xd8, 0x02, 0x6a,
+	0xa7, 0x2c, 0xfc, 0x87, 0x52, 0x8f, 0xa1, 0x32, 0x51, 0x89, 0x4a, 0x3a, 0xba, 0x9a, 0xe5, 0x24,
+	0xda, 0x71, 0x52, 0xed, 0x38, 0x2f, 0xa4, 0xba, 0xde, 0x04, 0x7c, 0xe0, 0x41, 0x12, 0x2e, 0x6d,
+	0xfb, 0x01, 0xd4, 0x92, 0xbd, 0xdf, 0x68, 0x1d, 0x0e, 0x6c, 0x9e, 0x46, 0xe1, 0x8d, 0xe3, 0x5b,
+	0xbf, 0x96, 0x60, 0xbd, 0xa3, 0x5b, 0x3e, 0x92, 0x7f, 0x29, 0xf2, 0x1e, 0xca, 0x97, 0xc7, 0x25,
+	0x56, 0x36, 0xcf, 0xbc, 0x92, 0xad, 0xdd, 0x2b, 0xb1, 0x44, 0x0d, 0xf6, 0xf6, 0xb7, 0x1f, 0xbf,
+	0xbf, 0x2f, 0x6c, 0x90, 0xaa, 0xfb, 0xf1, 0xa0, 0x8b, 0x22, 0x38, 0x70, 0x85, 0x22, 0x7c, 0x0b,
+	0xab, 0xfa, 0xb2, 0xc4, 0xcc, 0xf2, 0x67, 0x8f, 0x6d, 0xcd, 0x6d, 0xd1, 0xb6, 0x15, 0xd9, 0x1e,
+	0xb1, 0x66, 0xc9, 0xdc, 0x2f, 0x7a, 0xa6, 0x27, 0xcd, 0xaf, 0xa4, 0x03, 0x90, 0xe9, 0x80, 0xe4,
+	0x7a, 0x2b, 0xa8, 0xa3, 0x40, 0xbf, 0xa3, 0xe8, 0x37, 0xed, 0xb9, 0x5e, 0x0f, 0x8d, 0x26, 0x41,
+	0x80, 0xec, 0xe4, 0x79, 0xd6, 0x82, 0x10, 0x0a, 0xac, 0x4d, 0xc5, 0x7a, 0xb7, 0x75, 0xeb, 0xaa,
+	0xa6, 0x9d, 0xac, 0x73, 0x5d, 0x26, 0xbb, 0x71, 0xbe, 0x4c, 0xe1, 0xf2, 0xd6, 0x76, 0x41, 0x36,
+	0xcf, 0xe5, 0x93, 0x93, 0xee, 0xa8, 0xf9, 0xb7, 0x1d, 0x8d, 0x60, 0x2d, 0x2f, 0x0e, 0xb2, 0x9f,
+	0x9b, 0xa7, 0x28, 0x9a, 0xc2, 0x44, 0x8e, 0x2a, 0xd1, 0xb0, 0xef, 0x5c, 0x5f, 0xe2, 0x70, 0xa2,
+	0x79, 0x0e, 0x8d, 0xe6, 0x71, 0x1b, 0x76, 0x7a, 0xf1, 0x28, 0xed, 0x77, 0xf6, 0x55, 0x3e, 0xde,
+	0x9a, 0x51, 0xdd, 0x11, 0xa3, 0x6d, 0xe9, 0x6e, 0x1b, 0xef, 0xac, 0x3e, 0x15, 0xe7, 0x93, 0xae,
+	0xd3, 0x8b, 0x47, 0xae, 0x7e, 0x5d, 0xd3, 0xd4, 0xee, 0x8a, 0xca, 0x7d, 0xf4, 0x67, 0x00, 0x9a,
+	0xff, 0x12, 0x01, 0x07, 0x06, 0x00, 0x00,
 }
@@ -18,6 +18,8 @@ It has these top-level messages:
 	QueueLeafRequest
 	QueueLeafResponse
 	QueueLeavesResponse
+	AddSequencedLeavesRequest
+	AddSequencedLeavesResponse
 	GetInclusionProofRequest
 	GetInclusionProofResponse
 	GetInclusionProofByHashRequest
@@ -28,6 +30,12 @@ It has these top-level messages:
 	GetLeavesByHashResponse
 	GetLeavesByIndexRequest
 	GetLeavesByIndexResponse
+	GetLeavesByRangeRequest
+	GetLeavesByRangeResponse
+	GetLeavesByIdentityHashRequest
+	GetLeavesByIdentityHashResponse
+	GetLeafStatusRequest
+	GetLeafStatusResponse
 	GetSequencedLeafCountRequest
 	GetSequencedLeafCountResponse
 	GetLatestSignedLogRootRequest
@@ -38,6 +46,9 @@ It has these top-level messages:
 	MapLeafInclusion
 	GetMapLeavesRequest
 	GetMapLeavesResponse
+	GetLeafHistoryRequest
+	MapLeafRevision
+	GetLeafHistoryResponse
 	SetMapLeavesRequest
 	SetMapLeavesResponse
 	GetSignedMapRootRequest
@@ -49,6 +60,7 @@ It has these top-level messages:
 	CreateTreeRequest
 	UpdateTreeRequest
 	DeleteTreeRequest
+	UndeleteTreeRequest
 	Tree
 	SignedEntryTimestamp
 	SignedLogRoot
@@ -79,6 +91,35 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// LeafStatus describes how far a queued leaf has got through a log.
+type LeafStatus int32
+
+const (
+	// The log has no record of the leaf, which was either never queued or was
+	// lost.
+	LeafStatus_UNKNOWN_LEAF_STATUS LeafStatus = 0
+	// The leaf is queued, waiting to be sequenced.
+	LeafStatus_QUEUED LeafStatus = 1
+	// The leaf has been sequenced, and so is covered by a signed log root.
+	LeafStatus_SEQUENCED LeafStatus = 2
+)
+
+var LeafStatus_name = map[int32]string{
+	0: "UNKNOWN_LEAF_STATUS",
+	1: "QUEUED",
+	2: "SEQUENCED",
+}
+var LeafStatus_value = map[string]int32{
+	"UNKNOWN_LEAF_STATUS": 0,
+	"QUEUED":              1,
+	"SEQUENCED":           2,
+}
+
+func (x LeafStatus) String() string {
+	return proto.EnumName(LeafStatus_name, int32(x))
+}
+func (LeafStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 type LogLeaf struct {
 	// merkle_leaf_hash is over leaf data and optional extra_data.
 	MerkleLeafHash []byte `protobuf:"bytes,1,opt,name=merkle_leaf_hash,json=merkleLeafHash,proto3" json:"merkle_leaf_hash,omitempty"`
@@ -106,6 +147,9 @@ type LogLeaf struct {
 	// personality which fetches and submits the entries might set
 	// leaf_identity_hash to H(seq||certdata).
 	LeafIdentityHash []byte `protobuf:"bytes,5,opt,name=leaf_identity_hash,json=leafIdentityHash,proto3" json:"leaf_identity_hash,omitempty"`
+	// queue_timestamp_nanos is when the leaf was queued. It is set by the log on
+	// queued and dequeued leaves, and ignored in requests.
+	QueueTimestampNanos int64 `protobuf:"varint,6,opt,name=queue_timestamp_nanos,json=queueTimestampNanos" json:"queue_timestamp_nanos,omitempty"`
 }
 
 func (m *LogLeaf) Reset()                    { *m = LogLeaf{} }
@@ -148,6 +192,13 @@ func (m *LogLeaf) GetLeafIdentityHash() []byte {
 	return nil
 }
 
+func (m *LogLeaf) GetQueueTimestampNanos() int64 {
+	if m != nil {
+		return m.QueueTimestampNanos
+	}
+	return 0
+}
+
 type Proof struct {
 	LeafIndex int64    `protobuf:"varint,1,opt,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
 	Hashes    [][]byte `protobuf:"bytes,3,rep,name=hashes,proto3" json:"hashes,omitempty"`
@@ -180,6 +231,8 @@ type QueuedLogLeaf struct {
 	//  - google.rpc.ALREADY_EXISTS : the leaf is the one already present in the log.
 	Leaf   *LogLeaf           `protobuf:"bytes,1,opt,name=leaf" json:"leaf,omitempty"`
 	Status *google_rpc.Status `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// Set for newly queued leaves if the request asked for it.
+	SignedEntryTimestamp *SignedEntryTimestamp `protobuf:"bytes,3,opt,name=signed_entry_timestamp,json=signedEntryTimestamp" json:"signed_entry_timestamp,omitempty"`
 }
 
 func (m *QueuedLogLeaf) Reset()                    { *m = QueuedLogLeaf{} }
@@ -201,9 +254,18 @@ func (m *QueuedLogLeaf) GetStatus() *google_rpc.Status {
 	return nil
 }
 
+func (m *QueuedLogLeaf) GetSignedEntryTimestamp() *SignedEntryTimestamp {
+	if m != nil {
+		return m.SignedEntryTimestamp
+	}
+	return nil
+}
+
 type QueueLeavesRequest struct {
 	LogId  int64      `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	Leaves []*LogLeaf `protobuf:"bytes,2,rep,name=leaves" json:"leaves,omitempty"`
+	// If true, each newly queued leaf comes back with a SignedEntryTimestamp.
+	IncludeSignedEntryTimestamp bool `protobuf:"varint,3,opt,name=include_signed_entry_timestamp,json=includeSignedEntryTimestamp" json:"include_signed_entry_timestamp,omitempty"`
 }
 
 func (m *QueueLeavesRequest) Reset()                    { *m = QueueLeavesRequest{} }
@@ -225,9 +287,18 @@ func (m *QueueLeavesRequest) GetLeaves() []*LogLeaf {
 	return nil
 }
 
+func (m *QueueLeavesRequest) GetIncludeSignedEntryTimestamp() bool {
+	if m != nil {
+		return m.IncludeSignedEntryTimestamp
+	}
+	return false
+}
+
 type QueueLeafRequest struct {
 	LogId int64    `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	Leaf  *LogLeaf `protobuf:"bytes,2,opt,name=leaf" json:"leaf,omitempty"`
+	// If true, a newly queued leaf comes back with a SignedEntryTimestamp.
+	IncludeSignedEntryTimestamp bool `protobuf:"varint,3,opt,name=include_signed_entry_timestamp,json=includeSignedEntryTimestamp" json:"include_signed_entry_timestamp,omitempty"`
 }
 
 func (m *QueueLeafRequest) Reset()                    { *m = QueueLeafRequest{} }
@@ -249,6 +320,13 @@ func (m *QueueLeafRequest) GetLeaf() *LogLeaf {
 	return nil
 }
 
+func (m *QueueLeafRequest) GetIncludeSignedEntryTimestamp() bool {
+	if m != nil {
+		return m.IncludeSignedEntryTimestamp
+	}
+	return false
+}
+
 type QueueLeafResponse struct {
 	QueuedLeaf *QueuedLogLeaf `protobuf:"bytes,2,opt,name=queued_leaf,json=queuedLeaf" json:"queued_leaf,omitempty"`
 }
@@ -282,6 +360,53 @@ func (m *QueueLeavesResponse) GetQueuedLeaves() []*QueuedLogLeaf {
 	return nil
 }
 
+type AddSequencedLeavesRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	// Each leaf is stored at its leaf_index, which must be set by the caller.
+	Leaves []*LogLeaf `protobuf:"bytes,2,rep,name=leaves" json:"leaves,omitempty"`
+}
+
+func (m *AddSequencedLeavesRequest) Reset()                    { *m = AddSequencedLeavesRequest{} }
+func (m *AddSequencedLeavesRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddSequencedLeavesRequest) ProtoMessage()               {}
+func (*AddSequencedLeavesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *AddSequencedLeavesRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *AddSequencedLeavesRequest) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+type AddSequencedLeavesResponse struct {
+	// Same number and order as in the corresponding request. The status.code of
+	// each result is:
+	//  - google.rpc.OK : the leaf was added at its leaf_index.
+	//  - google.rpc.ALREADY_EXISTS : the same leaf is already stored at its leaf_index.
+	//  - google.rpc.FAILED_PRECONDITION : the leaf_index or leaf_identity_hash is
+	//    already taken by a different leaf.
+	Results []*QueuedLogLeaf `protobuf:"bytes,2,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *AddSequencedLeavesResponse) Reset()                    { *m = AddSequencedLeavesResponse{} }
+func (m *AddSequencedLeavesResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddSequencedLeavesResponse) ProtoMessage()               {}
+func (*AddSequencedLeavesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *AddSequencedLeavesResponse) GetResults() []*QueuedLogLeaf {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 type GetInclusionProofRequest struct {
 	LogId     int64 `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	LeafIndex int64 `protobuf:"varint,2,opt,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
@@ -291,7 +416,7 @@ type GetInclusionProofRequest struct {
 func (m *GetInclusionProofRequest) Reset()                    { *m = GetInclusionProofRequest{} }
 func (m *GetInclusionProofRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetInclusionProofRequest) ProtoMessage()               {}
-func (*GetInclusionProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*GetInclusionProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *GetInclusionProofRequest) GetLogId() int64 {
 	if m != nil {
@@ -316,12 +441,17 @@ func (m *GetInclusionProofRequest) GetTreeSize() int64 {
 
 type GetInclusionProofResponse struct {
 	Proof *Proof `protobuf:"bytes,2,opt,name=proof" json:"proof,omitempty"`
+	// The latest signed log root, read in the same storage snapshot as proof.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot" json:"signed_log_root,omitempty"`
+	// Consistency proof from the requested tree_size to signed_log_root. Set
+	// if tree_size is smaller than the tree size of signed_log_root.
+	ConsistencyProof *Proof `protobuf:"bytes,4,opt,name=consistency_proof,json=consistencyProof" json:"consistency_proof,omitempty"`
 }
 
 func (m *GetInclusionProofResponse) Reset()                    { *m = GetInclusionProofResponse{} }
 func (m *GetInclusionProofResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetInclusionProofResponse) ProtoMessage()               {}
-func (*GetInclusionProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*GetInclusionProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *GetInclusionProofResponse) GetProof() *Proof {
 	if m != nil {
@@ -330,17 +460,39 @@ func (m *GetInclusionProofResponse) GetProof() *Proof {
 	return nil
 }
 
+func (m *GetInclusionProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *GetInclusionProofResponse) GetConsistencyProof() *Proof {
+	if m != nil {
+		return m.ConsistencyProof
+	}
+	return nil
+}
+
 type GetInclusionProofByHashRequest struct {
-	LogId           int64  `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
-	LeafHash        []byte `protobuf:"bytes,2,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
-	TreeSize        int64  `protobuf:"varint,3,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
-	OrderBySequence bool   `protobuf:"varint,4,opt,name=order_by_sequence,json=orderBySequence" json:"order_by_sequence,omitempty"`
+	LogId    int64  `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	LeafHash []byte `protobuf:"bytes,2,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	// The tree size to prove inclusion in. Zero means the size of the latest
+	// signed log root, which is returned as signed_log_root.
+	TreeSize        int64 `protobuf:"varint,3,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+	OrderBySequence bool  `protobuf:"varint,4,opt,name=order_by_sequence,json=orderBySequence" json:"order_by_sequence,omitempty"`
+	// The next_page_token of a previous response, to continue from where it
+	// left off.
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+	// If positive, the response also holds a consistency proof from this tree
+	// size to the tree size the inclusion proofs are for.
+	FirstTreeSize int64 `protobuf:"varint,6,opt,name=first_tree_size,json=firstTreeSize" json:"first_tree_size,omitempty"`
 }
 
 func (m *GetInclusionProofByHashRequest) Reset()                    { *m = GetInclusionProofByHashRequest{} }
 func (m *GetInclusionProofByHashRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetInclusionProofByHashRequest) ProtoMessage()               {}
-func (*GetInclusionProofByHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*GetInclusionProofByHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *GetInclusionProofByHashRequest) GetLogId() int64 {
 	if m != nil {
@@ -370,18 +522,40 @@ func (m *GetInclusionProofByHashRequest) GetOrderBySequence() bool {
 	return false
 }
 
+func (m *GetInclusionProofByHashRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *GetInclusionProofByHashRequest) GetFirstTreeSize() int64 {
+	if m != nil {
+		return m.FirstTreeSize
+	}
+	return 0
+}
+
 type GetInclusionProofByHashResponse struct {
 	// Logs can potentially contain leaves with duplicate hashes so it's possible
 	// for this to return multiple proofs.
 	// TODO(gbelvin) only return one proof.
 	Proof []*Proof `protobuf:"bytes,2,rep,name=proof" json:"proof,omitempty"`
+	// Set if the server capped the number of proofs; pass it as page_token to
+	// fetch the rest.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
+	// The latest signed log root, read in the same storage snapshot as proof.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,4,opt,name=signed_log_root,json=signedLogRoot" json:"signed_log_root,omitempty"`
+	// Set if first_tree_size was requested, unless it is beyond the tree size
+	// of signed_log_root, i.e. the server lags behind the caller.
+	ConsistencyProof *Proof `protobuf:"bytes,5,opt,name=consistency_proof,json=consistencyProof" json:"consistency_proof,omitempty"`
 }
 
 func (m *GetInclusionProofByHashResponse) Reset()         { *m = GetInclusionProofByHashResponse{} }
 func (m *GetInclusionProofByHashResponse) String() string { return proto.CompactTextString(m) }
 func (*GetInclusionProofByHashResponse) ProtoMessage()    {}
 func (*GetInclusionProofByHashResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{10}
+	return fileDescriptor0, []int{12}
 }
 
 func (m *GetInclusionProofByHashResponse) GetProof() []*Proof {
@@ -391,6 +565,27 @@ func (m *GetInclusionProofByHashResponse) GetProof() []*Proof {
 	return nil
 }
 
+func (m *GetInclusionProofByHashResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *GetInclusionProofByHashResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *GetInclusionProofByHashResponse) GetConsistencyProof() *Proof {
+	if m != nil {
+		return m.ConsistencyProof
+	}
+	return nil
+}
+
 type GetConsistencyProofRequest struct {
 	LogId          int64 `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	FirstTreeSize  int64 `protobuf:"varint,2,opt,name=first_tree_size,json=firstTreeSize" json:"first_tree_size,omitempty"`
@@ -400,7 +595,7 @@ type GetConsistencyProofRequest struct {
 func (m *GetConsistencyProofRequest) Reset()                    { *m = GetConsistencyProofRequest{} }
 func (m *GetConsistencyProofRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetConsistencyProofRequest) ProtoMessage()               {}
-func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *GetConsistencyProofRequest) GetLogId() int64 {
 	if m != nil {
@@ -425,12 +620,14 @@ func (m *GetConsistencyProofRequest) GetSecondTreeSize() int64 {
 
 type GetConsistencyProofResponse struct {
 	Proof *Proof `protobuf:"bytes,2,opt,name=proof" json:"proof,omitempty"`
+	// The latest signed log root, read in the same storage snapshot as proof.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot" json:"signed_log_root,omitempty"`
 }
 
 func (m *GetConsistencyProofResponse) Reset()                    { *m = GetConsistencyProofResponse{} }
 func (m *GetConsistencyProofResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetConsistencyProofResponse) ProtoMessage()               {}
-func (*GetConsistencyProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*GetConsistencyProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *GetConsistencyProofResponse) GetProof() *Proof {
 	if m != nil {
@@ -439,16 +636,26 @@ func (m *GetConsistencyProofResponse) GetProof() *Proof {
 	return nil
 }
 
+func (m *GetConsistencyProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
 type GetLeavesByHashRequest struct {
 	LogId           int64    `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	LeafHash        [][]byte `protobuf:"bytes,2,rep,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
 	OrderBySequence bool     `protobuf:"varint,3,opt,name=order_by_sequence,json=orderBySequence" json:"order_by_sequence,omitempty"`
+	// The next_page_token of a previous response, to continue from where it
+	// left off.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
 }
 
 func (m *GetLeavesByHashRequest) Reset()                    { *m = GetLeavesByHashRequest{} }
 func (m *GetLeavesByHashRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetLeavesByHashRequest) ProtoMessage()               {}
-func (*GetLeavesByHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*GetLeavesByHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *GetLeavesByHashRequest) GetLogId() int64 {
 	if m != nil {
@@ -471,15 +678,25 @@ func (m *GetLeavesByHashRequest) GetOrderBySequence() bool {
 	return false
 }
 
+func (m *GetLeavesByHashRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type GetLeavesByHashResponse struct {
 	// TODO(gbelvin) reply with error codes.
 	Leaves []*LogLeaf `protobuf:"bytes,2,rep,name=leaves" json:"leaves,omitempty"`
+	// Set if the server capped the number of leaves; pass it as page_token to
+	// fetch the rest.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
 }
 
 func (m *GetLeavesByHashResponse) Reset()                    { *m = GetLeavesByHashResponse{} }
 func (m *GetLeavesByHashResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetLeavesByHashResponse) ProtoMessage()               {}
-func (*GetLeavesByHashResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*GetLeavesByHashResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *GetLeavesByHashResponse) GetLeaves() []*LogLeaf {
 	if m != nil {
@@ -488,6 +705,13 @@ func (m *GetLeavesByHashResponse) GetLeaves() []*LogLeaf {
 	return nil
 }
 
+func (m *GetLeavesByHashResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type GetLeavesByIndexRequest struct {
 	LogId     int64   `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 	LeafIndex []int64 `protobuf:"varint,2,rep,packed,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
@@ -496,7 +720,7 @@ type GetLeavesByIndexRequest struct {
 func (m *GetLeavesByIndexRequest) Reset()                    { *m = GetLeavesByIndexRequest{} }
 func (m *GetLeavesByIndexRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexRequest) ProtoMessage()               {}
-func (*GetLeavesByIndexRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*GetLeavesByIndexRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *GetLeavesByIndexRequest) GetLogId() int64 {
 	if m != nil {
@@ -520,7 +744,7 @@ type GetLeavesByIndexResponse struct {
 func (m *GetLeavesByIndexResponse) Reset()                    { *m = GetLeavesByIndexResponse{} }
 func (m *GetLeavesByIndexResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexResponse) ProtoMessage()               {}
-func (*GetLeavesByIndexResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*GetLeavesByIndexResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *GetLeavesByIndexResponse) GetLeaves() []*LogLeaf {
 	if m != nil {
@@ -529,6 +753,221 @@ func (m *GetLeavesByIndexResponse) GetLeaves() []*LogLeaf {
 	return nil
 }
 
+type GetLeavesByRangeRequest struct {
+	LogId      int64 `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	StartIndex int64 `protobuf:"varint,2,opt,name=start_index,json=startIndex" json:"start_index,omitempty"`
+	Count      int64 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *GetLeavesByRangeRequest) Reset()                    { *m = GetLeavesByRangeRequest{} }
+func (m *GetLeavesByRangeRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLeavesByRangeRequest) ProtoMessage()               {}
+func (*GetLeavesByRangeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *GetLeavesByRangeRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type GetLeavesByRangeResponse struct {
+	// leaves holds consecutive leaves from start_index on, in increasing
+	// order. There may be fewer than count leaves, as only leaves covered by
+	// the latest signed tree head are returned, and the server may limit the
+	// number of leaves per response.
+	Leaves []*LogLeaf `protobuf:"bytes,1,rep,name=leaves" json:"leaves,omitempty"`
+}
+
+func (m *GetLeavesByRangeResponse) Reset()                    { *m = GetLeavesByRangeResponse{} }
+func (m *GetLeavesByRangeResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetLeavesByRangeResponse) ProtoMessage()               {}
+func (*GetLeavesByRangeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *GetLeavesByRangeResponse) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+type GetLeavesByIdentityHashRequest struct {
+	LogId            int64    `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	LeafIdentityHash [][]byte `protobuf:"bytes,2,rep,name=leaf_identity_hash,json=leafIdentityHash,proto3" json:"leaf_identity_hash,omitempty"`
+	// If set, each leaf is returned with an inclusion proof at tree_size.
+	IncludeProof bool `protobuf:"varint,3,opt,name=include_proof,json=includeProof" json:"include_proof,omitempty"`
+	// The tree size to prove inclusion in. Zero means the size of the latest
+	// signed log root.
+	TreeSize int64 `protobuf:"varint,4,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+	// The next_page_token of a previous response, to continue from where it
+	// left off.
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+}
+
+func (m *GetLeavesByIdentityHashRequest) Reset()                    { *m = GetLeavesByIdentityHashRequest{} }
+func (m *GetLeavesByIdentityHashRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLeavesByIdentityHashRequest) ProtoMessage()               {}
+func (*GetLeavesByIdentityHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *GetLeavesByIdentityHashRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetLeavesByIdentityHashRequest) GetLeafIdentityHash() [][]byte {
+	if m != nil {
+		return m.LeafIdentityHash
+	}
+	return nil
+}
+
+func (m *GetLeavesByIdentityHashRequest) GetIncludeProof() bool {
+	if m != nil {
+		return m.IncludeProof
+	}
+	return false
+}
+
+func (m *GetLeavesByIdentityHashRequest) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetLeavesByIdentityHashRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type GetLeavesByIdentityHashResponse struct {
+	// The sequenced leaves with any of the requested identity hashes, in
+	// increasing index order. Leaves that haven't been sequenced yet are left
+	// out.
+	Leaves []*LogLeaf `protobuf:"bytes,1,rep,name=leaves" json:"leaves,omitempty"`
+	// The inclusion proofs of leaves, in the same order, if include_proof was
+	// set.
+	Proof []*Proof `protobuf:"bytes,2,rep,name=proof" json:"proof,omitempty"`
+	// The latest signed log root, read in the same storage snapshot as leaves.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot" json:"signed_log_root,omitempty"`
+	// Set if the server capped the number of leaves; pass it as page_token to
+	// fetch the rest.
+	NextPageToken string `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
+}
+
+func (m *GetLeavesByIdentityHashResponse) Reset()         { *m = GetLeavesByIdentityHashResponse{} }
+func (m *GetLeavesByIdentityHashResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLeavesByIdentityHashResponse) ProtoMessage()    {}
+func (*GetLeavesByIdentityHashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{22}
+}
+
+func (m *GetLeavesByIdentityHashResponse) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *GetLeavesByIdentityHashResponse) GetProof() []*Proof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *GetLeavesByIdentityHashResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *GetLeavesByIdentityHashResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type GetLeafStatusRequest struct {
+	LogId            int64  `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
+	LeafIdentityHash []byte `protobuf:"bytes,2,opt,name=leaf_identity_hash,json=leafIdentityHash,proto3" json:"leaf_identity_hash,omitempty"`
+}
+
+func (m *GetLeafStatusRequest) Reset()                    { *m = GetLeafStatusRequest{} }
+func (m *GetLeafStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLeafStatusRequest) ProtoMessage()               {}
+func (*GetLeafStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *GetLeafStatusRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetLeafStatusRequest) GetLeafIdentityHash() []byte {
+	if m != nil {
+		return m.LeafIdentityHash
+	}
+	return nil
+}
+
+type GetLeafStatusResponse struct {
+	Status LeafStatus `protobuf:"varint,1,opt,name=status,enum=trillian.LeafStatus" json:"status,omitempty"`
+	// When the leaf was queued, if status is QUEUED. Zero for a leaf that has
+	// been stored at its index but isn't covered by a signed log root yet.
+	QueueTimestampNanos int64 `protobuf:"varint,2,opt,name=queue_timestamp_nanos,json=queueTimestampNanos" json:"queue_timestamp_nanos,omitempty"`
+	// The index of the leaf, if status is SEQUENCED. Logs that allow
+	// duplicates report the lowest index.
+	LeafIndex int64 `protobuf:"varint,3,opt,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
+}
+
+func (m *GetLeafStatusResponse) Reset()                    { *m = GetLeafStatusResponse{} }
+func (m *GetLeafStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetLeafStatusResponse) ProtoMessage()               {}
+func (*GetLeafStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *GetLeafStatusResponse) GetStatus() LeafStatus {
+	if m != nil {
+		return m.Status
+	}
+	return LeafStatus_UNKNOWN_LEAF_STATUS
+}
+
+func (m *GetLeafStatusResponse) GetQueueTimestampNanos() int64 {
+	if m != nil {
+		return m.QueueTimestampNanos
+	}
+	return 0
+}
+
+func (m *GetLeafStatusResponse) GetLeafIndex() int64 {
+	if m != nil {
+		return m.LeafIndex
+	}
+	return 0
+}
+
 type GetSequencedLeafCountRequest struct {
 	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId" json:"log_id,omitempty"`
 }
@@ -536,7 +975,7 @@ type GetSequencedLeafCountRequest struct {
 func (m *GetSequencedLeafCountRequest) Reset()                    { *m = GetSequencedLeafCountRequest{} }
 func (m *GetSequencedLeafCountRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountRequest) ProtoMessage()               {}
-func (*GetSequencedLeafCountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*GetSequencedLeafCountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *GetSequencedLeafCountRequest) GetLogId() int64 {
 	if m != nil {
@@ -552,7 +991,7 @@ type GetSequencedLeafCountResponse struct {
 func (m *GetSequencedLeafCountResponse) Reset()                    { *m = GetSequencedLeafCountResponse{} }
 func (m *GetSequencedLeafCountResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountResponse) ProtoMessage()               {}
-func (*GetSequencedLeafCountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*GetSequencedLeafCountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *GetSequencedLeafCountResponse) GetLeafCount() int64 {
 	if m != nil {
@@ -568,7 +1007,7 @@ type GetLatestSignedLogRootRequest struct {
 func (m *GetLatestSignedLogRootRequest) Reset()                    { *m = GetLatestSignedLogRootRequest{} }
 func (m *GetLatestSignedLogRootRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootRequest) ProtoMessage()               {}
-func (*GetLatestSignedLogRootRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*GetLatestSignedLogRootRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *GetLatestSignedLogRootRequest) GetLogId() int64 {
 	if m != nil {
@@ -584,7 +1023,7 @@ type GetLatestSignedLogRootResponse struct {
 func (m *GetLatestSignedLogRootResponse) Reset()                    { *m = GetLatestSignedLogRootResponse{} }
 func (m *GetLatestSignedLogRootResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootResponse) ProtoMessage()               {}
-func (*GetLatestSignedLogRootResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*GetLatestSignedLogRootResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
 func (m *GetLatestSignedLogRootResponse) GetSignedLogRoot() *SignedLogRoot {
 	if m != nil {
@@ -602,7 +1041,7 @@ type GetEntryAndProofRequest struct {
 func (m *GetEntryAndProofRequest) Reset()                    { *m = GetEntryAndProofRequest{} }
 func (m *GetEntryAndProofRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetEntryAndProofRequest) ProtoMessage()               {}
-func (*GetEntryAndProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*GetEntryAndProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
 
 func (m *GetEntryAndProofRequest) GetLogId() int64 {
 	if m != nil {
@@ -628,12 +1067,17 @@ func (m *GetEntryAndProofRequest) GetTreeSize() int64 {
 type GetEntryAndProofResponse struct {
 	Proof *Proof   `protobuf:"bytes,2,opt,name=proof" json:"proof,omitempty"`
 	Leaf  *LogLeaf `protobuf:"bytes,3,opt,name=leaf" json:"leaf,omitempty"`
+	// The latest signed log root, read in the same storage snapshot as proof.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,4,opt,name=signed_log_root,json=signedLogRoot" json:"signed_log_root,omitempty"`
+	// Consistency proof from the requested tree_size to signed_log_root. Set
+	// if tree_size is smaller than the tree size of signed_log_root.
+	ConsistencyProof *Proof `protobuf:"bytes,5,opt,name=consistency_proof,json=consistencyProof" json:"consistency_proof,omitempty"`
 }
 
 func (m *GetEntryAndProofResponse) Reset()                    { *m = GetEntryAndProofResponse{} }
 func (m *GetEntryAndProofResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetEntryAndProofResponse) ProtoMessage()               {}
-func (*GetEntryAndProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*GetEntryAndProofResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *GetEntryAndProofResponse) GetProof() *Proof {
 	if m != nil {
@@ -649,6 +1093,20 @@ func (m *GetEntryAndProofResponse) GetLeaf() *LogLeaf {
 	return nil
 }
 
+func (m *GetEntryAndProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *GetEntryAndProofResponse) GetConsistencyProof() *Proof {
+	if m != nil {
+		return m.ConsistencyProof
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*LogLeaf)(nil), "trillian.LogLeaf")
 	proto.RegisterType((*Proof)(nil), "trillian.Proof")
@@ -657,6 +1115,8 @@ func init() {
 	proto.RegisterType((*QueueLeafRequest)(nil), "trillian.QueueLeafRequest")
 	proto.RegisterType((*QueueLeafResponse)(nil), "trillian.QueueLeafResponse")
 	proto.RegisterType((*QueueLeavesResponse)(nil), "trillian.QueueLeavesResponse")
+	proto.RegisterType((*AddSequencedLeavesRequest)(nil), "trillian.AddSequencedLeavesRequest")
+	proto.RegisterType((*AddSequencedLeavesResponse)(nil), "trillian.AddSequencedLeavesResponse")
 	proto.RegisterType((*GetInclusionProofRequest)(nil), "trillian.GetInclusionProofRequest")
 	proto.RegisterType((*GetInclusionProofResponse)(nil), "trillian.GetInclusionProofResponse")
 	proto.RegisterType((*GetInclusionProofByHashRequest)(nil), "trillian.GetInclusionProofByHashRequest")
@@ -667,12 +1127,19 @@ func init() {
 	proto.RegisterType((*GetLeavesByHashResponse)(nil), "trillian.GetLeavesByHashResponse")
 	proto.RegisterType((*GetLeavesByIndexRequest)(nil), "trillian.GetLeavesByIndexRequest")
 	proto.RegisterType((*GetLeavesByIndexResponse)(nil), "trillian.GetLeavesByIndexResponse")
+	proto.RegisterType((*GetLeavesByRangeRequest)(nil), "trillian.GetLeavesByRangeRequest")
+	proto.RegisterType((*GetLeavesByRangeResponse)(nil), "trillian.GetLeavesByRangeResponse")
+	proto.RegisterType((*GetLeavesByIdentityHashRequest)(nil), "trillian.GetLeavesByIdentityHashRequest")
+	proto.RegisterType((*GetLeavesByIdentityHashResponse)(nil), "trillian.GetLeavesByIdentityHashResponse")
+	proto.RegisterType((*GetLeafStatusRequest)(nil), "trillian.GetLeafStatusRequest")
+	proto.RegisterType((*GetLeafStatusResponse)(nil), "trillian.GetLeafStatusResponse")
 	proto.RegisterType((*GetSequencedLeafCountRequest)(nil), "trillian.GetSequencedLeafCountRequest")
 	proto.RegisterType((*GetSequencedLeafCountResponse)(nil), "trillian.GetSequencedLeafCountResponse")
 	proto.RegisterType((*GetLatestSignedLogRootRequest)(nil), "trillian.GetLatestSignedLogRootRequest")
 	proto.RegisterType((*GetLatestSignedLogRootResponse)(nil), "trillian.GetLatestSignedLogRootResponse")
 	proto.RegisterType((*GetEntryAndProofRequest)(nil), "trillian.GetEntryAndProofRequest")
 	proto.RegisterType((*GetEntryAndProofResponse)(nil), "trillian.GetEntryAndProofResponse")
+	proto.RegisterEnum("trillian.LeafStatus", LeafStatus_name, LeafStatus_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -699,8 +1166,23 @@ type TrillianLogClient interface {
 	GetEntryAndProof(ctx context.Context, in *GetEntryAndProofRequest, opts ...grpc.CallOption) (*GetEntryAndProofResponse, error)
 	// Corresponds to the LeafQueuer API
 	QueueLeaves(ctx context.Context, in *QueueLeavesRequest, opts ...grpc.CallOption) (*QueueLeavesResponse, error)
+	// AddSequencedLeaves adds leaves at the indices given by the caller to a
+	// PREORDERED_LOG tree. Gaps are allowed: leaves are integrated once all the
+	// leaves before them have been added.
+	AddSequencedLeaves(ctx context.Context, in *AddSequencedLeavesRequest, opts ...grpc.CallOption) (*AddSequencedLeavesResponse, error)
 	GetLeavesByIndex(ctx context.Context, in *GetLeavesByIndexRequest, opts ...grpc.CallOption) (*GetLeavesByIndexResponse, error)
 	GetLeavesByHash(ctx context.Context, in *GetLeavesByHashRequest, opts ...grpc.CallOption) (*GetLeavesByHashResponse, error)
+	// GetLeavesByIdentityHash looks up sequenced leaves by their
+	// LeafIdentityHash, for personalities that don't know the Merkle leaf hash.
+	GetLeavesByIdentityHash(ctx context.Context, in *GetLeavesByIdentityHashRequest, opts ...grpc.CallOption) (*GetLeavesByIdentityHashResponse, error)
+	// GetLeafStatus reports whether a leaf is queued or sequenced, looking it
+	// up by its LeafIdentityHash.
+	GetLeafStatus(ctx context.Context, in *GetLeafStatusRequest, opts ...grpc.CallOption) (*GetLeafStatusResponse, error)
+	GetLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (*GetLeavesByRangeResponse, error)
+	// StreamLeavesByRange returns the same leaves as GetLeavesByRange, in as
+	// many responses as needed, without a limit on the number of leaves.
+	// Each response is charged read quota like a GetLeavesByRange call.
+	StreamLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (TrillianLog_StreamLeavesByRangeClient, error)
 }
 
 type trillianLogClient struct {
@@ -783,6 +1265,15 @@ func (c *trillianLogClient) QueueLeaves(ctx context.Context, in *QueueLeavesRequ
 	return out, nil
 }
 
+func (c *trillianLogClient) AddSequencedLeaves(ctx context.Context, in *AddSequencedLeavesRequest, opts ...grpc.CallOption) (*AddSequencedLeavesResponse, error) {
+	out := new(AddSequencedLeavesResponse)
+	err := grpc.Invoke(ctx, "/trillian.TrillianLog/AddSequencedLeaves", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianLogClient) GetLeavesByIndex(ctx context.Context, in *GetLeavesByIndexRequest, opts ...grpc.CallOption) (*GetLeavesByIndexResponse, error) {
 	out := new(GetLeavesByIndexResponse)
 	err := grpc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByIndex", in, out, c.cc, opts...)
@@ -801,6 +1292,65 @@ func (c *trillianLogClient) GetLeavesByHash(ctx context.Context, in *GetLeavesBy
 	return out, nil
 }
 
+func (c *trillianLogClient) GetLeavesByIdentityHash(ctx context.Context, in *GetLeavesByIdentityHashRequest, opts ...grpc.CallOption) (*GetLeavesByIdentityHashResponse, error) {
+	out := new(GetLeavesByIdentityHashResponse)
+	err := grpc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByIdentityHash", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogClient) GetLeafStatus(ctx context.Context, in *GetLeafStatusRequest, opts ...grpc.CallOption) (*GetLeafStatusResponse, error) {
+	out := new(GetLeafStatusResponse)
+	err := grpc.Invoke(ctx, "/trillian.TrillianLog/GetLeafStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogClient) GetLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (*GetLeavesByRangeResponse, error) {
+	out := new(GetLeavesByRangeResponse)
+	err := grpc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByRange", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogClient) StreamLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (TrillianLog_StreamLeavesByRangeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_TrillianLog_serviceDesc.Streams[0], c.cc, "/trillian.TrillianLog/StreamLeavesByRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trillianLogStreamLeavesByRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrillianLog_StreamLeavesByRangeClient interface {
+	Recv() (*GetLeavesByRangeResponse, error)
+	grpc.ClientStream
+}
+
+type trillianLogStreamLeavesByRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *trillianLogStreamLeavesByRangeClient) Recv() (*GetLeavesByRangeResponse, error) {
+	m := new(GetLeavesByRangeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for TrillianLog service
 
 type TrillianLogServer interface {
@@ -817,8 +1367,23 @@ type TrillianLogServer interface {
 	GetEntryAndProof(context.Context, *GetEntryAndProofRequest) (*GetEntryAndProofResponse, error)
 	// Corresponds to the LeafQueuer API
 	QueueLeaves(context.Context, *QueueLeavesRequest) (*QueueLeavesResponse, error)
+	// AddSequencedLeaves adds leaves at the indices given by the caller to a
+	// PREORDERED_LOG tree. Gaps are allowed: leaves are integrated once all the
+	// leaves before them have been added.
+	AddSequencedLeaves(context.Context, *AddSequencedLeavesRequest) (*AddSequencedLeavesResponse, error)
 	GetLeavesByIndex(context.Context, *GetLeavesByIndexRequest) (*GetLeavesByIndexResponse, error)
 	GetLeavesByHash(context.Context, *GetLeavesByHashRequest) (*GetLeavesByHashResponse, error)
+	// GetLeavesByIdentityHash looks up sequenced leaves by their
+	// LeafIdentityHash, for personalities that don't know the Merkle leaf hash.
+	GetLeavesByIdentityHash(context.Context, *GetLeavesByIdentityHashRequest) (*GetLeavesByIdentityHashResponse, error)
+	// GetLeafStatus reports whether a leaf is queued or sequenced, looking it
+	// up by its LeafIdentityHash.
+	GetLeafStatus(context.Context, *GetLeafStatusRequest) (*GetLeafStatusResponse, error)
+	GetLeavesByRange(context.Context, *GetLeavesByRangeRequest) (*GetLeavesByRangeResponse, error)
+	// StreamLeavesByRange returns the same leaves as GetLeavesByRange, in as
+	// many responses as needed, without a limit on the number of leaves.
+	// Each response is charged read quota like a GetLeavesByRange call.
+	StreamLeavesByRange(*GetLeavesByRangeRequest, TrillianLog_StreamLeavesByRangeServer) error
 }
 
 func RegisterTrillianLogServer(s *grpc.Server, srv TrillianLogServer) {
@@ -969,6 +1534,24 @@ func _TrillianLog_QueueLeaves_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_AddSequencedLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSequencedLeavesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).AddSequencedLeaves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/AddSequencedLeaves",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).AddSequencedLeaves(ctx, req.(*AddSequencedLeavesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianLog_GetLeavesByIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetLeavesByIndexRequest)
 	if err := dec(in); err != nil {
@@ -1005,6 +1588,81 @@ func _TrillianLog_GetLeavesByHash_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_GetLeavesByIdentityHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeavesByIdentityHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetLeavesByIdentityHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetLeavesByIdentityHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetLeavesByIdentityHash(ctx, req.(*GetLeavesByIdentityHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLog_GetLeafStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeafStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetLeafStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetLeafStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetLeafStatus(ctx, req.(*GetLeafStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLog_GetLeavesByRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeavesByRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetLeavesByRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetLeavesByRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetLeavesByRange(ctx, req.(*GetLeavesByRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLog_StreamLeavesByRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLeavesByRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrillianLogServer).StreamLeavesByRange(m, &trillianLogStreamLeavesByRangeServer{stream})
+}
+
+type TrillianLog_StreamLeavesByRangeServer interface {
+	Send(*GetLeavesByRangeResponse) error
+	grpc.ServerStream
+}
+
+type trillianLogStreamLeavesByRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *trillianLogStreamLeavesByRangeServer) Send(m *GetLeavesByRangeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianLog",
 	HandlerType: (*TrillianLogServer)(nil),
@@ -1041,6 +1699,10 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueueLeaves",
 			Handler:    _TrillianLog_QueueLeaves_Handler,
 		},
+		{
+			MethodName: "AddSequencedLeaves",
+			Handler:    _TrillianLog_AddSequencedLeaves_Handler,
+		},
 		{
 			MethodName: "GetLeavesByIndex",
 			Handler:    _TrillianLog_GetLeavesByIndex_Handler,
@@ -1049,84 +1711,134 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLeavesByHash",
 			Handler:    _TrillianLog_GetLeavesByHash_Handler,
 		},
+		{
+			MethodName: "GetLeavesByIdentityHash",
+			Handler:    _TrillianLog_GetLeavesByIdentityHash_Handler,
+		},
+		{
+			MethodName: "GetLeafStatus",
+			Handler:    _TrillianLog_GetLeafStatus_Handler,
+		},
+		{
+			MethodName: "GetLeavesByRange",
+			Handler:    _TrillianLog_GetLeavesByRange_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLeavesByRange",
+			Handler:       _TrillianLog_StreamLeavesByRange_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "trillian_log_api.proto",
 }
 
 func init() { proto.RegisterFile("trillian_log_api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1135 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0x1b, 0xc5,
-	0x17, 0xef, 0x7a, 0x93, 0xfc, 0x93, 0x93, 0x2f, 0x67, 0xa2, 0x26, 0xee, 0x26, 0xf9, 0x93, 0x4e,
-	0x49, 0xeb, 0x86, 0xe2, 0x25, 0x41, 0x05, 0x14, 0x55, 0xa0, 0xba, 0xa9, 0xd2, 0x20, 0x03, 0xc1,
-	0xa9, 0x2a, 0x04, 0x17, 0xab, 0xb1, 0x77, 0xe2, 0xac, 0xd8, 0xec, 0xb8, 0x3b, 0xe3, 0x28, 0x69,
-	0xd5, 0x1b, 0x24, 0x2e, 0xb9, 0x82, 0x0b, 0x6e, 0x10, 0xdc, 0xf1, 0x00, 0xdc, 0xf3, 0x12, 0xbc,
-	0x02, 0x0f, 0x82, 0x76, 0x66, 0x36, 0xeb, 0x5d, 0xaf, 0xd7, 0x31, 0x12, 0x77, 0xf6, 0xf9, 0xf8,
-	0x9d, 0xdf, 0x39, 0x67, 0xce, 0x39, 0x36, 0xac, 0x88, 0xd0, 0xf3, 0x7d, 0x8f, 0x04, 0x8e, 0xcf,
-	0x3a, 0x0e, 0xe9, 0x7a, 0xb5, 0x6e, 0xc8, 0x04, 0x43, 0xd3, 0xb1, 0xdc, 0x5a, 0x88, 0x3f, 0x29,
-	0x8d, 0xb5, 0xda, 0x61, 0xac, 0xe3, 0x53, 0x3b, 0xec, 0xb6, 0x6d, 0x2e, 0x88, 0xe8, 0x71, 0xad,
-	0x58, 0xd7, 0x0a, 0xd2, 0xf5, 0x6c, 0x12, 0x04, 0x4c, 0x10, 0xe1, 0xb1, 0x40, 0x6b, 0xf1, 0x9f,
-	0x06, 0xfc, 0xaf, 0xc1, 0x3a, 0x0d, 0x4a, 0x4e, 0x50, 0x15, 0xca, 0x67, 0x34, 0xfc, 0xd6, 0xa7,
-	0x8e, 0x4f, 0xc9, 0x89, 0x73, 0x4a, 0xf8, 0x69, 0xc5, 0xd8, 0x34, 0xaa, 0x73, 0xcd, 0x05, 0x25,
-	0x8f, 0xac, 0x9e, 0x11, 0x7e, 0x8a, 0x36, 0x00, 0xa4, 0xc9, 0x39, 0xf1, 0x7b, 0xb4, 0x52, 0x92,
-	0x36, 0x33, 0x91, 0xe4, 0x45, 0x24, 0x88, 0xd4, 0xf4, 0x42, 0x84, 0xc4, 0x71, 0x89, 0x20, 0x15,
-	0x53, 0xa9, 0xa5, 0x64, 0x9f, 0x08, 0x72, 0xe5, 0xed, 0x05, 0x2e, 0xbd, 0xa8, 0x4c, 0x6c, 0x1a,
-	0x55, 0x53, 0x79, 0x1f, 0x46, 0x02, 0xf4, 0x00, 0x90, 0x52, 0xbb, 0x34, 0x10, 0x9e, 0xb8, 0x54,
-	0x44, 0x26, 0x25, 0x4a, 0x59, 0x9a, 0x69, 0x45, 0x44, 0x05, 0xef, 0xc3, 0xe4, 0x51, 0xc8, 0xd8,
-	0x49, 0x06, 0xd5, 0xc8, 0xa2, 0xae, 0xc0, 0x54, 0x84, 0x43, 0x79, 0xc5, 0xdc, 0x34, 0xab, 0x73,
-	0x4d, 0xfd, 0xed, 0xd3, 0x89, 0xe9, 0x52, 0xd9, 0xc4, 0x2d, 0x98, 0xff, 0xb2, 0x47, 0x7b, 0xd4,
-	0x8d, 0x6b, 0xb1, 0x05, 0x13, 0x91, 0xaf, 0xc4, 0x99, 0xdd, 0x5d, 0xaa, 0x5d, 0x55, 0x5b, 0x1b,
-	0x34, 0xa5, 0x1a, 0x6d, 0xc3, 0x94, 0x2a, 0xb6, 0x2c, 0xc2, 0xec, 0x2e, 0xaa, 0xa9, 0x6a, 0xd7,
-	0xc2, 0x6e, 0xbb, 0x76, 0x2c, 0x35, 0x4d, 0x6d, 0x81, 0x5f, 0x00, 0x92, 0x31, 0x1a, 0x94, 0x9c,
-	0x53, 0xde, 0xa4, 0x2f, 0x7b, 0x94, 0x0b, 0x74, 0x13, 0xa6, 0xa2, 0x16, 0x7b, 0xae, 0xa6, 0x3c,
-	0xe9, 0xb3, 0xce, 0xa1, 0x8b, 0xee, 0xc3, 0x94, 0x2f, 0xed, 0x2a, 0xa5, 0x4d, 0x33, 0x9f, 0x81,
-	0x36, 0xc0, 0x47, 0x50, 0x8e, 0x71, 0x4f, 0x46, 0xa0, 0xc6, 0x59, 0x95, 0x0a, 0xb3, 0xc2, 0x9f,
-	0xc1, 0x52, 0x1f, 0x22, 0xef, 0xb2, 0x80, 0x53, 0xf4, 0x11, 0xcc, 0xbe, 0x94, 0x25, 0x72, 0xfa,
-	0x20, 0x56, 0x13, 0x88, 0x54, 0xfd, 0x9a, 0xa0, 0x6c, 0xa3, 0xcf, 0xf8, 0x18, 0x96, 0x53, 0x89,
-	0x6b, 0xc0, 0x47, 0x30, 0x9f, 0x00, 0x26, 0x99, 0x0e, 0x85, 0x9c, 0xbb, 0x82, 0x8c, 0xb2, 0x3e,
-	0x83, 0xca, 0x01, 0x15, 0x87, 0x41, 0xdb, 0xef, 0x71, 0x8f, 0x05, 0xf2, 0x0d, 0x8c, 0xc8, 0x3e,
-	0xfd, 0x42, 0x4a, 0xd9, 0x17, 0xb2, 0x06, 0x33, 0x22, 0xa4, 0xd4, 0xe1, 0xde, 0x2b, 0x2a, 0x1f,
-	0xad, 0xd9, 0x9c, 0x8e, 0x04, 0xc7, 0xde, 0x2b, 0x8a, 0xeb, 0x70, 0x2b, 0x27, 0x9c, 0xce, 0x64,
-	0x0b, 0x26, 0xbb, 0x91, 0x40, 0x17, 0x65, 0x31, 0xc9, 0x40, 0xd9, 0x29, 0x2d, 0xfe, 0xc5, 0x80,
-	0xff, 0x0f, 0x80, 0xd4, 0xe5, 0x33, 0x1e, 0xc1, 0x7c, 0x0d, 0x66, 0x92, 0x91, 0x54, 0xe3, 0x36,
-	0xed, 0xc7, 0xc3, 0x58, 0xc4, 0x1b, 0x6d, 0xc3, 0x12, 0x0b, 0x5d, 0x1a, 0x3a, 0xad, 0x4b, 0x87,
-	0x47, 0x41, 0x82, 0x36, 0x95, 0x23, 0x37, 0xdd, 0x5c, 0x94, 0x8a, 0xfa, 0xe5, 0xb1, 0x16, 0xe3,
-	0x67, 0xf0, 0xd6, 0x50, 0x7a, 0x83, 0x99, 0x9a, 0x05, 0x99, 0x7e, 0x6f, 0x80, 0x75, 0x40, 0xc5,
-	0x13, 0x16, 0x70, 0x8f, 0x0b, 0x1a, 0xb4, 0x2f, 0xaf, 0xd3, 0x9f, 0xbb, 0xb0, 0x78, 0xe2, 0x85,
-	0x5c, 0x38, 0x49, 0x3a, 0xaa, 0x49, 0xf3, 0x52, 0xfc, 0x3c, 0xce, 0xa9, 0x0a, 0x65, 0x4e, 0xdb,
-	0x2c, 0x70, 0x9d, 0x6c, 0xde, 0x0b, 0x4a, 0x1e, 0x5b, 0xe2, 0x7d, 0x58, 0xcb, 0xa5, 0x31, 0x5e,
-	0xdf, 0x2e, 0x60, 0xe5, 0x80, 0x0a, 0xf5, 0xee, 0xfe, 0x4d, 0xbb, 0xcc, 0x54, 0xbb, 0x72, 0x3b,
-	0x62, 0xe6, 0x77, 0x64, 0x1f, 0x56, 0x07, 0x22, 0x6b, 0xee, 0x63, 0x2c, 0x88, 0x2f, 0x52, 0x28,
-	0xf2, 0xb1, 0x8f, 0x39, 0x29, 0x66, 0x6a, 0x52, 0xf0, 0x53, 0x39, 0x7b, 0x19, 0xc0, 0xf1, 0x79,
-	0x3d, 0x84, 0xf5, 0x03, 0x2a, 0xe2, 0x64, 0xe5, 0xae, 0x78, 0xc2, 0x7a, 0x81, 0x28, 0x26, 0x87,
-	0x3f, 0x86, 0x8d, 0x21, 0x6e, 0x9a, 0x42, 0xcc, 0xbe, 0x1d, 0x49, 0xfb, 0xe7, 0x5c, 0x9a, 0xe1,
-	0x0f, 0xa4, 0x7f, 0x83, 0x08, 0xca, 0xc5, 0xb1, 0xd7, 0x09, 0xe4, 0x86, 0x69, 0x32, 0x36, 0x2a,
-	0x2e, 0x91, 0xd3, 0x9b, 0xeb, 0xa7, 0x03, 0x7f, 0x02, 0x8b, 0x5c, 0x2a, 0xe4, 0xd5, 0x0e, 0x19,
-	0x13, 0x83, 0x6b, 0x32, 0xed, 0x39, 0xcf, 0xfb, 0xbf, 0x62, 0x5f, 0x76, 0xea, 0x69, 0x20, 0xc2,
-	0xcb, 0xc7, 0x81, 0xfb, 0x5f, 0xef, 0xb4, 0x53, 0xd9, 0xc6, 0x4c, 0xb4, 0xb1, 0x46, 0xe3, 0xea,
-	0xa0, 0x98, 0x85, 0x07, 0x65, 0xf7, 0x0f, 0x80, 0xd9, 0xe7, 0x5a, 0xd5, 0x60, 0x1d, 0x14, 0xc0,
-	0xcc, 0xd5, 0x81, 0x41, 0x56, 0x66, 0xe1, 0xf7, 0xdd, 0x31, 0x6b, 0x2d, 0x57, 0xa7, 0x38, 0xe2,
-	0xea, 0x77, 0x7f, 0xfd, 0xfd, 0x63, 0x09, 0xe3, 0x0d, 0xfb, 0x7c, 0xa7, 0x45, 0x05, 0xd9, 0xb1,
-	0x7d, 0xd6, 0xe1, 0xf6, 0x6b, 0x55, 0xa7, 0x37, 0xb6, 0x7a, 0x66, 0x7b, 0xc6, 0x36, 0xfa, 0xcd,
-	0x80, 0xa5, 0x81, 0xd5, 0x86, 0x70, 0x02, 0x3e, 0xec, 0x94, 0x58, 0x77, 0x0a, 0x6d, 0x34, 0x91,
-	0xba, 0x24, 0xf2, 0x08, 0xed, 0x15, 0x12, 0xb1, 0x5f, 0x27, 0x9d, 0x7a, 0xb3, 0xe7, 0xc5, 0x50,
-	0x8e, 0xaa, 0xe4, 0xef, 0x86, 0xec, 0x7d, 0xde, 0xf6, 0x45, 0xd5, 0x02, 0x12, 0xa9, 0x85, 0x64,
-	0xdd, 0xbf, 0x86, 0xa5, 0x26, 0xfd, 0xa1, 0x24, 0xbd, 0x83, 0xec, 0xe2, 0xea, 0x25, 0x3c, 0x5b,
-	0xea, 0x97, 0x18, 0xfa, 0xc9, 0x80, 0xe5, 0x9c, 0xad, 0x8a, 0xde, 0x4e, 0xc5, 0x1e, 0xb2, 0xfb,
-	0xad, 0xad, 0x11, 0x56, 0x9a, 0xdd, 0x7b, 0x92, 0xdd, 0x36, 0xaa, 0xe6, 0xb3, 0xdb, 0x6b, 0x27,
-	0x8e, 0xba, 0x80, 0x3f, 0x1b, 0x6a, 0x4d, 0x0f, 0xce, 0x27, 0xba, 0x97, 0x8a, 0x39, 0x7c, 0xf2,
-	0xad, 0xea, 0x68, 0x43, 0xcd, 0xef, 0x1d, 0xc9, 0x6f, 0x0b, 0xdd, 0x19, 0x52, 0xbd, 0x68, 0xf8,
-	0xf9, 0x9e, 0x2f, 0x11, 0xd0, 0xaf, 0x06, 0xdc, 0xcc, 0x5d, 0x59, 0xe8, 0x6e, 0x2a, 0xe0, 0xd0,
-	0x55, 0x68, 0xdd, 0x1b, 0x69, 0xa7, 0x79, 0x3d, 0x94, 0xbc, 0x6c, 0xf4, 0x6e, 0x71, 0x57, 0xe3,
-	0xc3, 0xe3, 0xaa, 0x25, 0x89, 0x7e, 0x30, 0xa0, 0x9c, 0xdd, 0x05, 0xe8, 0x76, 0x2a, 0x68, 0xde,
-	0x56, 0xb2, 0x70, 0x91, 0x89, 0xa6, 0xb4, 0x2b, 0x29, 0x3d, 0x40, 0xdb, 0xd7, 0x9f, 0x0e, 0xd4,
-	0x80, 0xd9, 0xbe, 0x9f, 0x8c, 0x68, 0x7d, 0x70, 0x0d, 0x24, 0x3f, 0xa1, 0xad, 0x8d, 0x21, 0x5a,
-	0x1d, 0xff, 0x06, 0xfa, 0x46, 0x26, 0x97, 0xba, 0x57, 0x99, 0xe4, 0xf2, 0x8e, 0x63, 0x26, 0xb9,
-	0xdc, 0x73, 0x87, 0x6f, 0xa0, 0xaf, 0x60, 0x31, 0x73, 0xa3, 0xd1, 0x66, 0xae, 0x63, 0xff, 0x9c,
-	0xde, 0x2e, 0xb0, 0x88, 0x91, 0xeb, 0x9f, 0xc3, 0xad, 0x36, 0x3b, 0x8b, 0xff, 0x51, 0xa4, 0xff,
-	0xef, 0xd5, 0x97, 0xfb, 0xf6, 0xe9, 0xe3, 0xae, 0x77, 0x14, 0x09, 0x8f, 0x8c, 0xaf, 0xad, 0x8e,
-	0x27, 0x4e, 0x7b, 0xad, 0x5a, 0x9b, 0x9d, 0xd9, 0xfa, 0x8f, 0x5f, 0xec, 0xd8, 0x9a, 0x92, 0x9e,
-	0xef, 0xff, 0x13, 0x00, 0x00, 0xff, 0xff, 0x3a, 0x13, 0x34, 0xdc, 0x5d, 0x0e, 0x00, 0x00,
+	// 1636 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xef, 0xda, 0x4e, 0x9a, 0xbc, 0xc4, 0x89, 0x33, 0x49, 0x13, 0x77, 0xd3, 0xa4, 0xe9, 0xb6,
+	0x69, 0xdd, 0x50, 0xe2, 0x26, 0xa8, 0x80, 0xa2, 0x0a, 0xc8, 0x1f, 0x13, 0x0a, 0x26, 0x4d, 0xd7,
+	0x09, 0x20, 0x2a, 0xb4, 0x6c, 0xbc, 0x13, 0x67, 0xd5, 0xcd, 0xae, 0xbb, 0x3b, 0x8e, 0x9a, 0x56,
+	0xbd, 0x20, 0xf5, 0xc8, 0x09, 0x10, 0x95, 0x7a, 0x80, 0x1b, 0x5f, 0x81, 0x2b, 0x17, 0xbe, 0x00,
+	0x57, 0x8e, 0x95, 0x38, 0x22, 0xf1, 0x09, 0xd0, 0xce, 0xcc, 0xda, 0xfb, 0xdf, 0x4e, 0xff, 0xc0,
+	0xcd, 0x7e, 0xef, 0xcd, 0x7b, 0xbf, 0x79, 0xef, 0xcd, 0x6f, 0xde, 0x2c, 0x4c, 0x12, 0x5b, 0x37,
+	0x0c, 0x5d, 0x35, 0x15, 0xc3, 0x6a, 0x28, 0x6a, 0x53, 0x5f, 0x6c, 0xda, 0x16, 0xb1, 0xd0, 0x80,
+	0x27, 0x17, 0x47, 0xbc, 0x5f, 0x4c, 0x23, 0x4e, 0x35, 0x2c, 0xab, 0x61, 0xe0, 0xb2, 0xdd, 0xac,
+	0x97, 0x1d, 0xa2, 0x92, 0x96, 0xc3, 0x15, 0xe7, 0xb8, 0x42, 0x6d, 0xea, 0x65, 0xd5, 0x34, 0x2d,
+	0xa2, 0x12, 0xdd, 0x32, 0xb9, 0x56, 0xfa, 0x47, 0x80, 0xd3, 0x55, 0xab, 0x51, 0xc5, 0xea, 0x3e,
+	0x2a, 0x41, 0xe1, 0x10, 0xdb, 0xf7, 0x0c, 0xac, 0x18, 0x58, 0xdd, 0x57, 0x0e, 0x54, 0xe7, 0xa0,
+	0x28, 0xcc, 0x09, 0xa5, 0x61, 0x79, 0x84, 0xc9, 0x5d, 0xab, 0x8f, 0x54, 0xe7, 0x00, 0xcd, 0x00,
+	0x50, 0x93, 0x23, 0xd5, 0x68, 0xe1, 0x62, 0x86, 0xda, 0x0c, 0xba, 0x92, 0xcf, 0x5c, 0x81, 0xab,
+	0xc6, 0x0f, 0x88, 0xad, 0x2a, 0x9a, 0x4a, 0xd4, 0x62, 0x96, 0xa9, 0xa9, 0x64, 0x43, 0x25, 0x6a,
+	0x7b, 0xb5, 0x6e, 0x6a, 0xf8, 0x41, 0x31, 0x37, 0x27, 0x94, 0xb2, 0x6c, 0xf5, 0x2d, 0x57, 0x80,
+	0xae, 0x01, 0x62, 0x6a, 0x0d, 0x9b, 0x44, 0x27, 0xc7, 0x0c, 0x48, 0x1f, 0xf5, 0x52, 0xa0, 0x66,
+	0x5c, 0x41, 0xa1, 0x2c, 0xc3, 0x99, 0xfb, 0x2d, 0xdc, 0xc2, 0x0a, 0xd1, 0x0f, 0xb1, 0x43, 0xd4,
+	0xc3, 0xa6, 0x62, 0xaa, 0xa6, 0xe5, 0x14, 0xfb, 0xa9, 0xdf, 0x71, 0xaa, 0xdc, 0xf1, 0x74, 0x5b,
+	0xae, 0x4a, 0xda, 0x80, 0xbe, 0x6d, 0xdb, 0xb2, 0xf6, 0x43, 0x48, 0x84, 0x30, 0x92, 0x49, 0xe8,
+	0x77, 0x63, 0x63, 0xa7, 0x98, 0x9d, 0xcb, 0x96, 0x86, 0x65, 0xfe, 0xef, 0xe3, 0xdc, 0x40, 0xa6,
+	0x90, 0x95, 0x7e, 0x15, 0x20, 0x7f, 0xc7, 0xf5, 0xae, 0x79, 0x09, 0x9c, 0x87, 0x9c, 0xbb, 0x98,
+	0x3a, 0x1a, 0x5a, 0x1e, 0x5b, 0x6c, 0x97, 0x88, 0x1b, 0xc8, 0x54, 0x8d, 0x16, 0xa0, 0x9f, 0x55,
+	0x88, 0x66, 0x6e, 0x68, 0x19, 0x2d, 0xb2, 0x12, 0x2d, 0xda, 0xcd, 0xfa, 0x62, 0x8d, 0x6a, 0x64,
+	0x6e, 0x81, 0x76, 0x60, 0xd2, 0xd1, 0x1b, 0x26, 0xd6, 0x14, 0x6c, 0x12, 0xfb, 0xb8, 0xb3, 0x4b,
+	0x9a, 0xd6, 0xa1, 0xe5, 0xd9, 0x4e, 0x90, 0x1a, 0xb5, 0xab, 0xb8, 0x66, 0xed, 0xfd, 0xca, 0x13,
+	0x4e, 0x8c, 0x54, 0x7a, 0x26, 0x00, 0xa2, 0xd0, 0xab, 0x58, 0x3d, 0xc2, 0x8e, 0x8c, 0xef, 0xb7,
+	0xb0, 0x43, 0xd0, 0x19, 0xe8, 0x77, 0xdb, 0x4d, 0xd7, 0x78, 0x2a, 0xfa, 0x0c, 0xab, 0x71, 0x4b,
+	0x43, 0x57, 0xa1, 0xdf, 0xa0, 0x76, 0xc5, 0xcc, 0x5c, 0x36, 0x7e, 0x63, 0xdc, 0x00, 0xad, 0xc3,
+	0xac, 0x6e, 0xd6, 0x8d, 0x96, 0x86, 0x95, 0x14, 0xd8, 0x03, 0xf2, 0x34, 0xb7, 0x8a, 0xc3, 0x2c,
+	0xfd, 0x20, 0x40, 0xc1, 0x43, 0xb7, 0xdf, 0x05, 0x9b, 0x97, 0xf2, 0x4c, 0x7a, 0xca, 0x5f, 0x09,
+	0xae, 0x4f, 0x61, 0xcc, 0x07, 0xcb, 0x69, 0x5a, 0xa6, 0x83, 0xd1, 0xbb, 0x30, 0x44, 0x5b, 0x4c,
+	0x53, 0x7c, 0x38, 0xa6, 0x3a, 0x38, 0x02, 0x1d, 0x22, 0x03, 0xb3, 0x75, 0x7f, 0x4b, 0x35, 0x18,
+	0x0f, 0xd4, 0x80, 0x3b, 0xbc, 0x09, 0xf9, 0x8e, 0xc3, 0x4e, 0xd2, 0x13, 0x5d, 0x0e, 0xb7, 0x5d,
+	0x1e, 0x61, 0x47, 0xfa, 0x0a, 0xce, 0xae, 0x6a, 0x5a, 0xcd, 0x4d, 0x9a, 0x59, 0xf7, 0xa4, 0xaf,
+	0xac, 0xbe, 0xd2, 0x6d, 0x10, 0xe3, 0xdc, 0x73, 0xe8, 0x4b, 0x70, 0xda, 0xc6, 0x4e, 0xcb, 0x20,
+	0x5d, 0x41, 0x7b, 0x76, 0xd2, 0x21, 0x14, 0x37, 0x31, 0xb9, 0xe5, 0x66, 0xdd, 0xd1, 0x2d, 0x93,
+	0x1e, 0xcb, 0x2e, 0x70, 0x83, 0x87, 0x36, 0x13, 0x3e, 0xb4, 0xd3, 0x30, 0x48, 0x6c, 0xec, 0xd6,
+	0xf9, 0x21, 0xa6, 0x55, 0xcd, 0xca, 0x03, 0xae, 0xa0, 0xa6, 0x3f, 0xc4, 0xd2, 0x6f, 0x02, 0x9c,
+	0x8d, 0x89, 0xc7, 0xf1, 0xcf, 0x43, 0x5f, 0xd3, 0x15, 0xf0, 0x2a, 0x8e, 0x76, 0xd0, 0x33, 0x3b,
+	0xa6, 0x45, 0xef, 0xc3, 0x28, 0x6f, 0x22, 0x17, 0x9e, 0x6d, 0x59, 0x84, 0x1f, 0xc6, 0xa9, 0xf0,
+	0x61, 0xac, 0x5a, 0x0d, 0xd9, 0xb2, 0x88, 0x9c, 0x77, 0xfc, 0x7f, 0xd1, 0x4d, 0x18, 0xab, 0x5b,
+	0xa6, 0xa3, 0x3b, 0x04, 0x9b, 0xf5, 0x63, 0x85, 0xc5, 0xcc, 0xc5, 0xc7, 0x2c, 0xf8, 0x2c, 0xa9,
+	0x44, 0x7a, 0x2e, 0xc0, 0x6c, 0x64, 0x0f, 0x6b, 0x94, 0x0d, 0xbb, 0x64, 0x6e, 0x1a, 0x06, 0x3b,
+	0xcc, 0xce, 0x58, 0x7b, 0xc0, 0xf0, 0x38, 0x3d, 0x2d, 0x6f, 0x68, 0x01, 0xc6, 0x2c, 0x5b, 0xc3,
+	0xb6, 0xb2, 0x77, 0xac, 0x38, 0xbc, 0xfa, 0x14, 0xf1, 0x80, 0x3c, 0x4a, 0x15, 0x6b, 0xc7, 0x5e,
+	0x53, 0xb8, 0xf5, 0x69, 0xaa, 0x0d, 0xac, 0x10, 0xeb, 0x1e, 0x36, 0x29, 0x6f, 0x0f, 0xca, 0x83,
+	0xae, 0x64, 0xc7, 0x15, 0xa0, 0xcb, 0x30, 0xba, 0xaf, 0xdb, 0x0e, 0x51, 0x3a, 0xd1, 0x18, 0x55,
+	0xe7, 0xa9, 0x78, 0xc7, 0x2b, 0xd5, 0xdf, 0x02, 0x9c, 0x4f, 0xdc, 0x66, 0xb4, 0x60, 0xd9, 0x94,
+	0x82, 0x5d, 0x86, 0x51, 0x13, 0x3f, 0x20, 0x8a, 0x0f, 0x56, 0x96, 0xc2, 0xca, 0xbb, 0xe2, 0xed,
+	0x36, 0xb4, 0x98, 0xc2, 0xe6, 0x5e, 0xbe, 0xb0, 0x7d, 0xbd, 0x16, 0xf6, 0x89, 0x00, 0xe2, 0x26,
+	0x26, 0xeb, 0x21, 0x79, 0x97, 0xa2, 0xc6, 0xe4, 0x33, 0x13, 0x93, 0x4f, 0xf7, 0x76, 0x77, 0x70,
+	0xdd, 0x32, 0x35, 0x25, 0x5c, 0xe6, 0x11, 0x26, 0x6f, 0x67, 0xfe, 0x89, 0x00, 0xd3, 0xb1, 0x38,
+	0xfe, 0xdb, 0x63, 0x22, 0xfd, 0x28, 0xc0, 0xe4, 0x26, 0x26, 0x8c, 0x64, 0x5e, 0xa4, 0xc1, 0xb3,
+	0x81, 0x06, 0x8f, 0xed, 0xe1, 0x6c, 0x2f, 0x3d, 0x9c, 0x0b, 0xf5, 0xb0, 0x64, 0xc0, 0x54, 0x04,
+	0x18, 0x4f, 0xce, 0x09, 0x2e, 0xcb, 0x1e, 0xdb, 0x52, 0xba, 0x1d, 0x88, 0x46, 0x59, 0xee, 0x84,
+	0x14, 0x99, 0x0d, 0x50, 0xa4, 0x54, 0x81, 0x62, 0xd4, 0xe1, 0x89, 0xf1, 0x4b, 0x8d, 0x00, 0x2e,
+	0x59, 0x35, 0x1b, 0xb8, 0x0b, 0xae, 0xf3, 0x30, 0xe4, 0x10, 0xd5, 0x26, 0x01, 0xee, 0x06, 0x2a,
+	0x62, 0xe4, 0x3d, 0x01, 0x7d, 0x75, 0xab, 0x65, 0x12, 0xde, 0x99, 0xec, 0x4f, 0x08, 0x2f, 0x0f,
+	0x14, 0xc1, 0x2b, 0x74, 0xc3, 0xfb, 0x3b, 0x23, 0xce, 0xf6, 0xbe, 0x7d, 0x63, 0x64, 0x17, 0xdc,
+	0xf1, 0x23, 0x29, 0x6b, 0xb0, 0xe8, 0x48, 0x7a, 0x11, 0xf2, 0xde, 0xb0, 0xc1, 0xce, 0x09, 0x6b,
+	0xb2, 0x61, 0x2e, 0x64, 0xa3, 0x67, 0x80, 0x6e, 0x73, 0x21, 0xba, 0x4d, 0xa7, 0x50, 0xe9, 0x4f,
+	0x46, 0x8d, 0xf1, 0x1b, 0x39, 0x71, 0x5e, 0x7a, 0x65, 0xd1, 0x97, 0xbe, 0xf6, 0x62, 0xfa, 0x3d,
+	0x17, 0xd7, 0xef, 0x77, 0x61, 0x82, 0xed, 0x6e, 0x9f, 0x0f, 0xc3, 0x2f, 0x56, 0x9c, 0xd8, 0xf7,
+	0x82, 0xf4, 0x54, 0x80, 0x33, 0x21, 0xef, 0x3c, 0x63, 0xd7, 0xda, 0x63, 0xb9, 0xeb, 0x7e, 0x64,
+	0x79, 0xc2, 0x97, 0xb1, 0x8e, 0x35, 0xb7, 0x49, 0x7e, 0x77, 0x64, 0x12, 0xdf, 0x1d, 0xa1, 0x63,
+	0x99, 0x0d, 0x4d, 0x2e, 0xd2, 0x0d, 0x38, 0xb7, 0x89, 0x89, 0x7f, 0xb8, 0xda, 0x5f, 0x77, 0xfb,
+	0x3f, 0x7d, 0xff, 0xd2, 0x7b, 0x30, 0x93, 0xb0, 0x8c, 0x6f, 0xcc, 0x0b, 0xcb, 0x4e, 0x96, 0x6f,
+	0x60, 0xa2, 0x66, 0xd2, 0xdb, 0x74, 0x7d, 0x55, 0x25, 0xd8, 0x21, 0xc1, 0xfa, 0xa5, 0xc7, 0x55,
+	0x61, 0x36, 0x69, 0x1d, 0x0f, 0x1c, 0xd3, 0x31, 0x99, 0x13, 0xdd, 0x00, 0x8c, 0x67, 0xe9, 0x18,
+	0xbe, 0x6a, 0x6a, 0xaf, 0x7b, 0x38, 0xfc, 0x4b, 0x80, 0x62, 0x34, 0xdc, 0xc9, 0x2e, 0x3d, 0xef,
+	0x3d, 0x92, 0x4d, 0x7f, 0x8f, 0xfc, 0xbf, 0x93, 0xc6, 0xc2, 0x07, 0x00, 0x9d, 0x96, 0x46, 0x53,
+	0x30, 0xbe, 0xbb, 0xf5, 0xc9, 0xd6, 0xed, 0xcf, 0xb7, 0x94, 0x6a, 0x65, 0xf5, 0x43, 0xa5, 0xb6,
+	0xb3, 0xba, 0xb3, 0x5b, 0x2b, 0x9c, 0x42, 0x00, 0xfd, 0x77, 0x76, 0x2b, 0xbb, 0x95, 0x8d, 0x82,
+	0x80, 0xf2, 0x30, 0x58, 0xab, 0xdc, 0xd9, 0xad, 0x6c, 0xad, 0x57, 0x36, 0x0a, 0x99, 0xe5, 0x67,
+	0x23, 0x30, 0xb4, 0xc3, 0xc3, 0x54, 0xad, 0x06, 0x32, 0x61, 0xb0, 0xfd, 0x36, 0x42, 0x62, 0x68,
+	0xec, 0xf7, 0xbd, 0xe3, 0xc4, 0xe9, 0x58, 0x1d, 0x4b, 0xb2, 0x54, 0xfa, 0xe6, 0x8f, 0xe7, 0xdf,
+	0x65, 0x24, 0x69, 0xa6, 0x7c, 0xb4, 0xb4, 0x87, 0x89, 0xba, 0x54, 0x36, 0xac, 0x86, 0x53, 0x7e,
+	0xc4, 0x2a, 0xfd, 0xb8, 0xcc, 0x08, 0x6b, 0x45, 0x58, 0x40, 0x3f, 0x0b, 0x30, 0x16, 0x99, 0x0e,
+	0x91, 0xd4, 0x71, 0x9e, 0xf4, 0xaa, 0x10, 0x2f, 0xa6, 0xda, 0x70, 0x20, 0x6b, 0x14, 0xc8, 0x4d,
+	0xb4, 0x92, 0x0a, 0xa4, 0xfc, 0xa8, 0xd3, 0x6b, 0x8f, 0x57, 0x74, 0xcf, 0x15, 0xab, 0x06, 0xfa,
+	0x45, 0x80, 0xa9, 0x48, 0x04, 0x36, 0x2d, 0xa0, 0x52, 0x0a, 0x88, 0xc0, 0xa4, 0x23, 0x5e, 0xed,
+	0xc1, 0x92, 0x83, 0x7e, 0x87, 0x82, 0x5e, 0x42, 0xe5, 0xf4, 0xec, 0x75, 0x70, 0xee, 0x31, 0xae,
+	0x44, 0xdf, 0x0b, 0x30, 0x1e, 0x33, 0xf0, 0xa1, 0x4b, 0x81, 0xd8, 0x09, 0x73, 0xa9, 0x38, 0xdf,
+	0xc5, 0x8a, 0xa3, 0xbb, 0x4e, 0xd1, 0x2d, 0xa0, 0x52, 0x3c, 0xba, 0x95, 0x48, 0x3b, 0xa3, 0xa7,
+	0x7c, 0xfe, 0x8b, 0x32, 0x0c, 0xba, 0x12, 0x88, 0x99, 0xcc, 0x5d, 0x62, 0xa9, 0xbb, 0x21, 0xc7,
+	0xf7, 0x06, 0xc5, 0x37, 0x8f, 0x2e, 0x26, 0x64, 0xcf, 0x3d, 0xa4, 0xce, 0x8a, 0x41, 0x3d, 0xa0,
+	0x9f, 0xd8, 0x2d, 0x12, 0x25, 0x5d, 0x74, 0x39, 0x10, 0x30, 0x91, 0xcc, 0xc5, 0x2b, 0x5d, 0xed,
+	0x38, 0xae, 0x1b, 0x14, 0x57, 0x19, 0xbd, 0x99, 0x5e, 0x55, 0x6f, 0xa2, 0xd5, 0x18, 0xcd, 0xa3,
+	0x6f, 0x05, 0x28, 0x84, 0xc9, 0x0c, 0x5d, 0x08, 0x04, 0x8d, 0xe3, 0x55, 0x51, 0x4a, 0x33, 0xe1,
+	0x90, 0x96, 0x29, 0xa4, 0x6b, 0x68, 0xa1, 0xf7, 0xd3, 0x81, 0xaa, 0x30, 0xe4, 0xfb, 0xda, 0x81,
+	0xce, 0x45, 0x69, 0xa0, 0xf3, 0xa1, 0x42, 0x9c, 0x49, 0xd0, 0xf2, 0xf8, 0xa7, 0x90, 0x0a, 0x28,
+	0xfa, 0x1d, 0x02, 0xf9, 0x8e, 0x76, 0xe2, 0x47, 0x10, 0xf1, 0x52, 0xba, 0x51, 0x3b, 0xc4, 0x5d,
+	0x9a, 0xbf, 0xc0, 0x90, 0x1c, 0xca, 0x5f, 0xdc, 0x44, 0x2e, 0x4a, 0x69, 0x26, 0x6d, 0xe7, 0x5f,
+	0xc0, 0x68, 0xe8, 0x01, 0x81, 0xe6, 0x62, 0x17, 0xfa, 0xa9, 0xe0, 0x42, 0x8a, 0x45, 0xdb, 0x73,
+	0x33, 0xf8, 0x58, 0xf0, 0xcf, 0xa5, 0xa5, 0x78, 0x68, 0xd1, 0x31, 0x58, 0xbc, 0xda, 0x83, 0x65,
+	0x3b, 0xa2, 0x0c, 0xf9, 0xc0, 0x40, 0x85, 0x66, 0xc3, 0xab, 0x83, 0x73, 0x9c, 0x78, 0x3e, 0x51,
+	0x9f, 0x90, 0x7c, 0x3a, 0xf1, 0x27, 0x24, 0xdf, 0xff, 0xec, 0x10, 0xa5, 0x34, 0x93, 0xb6, 0xf3,
+	0xaf, 0x61, 0xbc, 0x46, 0x6c, 0xac, 0x1e, 0xbe, 0x1e, 0xff, 0xd7, 0x85, 0xb5, 0x2d, 0x38, 0x5b,
+	0xb7, 0x0e, 0xbd, 0xcf, 0xba, 0xc1, 0x2f, 0xf5, 0x6b, 0xe3, 0xbe, 0x7b, 0x73, 0xb5, 0xa9, 0x6f,
+	0xbb, 0xc2, 0x6d, 0xe1, 0x4b, 0xb1, 0xa1, 0x93, 0x83, 0xd6, 0xde, 0x62, 0xdd, 0x3a, 0x2c, 0xb3,
+	0x85, 0x65, 0x6f, 0xe1, 0x5e, 0x3f, 0x5d, 0xf9, 0xd6, 0xbf, 0x03, 0x00, 0xfa, 0x86, 0x0c, 0x75,
+	0x17, 0x18, 0x00, 0x00,
 }
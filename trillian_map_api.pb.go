@@ -65,8 +65,18 @@ func (m *MapLeaf) GetExtraData() []byte {
 }
 
 type MapLeafInclusion struct {
-	Leaf      *MapLeaf `protobuf:"bytes,1,opt,name=leaf" json:"leaf,omitempty"`
+	Leaf *MapLeaf `protobuf:"bytes,1,opt,name=leaf" json:"leaf,omitempty"`
+	// inclusion holds the siblings of the path from the leaf to the root, leaf
+	// level first. Empty entries stand for the empty subtree at that position.
 	Inclusion [][]byte `protobuf:"bytes,2,rep,name=inclusion,proto3" json:"inclusion,omitempty"`
+	// inclusion_bitmap is set if the proof is compressed. inclusion then only
+	// holds the non-empty siblings, and bit i of the bitmap (bit i%8 of byte
+	// i/8, least significant bit first) is set iff sibling i is non-empty.
+	InclusionBitmap []byte `protobuf:"bytes,3,opt,name=inclusion_bitmap,json=inclusionBitmap,proto3" json:"inclusion_bitmap,omitempty"`
+	// absent is set if there is no leaf at the requested index. leaf then has an
+	// empty leaf_value, and the proof is a non-inclusion proof: it shows that
+	// the index holds the empty value of the map.
+	Absent bool `protobuf:"varint,4,opt,name=absent" json:"absent,omitempty"`
 }
 
 func (m *MapLeafInclusion) Reset()                    { *m = MapLeafInclusion{} }
@@ -88,10 +98,27 @@ func (m *MapLeafInclusion) GetInclusion() [][]byte {
 	return nil
 }
 
+func (m *MapLeafInclusion) GetInclusionBitmap() []byte {
+	if m != nil {
+		return m.InclusionBitmap
+	}
+	return nil
+}
+
+func (m *MapLeafInclusion) GetAbsent() bool {
+	if m != nil {
+		return m.Absent
+	}
+	return false
+}
+
 type GetMapLeavesRequest struct {
 	MapId    int64    `protobuf:"varint,1,opt,name=map_id,json=mapId" json:"map_id,omitempty"`
 	Index    [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
 	Revision int64    `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	// If compress_proofs is set, inclusion proofs are returned compressed.
+	// See MapLeafInclusion.inclusion_bitmap.
+	CompressProofs bool `protobuf:"varint,4,opt,name=compress_proofs,json=compressProofs" json:"compress_proofs,omitempty"`
 }
 
 func (m *GetMapLeavesRequest) Reset()                    { *m = GetMapLeavesRequest{} }
@@ -120,6 +147,13 @@ func (m *GetMapLeavesRequest) GetRevision() int64 {
 	return 0
 }
 
+func (m *GetMapLeavesRequest) GetCompressProofs() bool {
+	if m != nil {
+		return m.CompressProofs
+	}
+	return false
+}
+
 type GetMapLeavesResponse struct {
 	MapLeafInclusion []*MapLeafInclusion `protobuf:"bytes,2,rep,name=map_leaf_inclusion,json=mapLeafInclusion" json:"map_leaf_inclusion,omitempty"`
 	MapRoot          *SignedMapRoot      `protobuf:"bytes,3,opt,name=map_root,json=mapRoot" json:"map_root,omitempty"`
@@ -144,16 +178,145 @@ func (m *GetMapLeavesResponse) GetMapRoot() *SignedMapRoot {
 	return nil
 }
 
+type GetLeafHistoryRequest struct {
+	MapId int64  `protobuf:"varint,1,opt,name=map_id,json=mapId" json:"map_id,omitempty"`
+	Index []byte `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
+	// Revisions from from_revision to to_revision (inclusive) are searched.
+	// A negative to_revision stands for the latest revision of the map.
+	FromRevision int64 `protobuf:"varint,3,opt,name=from_revision,json=fromRevision" json:"from_revision,omitempty"`
+	ToRevision   int64 `protobuf:"varint,4,opt,name=to_revision,json=toRevision" json:"to_revision,omitempty"`
+	// page_size is the maximum number of revisions searched, and so returned.
+	// If zero, the server picks a default.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response, if any.
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+}
+
+func (m *GetLeafHistoryRequest) Reset()                    { *m = GetLeafHistoryRequest{} }
+func (m *GetLeafHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetLeafHistoryRequest) ProtoMessage()               {}
+func (*GetLeafHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func (m *GetLeafHistoryRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetIndex() []byte {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+func (m *GetLeafHistoryRequest) GetFromRevision() int64 {
+	if m != nil {
+		return m.FromRevision
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetToRevision() int64 {
+	if m != nil {
+		return m.ToRevision
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// MapLeafRevision is the value of a map leaf written in a given revision,
+// along with proof that the map root of that revision commits to it.
+type MapLeafRevision struct {
+	Revision int64 `protobuf:"varint,1,opt,name=revision" json:"revision,omitempty"`
+	// map_leaf_inclusion is marked absent if the leaf was deleted in revision.
+	MapLeafInclusion *MapLeafInclusion `protobuf:"bytes,2,opt,name=map_leaf_inclusion,json=mapLeafInclusion" json:"map_leaf_inclusion,omitempty"`
+	MapRoot          *SignedMapRoot    `protobuf:"bytes,3,opt,name=map_root,json=mapRoot" json:"map_root,omitempty"`
+}
+
+func (m *MapLeafRevision) Reset()                    { *m = MapLeafRevision{} }
+func (m *MapLeafRevision) String() string            { return proto.CompactTextString(m) }
+func (*MapLeafRevision) ProtoMessage()               {}
+func (*MapLeafRevision) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+
+func (m *MapLeafRevision) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *MapLeafRevision) GetMapLeafInclusion() *MapLeafInclusion {
+	if m != nil {
+		return m.MapLeafInclusion
+	}
+	return nil
+}
+
+func (m *MapLeafRevision) GetMapRoot() *SignedMapRoot {
+	if m != nil {
+		return m.MapRoot
+	}
+	return nil
+}
+
+type GetLeafHistoryResponse struct {
+	// revisions holds the revisions in which the leaf changed, in increasing
+	// order. Revisions which rewrite the value the leaf already had are skipped.
+	Revisions []*MapLeafRevision `protobuf:"bytes,1,rep,name=revisions" json:"revisions,omitempty"`
+	// next_page_token is set if there may be more revisions to return.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
+}
+
+func (m *GetLeafHistoryResponse) Reset()                    { *m = GetLeafHistoryResponse{} }
+func (m *GetLeafHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetLeafHistoryResponse) ProtoMessage()               {}
+func (*GetLeafHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+
+func (m *GetLeafHistoryResponse) GetRevisions() []*MapLeafRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+func (m *GetLeafHistoryResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type SetMapLeavesRequest struct {
-	MapId      int64           `protobuf:"varint,1,opt,name=map_id,json=mapId" json:"map_id,omitempty"`
+	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId" json:"map_id,omitempty"`
+	// Leaves to write. Leaves with an empty leaf_value are rejected with
+	// INVALID_ARGUMENT; they used to be ignored. Use delete_index to delete
+	// leaves.
 	Leaves     []*MapLeaf      `protobuf:"bytes,2,rep,name=leaves" json:"leaves,omitempty"`
 	MapperData *MapperMetadata `protobuf:"bytes,3,opt,name=mapper_data,json=mapperData" json:"mapper_data,omitempty"`
+	// Indexes of the leaves to delete from the map. An index may not be both
+	// written and deleted by the same request.
+	DeleteIndex [][]byte `protobuf:"bytes,4,rep,name=delete_index,json=deleteIndex,proto3" json:"delete_index,omitempty"`
 }
 
 func (m *SetMapLeavesRequest) Reset()                    { *m = SetMapLeavesRequest{} }
 func (m *SetMapLeavesRequest) String() string            { return proto.CompactTextString(m) }
 func (*SetMapLeavesRequest) ProtoMessage()               {}
-func (*SetMapLeavesRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*SetMapLeavesRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
 
 func (m *SetMapLeavesRequest) GetMapId() int64 {
 	if m != nil {
@@ -176,6 +339,13 @@ func (m *SetMapLeavesRequest) GetMapperData() *MapperMetadata {
 	return nil
 }
 
+func (m *SetMapLeavesRequest) GetDeleteIndex() [][]byte {
+	if m != nil {
+		return m.DeleteIndex
+	}
+	return nil
+}
+
 type SetMapLeavesResponse struct {
 	MapRoot *SignedMapRoot `protobuf:"bytes,2,opt,name=map_root,json=mapRoot" json:"map_root,omitempty"`
 }
@@ -183,7 +353,7 @@ type SetMapLeavesResponse struct {
 func (m *SetMapLeavesResponse) Reset()                    { *m = SetMapLeavesResponse{} }
 func (m *SetMapLeavesResponse) String() string            { return proto.CompactTextString(m) }
 func (*SetMapLeavesResponse) ProtoMessage()               {}
-func (*SetMapLeavesResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+func (*SetMapLeavesResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
 
 func (m *SetMapLeavesResponse) GetMapRoot() *SignedMapRoot {
 	if m != nil {
@@ -199,7 +369,7 @@ type GetSignedMapRootRequest struct {
 func (m *GetSignedMapRootRequest) Reset()                    { *m = GetSignedMapRootRequest{} }
 func (m *GetSignedMapRootRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetSignedMapRootRequest) ProtoMessage()               {}
-func (*GetSignedMapRootRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+func (*GetSignedMapRootRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9} }
 
 func (m *GetSignedMapRootRequest) GetMapId() int64 {
 	if m != nil {
@@ -217,7 +387,7 @@ func (m *GetSignedMapRootByRevisionRequest) Reset()         { *m = GetSignedMapR
 func (m *GetSignedMapRootByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootByRevisionRequest) ProtoMessage()    {}
 func (*GetSignedMapRootByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor1, []int{7}
+	return fileDescriptor1, []int{10}
 }
 
 func (m *GetSignedMapRootByRevisionRequest) GetMapId() int64 {
@@ -241,7 +411,7 @@ type GetSignedMapRootResponse struct {
 func (m *GetSignedMapRootResponse) Reset()                    { *m = GetSignedMapRootResponse{} }
 func (m *GetSignedMapRootResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetSignedMapRootResponse) ProtoMessage()               {}
-func (*GetSignedMapRootResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+func (*GetSignedMapRootResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{11} }
 
 func (m *GetSignedMapRootResponse) GetMapRoot() *SignedMapRoot {
 	if m != nil {
@@ -255,6 +425,9 @@ func init() {
 	proto.RegisterType((*MapLeafInclusion)(nil), "trillian.MapLeafInclusion")
 	proto.RegisterType((*GetMapLeavesRequest)(nil), "trillian.GetMapLeavesRequest")
 	proto.RegisterType((*GetMapLeavesResponse)(nil), "trillian.GetMapLeavesResponse")
+	proto.RegisterType((*GetLeafHistoryRequest)(nil), "trillian.GetLeafHistoryRequest")
+	proto.RegisterType((*MapLeafRevision)(nil), "trillian.MapLeafRevision")
+	proto.RegisterType((*GetLeafHistoryResponse)(nil), "trillian.GetLeafHistoryResponse")
 	proto.RegisterType((*SetMapLeavesRequest)(nil), "trillian.SetMapLeavesRequest")
 	proto.RegisterType((*SetMapLeavesResponse)(nil), "trillian.SetMapLeavesResponse")
 	proto.RegisterType((*GetSignedMapRootRequest)(nil), "trillian.GetSignedMapRootRequest")
@@ -274,9 +447,14 @@ const _ = grpc.SupportPackageIsVersion4
 
 type TrillianMapClient interface {
 	// GetLeaves returns an inclusion proof for each index requested.
-	// For indexes that do not exist, the inclusion proof will use nil for the empty leaf value.
+	// For indexes that do not exist, the inclusion is marked absent, and its
+	// proof shows that the index holds the empty leaf. Such proofs are checked
+	// with merkle.VerifyMapNonInclusionProof.
 	GetLeaves(ctx context.Context, in *GetMapLeavesRequest, opts ...grpc.CallOption) (*GetMapLeavesResponse, error)
 	SetLeaves(ctx context.Context, in *SetMapLeavesRequest, opts ...grpc.CallOption) (*SetMapLeavesResponse, error)
+	// GetLeafHistory returns each revision in which the leaf at an index
+	// changed, with an inclusion proof against the map root of that revision.
+	GetLeafHistory(ctx context.Context, in *GetLeafHistoryRequest, opts ...grpc.CallOption) (*GetLeafHistoryResponse, error)
 	GetSignedMapRoot(ctx context.Context, in *GetSignedMapRootRequest, opts ...grpc.CallOption) (*GetSignedMapRootResponse, error)
 	GetSignedMapRootByRevision(ctx context.Context, in *GetSignedMapRootByRevisionRequest, opts ...grpc.CallOption) (*GetSignedMapRootResponse, error)
 }
@@ -307,6 +485,15 @@ func (c *trillianMapClient) SetLeaves(ctx context.Context, in *SetMapLeavesReque
 	return out, nil
 }
 
+func (c *trillianMapClient) GetLeafHistory(ctx context.Context, in *GetLeafHistoryRequest, opts ...grpc.CallOption) (*GetLeafHistoryResponse, error) {
+	out := new(GetLeafHistoryResponse)
+	err := grpc.Invoke(ctx, "/trillian.TrillianMap/GetLeafHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianMapClient) GetSignedMapRoot(ctx context.Context, in *GetSignedMapRootRequest, opts ...grpc.CallOption) (*GetSignedMapRootResponse, error) {
 	out := new(GetSignedMapRootResponse)
 	err := grpc.Invoke(ctx, "/trillian.TrillianMap/GetSignedMapRoot", in, out, c.cc, opts...)
@@ -329,9 +516,14 @@ func (c *trillianMapClient) GetSignedMapRootByRevision(ctx context.Context, in *
 
 type TrillianMapServer interface {
 	// GetLeaves returns an inclusion proof for each index requested.
-	// For indexes that do not exist, the inclusion proof will use nil for the empty leaf value.
+	// For indexes that do not exist, the inclusion is marked absent, and its
+	// proof shows that the index holds the empty leaf. Such proofs are checked
+	// with merkle.VerifyMapNonInclusionProof.
 	GetLeaves(context.Context, *GetMapLeavesRequest) (*GetMapLeavesResponse, error)
 	SetLeaves(context.Context, *SetMapLeavesRequest) (*SetMapLeavesResponse, error)
+	// GetLeafHistory returns each revision in which the leaf at an index
+	// changed, with an inclusion proof against the map root of that revision.
+	GetLeafHistory(context.Context, *GetLeafHistoryRequest) (*GetLeafHistoryResponse, error)
 	GetSignedMapRoot(context.Context, *GetSignedMapRootRequest) (*GetSignedMapRootResponse, error)
 	GetSignedMapRootByRevision(context.Context, *GetSignedMapRootByRevisionRequest) (*GetSignedMapRootResponse, error)
 }
@@ -376,6 +568,24 @@ func _TrillianMap_SetLeaves_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianMap_GetLeafHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeafHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapServer).GetLeafHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMap/GetLeafHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapServer).GetLeafHistory(ctx, req.(*GetLeafHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianMap_GetSignedMapRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSignedMapRootRequest)
 	if err := dec(in); err != nil {
@@ -424,6 +634,10 @@ var _TrillianMap_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SetLeaves",
 			Handler:    _TrillianMap_SetLeaves_Handler,
 		},
+		{
+			MethodName: "GetLeafHistory",
+			Handler:    _TrillianMap_GetLeafHistory_Handler,
+		},
 		{
 			MethodName: "GetSignedMapRoot",
 			Handler:    _TrillianMap_GetSignedMapRoot_Handler,
@@ -440,43 +654,59 @@ var _TrillianMap_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("trillian_map_api.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 608 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0x49, 0x7f, 0xd2, 0x09, 0x42, 0x65, 0x5b, 0xa8, 0x31, 0x2d, 0x6a, 0x8d, 0x2a, 0xa8,
-	0x2a, 0xc5, 0x34, 0x9c, 0xe0, 0x46, 0x85, 0xd4, 0x16, 0x35, 0x55, 0x65, 0xa3, 0x22, 0x71, 0x20,
-	0x9a, 0x36, 0xdb, 0x64, 0x25, 0xdb, 0xbb, 0xd8, 0x9b, 0xa8, 0x50, 0xf5, 0xc2, 0x81, 0x07, 0x00,
-	0xce, 0xbc, 0x54, 0x5f, 0x81, 0x07, 0x41, 0xbb, 0xeb, 0xa4, 0x75, 0xe2, 0x84, 0x88, 0x9b, 0x3d,
-	0xdf, 0xcc, 0x37, 0xdf, 0x37, 0x33, 0x5a, 0x78, 0x28, 0x13, 0x16, 0x86, 0x0c, 0xe3, 0x66, 0x84,
-	0xa2, 0x89, 0x82, 0xd5, 0x44, 0xc2, 0x25, 0x27, 0x95, 0x7e, 0xdc, 0xb9, 0xd7, 0xff, 0x32, 0x88,
-	0xb3, 0xda, 0xe6, 0xbc, 0x1d, 0x52, 0x0f, 0x05, 0xf3, 0x30, 0x8e, 0xb9, 0x44, 0xc9, 0x78, 0x9c,
-	0x1a, 0xd4, 0xfd, 0x0a, 0xf3, 0x0d, 0x14, 0x87, 0x14, 0xcf, 0xc9, 0x32, 0xcc, 0xb2, 0xb8, 0x45,
-	0x2f, 0x6c, 0x6b, 0xdd, 0x7a, 0x7e, 0xd7, 0x37, 0x3f, 0xe4, 0x31, 0x2c, 0x84, 0x14, 0xcf, 0x9b,
-	0x1d, 0x4c, 0x3b, 0x76, 0x49, 0x23, 0x15, 0x15, 0xd8, 0xc7, 0xb4, 0x43, 0xd6, 0x00, 0x34, 0xd8,
-	0xc3, 0xb0, 0x4b, 0xed, 0xb2, 0x46, 0x75, 0xfa, 0x89, 0x0a, 0x28, 0x98, 0x5e, 0xc8, 0x04, 0x9b,
-	0x2d, 0x94, 0x68, 0xcf, 0x18, 0x58, 0x47, 0xde, 0xa2, 0x44, 0xf7, 0x03, 0x2c, 0x66, 0xbd, 0x0f,
-	0xe2, 0xb3, 0xb0, 0x9b, 0x32, 0x1e, 0x93, 0x4d, 0x98, 0x51, 0xf5, 0x5a, 0x43, 0xb5, 0x7e, 0xbf,
-	0x36, 0x30, 0x93, 0x65, 0xfa, 0x1a, 0x26, 0xab, 0xb0, 0xc0, 0xfa, 0x35, 0x76, 0x69, 0xbd, 0xac,
-	0x88, 0x07, 0x01, 0xf7, 0x13, 0x2c, 0xed, 0x51, 0x69, 0x2a, 0x7a, 0x34, 0xf5, 0xe9, 0xe7, 0x2e,
-	0x4d, 0x25, 0x79, 0x00, 0x73, 0x6a, 0x68, 0xac, 0xa5, 0xd9, 0xcb, 0xfe, 0x6c, 0x84, 0xe2, 0xa0,
-	0x75, 0xe3, 0xdb, 0xf0, 0x64, 0xbe, 0x1d, 0xa8, 0x24, 0xb4, 0xc7, 0x74, 0x83, 0xb2, 0x4e, 0x1f,
-	0xfc, 0xbb, 0xbf, 0x2c, 0x58, 0xce, 0x37, 0x48, 0x05, 0x8f, 0x53, 0x4a, 0xf6, 0x81, 0xa8, 0x0e,
-	0x7a, 0x26, 0x79, 0x7d, 0xd5, 0xba, 0x33, 0xe2, 0x65, 0xe0, 0xda, 0x5f, 0x8c, 0x86, 0xe7, 0x50,
-	0x87, 0x8a, 0x62, 0x4a, 0x38, 0x97, 0xba, 0x7d, 0xb5, 0xbe, 0x72, 0x53, 0x1f, 0xb0, 0x76, 0x4c,
-	0x5b, 0x0d, 0x14, 0x3e, 0xe7, 0xd2, 0x9f, 0x8f, 0xcc, 0x87, 0xfb, 0xc3, 0x82, 0xa5, 0x60, 0x7a,
-	0xdf, 0x5b, 0x30, 0x17, 0xea, 0xbc, 0x4c, 0x60, 0xc1, 0xb0, 0xb3, 0x04, 0xf2, 0x0a, 0xaa, 0x11,
-	0x0a, 0x41, 0x13, 0xb3, 0x49, 0x23, 0xc8, 0xce, 0xe5, 0x0b, 0x9a, 0x34, 0xa8, 0x44, 0x85, 0xfb,
-	0x60, 0x92, 0xf5, 0x92, 0xdf, 0xc1, 0x72, 0x50, 0x34, 0xaa, 0xdb, 0x06, 0x4b, 0x53, 0x1a, 0x7c,
-	0x01, 0x2b, 0x7b, 0x54, 0xe6, 0xc1, 0x89, 0x1e, 0xdd, 0x13, 0xd8, 0x18, 0xae, 0xd8, 0xfd, 0xe2,
-	0x67, 0x7b, 0xfc, 0xc7, 0x7c, 0x6e, 0x5f, 0x40, 0x69, 0xe8, 0x02, 0x8e, 0xc0, 0x1e, 0x55, 0xf2,
-	0xff, 0xce, 0xea, 0xd7, 0x65, 0xa8, 0xbe, 0xcf, 0x72, 0x1a, 0x28, 0xc8, 0x21, 0x2c, 0xec, 0x51,
-	0x69, 0x46, 0x46, 0xd6, 0x6e, 0xca, 0x0b, 0xce, 0xda, 0x79, 0x32, 0x0e, 0x36, 0x7a, 0xdc, 0x3b,
-	0x8a, 0x2d, 0x28, 0x62, 0x0b, 0x26, 0xb3, 0x05, 0xc5, 0x6c, 0xdf, 0x2d, 0x58, 0x1c, 0x36, 0x4f,
-	0x36, 0x72, 0x22, 0x8a, 0x56, 0xe4, 0xb8, 0x93, 0x52, 0x32, 0xf6, 0xed, 0x6f, 0xd7, 0x7f, 0x7e,
-	0x96, 0x36, 0xc9, 0x53, 0xaf, 0xb7, 0x73, 0x4a, 0x25, 0xee, 0x78, 0x11, 0x8a, 0xd4, 0xbb, 0x34,
-	0xfb, 0xb9, 0xf2, 0xd4, 0x50, 0xd3, 0xd7, 0x21, 0x4a, 0xb5, 0xb7, 0xdf, 0x16, 0x38, 0xe3, 0xb7,
-	0x4b, 0xb6, 0xc7, 0xf7, 0x1b, 0xb9, 0x81, 0xa9, 0xc4, 0x79, 0x5a, 0xdc, 0x16, 0x79, 0x36, 0x49,
-	0x9c, 0x77, 0xd9, 0x3f, 0x92, 0xab, 0xdd, 0x23, 0x78, 0x74, 0xc6, 0xa3, 0x9a, 0x79, 0x80, 0x6b,
-	0xf9, 0x77, 0x79, 0x77, 0xe9, 0xd6, 0xbe, 0xdf, 0x08, 0x76, 0xac, 0x82, 0xc7, 0xd6, 0x47, 0xa7,
-	0xcd, 0x64, 0xa7, 0x7b, 0x5a, 0x3b, 0xe3, 0x91, 0x97, 0xbd, 0xdc, 0xfd, 0xc2, 0xd3, 0x39, 0x5d,
-	0xf9, 0xf2, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe3, 0x5a, 0x04, 0x25, 0x05, 0x06, 0x00, 0x00,
+	// 854 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xcf, 0x6e, 0xdb, 0x36,
+	0x1c, 0x1e, 0x1d, 0xc7, 0xb5, 0x7f, 0x4e, 0x93, 0x8c, 0x49, 0x53, 0x55, 0x6d, 0x57, 0x47, 0x45,
+	0xd7, 0x04, 0x05, 0xac, 0xd5, 0x3b, 0x0c, 0xdb, 0x6d, 0xc1, 0x80, 0x24, 0x43, 0x53, 0x04, 0x72,
+	0xd7, 0xc3, 0x2e, 0x02, 0x1d, 0x33, 0x0e, 0x31, 0x49, 0xe4, 0x44, 0xda, 0x48, 0x52, 0xf4, 0xb2,
+	0xc3, 0xf6, 0x00, 0xdb, 0x69, 0x87, 0x3d, 0xc0, 0x76, 0xde, 0x2b, 0xec, 0x05, 0xf6, 0x0a, 0x7b,
+	0x90, 0x81, 0xa4, 0x24, 0x5b, 0xfe, 0x07, 0x63, 0x87, 0xdd, 0xc4, 0xef, 0xf7, 0xf1, 0xa7, 0x4f,
+	0xdf, 0xef, 0x23, 0x21, 0xd8, 0x53, 0x29, 0x8b, 0x22, 0x46, 0x92, 0x30, 0x26, 0x22, 0x24, 0x82,
+	0xb5, 0x45, 0xca, 0x15, 0xc7, 0xf5, 0x1c, 0x77, 0x37, 0xf3, 0x27, 0x5b, 0x71, 0x1f, 0x0d, 0x38,
+	0x1f, 0x44, 0xd4, 0x27, 0x82, 0xf9, 0x24, 0x49, 0xb8, 0x22, 0x8a, 0xf1, 0x44, 0xda, 0xaa, 0x77,
+	0x0b, 0x77, 0xce, 0x88, 0x78, 0x45, 0xc9, 0x25, 0xde, 0x85, 0x75, 0x96, 0xf4, 0xe9, 0xb5, 0x83,
+	0x5a, 0xe8, 0x60, 0x23, 0xb0, 0x0b, 0xfc, 0x10, 0x1a, 0x11, 0x25, 0x97, 0xe1, 0x15, 0x91, 0x57,
+	0x4e, 0xc5, 0x54, 0xea, 0x1a, 0x38, 0x21, 0xf2, 0x0a, 0x3f, 0x06, 0x30, 0xc5, 0x11, 0x89, 0x86,
+	0xd4, 0x59, 0x33, 0x55, 0x43, 0x7f, 0xab, 0x01, 0x5d, 0xa6, 0xd7, 0x2a, 0x25, 0x61, 0x9f, 0x28,
+	0xe2, 0x54, 0x6d, 0xd9, 0x20, 0x5f, 0x11, 0x45, 0xbc, 0x5f, 0x11, 0x6c, 0x67, 0x2f, 0x3f, 0x4d,
+	0x2e, 0xa2, 0xa1, 0x64, 0x3c, 0xc1, 0xcf, 0xa0, 0xaa, 0x1b, 0x18, 0x11, 0xcd, 0xce, 0x87, 0xed,
+	0xe2, 0x6b, 0x32, 0x66, 0x60, 0xca, 0xf8, 0x11, 0x34, 0x58, 0xbe, 0xc7, 0xa9, 0xb4, 0xd6, 0x74,
+	0xe7, 0x02, 0xc0, 0x87, 0xb0, 0x5d, 0x2c, 0xc2, 0x1e, 0x53, 0x31, 0x11, 0x99, 0xba, 0xad, 0x02,
+	0x3f, 0x32, 0x30, 0xde, 0x83, 0x1a, 0xe9, 0x49, 0x9a, 0x28, 0xa3, 0xaf, 0x1e, 0x64, 0x2b, 0xef,
+	0x27, 0x04, 0x3b, 0xc7, 0x54, 0xd9, 0xb7, 0x8e, 0xa8, 0x0c, 0xe8, 0xf7, 0x43, 0x2a, 0x15, 0xbe,
+	0x07, 0x35, 0xed, 0x3c, 0xeb, 0x1b, 0x85, 0x6b, 0xc1, 0x7a, 0x4c, 0xc4, 0x69, 0x7f, 0x6c, 0x9e,
+	0xd5, 0x62, 0x17, 0xd8, 0x85, 0x7a, 0x4a, 0x47, 0xcc, 0x88, 0x5c, 0x33, 0xf4, 0x62, 0x8d, 0x9f,
+	0xc3, 0xd6, 0x05, 0x8f, 0x45, 0x4a, 0xa5, 0x0c, 0x45, 0xca, 0xf9, 0xa5, 0xcc, 0x14, 0x6c, 0xe6,
+	0xf0, 0xb9, 0x41, 0xbd, 0x5f, 0x10, 0xec, 0x96, 0x95, 0x48, 0xc1, 0x13, 0x49, 0xf1, 0x09, 0x60,
+	0x2d, 0xc5, 0x4c, 0xa0, 0x6c, 0x46, 0xb3, 0xe3, 0xce, 0x18, 0x57, 0x58, 0x1c, 0x6c, 0xc7, 0xd3,
+	0xa6, 0x77, 0xa0, 0xae, 0x3b, 0xa5, 0x9c, 0x2b, 0xa3, 0xb3, 0xd9, 0xb9, 0x3f, 0xde, 0xdf, 0x65,
+	0x83, 0x84, 0xf6, 0xcf, 0x88, 0x08, 0x38, 0x57, 0xc1, 0x9d, 0xd8, 0x3e, 0x78, 0x7f, 0x21, 0xb8,
+	0x77, 0x4c, 0x95, 0x6e, 0x74, 0xc2, 0xa4, 0xe2, 0xe9, 0xcd, 0xea, 0x16, 0x4d, 0xe4, 0xeb, 0x29,
+	0xdc, 0xbd, 0x4c, 0x79, 0x1c, 0x4e, 0xf9, 0xb4, 0xa1, 0xc1, 0x20, 0xf7, 0xea, 0x09, 0x34, 0x15,
+	0x1f, 0x53, 0xaa, 0x86, 0x02, 0x8a, 0x17, 0x84, 0x87, 0xd0, 0x10, 0x64, 0x40, 0x43, 0xc9, 0x6e,
+	0xa9, 0xb3, 0xde, 0x42, 0x07, 0xeb, 0x41, 0x5d, 0x03, 0x5d, 0x76, 0x6b, 0x62, 0x68, 0x8a, 0x8a,
+	0x7f, 0x47, 0x13, 0xa7, 0xd6, 0x42, 0x07, 0x8d, 0xc0, 0xd0, 0xdf, 0x68, 0xc0, 0xfb, 0x03, 0xc1,
+	0x56, 0x1e, 0xae, 0xbc, 0xdf, 0xe4, 0xe0, 0xd0, 0xd4, 0xe0, 0x16, 0xd9, 0x8e, 0xfe, 0x17, 0xdb,
+	0x6f, 0x60, 0x6f, 0xda, 0xf5, 0x2c, 0x0e, 0x9f, 0x41, 0x23, 0xd7, 0x28, 0x1d, 0x64, 0x52, 0xf0,
+	0x60, 0xf6, 0xf8, 0x64, 0x8c, 0x60, 0xcc, 0xc5, 0x1f, 0xc3, 0x56, 0x42, 0xaf, 0x55, 0x38, 0x61,
+	0x52, 0xc5, 0x98, 0x74, 0x57, 0xc3, 0xe7, 0x85, 0x51, 0x7f, 0x22, 0xd8, 0xe9, 0xae, 0x7e, 0x24,
+	0x0e, 0xa1, 0x16, 0x19, 0x5e, 0x16, 0xc9, 0x39, 0x67, 0x39, 0x23, 0xe0, 0xcf, 0xa1, 0x19, 0x13,
+	0x21, 0x68, 0x6a, 0x6f, 0x0a, 0xeb, 0x85, 0x53, 0xe2, 0x0b, 0x9a, 0x9e, 0x51, 0x45, 0x74, 0x3d,
+	0x00, 0x4b, 0xd6, 0x97, 0x08, 0xde, 0x87, 0x8d, 0x3e, 0x8d, 0xa8, 0xa2, 0xa1, 0x0d, 0x57, 0xd5,
+	0x9c, 0xbf, 0xa6, 0xc5, 0x4e, 0x35, 0xe4, 0x7d, 0x0d, 0xbb, 0xdd, 0x79, 0xe7, 0x67, 0xd2, 0xfe,
+	0xca, 0x8a, 0xf6, 0x7f, 0x02, 0xf7, 0x8f, 0xa9, 0x2a, 0x17, 0x97, 0xda, 0xe0, 0xbd, 0x85, 0xfd,
+	0xe9, 0x1d, 0x47, 0x37, 0xc5, 0x18, 0x96, 0x5b, 0x38, 0x19, 0xc3, 0x4a, 0x39, 0x86, 0xde, 0x6b,
+	0x70, 0x66, 0x95, 0xfc, 0xf7, 0x2f, 0xeb, 0xfc, 0x5e, 0x85, 0xe6, 0x9b, 0x8c, 0x73, 0x46, 0x04,
+	0x7e, 0x05, 0x0d, 0x1b, 0x34, 0x3d, 0xa0, 0xc7, 0xe3, 0xed, 0x73, 0x2e, 0x45, 0xf7, 0xa3, 0x45,
+	0x65, 0xab, 0xc7, 0xfb, 0x40, 0x77, 0xeb, 0xce, 0xeb, 0xd6, 0x5d, 0xde, 0xad, 0x3b, 0xbf, 0xdb,
+	0x37, 0xb0, 0x59, 0x3e, 0x04, 0xf8, 0x49, 0x49, 0xc1, 0xec, 0xa5, 0xe4, 0xb6, 0x16, 0x13, 0x8a,
+	0xb6, 0x3f, 0x22, 0xd8, 0x9e, 0xf6, 0x14, 0xef, 0x97, 0x36, 0xce, 0x9b, 0xbc, 0xeb, 0x2d, 0xa3,
+	0x64, 0xdd, 0x5f, 0xfc, 0xf0, 0xf7, 0x3f, 0x3f, 0x57, 0x9e, 0xe1, 0xa7, 0xfe, 0xe8, 0x65, 0x8f,
+	0x2a, 0xf2, 0xd2, 0x8f, 0x89, 0x90, 0xfe, 0x3b, 0x3b, 0xf6, 0xf7, 0xbe, 0x9e, 0x95, 0xfc, 0x22,
+	0x22, 0x4a, 0xc7, 0xe1, 0x37, 0x04, 0xee, 0xe2, 0xd0, 0xe0, 0x17, 0x8b, 0xdf, 0x37, 0x13, 0xad,
+	0x95, 0xc4, 0xf9, 0x46, 0xdc, 0x21, 0x7e, 0xbe, 0x4c, 0x9c, 0xff, 0x2e, 0xcf, 0xde, 0xfb, 0xa3,
+	0xd7, 0xf0, 0xe0, 0x82, 0xc7, 0x6d, 0xfb, 0x6b, 0xd1, 0x2e, 0xff, 0x71, 0x1c, 0xed, 0x4c, 0xc4,
+	0xe8, 0x4b, 0xc1, 0xce, 0x35, 0x78, 0x8e, 0xbe, 0x75, 0x07, 0x4c, 0x5d, 0x0d, 0x7b, 0xed, 0x0b,
+	0x1e, 0xfb, 0xd9, 0x3f, 0x49, 0xbe, 0xb1, 0x57, 0x33, 0x3b, 0x3f, 0xfd, 0x77, 0x00, 0x0f, 0x61,
+	0x78, 0xec, 0xdf, 0x08, 0x00, 0x00,
 }
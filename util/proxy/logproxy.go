@@ -16,6 +16,8 @@
 package proxy
 
 import (
+	"io"
+
 	"github.com/google/trillian"
 	"golang.org/x/net/context"
 )
@@ -43,6 +45,11 @@ func (p *Log) QueueLeaves(ctx context.Context, in *trillian.QueueLeavesRequest)
 	return p.c.QueueLeaves(ctx, in)
 }
 
+// AddSequencedLeaves forwards the RPC.
+func (p *Log) AddSequencedLeaves(ctx context.Context, in *trillian.AddSequencedLeavesRequest) (*trillian.AddSequencedLeavesResponse, error) {
+	return p.c.AddSequencedLeaves(ctx, in)
+}
+
 // GetInclusionProof forwards the RPC.
 func (p *Log) GetInclusionProof(ctx context.Context, in *trillian.GetInclusionProofRequest) (*trillian.GetInclusionProofResponse, error) {
 	return p.c.GetInclusionProof(ctx, in)
@@ -78,7 +85,42 @@ func (p *Log) GetLeavesByHash(ctx context.Context, in *trillian.GetLeavesByHashR
 	return p.c.GetLeavesByHash(ctx, in)
 }
 
+// GetLeavesByIdentityHash forwards the RPC.
+func (p *Log) GetLeavesByIdentityHash(ctx context.Context, in *trillian.GetLeavesByIdentityHashRequest) (*trillian.GetLeavesByIdentityHashResponse, error) {
+	return p.c.GetLeavesByIdentityHash(ctx, in)
+}
+
+// GetLeafStatus forwards the RPC.
+func (p *Log) GetLeafStatus(ctx context.Context, in *trillian.GetLeafStatusRequest) (*trillian.GetLeafStatusResponse, error) {
+	return p.c.GetLeafStatus(ctx, in)
+}
+
 // GetEntryAndProof forwards the RPC.
 func (p *Log) GetEntryAndProof(ctx context.Context, in *trillian.GetEntryAndProofRequest) (*trillian.GetEntryAndProofResponse, error) {
 	return p.c.GetEntryAndProof(ctx, in)
 }
+
+// GetLeavesByRange forwards the RPC.
+func (p *Log) GetLeavesByRange(ctx context.Context, in *trillian.GetLeavesByRangeRequest) (*trillian.GetLeavesByRangeResponse, error) {
+	return p.c.GetLeavesByRange(ctx, in)
+}
+
+// StreamLeavesByRange forwards the RPC, passing on every response.
+func (p *Log) StreamLeavesByRange(in *trillian.GetLeavesByRangeRequest, stream trillian.TrillianLog_StreamLeavesByRangeServer) error {
+	c, err := p.c.StreamLeavesByRange(stream.Context(), in)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := c.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}